@@ -0,0 +1,170 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package analysis computes comment metrics for source files.
+package analysis
+
+import (
+	"github.com/ianlewis/todos/internal/natlang"
+	"github.com/ianlewis/todos/internal/scanner"
+)
+
+// Metrics are comment metrics for a file.
+type Metrics struct {
+	// Lines is the number of lines in the file.
+	Lines int `json:"lines"`
+
+	// BlankLines is the number of lines that only contain whitespace.
+	BlankLines int `json:"blank_lines"`
+
+	// CodeLines is the number of lines that contain code. Lines with both
+	// code and a comment are code lines.
+	CodeLines int `json:"code_lines"`
+
+	// CommentLines is the number of lines that only contain comments and
+	// whitespace.
+	CommentLines int `json:"comment_lines"`
+
+	// Declarations is the number of top-level declarations. A top-level
+	// declaration is an unindented code line that doesn't directly follow
+	// another code line. This is a heuristic that works for most languages.
+	Declarations int `json:"declarations"`
+
+	// Documented is the number of top-level declarations that directly follow
+	// a comment line.
+	Documented int `json:"documented"`
+
+	// CommentWords is the number of words in comments. Words in languages
+	// that don't separate words with spaces are counted per character. See
+	// natlang.Words.
+	CommentWords int `json:"comment_words"`
+}
+
+// CommentDensity returns the ratio of comment lines to code and comment
+// lines. It returns zero if the file has no code or comments.
+func (m *Metrics) CommentDensity() float64 {
+	if m.CodeLines+m.CommentLines == 0 {
+		return 0
+	}
+	return float64(m.CommentLines) / float64(m.CodeLines+m.CommentLines)
+}
+
+// DocCoverage returns the ratio of documented top-level declarations to all
+// top-level declarations. It returns zero if the file has no declarations.
+func (m *Metrics) DocCoverage() float64 {
+	if m.Declarations == 0 {
+		return 0
+	}
+	return float64(m.Documented) / float64(m.Declarations)
+}
+
+// Analyzer computes metrics for a file from the comments found in it.
+// Comments must be added in the order that they appear in the file.
+type Analyzer struct {
+	// comments are the start and end offsets of the comments in the file.
+	comments [][2]int
+
+	// words is the number of words in the comments.
+	words int
+}
+
+// Add records the comment. Only the comment's offsets and word count are
+// used so the comment can be reused after Add returns.
+func (a *Analyzer) Add(c *scanner.Comment) {
+	a.comments = append(a.comments, [2]int{c.Offset, c.EndOffset})
+	a.words += natlang.Words(c.Text)
+}
+
+// lineKind is the kind of a line.
+type lineKind int
+
+const (
+	blankLine lineKind = iota
+	commentLine
+	codeLine
+)
+
+// Metrics returns the metrics for the file with the given raw contents. The
+// contents must be the same that the comments' offsets refer to. Whitespace
+// is detected in the raw contents so metrics are only accurate for ASCII
+// compatible character sets.
+func (a *Analyzer) Metrics(rawContents []byte) *Metrics {
+	m := &Metrics{
+		CommentWords: a.words,
+	}
+
+	prev := blankLine
+	kind := blankLine
+	indented := false
+	lineStart := true
+	comment := 0
+
+	endLine := func() {
+		m.Lines++
+		switch kind {
+		case blankLine:
+			m.BlankLines++
+		case commentLine:
+			m.CommentLines++
+		case codeLine:
+			m.CodeLines++
+			if !indented && prev != codeLine {
+				m.Declarations++
+				if prev == commentLine {
+					m.Documented++
+				}
+			}
+		}
+		prev = kind
+		kind = blankLine
+		indented = false
+		lineStart = true
+	}
+
+	for i := 0; i < len(rawContents); i++ {
+		b := rawContents[i]
+		if b == '\n' || b == '\r' {
+			// NOTE: Windows line endings are a single line ending.
+			if b == '\r' && i+1 < len(rawContents) && rawContents[i+1] == '\n' {
+				i++
+			}
+			endLine()
+			continue
+		}
+
+		for comment < len(a.comments) && a.comments[comment][1] <= i {
+			comment++
+		}
+		inComment := comment < len(a.comments) && a.comments[comment][0] <= i
+
+		switch {
+		case b == ' ' || b == '\t' || b == '\f' || b == '\v':
+			if lineStart {
+				indented = true
+			}
+		case inComment:
+			if kind == blankLine {
+				kind = commentLine
+			}
+		default:
+			kind = codeLine
+		}
+		lineStart = false
+	}
+	if !lineStart {
+		endLine()
+	}
+
+	return m
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/scanner"
+)
+
+func TestAnalyzer_Metrics(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		src      string
+		expected *Metrics
+	}{
+		"empty": {
+			src:      "",
+			expected: &Metrics{},
+		},
+		"go": {
+			src: `// Package main is a command.
+package main
+
+import "fmt"
+
+/*
+  Foo is documented.
+*/
+func Foo() {
+	fmt.Println("foo") // TODO: trailing comment
+}
+
+func bar() {}
+`,
+			expected: &Metrics{
+				Lines:        13,
+				BlankLines:   3,
+				CodeLines:    6,
+				CommentLines: 4,
+				Declarations: 4,
+				Documented:   2,
+				CommentWords: 11,
+			},
+		},
+		"no trailing newline": {
+			src: "// comment\r\nx := 1",
+			expected: &Metrics{
+				Lines:        2,
+				CodeLines:    1,
+				CommentLines: 1,
+				Declarations: 1,
+				Documented:   1,
+				CommentWords: 1,
+			},
+		},
+		"japanese": {
+			src: "// 後で修正する\nx := 1",
+			expected: &Metrics{
+				Lines:        2,
+				CodeLines:    1,
+				CommentLines: 1,
+				Declarations: 1,
+				Documented:   1,
+				CommentWords: 6,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := scanner.FromBytes("main.go", []byte(tc.src), "UTF-8")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var a Analyzer
+			for s.Scan() {
+				a.Add(s.Next())
+			}
+			if err := s.Err(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expected, a.Metrics([]byte(tc.src))); diff != "" {
+				t.Errorf("unexpected metrics (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMetrics_ratios(t *testing.T) {
+	t.Parallel()
+
+	m := &Metrics{
+		CodeLines:    3,
+		CommentLines: 1,
+		Declarations: 4,
+		Documented:   1,
+	}
+	if got, want := m.CommentDensity(), 0.25; got != want {
+		t.Errorf("unexpected comment density, got: %v, want: %v", got, want)
+	}
+	if got, want := m.DocCoverage(), 0.25; got != want {
+		t.Errorf("unexpected doc coverage, got: %v, want: %v", got, want)
+	}
+
+	var empty Metrics
+	if got, want := empty.CommentDensity(), 0.0; got != want {
+		t.Errorf("unexpected comment density, got: %v, want: %v", got, want)
+	}
+	if got, want := empty.DocCoverage(), 0.0; got != want {
+		t.Errorf("unexpected doc coverage, got: %v, want: %v", got, want)
+	}
+}
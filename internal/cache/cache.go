@@ -0,0 +1,51 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cache implements caches for per-file scan results.
+package cache
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+
+	"lukechampine.com/blake3"
+)
+
+// ErrNotFound is returned by Get when the key is not in the cache.
+var ErrNotFound = errors.New("not found")
+
+// Cache stores scan results keyed by a hash of the file contents.
+type Cache interface {
+	// Get returns the value stored for the key. It returns ErrNotFound if
+	// the key is not in the cache.
+	Get(key string) ([]byte, error)
+
+	// Put stores the value for the key.
+	Put(key string, value []byte) error
+}
+
+// Key returns a cache key for the given parts. The key is the hex encoded
+// BLAKE3 hash of the parts. The parts should include everything that affects
+// the scan result such as the file contents and the scan configuration.
+func Key(parts ...[]byte) string {
+	h := blake3.New(32, nil)
+	for _, p := range parts {
+		// NOTE: Prefix each part with its length so that the boundaries
+		// between parts are unambiguous.
+		_, _ = h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(p))))
+		_, _ = h.Write(p)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		a, b     [][]byte
+		expected bool
+	}{
+		"same": {
+			a:        [][]byte{[]byte("foo"), []byte("bar")},
+			b:        [][]byte{[]byte("foo"), []byte("bar")},
+			expected: true,
+		},
+		"different": {
+			a:        [][]byte{[]byte("foo"), []byte("bar")},
+			b:        [][]byte{[]byte("foo"), []byte("baz")},
+			expected: false,
+		},
+		"different boundaries": {
+			a:        [][]byte{[]byte("foo"), []byte("bar")},
+			b:        [][]byte{[]byte("foob"), []byte("ar")},
+			expected: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := Key(tc.a...) == Key(tc.b...), tc.expected; got != want {
+				t.Errorf("Key(%q) == Key(%q); got: %v, want: %v", tc.a, tc.b, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var errDisk = errors.New("disk cache")
+
+// Disk is a Cache that stores values as files in a local directory.
+type Disk struct {
+	dir string
+}
+
+// NewDisk returns a new Disk cache that stores values in dir. The directory
+// is created if it does not exist.
+func NewDisk(dir string) (*Disk, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("%w: creating directory: %w", errDisk, err)
+	}
+	return &Disk{
+		dir: dir,
+	}, nil
+}
+
+// path returns the path of the file for the key. Files are split into sub
+// directories by the first two characters of the key to avoid very large
+// directories.
+func (d *Disk) path(key string) string {
+	if len(key) < 2 {
+		return filepath.Join(d.dir, key)
+	}
+	return filepath.Join(d.dir, key[:2], key)
+}
+
+// Get implements Cache.Get.
+func (d *Disk) Get(key string) ([]byte, error) {
+	b, err := os.ReadFile(d.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("%w: reading %q: %w", errDisk, key, err)
+	}
+	return b, nil
+}
+
+// Put implements Cache.Put.
+func (d *Disk) Put(key string, value []byte) error {
+	p := d.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return fmt.Errorf("%w: creating directory: %w", errDisk, err)
+	}
+
+	// NOTE: Write to a temporary file and rename it so that concurrent
+	// readers never see a partially written value.
+	f, err := os.CreateTemp(filepath.Dir(p), "tmp-*")
+	if err != nil {
+		return fmt.Errorf("%w: writing %q: %w", errDisk, key, err)
+	}
+	if _, err := f.Write(value); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("%w: writing %q: %w", errDisk, key, err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("%w: writing %q: %w", errDisk, key, err)
+	}
+	if err := os.Rename(f.Name(), p); err != nil {
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("%w: writing %q: %w", errDisk, key, err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDisk(t *testing.T) {
+	t.Parallel()
+
+	dir, err := os.MkdirTemp("", "cache")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	d, err := NewDisk(filepath.Join(dir, "sub"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	key := Key([]byte("foo"))
+	if _, err := d.Get(key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unexpected error, got: %v, want: %v", err, ErrNotFound)
+	}
+
+	want := []byte("value")
+	if err := d.Put(key, want); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := d.Get(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected value (-want, +got): \n%s", diff)
+	}
+
+	// Overwrite the value.
+	want = []byte("value2")
+	if err := d.Put(key, want); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err = d.Get(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected value (-want, +got): \n%s", diff)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var errHTTP = errors.New("http cache")
+
+// HTTP is a Cache that stores values on a remote server. Values are
+// retrieved with GET and stored with PUT requests to <baseURL>/<key>.
+type HTTP struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTP returns a new HTTP cache for the server at baseURL. If client is
+// nil then http.DefaultClient is used.
+func NewHTTP(baseURL string, client *http.Client) (*HTTP, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: parsing URL: %w", errHTTP, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("%w: unsupported URL scheme: %q", errHTTP, u.Scheme)
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTP{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  client,
+	}, nil
+}
+
+// Get implements Cache.Get.
+func (h *HTTP) Get(key string) ([]byte, error) {
+	resp, err := h.client.Get(h.baseURL + "/" + url.PathEscape(key))
+	if err != nil {
+		return nil, fmt.Errorf("%w: GET %q: %w", errHTTP, key, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("%w: GET %q: unexpected status: %s", errHTTP, key, resp.Status)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: GET %q: %w", errHTTP, key, err)
+	}
+	return b, nil
+}
+
+// Put implements Cache.Put.
+func (h *HTTP) Put(key string, value []byte) error {
+	req, err := http.NewRequest(http.MethodPut, h.baseURL+"/"+url.PathEscape(key), bytes.NewReader(value))
+	if err != nil {
+		return fmt.Errorf("%w: PUT %q: %w", errHTTP, key, err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: PUT %q: %w", errHTTP, key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: PUT %q: unexpected status: %s", errHTTP, key, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// testServer is a simple in-memory cache server.
+type testServer struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func (s *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.TrimPrefix(r.URL.Path, "/cache/")
+	switch r.Method {
+	case http.MethodGet:
+		v, ok := s.values[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(v)
+	case http.MethodPut:
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		s.values[key] = b
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(&testServer{
+		values: map[string][]byte{},
+	})
+	defer ts.Close()
+
+	h, err := NewHTTP(ts.URL+"/cache/", ts.Client())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	key := Key([]byte("foo"))
+	if _, err := h.Get(key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unexpected error, got: %v, want: %v", err, ErrNotFound)
+	}
+
+	want := []byte("value")
+	if err := h.Put(key, want); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := h.Get(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected value (-want, +got): \n%s", diff)
+	}
+}
+
+func TestHTTP_error(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	h, err := NewHTTP(ts.URL, ts.Client())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := h.Get("foo"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("unexpected error, got: %v", err)
+	}
+	if err := h.Put("foo", []byte("value")); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestNewHTTP_invalidScheme(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewHTTP("ftp://example.com/cache", nil); err == nil {
+		t.Errorf("expected error")
+	}
+}
@@ -16,6 +16,7 @@ package main
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -46,11 +47,27 @@ func (l langConfigs) Swap(i, j int) {
 }
 
 func main() {
+	// Extensions for languages that are not known to enry.
+	localExts := map[string][]string{}
+	for ext, extLangs := range scanner.ExtensionLanguages() {
+		for _, lang := range extLangs {
+			localExts[lang] = append(localExts[lang], ext)
+		}
+	}
+
 	var langs langConfigs
 	for lang, config := range scanner.LanguagesConfig {
 		info, err := enry.GetLanguageInfo(lang)
 		if err != nil {
-			panic(err)
+			exts, ok := localExts[lang]
+			if !ok {
+				panic(err)
+			}
+			sort.Strings(exts)
+			info = data.LanguageInfo{
+				Name:       lang,
+				Extensions: exts,
+			}
 		}
 
 		langs = append(langs, langConfig{
@@ -72,12 +89,28 @@ func main() {
 	for _, l := range langs {
 		var supported []string
 		for _, c := range l.config.LineComments {
-			supported = append(supported, fmt.Sprintf("`%s`", string(c.Start)))
+			s := fmt.Sprintf("`%s`", string(c.Start))
+			if c.AtLineStart {
+				s += " (line start)"
+			}
+			supported = append(supported, s)
 		}
 		for _, c := range l.config.MultilineComments {
 			s := fmt.Sprintf("`%s %s`", string(c.Start), string(c.End))
+			if c.AtLineStart {
+				s += " (line start)"
+			}
 			supported = append(supported, s)
 		}
+		for _, c := range l.config.StatementComments {
+			supported = append(supported, fmt.Sprintf("`%s %s` (statement)", string(c.Start), string(c.End)))
+		}
+		for _, e := range l.config.Embedded {
+			s := e.Language + " code"
+			if !slices.Contains(supported, s) {
+				supported = append(supported, s)
+			}
+		}
 
 		var extensions []string
 		for _, ext := range l.info.Extensions {
@@ -0,0 +1,239 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command todos-action is the entrypoint of the todos GitHub Action. It reads
+// the action inputs from the environment, scans the workspace for TODOs,
+// annotates them with workflow commands, and writes the action outputs.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ianlewis/todos/internal/todos"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+// newTODOsFile is the name of the file with the new TODOs that is written to
+// the runner's temporary directory.
+const newTODOsFile = "todos-new.jsonl"
+
+var (
+	errInput   = errors.New("invalid input")
+	errNewTODO = errors.New("new TODOs were found")
+	errOutput  = errors.New("writing outputs")
+	errScan    = errors.New("scan failed")
+)
+
+// inputs are the action inputs.
+type inputs struct {
+	// Paths are the paths to scan.
+	Paths []string
+
+	// Types are the TODO types to search for.
+	Types []string
+
+	// BaseRef is the git ref that new TODOs are compared against. All TODOs
+	// are new if it is empty.
+	BaseRef string
+
+	// FailOnNew indicates that the action should fail if new TODOs are found.
+	FailOnNew bool
+}
+
+// actionTODO is a TODO in the new TODOs file.
+type actionTODO struct {
+	Path        string `json:"path"`
+	Type        string `json:"type"`
+	Text        string `json:"text"`
+	Label       string `json:"label"`
+	Message     string `json:"message"`
+	Line        int    `json:"line"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+func main() {
+	if err := run(os.Getenv, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stdout, "::error::%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// inputsFromEnv reads the action inputs from the INPUT_ environment variables.
+func inputsFromEnv(getenv func(string) string) (*inputs, error) {
+	in := &inputs{
+		Paths:   strings.Fields(getenv("INPUT_PATHS")),
+		Types:   todos.DefaultTypes,
+		BaseRef: strings.TrimSpace(getenv("INPUT_BASE_REF")),
+	}
+	if len(in.Paths) == 0 {
+		in.Paths = []string{"."}
+	}
+	if typesStr := strings.TrimSpace(getenv("INPUT_TODO_TYPES")); typesStr != "" {
+		in.Types = nil
+		for _, typ := range strings.Split(typesStr, ",") {
+			if typ = strings.TrimSpace(typ); typ != "" {
+				in.Types = append(in.Types, typ)
+			}
+		}
+	}
+	if failStr := strings.TrimSpace(getenv("INPUT_FAIL_ON_NEW")); failStr != "" {
+		fail, err := strconv.ParseBool(failStr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: fail-on-new: %q", errInput, failStr)
+		}
+		in.FailOnNew = fail
+	}
+	return in, nil
+}
+
+// run runs the action with the given environment and writes workflow
+// commands to w.
+func run(getenv func(string) string, w io.Writer) error {
+	in, err := inputsFromEnv(getenv)
+	if err != nil {
+		return err
+	}
+
+	found, err := scan(in, "", w)
+	if err != nil {
+		return err
+	}
+
+	newTODOs := found
+	if in.BaseRef != "" {
+		base, err := scan(in, in.BaseRef, w)
+		if err != nil {
+			return err
+		}
+		known := map[string]bool{}
+		for _, r := range base {
+			known[r.Fingerprint] = true
+		}
+		newTODOs = nil
+		for _, r := range found {
+			if !known[r.Fingerprint] {
+				newTODOs = append(newTODOs, r)
+			}
+		}
+	}
+
+	for _, r := range newTODOs {
+		_, _ = fmt.Fprintf(w, "::warning file=%s,line=%d::%s\n", r.FileName, r.TODO.Line, r.TODO.Text)
+	}
+
+	tmpDir := getenv("RUNNER_TEMP")
+	if tmpDir == "" {
+		tmpDir = os.TempDir()
+	}
+	newPath := filepath.Join(tmpDir, newTODOsFile)
+	if err := writeTODOs(newPath, newTODOs); err != nil {
+		return err
+	}
+
+	outputs := []string{
+		fmt.Sprintf("todo-count=%d", len(found)),
+		fmt.Sprintf("new-todo-count=%d", len(newTODOs)),
+		fmt.Sprintf("new-todos=%s", newPath),
+	}
+	if err := writeOutputs(getenv("GITHUB_OUTPUT"), outputs); err != nil {
+		return err
+	}
+
+	if in.FailOnNew && len(newTODOs) > 0 {
+		return fmt.Errorf("%w: %d", errNewTODO, len(newTODOs))
+	}
+	return nil
+}
+
+// scan returns the TODOs in the paths at the given git ref, or in the working
+// tree if ref is empty. Errors are written to w as workflow commands.
+func scan(in *inputs, ref string, w io.Writer) ([]*walker.TODORef, error) {
+	var found []*walker.TODORef
+	opts := &walker.Options{
+		Config: &todos.Config{
+			Types: in.Types,
+		},
+		Charset: "UTF-8",
+		Paths:   in.Paths,
+		Ref:     ref,
+		TODOFunc: func(r *walker.TODORef) error {
+			if r != nil {
+				found = append(found, r)
+			}
+			return nil
+		},
+		ErrorFunc: func(err error) error {
+			_, _ = fmt.Fprintf(w, "::error::%v\n", err)
+			return nil
+		},
+	}
+	if walker.New(opts).Walk() {
+		return nil, errScan
+	}
+	return found, nil
+}
+
+// writeTODOs writes the TODOs to the file at path as JSON lines.
+func writeTODOs(path string, refs []*walker.TODORef) error {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	for _, r := range refs {
+		if err := enc.Encode(&actionTODO{
+			Path:        r.FileName,
+			Type:        r.TODO.Type,
+			Text:        r.TODO.Text,
+			Label:       r.TODO.Label,
+			Message:     r.TODO.Message,
+			Line:        r.TODO.Line,
+			Fingerprint: r.Fingerprint,
+		}); err != nil {
+			return fmt.Errorf("%w: %w", errOutput, err)
+		}
+	}
+
+	//nolint:gosec // G306: Expect WriteFile permissions to be 0600 or less.
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("%w: %w", errOutput, err)
+	}
+	return nil
+}
+
+// writeOutputs appends the outputs to the GITHUB_OUTPUT file at path. The
+// outputs are not written if path is empty, such as when running locally.
+func writeOutputs(path string, outputs []string) error {
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errOutput, err)
+	}
+	_, err = io.WriteString(f, strings.Join(outputs, "\n")+"\n")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", errOutput, err)
+	}
+	return nil
+}
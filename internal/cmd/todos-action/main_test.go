@@ -0,0 +1,168 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/ianlewis/todos/internal/testutils"
+	"github.com/ianlewis/todos/internal/todos"
+)
+
+func Test_inputsFromEnv(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		env      map[string]string
+		expected *inputs
+		err      error
+	}{
+		"defaults": {
+			env: map[string]string{},
+			expected: &inputs{
+				Paths: []string{"."},
+				Types: todos.DefaultTypes,
+			},
+		},
+		"inputs": {
+			env: map[string]string{
+				"INPUT_PATHS":       "cmd\ninternal\n",
+				"INPUT_TODO_TYPES":  "TODO, FIXME",
+				"INPUT_BASE_REF":    "origin/main",
+				"INPUT_FAIL_ON_NEW": "true",
+			},
+			expected: &inputs{
+				Paths:     []string{"cmd", "internal"},
+				Types:     []string{"TODO", "FIXME"},
+				BaseRef:   "origin/main",
+				FailOnNew: true,
+			},
+		},
+		"invalid fail-on-new": {
+			env: map[string]string{
+				"INPUT_FAIL_ON_NEW": "maybe",
+			},
+			err: errInput,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := inputsFromEnv(func(k string) string { return tc.env[k] })
+			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Fatalf("unexpected error (-want, +got): \n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected inputs (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_run(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		baseRef   bool
+		failOnNew bool
+
+		expected        string
+		expectedOutputs string
+		expectedNew     int
+		err             error
+	}{
+		"all": {
+			expected:        "::warning file=%[1]s/code.go,line=1::// TODO: committed\n::warning file=%[1]s/new.go,line=1::// TODO: new\n",
+			expectedOutputs: "todo-count=2\nnew-todo-count=2\nnew-todos=%[2]s\n",
+			expectedNew:     2,
+		},
+		"base ref": {
+			baseRef:         true,
+			expected:        "::warning file=%[1]s/new.go,line=1::// TODO: new\n",
+			expectedOutputs: "todo-count=2\nnew-todo-count=1\nnew-todos=%[2]s\n",
+			expectedNew:     1,
+		},
+		"fail on new": {
+			baseRef:         true,
+			failOnNew:       true,
+			expected:        "::warning file=%[1]s/new.go,line=1::// TODO: new\n",
+			expectedOutputs: "todo-count=2\nnew-todo-count=1\nnew-todos=%[2]s\n",
+			expectedNew:     1,
+			err:             errNewTODO,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			repo := testutils.NewTestRepo(dir, "Author", "author@example.com", []*testutils.File{
+				{
+					Path:     "code.go",
+					Contents: []byte("// TODO: committed"),
+					Mode:     0o600,
+				},
+			})
+			testutils.Check(os.WriteFile(filepath.Join(repo.Dir(), "new.go"), []byte("// TODO: new"), 0o600))
+
+			tmpDir := t.TempDir()
+			outputPath := filepath.Join(tmpDir, "output")
+			env := map[string]string{
+				"INPUT_PATHS":   repo.Dir(),
+				"RUNNER_TEMP":   tmpDir,
+				"GITHUB_OUTPUT": outputPath,
+			}
+			if tc.baseRef {
+				env["INPUT_BASE_REF"] = "HEAD"
+			}
+			if tc.failOnNew {
+				env["INPUT_FAIL_ON_NEW"] = "true"
+			}
+
+			var b strings.Builder
+			err := run(func(k string) string { return env[k] }, &b)
+			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Fatalf("unexpected error (-want, +got): \n%s", diff)
+			}
+
+			newPath := filepath.Join(tmpDir, newTODOsFile)
+			expected := fmt.Sprintf(tc.expected, repo.Dir(), newPath)
+			if diff := cmp.Diff(expected, b.String()); diff != "" {
+				t.Errorf("unexpected annotations (-want, +got): \n%s", diff)
+			}
+
+			expectedOutputs := fmt.Sprintf(tc.expectedOutputs, repo.Dir(), newPath)
+			outputs := string(testutils.Must(os.ReadFile(outputPath)))
+			if diff := cmp.Diff(expectedOutputs, outputs); diff != "" {
+				t.Errorf("unexpected outputs (-want, +got): \n%s", diff)
+			}
+
+			newTODOs := strings.Count(string(testutils.Must(os.ReadFile(newPath))), "\n")
+			if got, want := newTODOs, tc.expectedNew; got != want {
+				t.Errorf("unexpected # of new TODOs, got: %v, want: %v", got, want)
+			}
+		})
+	}
+}
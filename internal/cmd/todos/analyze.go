@@ -0,0 +1,145 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/todos/internal/analysis"
+	"github.com/ianlewis/todos/internal/utils"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+// fileAnalysis is the analysis output for a single file.
+type fileAnalysis struct {
+	// Path is the path to the file.
+	Path string `json:"path"`
+
+	// Language is the detected language of the file.
+	Language string `json:"language"`
+
+	// Detection describes how the language was detected.
+	Detection *outDetection `json:"detection,omitempty"`
+
+	*analysis.Metrics
+
+	// TODOs is the number of TODOs found in the file.
+	TODOs int `json:"todos"`
+
+	// CommentDensity is the ratio of comment lines to code and comment lines.
+	CommentDensity float64 `json:"comment_density"`
+
+	// DocCoverage is the ratio of documented top-level declarations to all
+	// top-level declarations.
+	DocCoverage float64 `json:"doc_coverage"`
+}
+
+var analyzeOutTypes = map[string]func(io.Writer, []*fileAnalysis){
+	"":      outAnalyzeTable,
+	"table": outAnalyzeTable,
+	"json":  outAnalyzeJSON,
+}
+
+func newAnalyzeCommand() *cli.Command {
+	flags := sortedFlags(append(walkerFlags(),
+		&cli.StringFlag{
+			Name:    "output",
+			Usage:   "output `TYPE` (table, json)",
+			Value:   "table",
+			Aliases: []string{"o"},
+		},
+	))
+
+	return &cli.Command{
+		Name:      "analyze",
+		Usage:     "Print comment metrics for each file.",
+		ArgsUsage: "[PATH]...",
+		HideHelp:  true,
+		Flags: append(flags,
+			&cli.BoolFlag{
+				Name:               "help",
+				Usage:              "print this help text and exit",
+				Aliases:            []string{"h"},
+				DisableDefaultText: true,
+			},
+		),
+		Action: func(c *cli.Context) error {
+			if c.Bool("help") {
+				utils.Check(cli.ShowCommandHelp(c, c.Command.Name))
+				return nil
+			}
+
+			outType := c.String("output")
+			outFunc, ok := analyzeOutTypes[outType]
+			if !ok {
+				return fmt.Errorf("%w: invalid output type: %v", ErrFlagParse, outType)
+			}
+
+			opts, err := walkerOptionsFromContext(c)
+			if err != nil {
+				return err
+			}
+			opts.Metrics = true
+
+			var files []*fileAnalysis
+			opts.FileResultFunc = func(r *walker.FileResult) error {
+				// NOTE: Metrics are not computed for binary files.
+				if r.Metrics == nil {
+					return nil
+				}
+				files = append(files, &fileAnalysis{
+					Path:           r.FileName,
+					Language:       r.Language,
+					Detection:      newOutDetection(r.LanguageDetection),
+					Metrics:        r.Metrics,
+					TODOs:          len(r.TODOs),
+					CommentDensity: r.Metrics.CommentDensity(),
+					DocCoverage:    r.Metrics.DocCoverage(),
+				})
+				return nil
+			}
+
+			walkErr := walker.New(opts).Walk()
+			outFunc(c.App.Writer, files)
+			if walkErr {
+				return ErrWalk
+			}
+			return nil
+		},
+	}
+}
+
+func outAnalyzeTable(w io.Writer, files []*fileAnalysis) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_ = utils.Must(fmt.Fprintln(tw, "PATH\tCODE\tCOMMENTS\tWORDS\tTODOS\tDENSITY\tDOC COVERAGE"))
+	for _, f := range files {
+		_ = utils.Must(fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%.2f\t%.2f\n",
+			f.Path, f.CodeLines, f.CommentLines, f.CommentWords, f.TODOs, f.CommentDensity, f.DocCoverage))
+	}
+	utils.Check(tw.Flush())
+}
+
+func outAnalyzeJSON(w io.Writer, files []*fileAnalysis) {
+	for _, f := range files {
+		b := utils.Must(json.Marshal(f))
+		_ = utils.Must(w.Write(b))
+		_ = utils.Must(w.Write([]byte("\n")))
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/analysis"
+	"github.com/ianlewis/todos/internal/testutils"
+)
+
+func Test_analyzeOutTypes(t *testing.T) {
+	t.Parallel()
+
+	files := []*fileAnalysis{
+		{
+			Path:     "foo.go",
+			Language: "Go",
+			Metrics: &analysis.Metrics{
+				Lines:        10,
+				BlankLines:   2,
+				CodeLines:    6,
+				CommentLines: 2,
+				Declarations: 2,
+				Documented:   1,
+				CommentWords: 9,
+			},
+			TODOs:          1,
+			CommentDensity: 0.25,
+			DocCoverage:    0.5,
+		},
+	}
+
+	testCases := map[string]struct {
+		outType  string
+		expected string
+	}{
+		"table": {
+			outType: "table",
+			expected: `PATH    CODE  COMMENTS  WORDS  TODOS  DENSITY  DOC COVERAGE
+foo.go  6     2         9      1      0.25     0.50
+`,
+		},
+		"json": {
+			outType: "json",
+			expected: `{"path":"foo.go","language":"Go","lines":10,"blank_lines":2,"code_lines":6,"comment_lines":2,"declarations":2,"documented":1,"comment_words":9,"todos":1,"comment_density":0.25,"doc_coverage":0.5}
+`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+			analyzeOutTypes[tc.outType](&b, files)
+			if diff := cmp.Diff(tc.expected, b.String()); diff != "" {
+				t.Errorf("unexpected output (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_TODOsApp_analyze(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "foo.go",
+			Contents: []byte("// Package foo does foo.\npackage foo\n\n// TODO: foo\n"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	app := newTODOsApp()
+	var b strings.Builder
+	app.Writer = &b
+	if err := app.Run([]string{"todos", "analyze", "--output=json", d.Dir()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := fmt.Sprintf(`{"path":%q,"language":"Go","detection":{"method":"extension","candidates":["Go"],"confident":true},"lines":4,"blank_lines":1,"code_lines":1,"comment_lines":2,"declarations":1,"documented":1,"comment_words":6,"todos":1,"comment_density":0.6666666666666666,"doc_coverage":1}
+`, filepath.Join(d.Dir(), "foo.go"))
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("unexpected output (-want, +got): \n%s", diff)
+	}
+}
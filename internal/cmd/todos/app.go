@@ -19,16 +19,25 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/gobwas/glob"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/text/encoding/ianaindex"
-	"sigs.k8s.io/release-utils/version"
 
+	"github.com/ianlewis/todos/internal/cache"
+	"github.com/ianlewis/todos/internal/natlang"
+	"github.com/ianlewis/todos/internal/scanner"
 	"github.com/ianlewis/todos/internal/todos"
 	"github.com/ianlewis/todos/internal/utils"
 	"github.com/ianlewis/todos/internal/walker"
@@ -46,10 +55,24 @@ const (
 
 	// ExitCodeUnknownError is the exit code for an unknown error.
 	ExitCodeUnknownError
+
+	// ExitCodeRatchetError is the exit code for when the number of TODOs
+	// increased since the ratchet state was recorded.
+	ExitCodeRatchetError
+
+	// ExitCodeNotIgnored is the exit code for why-ignored when none of the
+	// paths are ignored.
+	ExitCodeNotIgnored
 )
 
 const defaultCharset = "UTF-8"
 
+// defaultMaxLineLength is the default maximum length in bytes of comment lines.
+const defaultMaxLineLength = 64 * 1024
+
+// cacheTimeout is the timeout for requests to the HTTP cache.
+const cacheTimeout = 30 * time.Second
+
 var (
 	// ErrFlagParse is a flag parsing error.
 	ErrFlagParse = errors.New("parsing flags")
@@ -74,78 +97,356 @@ func init() {
 	}
 }
 
+// walkerFlags returns the flags that configure the walker. They are shared
+// by all commands that scan files.
+func walkerFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "assignee",
+			Usage: "only output TODOs assigned to `USER` by their label",
+		},
+		&cli.StringSliceFlag{
+			Name:  "attr",
+			Usage: "only output TODOs with attribute `KEY[=GLOB]` in their label",
+		},
+		&cli.BoolFlag{
+			Name:               "audit",
+			Usage:              "warn about skipped files that contain TODOs",
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "blame",
+			Usage:              "[BETA] attempt to find committer info",
+			Value:              false,
+			DisableDefaultText: true,
+		},
+		&cli.StringFlag{
+			Name:  "cache-dir",
+			Usage: "cache scan results in `DIR`",
+		},
+		&cli.StringFlag{
+			Name:  "cache-url",
+			Usage: "cache scan results on the HTTP server at `URL`",
+		},
+		&cli.StringFlag{
+			Name:    "charset",
+			Usage:   "character set to use when reading files ('detect' to perform charset detection)",
+			Value:   defaultCharset,
+			Aliases: []string{"c"},
+		},
+		&cli.BoolFlag{
+			Name:               "confine",
+			Usage:              "skip files and configuration files that resolve outside of the given paths",
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "dedup",
+			Usage:              "skip hard links and bind mounts of files that were already scanned",
+			DisableDefaultText: true,
+		},
+		&cli.StringSliceFlag{
+			Name:  "exclude",
+			Usage: "exclude files that match `GLOB`",
+		},
+		&cli.StringSliceFlag{
+			Name:  "exclude-dir",
+			Usage: "exclude directories that match `GLOB`",
+		},
+		&cli.BoolFlag{
+			Name:               "exclude-hidden",
+			Usage:              "exclude hidden files and directories",
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "extract",
+			Usage:              "scan text extracted from documents such as .docx files if todos was built with extractors",
+			DisableDefaultText: true,
+		},
+		&cli.StringSliceFlag{
+			Name:  "extension-language",
+			Usage: "scan files with an extension as a language given as `EXT=LANGUAGE`",
+		},
+		&cli.StringSliceFlag{
+			Name:  "exclude-label",
+			Usage: "do not output TODOs with labels that match `GLOB`",
+		},
+		&cli.StringSliceFlag{
+			Name:  "exclude-type",
+			Usage: "do not output TODOs of `TYPE`",
+		},
+		&cli.StringFlag{
+			Name:  "fallback-lang",
+			Usage: "scan files of unsupported languages as `LANGUAGE`, or for TODOs on whole lines if it is Text",
+		},
+		&cli.StringFlag{
+			Name:  "follow-symlinks",
+			Usage: "follow symbolic links to files by `MODE` (all, in-tree, none)",
+			Value: "all",
+		},
+		&cli.BoolFlag{
+			Name:               "fast",
+			Usage:              "don't parse string literals for faster, less precise scans",
+			DisableDefaultText: true,
+		},
+		&cli.StringFlag{
+			Name:  "glob-case",
+			Usage: "match exclude globs by case `MODE` (sensitive, insensitive, auto)",
+			Value: "sensitive",
+		},
+		&cli.BoolFlag{
+			Name:               "patch",
+			Usage:              "scan unified diff files and report TODOs on the lines they add",
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "include-docs",
+			Usage:              "include documentation files and directories",
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "include-vcs",
+			Usage:              "include version control directories (.git, .hg, .svn)",
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "include-generated",
+			Usage:              "include generated files",
+			Value:              false,
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "include-vendored",
+			Usage:              "include vendored directories",
+			Value:              false,
+			DisableDefaultText: true,
+		},
+		&cli.StringSliceFlag{
+			Name:  "grep",
+			Usage: "only output TODOs with text that matches `REGEX`",
+		},
+		&cli.StringSliceFlag{
+			Name:    "label",
+			Usage:   "only output TODOs that match `GLOB`",
+			Aliases: []string{"l"},
+		},
+		&cli.StringFlag{
+			Name:  "lines",
+			Usage: "only output TODOs in the line `RANGE` (START:END) of a single file",
+		},
+		&cli.BoolFlag{
+			Name:               "match-anywhere",
+			Usage:              "match TODOs that follow other text in a comment",
+			DisableDefaultText: true,
+		},
+		&cli.StringFlag{
+			Name:  "max-line-length",
+			Usage: "truncate comment lines longer than `SIZE` (e.g. 64K, 0 for no limit)",
+			Value: strconv.Itoa(defaultMaxLineLength),
+		},
+		&cli.StringFlag{
+			Name:  "max-size",
+			Usage: "only scan files smaller than `SIZE` (e.g. 512K, 10M)",
+		},
+		&cli.StringFlag{
+			Name:  "min-size",
+			Usage: "only scan files larger than `SIZE` (e.g. 512K, 10M)",
+		},
+		&cli.StringFlag{
+			Name:  "modified-since",
+			Usage: "only scan files modified since `DATE` (YYYY-MM-DD or RFC 3339)",
+		},
+		&cli.BoolFlag{
+			Name:               "owners",
+			Usage:              "find file owners from CODEOWNERS",
+			DisableDefaultText: true,
+		},
+		&cli.StringFlag{
+			Name:  "ref",
+			Usage: "scan files at the git `REF` (tag, branch, or commit) instead of the working tree",
+		},
+		&cli.StringFlag{
+			Name:  "repo",
+			Usage: "resolve short issue references (e.g. #123) in labels against the GitHub `OWNER/REPO`",
+		},
+		&cli.BoolFlag{
+			Name:               "detect-message-language",
+			Usage:              "detect the natural language that TODO messages are written in",
+			Value:              false,
+			DisableDefaultText: true,
+		},
+		&cli.StringSliceFlag{
+			Name:  "message-language",
+			Usage: "only output TODOs with messages written in the natural language `LANG` given as an ISO 639-1 code, or und if undetermined",
+		},
+		&cli.BoolFlag{
+			Name:               "check-secrets",
+			Usage:              "warn about TODOs that contain text that looks like credentials or internal URLs",
+			Value:              false,
+			DisableDefaultText: true,
+		},
+		&cli.StringSliceFlag{
+			Name:  "secret-pattern",
+			Usage: "also warn about TODOs with text that matches a pattern given as `NAME=REGEX` (implies --check-secrets)",
+		},
+		&cli.BoolFlag{
+			Name:               "infer-priority",
+			Usage:              "infer the priority of TODOs from keywords in their message",
+			Value:              false,
+			DisableDefaultText: true,
+		},
+		&cli.StringSliceFlag{
+			Name:  "priority-keyword",
+			Usage: "infer priority PRIORITY for TODOs with KEYWORD in their message given as `KEYWORD=PRIORITY` (implies --infer-priority)",
+		},
+		&cli.StringFlag{
+			Name:  "shard",
+			Usage: "only scan files in shard `I/N` where I is between 1 and N",
+		},
+		&cli.BoolFlag{
+			Name:               "verbose",
+			Usage:              "print the paths that are skipped and why, and the configuration files that are loaded",
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "skip-shebang",
+			Usage:              "don't scan shebang lines at the start of files",
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "skip-tests",
+			Usage:              "exclude well-known test files and directories",
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "strict-config",
+			Usage:              "stop with an error if a .todos.yml file can't be read or parsed",
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "strict-match",
+			Usage:              "only match TODOs with a colon after the type or label (e.g. TODO: msg)",
+			DisableDefaultText: true,
+		},
+		&cli.StringFlag{
+			Name:  "todo-types",
+			Usage: "comma separated list of TODO `TYPES`",
+			Value: strings.Join(todos.DefaultTypes, ","),
+		},
+		&cli.BoolFlag{
+			Name:               "warn-near-misses",
+			Usage:              "warn about TODOs whose type only differs from TODO `TYPES` by case",
+			DisableDefaultText: true,
+		},
+	}
+}
+
+// sortedFlags sorts the flags by name so that they are shown in alphabetical
+// order in the help output.
+func sortedFlags(flags []cli.Flag) []cli.Flag {
+	sort.Sort(cli.FlagsByName(flags))
+	return flags
+}
+
+// defaultOutputType returns the default output type for the current
+// environment.
+func defaultOutputType() string {
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		return "github"
+	}
+	if os.Getenv("TF_BUILD") == "True" {
+		return "azure"
+	}
+	return "default"
+}
+
 // newTODOsApp returns a new `todos` application.
 func newTODOsApp() *cli.App {
-	defaultOutput := "default"
-	gha := os.Getenv("GITHUB_ACTIONS")
-	if gha == "true" {
-		defaultOutput = "github"
-	}
+	defaultOutput := defaultOutputType()
+
+	// NOTE: Flags for functionality are in alphabetical order.
+	flags := sortedFlags(append(append(walkerFlags(), redactFlags()...),
+		&cli.StringFlag{
+			Name:  "explain",
+			Usage: "print the reason that `PATH` is skipped and exit",
+		},
+		&cli.StringFlag{
+			Name:  "ratchet",
+			Usage: "fail if the number of TODOs in a directory increased since the counts were recorded in `FILE`",
+		},
+		&cli.BoolFlag{
+			Name:               "server",
+			Usage:              "answer JSON-RPC requests read from stdin one per line",
+			DisableDefaultText: true,
+		},
+		&cli.StringFlag{
+			Name:  "server-max-request-size",
+			Usage: "reject server requests larger than `SIZE` (e.g. 512K, 10M)",
+		},
+		&cli.DurationFlag{
+			Name:  "server-timeout",
+			Usage: "stop server requests that scan for longer than `DURATION`",
+		},
+		&cli.IntFlag{
+			Name:  "server-max-results",
+			Usage: "stop server requests that find more than `N` TODOs",
+		},
+		&cli.IntFlag{
+			Name:  "server-max-concurrent",
+			Usage: "handle up to `N` server requests at the same time",
+			Value: 1,
+		},
+		&cli.BoolFlag{
+			Name:               "list-files",
+			Usage:              "list the files that would be scanned and exit",
+			DisableDefaultText: true,
+		},
+		&cli.StringFlag{
+			Name:  "gerrit-change",
+			Usage: "post TODOs added by --patch as robot comments on the Gerrit change with the given `ID`",
+		},
+		&cli.StringFlag{
+			Name:  "gerrit-revision",
+			Usage: "`REVISION` of the Gerrit change to comment on",
+			Value: "current",
+		},
+		&cli.StringFlag{
+			Name:  "gerrit-url",
+			Usage: "`URL` of the Gerrit server",
+		},
+		&cli.StringFlag{
+			Name:  "manifest",
+			Usage: "write a JSON manifest describing the run to `FILE`",
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Usage:   "output `TYPE` (default, github, azure, bitbucket, json, ndjson, sqlite:PATH, postgres:TABLE, bigquery:PROJECT.DATASET.TABLE, webhook:URL)",
+			Value:   defaultOutput,
+			Aliases: []string{"o"},
+		},
+		&cli.StringFlag{
+			Name:  "check-run",
+			Usage: "publish results as a GitHub check run with the given `NAME`",
+		},
+		&cli.StringFlag{
+			Name:  "check-run-conclusion",
+			Usage: "check run `CONCLUSION` if TODOs are found (neutral, failure, success)",
+			Value: "neutral",
+		},
+		&cli.BoolFlag{
+			Name:               "stats",
+			Usage:              "print scan statistics to stderr after the run",
+			DisableDefaultText: true,
+		},
+		&cli.StringFlag{
+			Name:  "state-file",
+			Usage: "save the walk state to `FILE` and resume an interrupted walk from it",
+		},
+	))
 
 	return &cli.App{
 		Name:  filepath.Base(os.Args[0]),
 		Usage: "Search for TODOS in code.",
-		Flags: []cli.Flag{
-			// Flags for functionality are in alphabetical order.
-			&cli.BoolFlag{
-				Name:               "blame",
-				Usage:              "[BETA] attempt to find committer info",
-				Value:              false,
-				DisableDefaultText: true,
-			},
-			&cli.StringFlag{
-				Name:    "charset",
-				Usage:   "character set to use when reading files ('detect' to perform charset detection)",
-				Value:   defaultCharset,
-				Aliases: []string{"c"},
-			},
-			&cli.StringSliceFlag{
-				Name:  "exclude",
-				Usage: "exclude files that match `GLOB`",
-			},
-			&cli.StringSliceFlag{
-				Name:  "exclude-dir",
-				Usage: "exclude directories that match `GLOB`",
-			},
-			&cli.BoolFlag{
-				Name:               "exclude-hidden",
-				Usage:              "exclude hidden files and directories",
-				DisableDefaultText: true,
-			},
-			&cli.BoolFlag{
-				Name:               "include-vcs",
-				Usage:              "include version control directories (.git, .hg, .svn)",
-				DisableDefaultText: true,
-			},
-			&cli.BoolFlag{
-				Name:               "include-generated",
-				Usage:              "include generated files",
-				Value:              false,
-				DisableDefaultText: true,
-			},
-			&cli.BoolFlag{
-				Name:               "include-vendored",
-				Usage:              "include vendored directories",
-				Value:              false,
-				DisableDefaultText: true,
-			},
-			&cli.StringSliceFlag{
-				Name:    "label",
-				Usage:   "only output TODOs that match `GLOB`",
-				Aliases: []string{"l"},
-			},
-			&cli.StringFlag{
-				Name:    "output",
-				Usage:   "output `TYPE` (default, github, json)",
-				Value:   defaultOutput,
-				Aliases: []string{"o"},
-			},
-			&cli.StringFlag{
-				Name:  "todo-types",
-				Usage: "comma separated list of TODO `TYPES`",
-				Value: strings.Join(todos.DefaultTypes, ","),
-			},
-
+		Flags: append(flags,
 			// Special flags are shown at the end.
 			&cli.BoolFlag{
 				Name:               "help",
@@ -159,6 +460,17 @@ func newTODOsApp() *cli.App {
 				Aliases:            []string{"v"},
 				DisableDefaultText: true,
 			},
+		),
+		Commands: []*cli.Command{
+			newAnalyzeCommand(),
+			newDiffCommand(),
+			newExportMDCommand(),
+			newLanguagesCommand(),
+			newMergeCommand(),
+			newSummaryCommand(),
+			newVerifyCommand(),
+			newVersionCommand(),
+			newWhyIgnoredCommand(),
 		},
 		ArgsUsage:       "[PATH]...",
 		Copyright:       "Google LLC",
@@ -171,20 +483,81 @@ func newTODOsApp() *cli.App {
 			}
 
 			if c.Bool("version") {
-				versionInfo := version.GetVersionInfo()
-				_ = utils.Must(fmt.Fprintf(c.App.Writer, `%s %s
-Copyright (c) Google LLC
-
-%s`, c.App.Name, versionInfo.GitVersion, versionInfo.String()))
+				printVersion(c.App.Writer, c.App.Name)
 				return nil
 			}
 
-			opts, err := walkerOptionsFromContext(c)
+			if c.Bool("server") {
+				opts, err := walkerOptionsFromContext(c)
+				if err != nil {
+					return err
+				}
+				limits, err := serverLimitsFromContext(c)
+				if err != nil {
+					return err
+				}
+				return serve(c.App.Reader, c.App.Writer, opts, limits)
+			}
+
+			opts, sk, err := todosOptionsFromContext(c)
 			if err != nil {
 				return err
 			}
-			w := walker.New(opts)
-			if w.Walk() {
+			if p := c.String("explain"); p != "" {
+				return explain(c.App.Writer, walker.New(opts), p)
+			}
+
+			m := manifestFromContext(c, opts)
+			st := statsFromContext(c, opts)
+			rt, err := ratchetFromContext(c, opts)
+			if err != nil {
+				return err
+			}
+			cr, err := checkRunFromContext(c, opts)
+			if err != nil {
+				return err
+			}
+			gr, err := gerritFromContext(c, opts)
+			if err != nil {
+				return err
+			}
+			walkErr := walker.New(opts).Walk()
+
+			if err := cr.publish(walkErr); err != nil {
+				return err
+			}
+
+			if err := gr.publish(); err != nil {
+				return err
+			}
+
+			if sk != nil {
+				if err := sk.Close(walkErr); err != nil {
+					return err
+				}
+			}
+
+			if err := writeStats(c.App.ErrWriter, st); err != nil {
+				return err
+			}
+
+			if err := writeManifest(c.String("manifest"), m); err != nil {
+				return err
+			}
+
+			// NOTE: Counts are incomplete if there were errors.
+			if !walkErr {
+				if err := rt.check(c.App.ErrWriter, c.App.Name); err != nil {
+					return err
+				}
+			}
+
+			// NOTE: The walk completed so the state file is no longer needed.
+			if err := removeState(c.String("state-file")); err != nil {
+				return err
+			}
+
+			if walkErr {
 				return ErrWalk
 			}
 
@@ -202,18 +575,43 @@ Copyright (c) Google LLC
 				return
 			}
 
+			// NOTE: Like `git check-ignore`, why-ignored exits with an exit
+			// code without printing an error if no paths are ignored.
+			if errors.Is(err, ErrNotIgnored) {
+				cli.OsExiter(ExitCodeNotIgnored)
+				return
+			}
+
 			// ExitCode return an exit code for the given error.
-			_ = utils.Must(fmt.Fprintf(c.App.ErrWriter, "%s: %v\n", c.App.Name, err))
+			_ = utils.Must(fmt.Fprintf(c.App.ErrWriter, "%s: %s\n", c.App.Name, messageCatalog(c).errorText(err)))
 			if errors.Is(err, ErrFlagParse) {
 				cli.OsExiter(ExitCodeFlagParseError)
 				return
 			}
+			if errors.Is(err, ErrRatchet) {
+				cli.OsExiter(ExitCodeRatchetError)
+				return
+			}
 
 			cli.OsExiter(ExitCodeUnknownError)
 		},
 	}
 }
 
+// explain prints the reason that the walker skips the path.
+func explain(out io.Writer, w *walker.TODOWalker, path string) error {
+	r, err := w.Explain(path)
+	if err != nil {
+		return fmt.Errorf("explain: %w", err)
+	}
+	if r == nil {
+		_ = utils.Must(fmt.Fprintf(out, "%s: not skipped\n", path))
+		return nil
+	}
+	_ = utils.Must(fmt.Fprintf(out, "%s: skipped: %s\n", path, r))
+	return nil
+}
+
 func outCLI(w io.Writer) walker.TODOHandler {
 	return func(o *walker.TODORef) error {
 		if o == nil {
@@ -239,19 +637,106 @@ func outCLI(w io.Writer) walker.TODOHandler {
 	}
 }
 
+// githubLevel returns the GitHub annotation level for the TODO type.
+func githubLevel(typ string) string {
+	switch typ {
+	case "TODO", "HACK", "COMBAK":
+		return "warning"
+	case "FIXME", "XXX", "BUG":
+		return "error"
+	}
+	return "notice"
+}
+
 func outGithub(w io.Writer) walker.TODOHandler {
 	return func(o *walker.TODORef) error {
 		if o == nil {
 			return nil
 		}
-		typ := "notice"
-		switch o.TODO.Type {
-		case "TODO", "HACK", "COMBAK":
-			typ = "warning"
-		case "FIXME", "XXX", "BUG":
+		typ := githubLevel(o.TODO.Type)
+		loc := fmt.Sprintf("file=%s,line=%d", o.FileName, o.TODO.Line)
+		if o.TODO.EndLine > 0 {
+			loc += fmt.Sprintf(",endLine=%d", o.TODO.EndLine)
+		}
+		_ = utils.Must(fmt.Fprintf(w, "::%s %s::%s\n", typ, loc, o.TODO.Text))
+		return nil
+	}
+}
+
+// azureMessageEscaper escapes messages in Azure DevOps logging commands.
+var azureMessageEscaper = strings.NewReplacer(
+	"%", "%AZP25",
+	"\r", "%0D",
+	"\n", "%0A",
+)
+
+// azurePropertyEscaper escapes property values in Azure DevOps logging
+// commands.
+var azurePropertyEscaper = strings.NewReplacer(
+	"%", "%AZP25",
+	"\r", "%0D",
+	"\n", "%0A",
+	";", "%3B",
+	"]", "%5D",
+)
+
+func outAzure(w io.Writer) walker.TODOHandler {
+	return func(o *walker.TODORef) error {
+		if o == nil {
+			return nil
+		}
+		// NOTE: Azure DevOps only supports warning and error issues.
+		typ := "warning"
+		if githubLevel(o.TODO.Type) == "error" {
 			typ = "error"
 		}
-		_ = utils.Must(fmt.Fprintf(w, "::%s file=%s,line=%d::%s\n", typ, o.FileName, o.TODO.Line, o.TODO.Text))
+		_ = utils.Must(fmt.Fprintf(w, "##vso[task.logissue type=%s;sourcepath=%s;linenumber=%d;]%s\n",
+			typ, azurePropertyEscaper.Replace(o.FileName), o.TODO.Line, azureMessageEscaper.Replace(o.TODO.Text)))
+		return nil
+	}
+}
+
+// maxBitbucketSummary is the maximum length of the summary of a Bitbucket
+// Code Insights annotation.
+const maxBitbucketSummary = 255
+
+// bitbucketSeverities are the Bitbucket Code Insights severities for each
+// GitHub annotation level.
+var bitbucketSeverities = map[string]string{
+	"notice":  "LOW",
+	"warning": "MEDIUM",
+	"error":   "HIGH",
+}
+
+// bitbucketAnnotation is a Bitbucket Code Insights annotation.
+type bitbucketAnnotation struct {
+	ExternalID     string `json:"external_id"`
+	AnnotationType string `json:"annotation_type"`
+	Path           string `json:"path"`
+	Line           int    `json:"line"`
+	Summary        string `json:"summary"`
+	Severity       string `json:"severity"`
+}
+
+func outBitbucket(w io.Writer) walker.TODOHandler {
+	return func(o *walker.TODORef) error {
+		if o == nil {
+			return nil
+		}
+		summary := o.TODO.Text
+		if r := []rune(summary); len(r) > maxBitbucketSummary {
+			summary = string(r[:maxBitbucketSummary])
+		}
+		b := utils.Must(json.Marshal(&bitbucketAnnotation{
+			ExternalID:     o.Fingerprint,
+			AnnotationType: "CODE_SMELL",
+			Path:           filepath.ToSlash(filepath.Clean(o.FileName)),
+			Line:           o.TODO.Line,
+			Summary:        summary,
+			Severity:       bitbucketSeverities[githubLevel(o.TODO.Type)],
+		}))
+		_ = utils.Must(w.Write(b))
+		_ = utils.Must(w.Write([]byte("\n")))
 		return nil
 	}
 }
@@ -274,6 +759,14 @@ type outTODO struct {
 	// Label is the label part (the part in parenthesis)
 	Label string `json:"label"`
 
+	// CanonicalLabel is the canonical issue reference if the label refers to
+	// an issue.
+	CanonicalLabel string `json:"canonical_label,omitempty"`
+
+	// ReferenceKind is the kind of item (issue, pull_request, or discussion)
+	// the label refers to if it is an issue reference.
+	ReferenceKind string `json:"reference_kind,omitempty"`
+
 	// Message is the comment message (the part after the parenthesis).
 	Message string `json:"message"`
 
@@ -283,33 +776,175 @@ type outTODO struct {
 	// CommentLine is the line where the comment starts.
 	CommentLine int `json:"comment_line"`
 
+	// EndLine is the line where a TODO region ends.
+	EndLine int `json:"end_line,omitempty"`
+
+	// CommentOffset is the byte offset where the comment starts in the file.
+	CommentOffset int `json:"comment_offset"`
+
+	// CommentEndOffset is the byte offset where the comment ends in the file.
+	CommentEndOffset int `json:"comment_end_offset,omitempty"`
+
 	// GitUser is the committer of the TODO.
 	GitUser *outUser `json:"git_user,omitempty"`
+
+	// Fingerprint is a stable identifier for the TODO that does not depend on
+	// its line number.
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// Owners are the owners of the file from CODEOWNERS.
+	Owners []string `json:"owners,omitempty"`
+
+	// Attributes are key=value attributes parsed from the label.
+	Attributes map[string]string `json:"attributes,omitempty"`
+
+	// InferredPriority is the priority inferred from keywords in the
+	// message.
+	InferredPriority string `json:"inferred_priority,omitempty"`
+
+	// Findings are the problems found in the TODO by checks.
+	Findings []*outFinding `json:"findings,omitempty"`
+
+	// MessageLanguage is the natural language of the message.
+	MessageLanguage string `json:"message_language,omitempty"`
+}
+
+// outFinding is a problem found in a TODO by a check.
+type outFinding struct {
+	// Category is the category of the finding, such as "secret".
+	Category string `json:"category"`
+
+	// Rule is the name of the rule that found the problem.
+	Rule string `json:"rule"`
+}
+
+// flusher is a writer that buffers output.
+type flusher interface {
+	Flush() error
 }
 
+// outJSON writes each TODO as a JSON object on its own line as soon as it is
+// found so that the output can be consumed as a stream of newline-delimited
+// JSON.
 func outJSON(w io.Writer) walker.TODOHandler {
 	return func(o *walker.TODORef) error {
 		if o == nil {
 			return nil
 		}
 
-		out := outTODO{
-			Path:        o.FileName,
-			Type:        o.TODO.Type,
-			Text:        o.TODO.Text,
-			Label:       o.TODO.Label,
-			Message:     o.TODO.Message,
-			Line:        o.TODO.Line,
-			CommentLine: o.TODO.CommentLine,
+		b := utils.Must(json.Marshal(newOutTODO(o)))
+
+		// NOTE: The line is written with a single call so that readers never
+		//       see a partial line for a TODO.
+		_ = utils.Must(w.Write(append(b, '\n')))
+		if f, ok := w.(flusher); ok {
+			utils.Check(f.Flush())
 		}
-		if o.GitUser != nil {
-			out.GitUser = &outUser{
-				Name:  o.GitUser.Name,
-				Email: o.GitUser.Email,
-			}
+
+		return nil
+	}
+}
+
+// newOutTODO returns the JSON output for the TODO.
+func newOutTODO(o *walker.TODORef) *outTODO {
+	out := &outTODO{
+		Path:        o.FileName,
+		Type:        o.TODO.Type,
+		Text:        o.TODO.Text,
+		Label:       o.TODO.Label,
+		Message:     o.TODO.Message,
+		Line:        o.TODO.Line,
+		CommentLine: o.TODO.CommentLine,
+		EndLine:     o.TODO.EndLine,
+
+		CommentOffset:    o.TODO.CommentOffset,
+		CommentEndOffset: o.TODO.CommentEndOffset,
+		Fingerprint:      o.Fingerprint,
+		Owners:           o.Owners,
+		Attributes:       o.TODO.Attributes,
+		CanonicalLabel:   o.TODO.CanonicalLabel,
+		ReferenceKind:    string(o.TODO.ReferenceKind),
+		InferredPriority: o.TODO.InferredPriority,
+		MessageLanguage:  o.MessageLanguage,
+	}
+	if o.GitUser != nil {
+		out.GitUser = &outUser{
+			Name:  o.GitUser.Name,
+			Email: o.GitUser.Email,
+		}
+	}
+	for _, f := range o.Findings {
+		out.Findings = append(out.Findings, &outFinding{
+			Category: string(f.Category),
+			Rule:     f.Rule,
+		})
+	}
+	return out
+}
+
+func outFileCLI(w io.Writer) walker.FileHandler {
+	return func(o *walker.FileRef) error {
+		if o == nil {
+			return nil
+		}
+		_ = utils.Must(fmt.Fprintf(w, "%s%s%s\n",
+			color.MagentaString(o.FileName),
+			color.CyanString(":"),
+			o.Language,
+		))
+		return nil
+	}
+}
+
+type outFile struct {
+	// Path is the path to the file.
+	Path string `json:"path"`
+
+	// Language is the detected language of the file.
+	Language string `json:"language"`
+
+	// Detection describes how the language was detected.
+	Detection *outDetection `json:"detection,omitempty"`
+}
+
+// outDetection describes how the language of a file was detected.
+type outDetection struct {
+	// Method is the strategy that detected the language.
+	Method string `json:"method"`
+
+	// Candidates are the languages that the file could have been detected
+	// as.
+	Candidates []string `json:"candidates,omitempty"`
+
+	// Confident indicates that the language was not guessed from several
+	// candidates.
+	Confident bool `json:"confident"`
+}
+
+// newOutDetection returns the output for the language detection. It returns
+// nil if the detection is nil.
+func newOutDetection(d *scanner.LanguageDetection) *outDetection {
+	if d == nil {
+		return nil
+	}
+	return &outDetection{
+		Method:     d.Method,
+		Candidates: d.Candidates,
+		Confident:  d.Confident,
+	}
+}
+
+func outFileJSON(w io.Writer) walker.FileHandler {
+	return func(o *walker.FileRef) error {
+		if o == nil {
+			return nil
 		}
 
-		b := utils.Must(json.Marshal(out))
+		b := utils.Must(json.Marshal(outFile{
+			Path:      o.FileName,
+			Language:  o.Language,
+			Detection: newOutDetection(o.LanguageDetection),
+		}))
 
 		_ = utils.Must(w.Write(b))
 		_ = utils.Must(w.Write([]byte("\n")))
@@ -320,12 +955,241 @@ func outJSON(w io.Writer) walker.TODOHandler {
 
 var outTypes = map[string]func(io.Writer) walker.TODOHandler{
 	// NOTE: An empty value is treated as the default value.
-	"":        outCLI,
-	"default": outCLI,
-	"github":  outGithub,
-	"json":    outJSON,
+	"":          outCLI,
+	"default":   outCLI,
+	"github":    outGithub,
+	"azure":     outAzure,
+	"bitbucket": outBitbucket,
+	"json":      outJSON,
+	"ndjson":    outJSON,
+}
+
+// fileOutTypes are the output types used when listing files.
+var fileOutTypes = map[string]func(io.Writer) walker.FileHandler{
+	"":          outFileCLI,
+	"default":   outFileCLI,
+	"github":    outFileCLI,
+	"azure":     outFileCLI,
+	"bitbucket": outFileJSON,
+	"json":      outFileJSON,
+	"ndjson":    outFileJSON,
+}
+
+var errInvalidShard = errors.New("invalid shard")
+
+// parseShard parses a shard of the form I/N and returns the 0-based shard
+// index and the number of shards.
+func parseShard(shard string) (int, int, error) {
+	iStr, nStr, ok := strings.Cut(shard, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: %q: must be of the form I/N", errInvalidShard, shard)
+	}
+	i, err := strconv.Atoi(iStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %q: %w", errInvalidShard, shard, err)
+	}
+	n, err := strconv.Atoi(nStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %q: %w", errInvalidShard, shard, err)
+	}
+	if n < 1 || i < 1 || i > n {
+		return 0, 0, fmt.Errorf("%w: %q: I must be between 1 and N", errInvalidShard, shard)
+	}
+	return i - 1, n, nil
+}
+
+var errInvalidSize = errors.New("invalid size")
+
+// sizeUnits are the suffixes accepted by parseSize.
+var sizeUnits = map[string]int64{
+	"":  1,
+	"K": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+}
+
+// parseSize parses a size in bytes with an optional K, M, or G suffix.
+func parseSize(size string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(size))
+	unit := strings.TrimLeft(s, "0123456789")
+	m, ok := sizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q: unknown unit %q", errInvalidSize, size, unit)
+	}
+	n, err := strconv.ParseInt(strings.TrimSuffix(s, unit), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q: %w", errInvalidSize, size, err)
+	}
+	return n * m, nil
+}
+
+var errInvalidLines = errors.New("invalid line range")
+
+// parseLines parses a line range of the form START:END. Either START or END
+// can be omitted to include the lines from the start or to the end of the
+// file. A single line number is also accepted.
+func parseLines(lines string) (*walker.LineRange, error) {
+	startStr, endStr, ok := strings.Cut(lines, ":")
+	if !ok {
+		endStr = startStr
+	}
+
+	r := &walker.LineRange{
+		Start: 1,
+	}
+	if startStr != "" {
+		n, err := strconv.Atoi(startStr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", errInvalidLines, lines, err)
+		}
+		r.Start = n
+	}
+	if endStr != "" {
+		n, err := strconv.Atoi(endStr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", errInvalidLines, lines, err)
+		}
+		r.End = n
+	}
+	if r.Start < 1 || r.End < 0 || (r.End != 0 && r.End < r.Start) {
+		return nil, fmt.Errorf("%w: %q: must be of the form START:END", errInvalidLines, lines)
+	}
+	return r, nil
+}
+
+var errInvalidExtensionLanguage = errors.New("invalid extension language")
+
+// parseExtensionLanguage parses an extension to language mapping of the form
+// EXT=LANGUAGE. The extension is returned in lower case with a leading dot.
+func parseExtensionLanguage(m string) (string, string, error) {
+	ext, lang, ok := strings.Cut(m, "=")
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	lang = strings.TrimSpace(lang)
+	if !ok || ext == "" || lang == "" {
+		return "", "", fmt.Errorf("%w: %q: must be of the form EXT=LANGUAGE", errInvalidExtensionLanguage, m)
+	}
+	if _, ok := scanner.LanguagesConfig[lang]; !ok {
+		return "", "", fmt.Errorf("%w: %q: unsupported language %q", errInvalidExtensionLanguage, m, lang)
+	}
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext, lang, nil
+}
+
+var errInvalidSecretPattern = errors.New("invalid secret pattern")
+
+// parseSecretPattern parses a secret rule of the form NAME=REGEX.
+func parseSecretPattern(p string) (*walker.SecretRule, error) {
+	name, expr, ok := strings.Cut(p, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || expr == "" {
+		return nil, fmt.Errorf("%w: %q: must be of the form NAME=REGEX", errInvalidSecretPattern, p)
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %w", errInvalidSecretPattern, p, err)
+	}
+	return &walker.SecretRule{Name: name, Pattern: re}, nil
+}
+
+var errInvalidPriorityKeyword = errors.New("invalid priority keyword")
+
+// parsePriorityKeyword parses a keyword to priority mapping of the form
+// KEYWORD=PRIORITY.
+func parsePriorityKeyword(m string) (todos.PriorityKeyword, error) {
+	keyword, priority, ok := strings.Cut(m, "=")
+	keyword = strings.TrimSpace(keyword)
+	priority = strings.TrimSpace(priority)
+	if !ok || keyword == "" || priority == "" {
+		return todos.PriorityKeyword{}, fmt.Errorf("%w: %q: must be of the form KEYWORD=PRIORITY", errInvalidPriorityKeyword, m)
+	}
+	return todos.PriorityKeyword{Keyword: keyword, Priority: priority}, nil
+}
+
+var errInvalidGlobCase = errors.New("invalid mode")
+
+// globCaseInsensitive returns whether globs are matched regardless of case for
+// the glob-case mode on the operating system goos. The auto mode matches
+// globs regardless of case on Windows and macOS, whose file systems are
+// case-insensitive by default.
+func globCaseInsensitive(mode, goos string) (bool, error) {
+	switch mode {
+	case "sensitive":
+		return false, nil
+	case "insensitive":
+		return true, nil
+	case "auto":
+		return goos == "windows" || goos == "darwin", nil
+	default:
+		return false, fmt.Errorf("%w: %q", errInvalidGlobCase, mode)
+	}
+}
+
+var errInvalidDate = errors.New("invalid date")
+
+// parseDate parses a date of the form YYYY-MM-DD or an RFC 3339 timestamp.
+func parseDate(date string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, date); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q: must be YYYY-MM-DD or RFC 3339", errInvalidDate, date)
+	}
+	return t, nil
+}
+
+// todosOptionsFromContext returns the walker options for the root command
+// including the output handlers. The sink is nil unless the output type is a
+// sink.
+func todosOptionsFromContext(c *cli.Context) (*walker.Options, sink, error) {
+	o, err := walkerOptionsFromContext(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	outType := c.String("output")
+	sk, isSink, err := sinkFromOutput(outType)
+	if err != nil {
+		return nil, nil, err
+	}
+	var h walker.TODOHandler
+	if isSink {
+		if c.Bool("list-files") {
+			return nil, nil, fmt.Errorf("%w: list-files cannot be used with output %v", ErrFlagParse, outType)
+		}
+		h = sk.Handle
+	} else {
+		outFunc, ok := outTypes[outType]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: invalid output type: %v", ErrFlagParse, outType)
+		}
+		h = outFunc(c.App.Writer)
+	}
+
+	o.TODOFunc, err = redactFromContext(c, h)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.String("explain") != "" && o.Ref != "" {
+		return nil, nil, fmt.Errorf("%w: explain cannot be used with ref", ErrFlagParse)
+	}
+
+	if c.Bool("list-files") {
+		o.ListFiles = true
+		o.FileFunc = fileOutTypes[outType](c.App.Writer)
+	}
+
+	if err := stateFromContext(c, o); err != nil {
+		return nil, nil, err
+	}
+
+	return o, sk, nil
 }
 
+// walkerOptionsFromContext returns the walker options for the flags returned
+// by walkerFlags.
 func walkerOptionsFromContext(c *cli.Context) (*walker.Options, error) {
 	o := walker.Options{}
 
@@ -350,7 +1214,7 @@ func walkerOptionsFromContext(c *cli.Context) (*walker.Options, error) {
 	o.Charset = charset
 
 	for _, gs := range c.StringSlice("exclude") {
-		g, err := glob.Compile(gs)
+		g, err := walker.CompileGlob(gs)
 		if err != nil {
 			return nil, fmt.Errorf("%w: exclude: %w", ErrFlagParse, err)
 		}
@@ -358,20 +1222,128 @@ func walkerOptionsFromContext(c *cli.Context) (*walker.Options, error) {
 	}
 
 	for _, gs := range c.StringSlice("exclude-dir") {
-		g, err := glob.Compile(strings.TrimRight(gs, string(os.PathSeparator)))
+		g, err := walker.CompileDirGlob(gs)
 		if err != nil {
 			return nil, fmt.Errorf("%w: exclude-dir: %w", ErrFlagParse, err)
 		}
 		o.ExcludeDirGlobs = append(o.ExcludeDirGlobs, g)
 	}
 
+	for _, m := range c.StringSlice("extension-language") {
+		ext, lang, err := parseExtensionLanguage(m)
+		if err != nil {
+			return nil, fmt.Errorf("%w: extension-language: %w", ErrFlagParse, err)
+		}
+		if o.ExtensionLanguages == nil {
+			o.ExtensionLanguages = map[string]string{}
+		}
+		o.ExtensionLanguages[ext] = lang
+	}
+
+	if lang := c.String("fallback-lang"); lang != "" {
+		if _, ok := scanner.LanguagesConfig[lang]; !ok && lang != "Text" {
+			return nil, fmt.Errorf("%w: fallback-lang: unsupported language %q", ErrFlagParse, lang)
+		}
+		o.FallbackLanguage = lang
+	}
+
+	o.Audit = c.Bool("audit")
 	o.Blame = c.Bool("blame")
+	o.Dedup = c.Bool("dedup")
+	o.Ref = c.String("ref")
+	o.Owners = c.Bool("owners")
+
+	cacheDir := c.String("cache-dir")
+	cacheURL := c.String("cache-url")
+	switch {
+	case cacheDir != "" && cacheURL != "":
+		return nil, fmt.Errorf("%w: cache-dir and cache-url cannot be used together", ErrFlagParse)
+	case cacheDir != "":
+		d, err := cache.NewDisk(cacheDir)
+		if err != nil {
+			return nil, fmt.Errorf("%w: cache-dir: %w", ErrFlagParse, err)
+		}
+		o.Cache = d
+	case cacheURL != "":
+		h, err := cache.NewHTTP(cacheURL, &http.Client{
+			Timeout: cacheTimeout,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: cache-url: %w", ErrFlagParse, err)
+		}
+		o.Cache = h
+	}
 
 	// File Includes
+	o.IncludeDocs = c.Bool("include-docs")
 	o.IncludeGenerated = c.Bool("include-generated")
 	o.IncludeHidden = !c.Bool("exclude-hidden")
 	o.IncludeVCS = c.Bool("include-vcs")
 	o.IncludeVendored = c.Bool("include-vendored")
+	o.SkipTests = c.Bool("skip-tests")
+
+	switch mode := walker.SymlinkMode(c.String("follow-symlinks")); mode {
+	case "all":
+		o.FollowSymlinks = walker.SymlinksAll
+	case walker.SymlinksInTree, walker.SymlinksNone:
+		o.FollowSymlinks = mode
+	default:
+		return nil, fmt.Errorf("%w: follow-symlinks: invalid mode %q", ErrFlagParse, mode)
+	}
+	o.Confine = c.Bool("confine")
+	o.Extract = c.Bool("extract")
+
+	caseInsensitive, err := globCaseInsensitive(c.String("glob-case"), runtime.GOOS)
+	if err != nil {
+		return nil, fmt.Errorf("%w: glob-case: %w", ErrFlagParse, err)
+	}
+	o.CaseInsensitiveGlobs = caseInsensitive
+
+	o.SkipShebang = c.Bool("skip-shebang")
+	o.SkipStrings = c.Bool("fast")
+	o.Patches = c.Bool("patch")
+
+	// Metadata filters
+	if size := c.String("min-size"); size != "" {
+		n, err := parseSize(size)
+		if err != nil {
+			return nil, fmt.Errorf("%w: min-size: %w", ErrFlagParse, err)
+		}
+		o.MinSize = n
+	}
+	if size := c.String("max-line-length"); size != "" {
+		n, err := parseSize(size)
+		if err != nil {
+			return nil, fmt.Errorf("%w: max-line-length: %w", ErrFlagParse, err)
+		}
+		o.MaxLineLength = int(n)
+	}
+	if size := c.String("max-size"); size != "" {
+		n, err := parseSize(size)
+		if err != nil {
+			return nil, fmt.Errorf("%w: max-size: %w", ErrFlagParse, err)
+		}
+		o.MaxSize = n
+	}
+	if since := c.String("modified-since"); since != "" {
+		if o.Ref != "" {
+			return nil, fmt.Errorf("%w: modified-since cannot be used with ref", ErrFlagParse)
+		}
+		t, err := parseDate(since)
+		if err != nil {
+			return nil, fmt.Errorf("%w: modified-since: %w", ErrFlagParse, err)
+		}
+		o.ModifiedSince = t
+	}
+
+	if shard := c.String("shard"); shard != "" {
+		i, n, err := parseShard(shard)
+		if err != nil {
+			return nil, fmt.Errorf("%w: shard: %w", ErrFlagParse, err)
+		}
+		o.ShardIndex = i
+		o.ShardCount = n
+	}
 
 	// Filters
 	for _, label := range c.StringSlice("label") {
@@ -382,19 +1354,114 @@ func walkerOptionsFromContext(c *cli.Context) (*walker.Options, error) {
 		o.LabelGlobs = append(o.LabelGlobs, g)
 	}
 
-	outType := c.String("output")
-	outFunc, ok := outTypes[outType]
-	if !ok {
-		return nil, fmt.Errorf("%w: invalid output type: %v", ErrFlagParse, outType)
+	for _, label := range c.StringSlice("exclude-label") {
+		g, err := glob.Compile(label)
+		if err != nil {
+			return nil, fmt.Errorf("%w: exclude-label: %w", ErrFlagParse, err)
+		}
+		o.ExcludeLabelGlobs = append(o.ExcludeLabelGlobs, g)
+	}
+
+	for _, a := range c.StringSlice("assignee") {
+		o.Assignees = append(o.Assignees, strings.TrimPrefix(strings.TrimSpace(a), "@"))
 	}
 
-	o.TODOFunc = outFunc(c.App.Writer)
+	for _, todoType := range c.StringSlice("exclude-type") {
+		o.ExcludeTypes = append(o.ExcludeTypes, strings.TrimSpace(todoType))
+	}
+
+	for _, attr := range c.StringSlice("attr") {
+		key, value, ok := strings.Cut(attr, "=")
+		f := &walker.AttrFilter{
+			Key: strings.TrimSpace(key),
+		}
+		if ok {
+			g, err := glob.Compile(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: attr: %w", ErrFlagParse, err)
+			}
+			f.Value = g
+		}
+		o.AttrFilters = append(o.AttrFilters, f)
+	}
+
+	for _, expr := range c.StringSlice("grep") {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: grep: %w", ErrFlagParse, err)
+		}
+		o.GrepRegexps = append(o.GrepRegexps, re)
+	}
+
+	o.DetectMessageLanguage = c.Bool("detect-message-language")
+	for _, lang := range c.StringSlice("message-language") {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if lang != natlang.Undetermined && !slices.Contains(natlang.Languages(), lang) {
+			return nil, fmt.Errorf("%w: message-language: unsupported language %q", ErrFlagParse, lang)
+		}
+		o.MessageLanguages = append(o.MessageLanguages, lang)
+	}
+
+	var secretRules []*walker.SecretRule
+	for _, p := range c.StringSlice("secret-pattern") {
+		r, err := parseSecretPattern(p)
+		if err != nil {
+			return nil, fmt.Errorf("%w: secret-pattern: %w", ErrFlagParse, err)
+		}
+		secretRules = append(secretRules, r)
+	}
+	if c.Bool("check-secrets") || len(secretRules) > 0 {
+		o.SecretRules = append(append([]*walker.SecretRule{}, walker.DefaultSecretRules...), secretRules...)
+	}
+
+	cat := messageCatalog(c)
 	o.ErrorFunc = func(err error) error {
-		_ = utils.Must(fmt.Fprintf(c.App.ErrWriter, "%s: %v\n", c.App.Name, err))
+		_ = utils.Must(fmt.Fprintf(c.App.ErrWriter, "%s: %s\n", c.App.Name, cat.errorText(err)))
+		return nil
+	}
+	o.WarningFunc = func(err error) error {
+		_ = utils.Must(fmt.Fprintf(c.App.ErrWriter, "%s: "+cat.text(msgWarning)+"\n", c.App.Name, err))
 		return nil
 	}
 
-	o.Config = &todos.Config{}
+	if c.Bool("verbose") {
+		o.SkipFunc = func(r *walker.SkipReason) error {
+			_ = utils.Must(fmt.Fprintf(c.App.ErrWriter, "%s: "+cat.text(msgSkipped)+"\n", c.App.Name, r))
+			return nil
+		}
+		o.NoteFunc = func(fileName, note string) error {
+			_ = utils.Must(fmt.Fprintf(c.App.ErrWriter, "%s: %s: %s\n", c.App.Name, fileName, note))
+			return nil
+		}
+		o.ConfigFunc = func(path string) error {
+			_ = utils.Must(fmt.Fprintf(c.App.ErrWriter, "%s: "+cat.text(msgLoadedConfig)+"\n", c.App.Name, path))
+			return nil
+		}
+	}
+	o.StrictConfig = c.Bool("strict-config")
+
+	o.Config = &todos.Config{
+		NearMisses:       c.Bool("warn-near-misses"),
+		RequireDelimiter: c.Bool("strict-match"),
+		MatchAnywhere:    c.Bool("match-anywhere"),
+		Repo:             c.String("repo"),
+	}
+	if repo := o.Config.Repo; repo != "" {
+		owner, name, _ := strings.Cut(repo, "/")
+		if owner == "" || name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("%w: repo: %q is not OWNER/REPO", ErrFlagParse, repo)
+		}
+	}
+	for _, m := range c.StringSlice("priority-keyword") {
+		k, err := parsePriorityKeyword(m)
+		if err != nil {
+			return nil, fmt.Errorf("%w: priority-keyword: %w", ErrFlagParse, err)
+		}
+		o.Config.PriorityKeywords = append(o.Config.PriorityKeywords, k)
+	}
+	if c.Bool("infer-priority") && len(o.Config.PriorityKeywords) == 0 {
+		o.Config.PriorityKeywords = todos.DefaultPriorityKeywords
+	}
 
 	todoTypesStr := c.String("todo-types")
 	if todoTypesStr != "" {
@@ -404,9 +1471,32 @@ func walkerOptionsFromContext(c *cli.Context) (*walker.Options, error) {
 	}
 
 	o.Paths = c.Args().Slice()
+	if o.Ref == "" {
+		paths, err := expandPaths(o.Paths)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFlagParse, err)
+		}
+		o.Paths = paths
+	}
 	if len(o.Paths) == 0 {
 		o.Paths = []string{"."}
 	}
 
+	if lines := c.String("lines"); lines != "" {
+		if len(o.Paths) != 1 {
+			return nil, fmt.Errorf("%w: lines can only be used with a single file", ErrFlagParse)
+		}
+		if o.Ref == "" {
+			if info, err := os.Stat(o.Paths[0]); err == nil && info.IsDir() {
+				return nil, fmt.Errorf("%w: lines can only be used with a single file", ErrFlagParse)
+			}
+		}
+		r, err := parseLines(lines)
+		if err != nil {
+			return nil, fmt.Errorf("%w: lines: %w", ErrFlagParse, err)
+		}
+		o.Lines = r
+	}
+
 	return &o, nil
 }
@@ -19,14 +19,18 @@ import (
 	"encoding/json"
 	"flag"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gobwas/glob"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/urfave/cli/v2"
 
+	"github.com/ianlewis/todos/internal/scanner"
 	"github.com/ianlewis/todos/internal/testutils"
 	"github.com/ianlewis/todos/internal/todos"
 	"github.com/ianlewis/todos/internal/walker"
@@ -45,6 +49,10 @@ func newContext(app *cli.App, args []string) *cli.Context {
 	return cli.NewContext(app, fs, nil)
 }
 
+func mustCompileGlob(pattern string) glob.Glob {
+	return testutils.Must(walker.CompileGlob(pattern))
+}
+
 func Test_TODOsApp_help(t *testing.T) {
 	t.Parallel()
 
@@ -129,6 +137,163 @@ func Test_TODOsApp_Walk(t *testing.T) {
 	}
 }
 
+func Test_TODOsApp_verbose(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "foo.go",
+			Contents: []byte("// TODO: foo"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "vendor/bar.go",
+			Contents: []byte("// TODO: bar"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	app := newTODOsApp()
+	var b, errb strings.Builder
+	app.Writer = &b
+	app.ErrWriter = &errb
+	c := newContext(app, []string{"--verbose", d.Dir()})
+	if err := app.Action(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `skipped vendored pattern "(^|/)vendors?/" matched "vendor"` + "\n"
+	if !strings.HasSuffix(errb.String(), want) {
+		t.Errorf("unexpected verbose output, got: %q, want suffix: %q", errb.String(), want)
+	}
+}
+
+func Test_TODOsApp_strictConfig(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "a/.todos.yml",
+			Contents: []byte("exclude: [\"*_gen.go\"]\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "a/foo.go",
+			Contents: []byte("// TODO: foo"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "b/.todos.yml",
+			Contents: []byte("exclude: [\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "b/bar_gen.go",
+			Contents: []byte("// TODO: bar"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	t.Cleanup(d.Cleanup)
+
+	testCases := map[string]struct {
+		args   []string
+		err    error
+		output bool
+	}{
+		"not strict": {
+			args:   []string{"--verbose"},
+			err:    ErrWalk,
+			output: true,
+		},
+		"strict": {
+			args: []string{"--verbose", "--strict-config"},
+			err:  ErrWalk,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			app := newTODOsApp()
+			app.ExitErrHandler = nil
+			var b, errb strings.Builder
+			app.Writer = &b
+			app.ErrWriter = &errb
+			err := app.Run(append(append([]string{"todos"}, tc.args...), filepath.Join(d.Dir(), "a"), filepath.Join(d.Dir(), "b")))
+			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("unexpected error (-want, +got): \n%s", diff)
+			}
+
+			want := "loaded configuration " + filepath.Join(d.Dir(), "a", ".todos.yml") + "\n"
+			if !strings.Contains(errb.String(), want) {
+				t.Errorf("unexpected verbose output, got: %q, want: %q", errb.String(), want)
+			}
+			if got, want := strings.Contains(b.String(), "bar"), tc.output; got != want {
+				t.Errorf("unexpected output: %q", b.String())
+			}
+		})
+	}
+}
+
+func Test_TODOsApp_explain(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "foo.go",
+			Contents: []byte("// TODO: foo"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "vendor/bar.go",
+			Contents: []byte("// TODO: bar"),
+			Mode:     0o600,
+		},
+	}
+
+	testCases := map[string]struct {
+		path     string
+		expected string
+	}{
+		"not skipped": {
+			path:     "foo.go",
+			expected: ": not skipped\n",
+		},
+		"vendored": {
+			path:     filepath.Join("vendor", "bar.go"),
+			expected: ": skipped: vendored pattern \"(^|/)vendors?/\" matched \"vendor\"\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			d := testutils.NewTempDir(files)
+			defer d.Cleanup()
+
+			app := newTODOsApp()
+			var b strings.Builder
+			app.Writer = &b
+			path := filepath.Join(d.Dir(), tc.path)
+			c := newContext(app, []string{"--explain", path, d.Dir()})
+			if err := app.Action(c); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(path+tc.expected, b.String()); diff != "" {
+				t.Errorf("unexpected output (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
 //nolint:paralleltest // modifies cli.OsExiter
 func Test_TODOsApp_ExitErrHandler_ErrWalk(t *testing.T) {
 	oldExiter := cli.OsExiter
@@ -254,6 +419,18 @@ func Test_outGithub(t *testing.T) {
 			},
 			expected: "::error file=foo.go,line=16::// FIXME: this is a message\n",
 		},
+		"region": {
+			ref: &walker.TODORef{
+				FileName: "foo.go",
+				TODO: &todos.TODO{
+					Type:    "TODO",
+					Line:    16,
+					EndLine: 20,
+					Text:    "// TODO-BEGIN(#123): disabled",
+				},
+			},
+			expected: "::warning file=foo.go,line=16,endLine=20::// TODO-BEGIN(#123): disabled\n",
+		},
 	}
 
 	for name, tc := range testCases {
@@ -273,6 +450,124 @@ func Test_outGithub(t *testing.T) {
 	}
 }
 
+func Test_outAzure(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ref      *walker.TODORef
+		expected string
+	}{
+		"nil": {
+			ref:      nil,
+			expected: "",
+		},
+		"NOTE warning": {
+			ref: &walker.TODORef{
+				FileName: "foo.go",
+				TODO: &todos.TODO{
+					Type: "NOTE",
+					Line: 16,
+					Text: "// NOTE: this is a message",
+				},
+			},
+			expected: "##vso[task.logissue type=warning;sourcepath=foo.go;linenumber=16;]// NOTE: this is a message\n",
+		},
+		"FIXME error": {
+			ref: &walker.TODORef{
+				FileName: "foo.go",
+				TODO: &todos.TODO{
+					Type: "FIXME",
+					Line: 16,
+					Text: "// FIXME: this is a message",
+				},
+			},
+			expected: "##vso[task.logissue type=error;sourcepath=foo.go;linenumber=16;]// FIXME: this is a message\n",
+		},
+		"escaped": {
+			ref: &walker.TODORef{
+				FileName: "a;b].go",
+				TODO: &todos.TODO{
+					Type: "TODO",
+					Line: 1,
+					Text: "/* TODO: 100%;\nmore */",
+				},
+			},
+			expected: "##vso[task.logissue type=warning;sourcepath=a%3Bb%5D.go;linenumber=1;]/* TODO: 100%AZP25;%0Amore */\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var w strings.Builder
+			h := outAzure(&w)
+			err := h(tc.ref)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, w.String()); diff != "" {
+				t.Errorf("unexpected output (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_outBitbucket(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ref      *walker.TODORef
+		expected string
+	}{
+		"nil": {
+			ref:      nil,
+			expected: "",
+		},
+		"TODO": {
+			ref: &walker.TODORef{
+				FileName:    "./dir/foo.go",
+				Fingerprint: "abc123",
+				TODO: &todos.TODO{
+					Type: "TODO",
+					Line: 16,
+					Text: "// TODO: this is a message",
+				},
+			},
+			expected: `{"external_id":"abc123","annotation_type":"CODE_SMELL","path":"dir/foo.go","line":16,"summary":"// TODO: this is a message","severity":"MEDIUM"}` + "\n",
+		},
+		"long summary": {
+			ref: &walker.TODORef{
+				FileName:    "foo.go",
+				Fingerprint: "abc123",
+				TODO: &todos.TODO{
+					Type: "BUG",
+					Line: 1,
+					Text: "// BUG: " + strings.Repeat("x", 300),
+				},
+			},
+			expected: `{"external_id":"abc123","annotation_type":"CODE_SMELL","path":"foo.go","line":1,"summary":"// BUG: ` +
+				strings.Repeat("x", maxBitbucketSummary-len("// BUG: ")) + `","severity":"HIGH"}` + "\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var w strings.Builder
+			h := outBitbucket(&w)
+			err := h(tc.ref)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, w.String()); diff != "" {
+				t.Errorf("unexpected output (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
 func Test_outJSON(t *testing.T) {
 	t.Parallel()
 
@@ -316,6 +611,84 @@ func Test_outJSON(t *testing.T) {
 				Text: "// TODO: this is a message",
 			},
 		},
+		"offsets": {
+			ref: &walker.TODORef{
+				FileName: "foo.go",
+				TODO: &todos.TODO{
+					Type:             "TODO",
+					Line:             16,
+					Text:             "// TODO: this is a message",
+					CommentOffset:    120,
+					CommentEndOffset: 146,
+				},
+			},
+			expected: &outTODO{
+				Path:             "foo.go",
+				Type:             "TODO",
+				Line:             16,
+				Text:             "// TODO: this is a message",
+				CommentOffset:    120,
+				CommentEndOffset: 146,
+			},
+		},
+		"reference": {
+			ref: &walker.TODORef{
+				FileName: "foo.go",
+				TODO: &todos.TODO{
+					Type:           "TODO",
+					Line:           16,
+					Text:           "// TODO(github.com/o/r/pull/55): this is a message",
+					Label:          "github.com/o/r/pull/55",
+					CanonicalLabel: "o/r#55",
+					ReferenceKind:  todos.ReferencePullRequest,
+				},
+			},
+			expected: &outTODO{
+				Path:           "foo.go",
+				Type:           "TODO",
+				Line:           16,
+				Text:           "// TODO(github.com/o/r/pull/55): this is a message",
+				Label:          "github.com/o/r/pull/55",
+				CanonicalLabel: "o/r#55",
+				ReferenceKind:  "pull_request",
+			},
+		},
+		"fingerprint": {
+			ref: &walker.TODORef{
+				FileName: "foo.go",
+				TODO: &todos.TODO{
+					Type: "TODO",
+					Line: 16,
+					Text: "// TODO: this is a message",
+				},
+				Fingerprint: "abc123",
+			},
+			expected: &outTODO{
+				Path:        "foo.go",
+				Type:        "TODO",
+				Line:        16,
+				Text:        "// TODO: this is a message",
+				Fingerprint: "abc123",
+			},
+		},
+		"owners": {
+			ref: &walker.TODORef{
+				FileName: "foo.go",
+				TODO: &todos.TODO{
+					Type: "TODO",
+					Line: 16,
+					Text: "// TODO: this is a message",
+				},
+				Owners: []string{"@foo", "@bar"},
+			},
+			expected: &outTODO{
+				Path:   "foo.go",
+				Type:   "TODO",
+				Line:   16,
+				Text:   "// TODO: this is a message",
+				Owners: []string{"@foo", "@bar"},
+			},
+		},
 		"FIXME error": {
 			ref: &walker.TODORef{
 				FileName: "foo.go",
@@ -362,14 +735,222 @@ func Test_outJSON(t *testing.T) {
 	}
 }
 
-func Test_walkerOptionsFromContext(t *testing.T) {
+// flushWriter is a writer that records the output written before each flush.
+type flushWriter struct {
+	bytes.Buffer
+
+	flushed []string
+}
+
+func (w *flushWriter) Flush() error {
+	w.flushed = append(w.flushed, w.String())
+	return nil
+}
+
+func Test_outJSON_flush(t *testing.T) {
 	t.Parallel()
 
-	testCases := map[string]struct {
-		args []string
+	var w flushWriter
+	h := outJSON(&w)
+	for _, msg := range []string{"foo", "bar"} {
+		if err := h(&walker.TODORef{
+			FileName: "foo.go",
+			TODO:     &todos.TODO{Type: "TODO", Text: "// TODO: " + msg, Message: msg, Line: 1},
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
 
-		expected *walker.Options
-		err      error
+	lines := strings.SplitAfter(w.String(), "\n")
+	want := []string{lines[0], lines[0] + lines[1]}
+	if diff := cmp.Diff(want, w.flushed); diff != "" {
+		t.Errorf("unexpected flushes (-want, +got): \n%s", diff)
+	}
+}
+
+func Test_TODOsApp_ndjson(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "bar.go",
+			Contents: []byte("// TODO: bar"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "foo.go",
+			Contents: []byte("// TODO: foo\n// TODO: foo2"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	app := newTODOsApp()
+	var b strings.Builder
+	app.Writer = &b
+	if err := app.Run([]string{"todos", "--output=ndjson", d.Dir()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	dec := json.NewDecoder(strings.NewReader(b.String()))
+	for dec.More() {
+		var o outTODO
+		if err := dec.Decode(&o); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, o.Message)
+	}
+	if diff := cmp.Diff([]string{"bar", "foo", "foo2"}, got); diff != "" {
+		t.Errorf("unexpected TODOs (-want, +got): \n%s", diff)
+	}
+	if got, want := strings.Count(b.String(), "\n"), 3; got != want {
+		t.Errorf("unexpected number of lines, got: %d, want: %d", got, want)
+	}
+}
+
+func Test_TODOsApp_checkSecrets(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "foo.go",
+			Contents: []byte("// TODO: remove password=hunter2\n\n// TODO: bar\n"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	app := newTODOsApp()
+	var b, e strings.Builder
+	app.Writer = &b
+	app.ErrWriter = &e
+	if err := app.Run([]string{"todos", "--check-secrets", "--output=json", d.Dir()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got [][]*outFinding
+	dec := json.NewDecoder(strings.NewReader(b.String()))
+	for dec.More() {
+		var o outTODO
+		if err := dec.Decode(&o); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, o.Findings)
+	}
+	want := [][]*outFinding{
+		{{Category: "secret", Rule: "password-assignment"}},
+		nil,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected findings (-want, +got): \n%s", diff)
+	}
+
+	if !strings.Contains(e.String(), "warning:") || !strings.Contains(e.String(), "password-assignment") {
+		t.Errorf("expected warning, got: %q", e.String())
+	}
+	if strings.Contains(e.String(), "hunter2") {
+		t.Errorf("secret copied to warning: %q", e.String())
+	}
+}
+
+func Test_outFileCLI(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ref      *walker.FileRef
+		expected string
+	}{
+		"nil": {
+			ref:      nil,
+			expected: "",
+		},
+		"file": {
+			ref: &walker.FileRef{
+				FileName: "foo.go",
+				Language: "Go",
+			},
+			expected: "foo.go:Go\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var w strings.Builder
+			h := outFileCLI(&w)
+			err := h(tc.ref)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, w.String()); diff != "" {
+				t.Errorf("unexpected output (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_outFileJSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ref      *walker.FileRef
+		expected string
+	}{
+		"nil": {
+			ref:      nil,
+			expected: "",
+		},
+		"file": {
+			ref: &walker.FileRef{
+				FileName: "foo.go",
+				Language: "Go",
+			},
+			expected: `{"path":"foo.go","language":"Go"}` + "\n",
+		},
+		"detection": {
+			ref: &walker.FileRef{
+				FileName: "foo.pl",
+				Language: "Perl",
+				LanguageDetection: &scanner.LanguageDetection{
+					Method:     "classifier",
+					Candidates: []string{"Perl", "Prolog"},
+				},
+			},
+			expected: `{"path":"foo.pl","language":"Perl","detection":{"method":"classifier","candidates":["Perl","Prolog"],"confident":false}}` + "\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var w strings.Builder
+			h := outFileJSON(&w)
+			err := h(tc.ref)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, w.String()); diff != "" {
+				t.Errorf("unexpected output (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_todosOptionsFromContext(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		args []string
+
+		expected *walker.Options
+		err      error
 	}{
 		"no args": {
 			args: nil,
@@ -378,6 +959,7 @@ func Test_walkerOptionsFromContext(t *testing.T) {
 					Types: todos.DefaultTypes,
 				},
 				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
 				IncludeHidden: true,
 				Paths:         []string{"."},
 			},
@@ -390,6 +972,7 @@ func Test_walkerOptionsFromContext(t *testing.T) {
 					Types: todos.DefaultTypes,
 				},
 				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
 				IncludeHidden: true,
 				Paths:         []string{"."},
 			},
@@ -405,6 +988,7 @@ func Test_walkerOptionsFromContext(t *testing.T) {
 					Types: []string{"TODO", "FIXME"},
 				},
 				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
 				IncludeHidden: true,
 				Paths:         []string{"."},
 			},
@@ -416,6 +1000,7 @@ func Test_walkerOptionsFromContext(t *testing.T) {
 					Types: todos.DefaultTypes,
 				},
 				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
 				IncludeHidden: false,
 				Paths:         []string{"."},
 			},
@@ -427,6 +1012,7 @@ func Test_walkerOptionsFromContext(t *testing.T) {
 					Types: todos.DefaultTypes,
 				},
 				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
 				IncludeHidden: true,
 				IncludeVCS:    true,
 				Paths:         []string{"."},
@@ -439,11 +1025,166 @@ func Test_walkerOptionsFromContext(t *testing.T) {
 					Types: todos.DefaultTypes,
 				},
 				Charset:         defaultCharset,
+				MaxLineLength:   defaultMaxLineLength,
 				IncludeHidden:   true,
 				IncludeVendored: true,
 				Paths:           []string{"."},
 			},
 		},
+		"dedup": {
+			args: []string{"--dedup"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				Dedup:         true,
+				IncludeHidden: true,
+				Paths:         []string{"."},
+			},
+		},
+		"audit": {
+			args: []string{"--audit"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				Audit:         true,
+				IncludeHidden: true,
+				Paths:         []string{"."},
+			},
+		},
+		"skip shebang": {
+			args: []string{"--skip-shebang"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				SkipShebang:   true,
+				Paths:         []string{"."},
+			},
+		},
+		"strict-match": {
+			args: []string{"--strict-match"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types:            todos.DefaultTypes,
+					RequireDelimiter: true,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				Paths:         []string{"."},
+			},
+		},
+		"match-anywhere": {
+			args: []string{"--match-anywhere"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types:         todos.DefaultTypes,
+					MatchAnywhere: true,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				Paths:         []string{"."},
+			},
+		},
+		"fast": {
+			args: []string{"--fast"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				SkipStrings:   true,
+				Paths:         []string{"."},
+			},
+		},
+		"extension-language": {
+			args: []string{"--extension-language=.m=MATLAB", "--extension-language=H=Objective-C"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				ExtensionLanguages: map[string]string{
+					".m": "MATLAB",
+					".h": "Objective-C",
+				},
+				Paths: []string{"."},
+			},
+		},
+		"fallback-lang": {
+			args: []string{"--fallback-lang=Text"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:          defaultCharset,
+				MaxLineLength:    defaultMaxLineLength,
+				IncludeHidden:    true,
+				FallbackLanguage: "Text",
+				Paths:            []string{"."},
+			},
+		},
+		"invalid fallback-lang": {
+			args: []string{"--fallback-lang=Brainfuck"},
+			err:  ErrFlagParse,
+		},
+		"patch": {
+			args: []string{"--patch"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				Patches:       true,
+				Paths:         []string{"."},
+			},
+		},
+		"ref": {
+			args: []string{"--ref=v1.2.3"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				Paths:         []string{"."},
+				Ref:           "v1.2.3",
+			},
+		},
+		"explain with ref": {
+			args: []string{"--ref=v1.2.3", "--explain=foo.go"},
+			err:  ErrFlagParse,
+		},
+		"skip-tests": {
+			args: []string{"--skip-tests"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				Paths:         []string{"."},
+				SkipTests:     true,
+			},
+		},
 		"paths": {
 			args: []string{"/path/to/code"},
 			expected: &walker.Options{
@@ -451,6 +1192,7 @@ func Test_walkerOptionsFromContext(t *testing.T) {
 					Types: todos.DefaultTypes,
 				},
 				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
 				IncludeHidden: true,
 				Paths:         []string{"/path/to/code"},
 			},
@@ -462,6 +1204,7 @@ func Test_walkerOptionsFromContext(t *testing.T) {
 					Types: todos.DefaultTypes,
 				},
 				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
 				IncludeHidden: true,
 				Paths:         []string{"/path/to/code", "/other/path"},
 			},
@@ -473,8 +1216,9 @@ func Test_walkerOptionsFromContext(t *testing.T) {
 					Types: todos.DefaultTypes,
 				},
 				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
 				IncludeHidden: true,
-				ExcludeGlobs:  []glob.Glob{glob.MustCompile("exclude.*"), glob.MustCompile("foo")},
+				ExcludeGlobs:  []glob.Glob{mustCompileGlob("exclude.*"), mustCompileGlob("foo")},
 				Paths:         []string{"."},
 			},
 		},
@@ -485,8 +1229,9 @@ func Test_walkerOptionsFromContext(t *testing.T) {
 					Types: todos.DefaultTypes,
 				},
 				Charset:         defaultCharset,
+				MaxLineLength:   defaultMaxLineLength,
 				IncludeHidden:   true,
-				ExcludeDirGlobs: []glob.Glob{glob.MustCompile("exclude?"), glob.MustCompile("foo")},
+				ExcludeDirGlobs: []glob.Glob{mustCompileGlob("exclude?"), mustCompileGlob("foo")},
 				Paths:           []string{"."},
 			},
 		},
@@ -497,11 +1242,42 @@ func Test_walkerOptionsFromContext(t *testing.T) {
 					Types: todos.DefaultTypes,
 				},
 				Charset:         defaultCharset,
+				MaxLineLength:   defaultMaxLineLength,
+				IncludeHidden:   true,
+				ExcludeDirGlobs: []glob.Glob{mustCompileGlob("exclude")},
+				Paths:           []string{"."},
+			},
+		},
+		"exclude-dir-slash": {
+			args: []string{"--exclude-dir=exclude/", `--exclude-dir=foo\`},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:         defaultCharset,
+				MaxLineLength:   defaultMaxLineLength,
 				IncludeHidden:   true,
-				ExcludeDirGlobs: []glob.Glob{glob.MustCompile("exclude")},
+				ExcludeDirGlobs: []glob.Glob{mustCompileGlob("exclude"), mustCompileGlob("foo")},
 				Paths:           []string{"."},
 			},
 		},
+		"glob-case": {
+			args: []string{"--glob-case=insensitive"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:              defaultCharset,
+				MaxLineLength:        defaultMaxLineLength,
+				IncludeHidden:        true,
+				CaseInsensitiveGlobs: true,
+				Paths:                []string{"."},
+			},
+		},
+		"glob-case-invalid": {
+			args: []string{"--glob-case=foo"},
+			err:  ErrFlagParse,
+		},
 		"charset": {
 			args: []string{"--charset=UTF-16"},
 			expected: &walker.Options{
@@ -509,6 +1285,7 @@ func Test_walkerOptionsFromContext(t *testing.T) {
 					Types: todos.DefaultTypes,
 				},
 				Charset:       "UTF-16",
+				MaxLineLength: defaultMaxLineLength,
 				IncludeHidden: true,
 				Paths:         []string{"."},
 			},
@@ -520,6 +1297,7 @@ func Test_walkerOptionsFromContext(t *testing.T) {
 					Types: todos.DefaultTypes,
 				},
 				Charset:       "detect",
+				MaxLineLength: defaultMaxLineLength,
 				IncludeHidden: true,
 				Paths:         []string{"."},
 			},
@@ -528,38 +1306,689 @@ func Test_walkerOptionsFromContext(t *testing.T) {
 			args: []string{"--charset=invalid"},
 			err:  ErrFlagParse,
 		},
-		"label": {
-			args: []string{"--label=foo", "--label=bar-*"},
+		"cache-dir and cache-url": {
+			args: []string{"--cache-dir=foo", "--cache-url=http://localhost/cache"},
+			err:  ErrFlagParse,
+		},
+		"invalid cache-url": {
+			args: []string{"--cache-url=ftp://localhost/cache"},
+			err:  ErrFlagParse,
+		},
+		"owners": {
+			args: []string{"--owners"},
 			expected: &walker.Options{
 				Config: &todos.Config{
 					Types: todos.DefaultTypes,
 				},
-				LabelGlobs:    []glob.Glob{glob.MustCompile("foo"), glob.MustCompile("bar-*")},
 				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
 				IncludeHidden: true,
+				Owners:        true,
 				Paths:         []string{"."},
 			},
 		},
-	}
-
-	for name, tc := range testCases {
-		t.Run(name, func(t *testing.T) {
-			t.Parallel()
-
+		"shard": {
+			args: []string{"--shard=3/8"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				ShardIndex:    2,
+				ShardCount:    8,
+				Paths:         []string{"."},
+			},
+		},
+		"shard out of range": {
+			args: []string{"--shard=9/8"},
+			err:  ErrFlagParse,
+		},
+		"shard zero": {
+			args: []string{"--shard=0/8"},
+			err:  ErrFlagParse,
+		},
+		"invalid shard": {
+			args: []string{"--shard=3"},
+			err:  ErrFlagParse,
+		},
+		"size": {
+			args: []string{"--min-size=10", "--max-size=2K"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				MinSize:       10,
+				MaxSize:       2048,
+				Paths:         []string{"."},
+			},
+		},
+		"invalid size": {
+			args: []string{"--max-size=2X"},
+			err:  ErrFlagParse,
+		},
+		"max-line-length": {
+			args: []string{"--max-line-length=1K"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: 1024,
+				IncludeHidden: true,
+				Paths:         []string{"."},
+			},
+		},
+		"no max-line-length": {
+			args: []string{"--max-line-length=0"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:       defaultCharset,
+				IncludeHidden: true,
+				Paths:         []string{"."},
+			},
+		},
+		"invalid max-line-length": {
+			args: []string{"--max-line-length=foo"},
+			err:  ErrFlagParse,
+		},
+		"repo": {
+			args: []string{"--repo=ianlewis/todos"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+					Repo:  "ianlewis/todos",
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				Paths:         []string{"."},
+			},
+		},
+		"invalid repo": {
+			args: []string{"--repo=ianlewis"},
+			err:  ErrFlagParse,
+		},
+		"infer-priority": {
+			args: []string{"--infer-priority"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types:            todos.DefaultTypes,
+					PriorityKeywords: todos.DefaultPriorityKeywords,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				Paths:         []string{"."},
+			},
+		},
+		"priority-keyword": {
+			args: []string{"--priority-keyword=security=p0", "--priority-keyword= tech debt = p3 "},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+					PriorityKeywords: []todos.PriorityKeyword{
+						{Keyword: "security", Priority: "p0"},
+						{Keyword: "tech debt", Priority: "p3"},
+					},
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				Paths:         []string{"."},
+			},
+		},
+		"invalid priority-keyword": {
+			args: []string{"--priority-keyword=security"},
+			err:  ErrFlagParse,
+		},
+		"check-secrets": {
+			args: []string{"--check-secrets"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				SecretRules:   walker.DefaultSecretRules,
+				Paths:         []string{"."},
+			},
+		},
+		"secret-pattern": {
+			args: []string{"--secret-pattern=jira=jira\\.internal"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				SecretRules: append(append([]*walker.SecretRule{}, walker.DefaultSecretRules...), &walker.SecretRule{
+					Name:    "jira",
+					Pattern: regexp.MustCompile(`jira\.internal`),
+				}),
+				Paths: []string{"."},
+			},
+		},
+		"invalid secret-pattern": {
+			args: []string{"--secret-pattern=jira"},
+			err:  ErrFlagParse,
+		},
+		"invalid secret-pattern regex": {
+			args: []string{"--secret-pattern=jira=("},
+			err:  ErrFlagParse,
+		},
+		"detect-message-language": {
+			args: []string{"--detect-message-language"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:               defaultCharset,
+				MaxLineLength:         defaultMaxLineLength,
+				IncludeHidden:         true,
+				DetectMessageLanguage: true,
+				Paths:                 []string{"."},
+			},
+		},
+		"message-language": {
+			args: []string{"--message-language=JA", "--message-language=und"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:          defaultCharset,
+				MaxLineLength:    defaultMaxLineLength,
+				IncludeHidden:    true,
+				MessageLanguages: []string{"ja", "und"},
+				Paths:            []string{"."},
+			},
+		},
+		"invalid message-language": {
+			args: []string{"--message-language=klingon"},
+			err:  ErrFlagParse,
+		},
+		"strict-config": {
+			args: []string{"--strict-config"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				StrictConfig:  true,
+				Paths:         []string{"."},
+			},
+		},
+		"modified-since": {
+			args: []string{"--modified-since=2024-01-01"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				ModifiedSince: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				Paths:         []string{"."},
+			},
+		},
+		"modified-since timestamp": {
+			args: []string{"--modified-since=2024-01-01T12:00:00Z"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				ModifiedSince: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+				Paths:         []string{"."},
+			},
+		},
+		"invalid modified-since": {
+			args: []string{"--modified-since=yesterday"},
+			err:  ErrFlagParse,
+		},
+		"modified-since with ref": {
+			args: []string{"--modified-since=2024-01-01", "--ref=v1"},
+			err:  ErrFlagParse,
+		},
+		"list-files": {
+			args: []string{"--list-files"},
+			// NOTE: Doesn't actually check FileFunc.
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				ListFiles:     true,
+				Paths:         []string{"."},
+			},
+		},
+		"label": {
+			args: []string{"--label=foo", "--label=bar-*"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				LabelGlobs:    []glob.Glob{glob.MustCompile("foo"), glob.MustCompile("bar-*")},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				Paths:         []string{"."},
+			},
+		},
+		"grep": {
+			args: []string{"--grep=deprecat", "--grep=(?i)remove"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				GrepRegexps:   []*regexp.Regexp{regexp.MustCompile("deprecat"), regexp.MustCompile("(?i)remove")},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				Paths:         []string{"."},
+			},
+		},
+		"exclude-type and exclude-label": {
+			args: []string{"--exclude-type=NOTE,XXX", "--exclude-label=wontfix*"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				ExcludeTypes:      []string{"NOTE", "XXX"},
+				ExcludeLabelGlobs: []glob.Glob{glob.MustCompile("wontfix*")},
+				Charset:           defaultCharset,
+				MaxLineLength:     defaultMaxLineLength,
+				IncludeHidden:     true,
+				Paths:             []string{"."},
+			},
+		},
+		"assignee": {
+			args: []string{"--assignee=alice", "--assignee= @bob"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Assignees:     []string{"alice", "bob"},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				Paths:         []string{"."},
+			},
+		},
+		"follow-symlinks": {
+			args: []string{"--follow-symlinks=in-tree"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				FollowSymlinks: walker.SymlinksInTree,
+				Charset:        defaultCharset,
+				MaxLineLength:  defaultMaxLineLength,
+				IncludeHidden:  true,
+				Paths:          []string{"."},
+			},
+		},
+		"confine": {
+			args: []string{"--confine"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Confine:       true,
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				Paths:         []string{"."},
+			},
+		},
+		"extract": {
+			args: []string{"--extract"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Extract:       true,
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				Paths:         []string{"."},
+			},
+		},
+		"invalid follow-symlinks": {
+			args: []string{"--follow-symlinks=some"},
+			err:  ErrFlagParse,
+		},
+		"invalid grep": {
+			args: []string{"--grep=("},
+			err:  ErrFlagParse,
+		},
+		"attr": {
+			args: []string{"--attr=priority=p1", "--attr=assignee"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				AttrFilters: []*walker.AttrFilter{
+					{Key: "priority", Value: glob.MustCompile("p1")},
+					{Key: "assignee"},
+				},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				Paths:         []string{"."},
+			},
+		},
+		"lines": {
+			args: []string{"--lines=10:20", "foo.go"},
+			expected: &walker.Options{
+				Config: &todos.Config{
+					Types: todos.DefaultTypes,
+				},
+				Lines:         &walker.LineRange{Start: 10, End: 20},
+				Charset:       defaultCharset,
+				MaxLineLength: defaultMaxLineLength,
+				IncludeHidden: true,
+				Paths:         []string{"foo.go"},
+			},
+		},
+		"lines with multiple paths": {
+			args: []string{"--lines=10:20", "foo.go", "bar.go"},
+			err:  ErrFlagParse,
+		},
+		"lines with directory": {
+			args: []string{"--lines=10:20", "."},
+			err:  ErrFlagParse,
+		},
+		"invalid attr": {
+			args: []string{"--attr=priority=[p1"},
+			err:  ErrFlagParse,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
 			app := newTODOsApp()
 			c := newContext(app, tc.args)
 
-			o, err := walkerOptionsFromContext(c)
+			o, _, err := todosOptionsFromContext(c)
 
 			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
 				t.Errorf("unexpected error (-want, +got): \n%s", diff)
 			}
 			if err == nil {
-				// NOTE: Do not consider the ErrorFunc for comparison.
-				if diff := cmp.Diff(tc.expected, o, cmpopts.IgnoreFields(walker.Options{}, "TODOFunc", "ErrorFunc")); diff != "" {
+				// NOTE: Do not consider the handler funcs for comparison.
+				ignoreFuncs := cmpopts.IgnoreFields(walker.Options{},
+					"TODOFunc", "ErrorFunc", "FileFunc", "WarningFunc", "SkipFunc", "NoteFunc", "ConfigFunc")
+				compareRegexp := cmp.Comparer(func(x, y *regexp.Regexp) bool {
+					return x.String() == y.String()
+				})
+				if diff := cmp.Diff(tc.expected, o, ignoreFuncs, compareRegexp); diff != "" {
 					t.Errorf("unexpected options (-want, +got): \n%s", diff)
 				}
 			}
 		})
 	}
 }
+
+func Test_parseSize(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		size     string
+		expected int64
+		err      error
+	}{
+		"bytes": {
+			size:     "100",
+			expected: 100,
+		},
+		"kilobytes": {
+			size:     "2K",
+			expected: 2 << 10,
+		},
+		"megabytes lower": {
+			size:     "3m",
+			expected: 3 << 20,
+		},
+		"gigabytes": {
+			size:     "1G",
+			expected: 1 << 30,
+		},
+		"unknown unit": {
+			size: "1T",
+			err:  errInvalidSize,
+		},
+		"empty number": {
+			size: "K",
+			err:  errInvalidSize,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseSize(tc.size)
+			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("unexpected error (-want, +got): \n%s", diff)
+			}
+			if got != tc.expected {
+				t.Errorf("unexpected size, got: %d, want: %d", got, tc.expected)
+			}
+		})
+	}
+}
+
+func Test_parseExtensionLanguage(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		mapping      string
+		expectedExt  string
+		expectedLang string
+		err          error
+	}{
+		"extension": {
+			mapping:      ".m=MATLAB",
+			expectedExt:  ".m",
+			expectedLang: "MATLAB",
+		},
+		"no dot": {
+			mapping:      "M=Objective-C",
+			expectedExt:  ".m",
+			expectedLang: "Objective-C",
+		},
+		"no language": {
+			mapping: ".m",
+			err:     errInvalidExtensionLanguage,
+		},
+		"unsupported language": {
+			mapping: ".m=NotALanguage",
+			err:     errInvalidExtensionLanguage,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ext, lang, err := parseExtensionLanguage(tc.mapping)
+			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("unexpected error (-want, +got): \n%s", diff)
+			}
+			if ext != tc.expectedExt || lang != tc.expectedLang {
+				t.Errorf("unexpected mapping, got: %q=%q, want: %q=%q", ext, lang, tc.expectedExt, tc.expectedLang)
+			}
+		})
+	}
+}
+
+func Test_parsePriorityKeyword(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		m        string
+		expected todos.PriorityKeyword
+		err      error
+	}{
+		"keyword": {
+			m:        "urgent=high",
+			expected: todos.PriorityKeyword{Keyword: "urgent", Priority: "high"},
+		},
+		"phrase": {
+			m:        "data loss = p0",
+			expected: todos.PriorityKeyword{Keyword: "data loss", Priority: "p0"},
+		},
+		"no priority": {
+			m:   "urgent=",
+			err: errInvalidPriorityKeyword,
+		},
+		"no keyword": {
+			m:   "=high",
+			err: errInvalidPriorityKeyword,
+		},
+		"no separator": {
+			m:   "urgent",
+			err: errInvalidPriorityKeyword,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parsePriorityKeyword(tc.m)
+			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("unexpected error (-want, +got): \n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected keyword (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_globCaseInsensitive(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		mode     string
+		goos     string
+		expected bool
+		err      error
+	}{
+		"sensitive": {
+			mode:     "sensitive",
+			goos:     "windows",
+			expected: false,
+		},
+		"insensitive": {
+			mode:     "insensitive",
+			goos:     "linux",
+			expected: true,
+		},
+		"auto linux": {
+			mode:     "auto",
+			goos:     "linux",
+			expected: false,
+		},
+		"auto windows": {
+			mode:     "auto",
+			goos:     "windows",
+			expected: true,
+		},
+		"auto darwin": {
+			mode:     "auto",
+			goos:     "darwin",
+			expected: true,
+		},
+		"invalid": {
+			mode: "foo",
+			goos: "linux",
+			err:  errInvalidGlobCase,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := globCaseInsensitive(tc.mode, tc.goos)
+			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("unexpected error (-want, +got): \n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected result (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_parseLines(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		lines    string
+		expected *walker.LineRange
+		err      error
+	}{
+		"range": {
+			lines:    "10:20",
+			expected: &walker.LineRange{Start: 10, End: 20},
+		},
+		"single line": {
+			lines:    "5",
+			expected: &walker.LineRange{Start: 5, End: 5},
+		},
+		"no start": {
+			lines:    ":20",
+			expected: &walker.LineRange{Start: 1, End: 20},
+		},
+		"no end": {
+			lines:    "10:",
+			expected: &walker.LineRange{Start: 10},
+		},
+		"reversed": {
+			lines: "20:10",
+			err:   errInvalidLines,
+		},
+		"zero": {
+			lines: "0:10",
+			err:   errInvalidLines,
+		},
+		"not a number": {
+			lines: "a:b",
+			err:   errInvalidLines,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseLines(tc.lines)
+			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("unexpected error (-want, +got): \n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected range (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultBigQueryAPIURL is the BigQuery API URL used if BIGQUERY_API_URL is
+// not set.
+const defaultBigQueryAPIURL = "https://bigquery.googleapis.com"
+
+// maxBigQueryRows is the maximum number of rows sent to the BigQuery API in
+// a single insertAll request.
+const maxBigQueryRows = 500
+
+var errBigQuery = errors.New("bigquery")
+
+// bigQueryInsertRow is a row in a BigQuery insertAll request.
+type bigQueryInsertRow struct {
+	InsertID string   `json:"insertId"`
+	JSON     *sinkRow `json:"json"`
+}
+
+// bigQueryInsertRequest is the body of a BigQuery insertAll request.
+type bigQueryInsertRequest struct {
+	Rows []*bigQueryInsertRow `json:"rows"`
+}
+
+// bigQueryInsertResponse is the response to a BigQuery insertAll request.
+type bigQueryInsertResponse struct {
+	InsertErrors []struct {
+		Index  int `json:"index"`
+		Errors []struct {
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	} `json:"insertErrors"`
+}
+
+// bigQuerySink appends the results of a run to an existing BigQuery table
+// with the streaming insertAll API. The access token is read from
+// GOOGLE_OAUTH_ACCESS_TOKEN.
+type bigQuerySink struct {
+	*runRows
+
+	// apiURL is the base URL of the BigQuery API.
+	apiURL string
+
+	// project, dataset, and table identify the table.
+	project, dataset, table string
+
+	// token is the OAuth access token.
+	token string
+
+	client *http.Client
+}
+
+// parseBigQueryTable parses a PROJECT.DATASET.TABLE table reference.
+func parseBigQueryTable(target string) (string, string, string, error) {
+	// NOTE: Domain-scoped project IDs contain dots so the dataset and table
+	// are split from the end.
+	i := strings.LastIndex(target, ".")
+	j := strings.LastIndex(target[:max(i, 0)], ".")
+	if i <= 0 || j <= 0 || i == len(target)-1 || i == j+1 {
+		return "", "", "", fmt.Errorf("%w: %q: must be of the form PROJECT.DATASET.TABLE", errBigQuery, target)
+	}
+	return target[:j], target[j+1 : i], target[i+1:], nil
+}
+
+func newBigQuerySink(target string) (sink, error) {
+	project, dataset, table, err := parseBigQueryTable(target)
+	if err != nil {
+		return nil, err
+	}
+
+	token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("%w: GOOGLE_OAUTH_ACCESS_TOKEN must be set", errBigQuery)
+	}
+
+	r, err := newRunRows()
+	if err != nil {
+		return nil, err
+	}
+	s := &bigQuerySink{
+		runRows: r,
+		apiURL:  defaultBigQueryAPIURL,
+		project: project,
+		dataset: dataset,
+		table:   table,
+		token:   token,
+		client:  &http.Client{Timeout: time.Minute},
+	}
+	if u := os.Getenv("BIGQUERY_API_URL"); u != "" {
+		s.apiURL = u
+	}
+	return s, nil
+}
+
+// Close implements sink.Close.
+func (s *bigQuerySink) Close(walkErr bool) error {
+	endpoint := fmt.Sprintf("%s/bigquery/v2/projects/%s/datasets/%s/tables/%s/insertAll",
+		strings.TrimRight(s.apiURL, "/"), url.PathEscape(s.project), url.PathEscape(s.dataset), url.PathEscape(s.table))
+
+	rows := s.rows(!walkErr)
+	for i := 0; i < len(rows); i += maxBigQueryRows {
+		var req bigQueryInsertRequest
+		for j, r := range rows[i:min(len(rows), i+maxBigQueryRows)] {
+			// NOTE: Insert IDs let BigQuery drop duplicate rows if a
+			// request is retried.
+			req.Rows = append(req.Rows, &bigQueryInsertRow{
+				InsertID: r.RunID + "-" + strconv.Itoa(i+j),
+				JSON:     r,
+			})
+		}
+		if err := s.insert(endpoint, &req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insert sends an insertAll request.
+func (s *bigQuerySink) insert(endpoint string, body *bigQueryInsertRequest) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("%w: encoding request: %w", errBigQuery, err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("%w: POST %s: %w", errBigQuery, endpoint, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: POST %s: %w", errBigQuery, endpoint, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("%w: POST %s: unexpected status: %s: %s", errBigQuery, endpoint, res.Status, bytes.TrimSpace(msg))
+	}
+
+	var resp bigQueryInsertResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return fmt.Errorf("%w: decoding response: %w", errBigQuery, err)
+	}
+	if len(resp.InsertErrors) > 0 {
+		e := resp.InsertErrors[0]
+		msg := "unknown error"
+		if len(e.Errors) > 0 {
+			msg = e.Errors[0].Reason + ": " + e.Errors[0].Message
+		}
+		return fmt.Errorf("%w: %d rows not inserted: row %d: %s", errBigQuery, len(resp.InsertErrors), e.Index, msg)
+	}
+	return nil
+}
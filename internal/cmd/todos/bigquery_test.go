@@ -0,0 +1,241 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/ianlewis/todos/internal/todos"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+func Test_parseBigQueryTable(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		target string
+		want   []string
+		err    error
+	}{
+		"table": {
+			target: "proj.data.todos",
+			want:   []string{"proj", "data", "todos"},
+		},
+		"domain-scoped project": {
+			target: "example.com:proj.data.todos",
+			want:   []string{"example.com:proj", "data", "todos"},
+		},
+		"no dataset": {
+			target: "data.todos",
+			err:    errBigQuery,
+		},
+		"empty table": {
+			target: "proj.data.",
+			err:    errBigQuery,
+		},
+		"empty dataset": {
+			target: "proj..todos",
+			err:    errBigQuery,
+		},
+		"empty": {
+			target: "",
+			err:    errBigQuery,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			project, dataset, table, err := parseBigQueryTable(tc.target)
+			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("unexpected error (-want, +got): \n%s", diff)
+			}
+			if tc.err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want, []string{project, dataset, table}); diff != "" {
+				t.Errorf("unexpected table (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_bigQuerySink(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		n        int
+		walkErr  bool
+		status   int
+		response string
+		requests []int
+		err      error
+	}{
+		"complete": {
+			n:        2,
+			status:   http.StatusOK,
+			response: `{}`,
+			requests: []int{2},
+		},
+		"chunked": {
+			n:        maxBigQueryRows + 1,
+			status:   http.StatusOK,
+			response: `{}`,
+			requests: []int{maxBigQueryRows, 1},
+		},
+		"no rows": {
+			status:   http.StatusOK,
+			response: `{}`,
+		},
+		"walk error": {
+			n:        1,
+			walkErr:  true,
+			status:   http.StatusOK,
+			response: `{}`,
+			requests: []int{1},
+		},
+		"bad status": {
+			n:        1,
+			status:   http.StatusForbidden,
+			response: `{"error": {"message": "denied"}}`,
+			requests: []int{1},
+			err:      errBigQuery,
+		},
+		"insert errors": {
+			n:        1,
+			status:   http.StatusOK,
+			response: `{"insertErrors": [{"index": 0, "errors": [{"reason": "invalid", "message": "no such field"}]}]}`,
+			requests: []int{1},
+			err:      errBigQuery,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests []int
+			var rows []*bigQueryInsertRow
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got, want := r.URL.Path, "/bigquery/v2/projects/proj/datasets/data/tables/todos/insertAll"; got != want {
+					t.Errorf("unexpected path, got: %q, want: %q", got, want)
+				}
+				if got, want := r.Header.Get("Authorization"), "Bearer token"; got != want {
+					t.Errorf("unexpected authorization, got: %q, want: %q", got, want)
+				}
+				var req bigQueryInsertRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("decoding request: %v", err)
+				}
+				requests = append(requests, len(req.Rows))
+				rows = append(rows, req.Rows...)
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer srv.Close()
+
+			r, err := newRunRows()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			s := &bigQuerySink{
+				runRows: r,
+				apiURL:  srv.URL + "/",
+				project: "proj",
+				dataset: "data",
+				table:   "todos",
+				token:   "token",
+				client:  srv.Client(),
+			}
+			for range tc.n {
+				if err := s.Handle(&walker.TODORef{
+					FileName: "a.go",
+					TODO:     &todos.TODO{Type: "TODO", Text: "// TODO: a", Message: "a", Line: 1},
+				}); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			err = s.Close(tc.walkErr)
+			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("unexpected error (-want, +got): \n%s", diff)
+			}
+			if diff := cmp.Diff(tc.requests, requests); diff != "" {
+				t.Errorf("unexpected requests (-want, +got): \n%s", diff)
+			}
+
+			ids := map[string]bool{}
+			for _, row := range rows {
+				if !strings.HasPrefix(row.InsertID, r.id+"-") {
+					t.Errorf("unexpected insert ID %q", row.InsertID)
+				}
+				if ids[row.InsertID] {
+					t.Errorf("duplicate insert ID %q", row.InsertID)
+				}
+				ids[row.InsertID] = true
+				if got, want := row.JSON.Complete, !tc.walkErr; got != want {
+					t.Errorf("unexpected complete, got: %v, want: %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func Test_bigQuerySink_escape(t *testing.T) {
+	t.Parallel()
+
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	r, err := newRunRows()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := &bigQuerySink{
+		runRows: r,
+		apiURL:  srv.URL,
+		project: "example.com:proj",
+		dataset: "da ta",
+		table:   "to/dos",
+		token:   "token",
+		client:  srv.Client(),
+	}
+	if err := s.Handle(&walker.TODORef{
+		FileName: "a.go",
+		TODO:     &todos.TODO{Type: "TODO", Text: "// TODO: a", Message: "a", Line: 1},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Close(false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "/bigquery/v2/projects/example.com:proj/datasets/da%20ta/tables/to%2Fdos/insertAll"
+	if diff := cmp.Diff(want, path); diff != "" {
+		t.Errorf("unexpected path (-want, +got): \n%s", diff)
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+// maxAnnotations is the maximum number of annotations that can be sent to the
+// GitHub API in a single check run request.
+const maxAnnotations = 50
+
+// defaultGitHubAPIURL is the GitHub API URL used if GITHUB_API_URL is not set.
+const defaultGitHubAPIURL = "https://api.github.com"
+
+var errCheckRun = errors.New("check run")
+
+// checkRunConclusions are the supported values of --check-run-conclusion.
+var checkRunConclusions = []string{"neutral", "failure", "success"}
+
+// checkAnnotation is an annotation on a GitHub check run.
+type checkAnnotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	AnnotationLevel string `json:"annotation_level"`
+	Title           string `json:"title,omitempty"`
+	Message         string `json:"message"`
+}
+
+// checkOutput is the output of a GitHub check run.
+type checkOutput struct {
+	Title       string             `json:"title"`
+	Summary     string             `json:"summary"`
+	Annotations []*checkAnnotation `json:"annotations,omitempty"`
+}
+
+// checkRunRequest is the body of a request to create or update a GitHub check
+// run.
+type checkRunRequest struct {
+	Name       string       `json:"name,omitempty"`
+	HeadSHA    string       `json:"head_sha,omitempty"`
+	Status     string       `json:"status,omitempty"`
+	Conclusion string       `json:"conclusion,omitempty"`
+	Output     *checkOutput `json:"output"`
+}
+
+// checkRun publishes the TODOs found in a run as a GitHub check run.
+type checkRun struct {
+	// name is the name of the check run.
+	name string
+
+	// conclusion is the conclusion of the check run if TODOs are found.
+	conclusion string
+
+	// apiURL is the base URL of the GitHub API.
+	apiURL string
+
+	// repo is the repository in OWNER/REPO format.
+	repo string
+
+	// sha is the commit that the check run is created for.
+	sha string
+
+	// token is the token used to authenticate with the GitHub API.
+	token string
+
+	client *http.Client
+
+	annotations []*checkAnnotation
+	files       map[string]bool
+}
+
+// checkRunFromContext returns a new check run if the check-run flag is set.
+// The walker options' TODO handler is wrapped to collect annotations.
+func checkRunFromContext(c *cli.Context, o *walker.Options) (*checkRun, error) {
+	name := c.String("check-run")
+	if name == "" {
+		return nil, nil
+	}
+
+	conclusion := c.String("check-run-conclusion")
+	if !slices.Contains(checkRunConclusions, conclusion) {
+		return nil, fmt.Errorf("%w: invalid check run conclusion: %v", ErrFlagParse, conclusion)
+	}
+
+	r := &checkRun{
+		name:       name,
+		conclusion: conclusion,
+		apiURL:     defaultGitHubAPIURL,
+		// NOTE: Don't let an unresponsive API hang CI forever.
+		client: &http.Client{Timeout: time.Minute},
+		files:  map[string]bool{},
+	}
+	if u := os.Getenv("GITHUB_API_URL"); u != "" {
+		r.apiURL = u
+	}
+	for _, env := range []struct {
+		name string
+		v    *string
+	}{
+		{"GITHUB_TOKEN", &r.token},
+		{"GITHUB_REPOSITORY", &r.repo},
+		{"GITHUB_SHA", &r.sha},
+	} {
+		*env.v = os.Getenv(env.name)
+		if *env.v == "" {
+			return nil, fmt.Errorf("%w: check-run: %s must be set", ErrFlagParse, env.name)
+		}
+	}
+
+	todoFunc := o.TODOFunc
+	o.TODOFunc = func(ref *walker.TODORef) error {
+		if ref != nil {
+			r.add(ref)
+		}
+		if todoFunc == nil {
+			return nil
+		}
+		return todoFunc(ref)
+	}
+
+	return r, nil
+}
+
+// add adds an annotation for the TODO.
+func (r *checkRun) add(ref *walker.TODORef) {
+	level := githubLevel(ref.TODO.Type)
+	if level == "error" {
+		level = "failure"
+	}
+	path := filepath.ToSlash(filepath.Clean(ref.FileName))
+	r.files[path] = true
+	r.annotations = append(r.annotations, &checkAnnotation{
+		Path:            path,
+		StartLine:       ref.TODO.Line,
+		EndLine:         max(ref.TODO.Line, ref.TODO.EndLine),
+		AnnotationLevel: level,
+		Title:           ref.TODO.Type,
+		Message:         ref.TODO.Text,
+	})
+}
+
+// publish creates the check run. The check run fails if errors were
+// encountered during the walk.
+func (r *checkRun) publish(walkErr bool) error {
+	if r == nil {
+		return nil
+	}
+
+	conclusion := r.conclusion
+	switch {
+	case walkErr:
+		conclusion = "failure"
+	case len(r.annotations) == 0:
+		conclusion = "success"
+	}
+
+	title := fmt.Sprintf("%d TODOs found", len(r.annotations))
+	summary := fmt.Sprintf("Found %d TODOs in %d files.", len(r.annotations), len(r.files))
+
+	// NOTE: The GitHub API limits the number of annotations per request so
+	// the remaining annotations are added by updating the check run.
+	batch := r.annotations[:min(len(r.annotations), maxAnnotations)]
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	endpoint := fmt.Sprintf("%s/repos/%s/check-runs", strings.TrimRight(r.apiURL, "/"), r.repo)
+	if err := r.do(http.MethodPost, endpoint, &checkRunRequest{
+		Name:       r.name,
+		HeadSHA:    r.sha,
+		Status:     "completed",
+		Conclusion: conclusion,
+		Output: &checkOutput{
+			Title:       title,
+			Summary:     summary,
+			Annotations: batch,
+		},
+	}, &created); err != nil {
+		return err
+	}
+
+	for i := len(batch); i < len(r.annotations); i += maxAnnotations {
+		batch := r.annotations[i:min(len(r.annotations), i+maxAnnotations)]
+		if err := r.do(http.MethodPatch, fmt.Sprintf("%s/%d", endpoint, created.ID), &checkRunRequest{
+			Output: &checkOutput{
+				Title:       title,
+				Summary:     summary,
+				Annotations: batch,
+			},
+		}, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// do sends the request body to the GitHub API and decodes the response into
+// resp if it is not nil.
+func (r *checkRun) do(method, url string, body, resp any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("%w: encoding request: %w", errCheckRun, err)
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("%w: %s %s: %w", errCheckRun, method, url, err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+r.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %s %s: %w", errCheckRun, method, url, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("%w: %s %s: unexpected status: %s: %s", errCheckRun, method, url, res.Status, bytes.TrimSpace(msg))
+	}
+
+	if resp == nil {
+		return nil
+	}
+	if err := json.NewDecoder(res.Body).Decode(resp); err != nil {
+		return fmt.Errorf("%w: decoding response: %w", errCheckRun, err)
+	}
+	return nil
+}
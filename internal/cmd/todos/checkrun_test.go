@@ -0,0 +1,266 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/todos"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+// checkRunCall is a request received by the fake GitHub API.
+type checkRunCall struct {
+	Method        string
+	Path          string
+	Authorization string
+	Body          *checkRunRequest
+}
+
+// newCheckRunServer returns a fake GitHub API server that records the
+// requests it receives.
+func newCheckRunServer(calls *[]*checkRunCall) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body checkRunRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		*calls = append(*calls, &checkRunCall{
+			Method:        r.Method,
+			Path:          r.URL.Path,
+			Authorization: r.Header.Get("Authorization"),
+			Body:          &body,
+		})
+		_, _ = w.Write([]byte(`{"id": 42}`))
+	}))
+}
+
+func newTestCheckRun(apiURL string) *checkRun {
+	return &checkRun{
+		name:       "todos",
+		conclusion: "neutral",
+		apiURL:     apiURL,
+		repo:       "ianlewis/todos",
+		sha:        "abc123",
+		token:      "secret",
+		client:     http.DefaultClient,
+		files:      map[string]bool{},
+	}
+}
+
+func Test_checkRun_publish(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		refs     []*walker.TODORef
+		walkErr  bool
+		expected []*checkRunCall
+	}{
+		"no todos": {
+			expected: []*checkRunCall{
+				{
+					Method:        http.MethodPost,
+					Path:          "/repos/ianlewis/todos/check-runs",
+					Authorization: "Bearer secret",
+					Body: &checkRunRequest{
+						Name:       "todos",
+						HeadSHA:    "abc123",
+						Status:     "completed",
+						Conclusion: "success",
+						Output: &checkOutput{
+							Title:   "0 TODOs found",
+							Summary: "Found 0 TODOs in 0 files.",
+						},
+					},
+				},
+			},
+		},
+		"todos": {
+			refs: []*walker.TODORef{
+				{
+					FileName: "./foo.go",
+					TODO: &todos.TODO{
+						Type: "FIXME",
+						Text: "// FIXME: foo",
+						Line: 3,
+					},
+				},
+				{
+					FileName: "bar.py",
+					TODO: &todos.TODO{
+						Type:    "NOTE",
+						Text:    "# NOTE: bar",
+						Line:    1,
+						EndLine: 2,
+					},
+				},
+			},
+			expected: []*checkRunCall{
+				{
+					Method:        http.MethodPost,
+					Path:          "/repos/ianlewis/todos/check-runs",
+					Authorization: "Bearer secret",
+					Body: &checkRunRequest{
+						Name:       "todos",
+						HeadSHA:    "abc123",
+						Status:     "completed",
+						Conclusion: "neutral",
+						Output: &checkOutput{
+							Title:   "2 TODOs found",
+							Summary: "Found 2 TODOs in 2 files.",
+							Annotations: []*checkAnnotation{
+								{
+									Path:            "foo.go",
+									StartLine:       3,
+									EndLine:         3,
+									AnnotationLevel: "failure",
+									Title:           "FIXME",
+									Message:         "// FIXME: foo",
+								},
+								{
+									Path:            "bar.py",
+									StartLine:       1,
+									EndLine:         2,
+									AnnotationLevel: "notice",
+									Title:           "NOTE",
+									Message:         "# NOTE: bar",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"walk error": {
+			walkErr: true,
+			expected: []*checkRunCall{
+				{
+					Method:        http.MethodPost,
+					Path:          "/repos/ianlewis/todos/check-runs",
+					Authorization: "Bearer secret",
+					Body: &checkRunRequest{
+						Name:       "todos",
+						HeadSHA:    "abc123",
+						Status:     "completed",
+						Conclusion: "failure",
+						Output: &checkOutput{
+							Title:   "0 TODOs found",
+							Summary: "Found 0 TODOs in 0 files.",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls []*checkRunCall
+			s := newCheckRunServer(&calls)
+			defer s.Close()
+
+			r := newTestCheckRun(s.URL)
+			for _, ref := range tc.refs {
+				r.add(ref)
+			}
+			if err := r.publish(tc.walkErr); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expected, calls); diff != "" {
+				t.Errorf("unexpected requests (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_checkRun_publishBatches(t *testing.T) {
+	t.Parallel()
+
+	var calls []*checkRunCall
+	s := newCheckRunServer(&calls)
+	defer s.Close()
+
+	r := newTestCheckRun(s.URL)
+	for i := range maxAnnotations + 1 {
+		r.add(&walker.TODORef{
+			FileName: "foo.go",
+			TODO: &todos.TODO{
+				Type: "TODO",
+				Text: fmt.Sprintf("// TODO: %d", i),
+				Line: i + 1,
+			},
+		})
+	}
+	if err := r.publish(false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := len(calls), 2; got != want {
+		t.Fatalf("unexpected number of requests, got: %d, want: %d", got, want)
+	}
+	if got, want := len(calls[0].Body.Output.Annotations), maxAnnotations; got != want {
+		t.Errorf("unexpected number of annotations, got: %d, want: %d", got, want)
+	}
+
+	want := &checkRunCall{
+		Method:        http.MethodPatch,
+		Path:          "/repos/ianlewis/todos/check-runs/42",
+		Authorization: "Bearer secret",
+		Body: &checkRunRequest{
+			Output: &checkOutput{
+				Title:   "51 TODOs found",
+				Summary: "Found 51 TODOs in 1 files.",
+				Annotations: []*checkAnnotation{
+					{
+						Path:            "foo.go",
+						StartLine:       51,
+						EndLine:         51,
+						AnnotationLevel: "warning",
+						Title:           "TODO",
+						Message:         "// TODO: 50",
+					},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, calls[1]); diff != "" {
+		t.Errorf("unexpected request (-want, +got): \n%s", diff)
+	}
+}
+
+func Test_checkRun_publishError(t *testing.T) {
+	t.Parallel()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "Bad credentials", http.StatusUnauthorized)
+	}))
+	defer s.Close()
+
+	r := newTestCheckRun(s.URL)
+	if err := r.publish(false); !errors.Is(err, errCheckRun) {
+		t.Errorf("unexpected error, got: %v, want: %v", err, errCheckRun)
+	}
+}
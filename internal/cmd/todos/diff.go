@@ -0,0 +1,257 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/todos/internal/utils"
+)
+
+// minSimilarity is the minimum similarity for two TODOs with different text to
+// be considered the same TODO.
+const minSimilarity = 0.8
+
+// diffStatus is the status of a TODO in a diff.
+type diffStatus string
+
+const (
+	diffAdded   diffStatus = "added"
+	diffRemoved diffStatus = "removed"
+	diffMoved   diffStatus = "moved"
+)
+
+// diffEntry is a change to a TODO between two result sets.
+type diffEntry struct {
+	// Status is the type of change.
+	Status diffStatus `json:"status"`
+
+	// TODO is the TODO in the new result set or the old result set if it
+	// was removed.
+	TODO *outTODO `json:"todo"`
+
+	// OldLine is the previous line of moved TODOs.
+	OldLine int `json:"old_line,omitempty"`
+}
+
+func newDiffCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "diff",
+		Usage:     "Compare two JSON result files.",
+		ArgsUsage: "OLD NEW",
+		HideHelp:  true,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Usage:   "output `TYPE` (default, json)",
+				Value:   "default",
+				Aliases: []string{"o"},
+			},
+			&cli.BoolFlag{
+				Name:               "help",
+				Usage:              "print this help text and exit",
+				Aliases:            []string{"h"},
+				DisableDefaultText: true,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.Bool("help") {
+				utils.Check(cli.ShowCommandHelp(c, c.Command.Name))
+				return nil
+			}
+
+			outType := c.String("output")
+			outFunc, ok := diffOutTypes[outType]
+			if !ok {
+				return fmt.Errorf("%w: invalid output type: %v", ErrFlagParse, outType)
+			}
+
+			if c.NArg() != 2 {
+				return fmt.Errorf("%w: expected 2 arguments, got %d", ErrFlagParse, c.NArg())
+			}
+
+			oldResults, err := readResultsFile(c.Args().Get(0))
+			if err != nil {
+				return err
+			}
+			newResults, err := readResultsFile(c.Args().Get(1))
+			if err != nil {
+				return err
+			}
+
+			for _, e := range diffResults(oldResults, newResults) {
+				outFunc(c.App.Writer, e)
+			}
+			return nil
+		},
+	}
+}
+
+var diffOutTypes = map[string]func(io.Writer, *diffEntry){
+	"":        outDiffCLI,
+	"default": outDiffCLI,
+	"json":    outDiffJSON,
+}
+
+func outDiffCLI(w io.Writer, e *diffEntry) {
+	switch e.Status {
+	case diffAdded:
+		_ = utils.Must(fmt.Fprintf(w, "%s %s:%d:%s\n", color.GreenString("+"), e.TODO.Path, e.TODO.Line, e.TODO.Text))
+	case diffRemoved:
+		_ = utils.Must(fmt.Fprintf(w, "%s %s:%d:%s\n", color.RedString("-"), e.TODO.Path, e.TODO.Line, e.TODO.Text))
+	case diffMoved:
+		_ = utils.Must(fmt.Fprintf(w, "%s %s:%d->%d:%s\n",
+			color.YellowString("~"), e.TODO.Path, e.OldLine, e.TODO.Line, e.TODO.Text))
+	}
+}
+
+func outDiffJSON(w io.Writer, e *diffEntry) {
+	b := utils.Must(json.Marshal(e))
+	_ = utils.Must(w.Write(b))
+	_ = utils.Must(w.Write([]byte("\n")))
+}
+
+// diffResults compares the old and new results. TODOs are first matched by
+// path, text, and line, then by path and text, and finally by path, type,
+// and similar message so that TODOs survive line shifts and small edits. TODOs that
+// are matched but whose line changed are reported as moved. The entries are
+// sorted by path and line.
+func diffResults(oldResults, newResults []*outTODO) []*diffEntry {
+	oldMatched := make([]bool, len(oldResults))
+	newMatched := make([]bool, len(newResults))
+	var entries []*diffEntry
+
+	match := func(isMatch func(o, n *outTODO) bool) {
+		for j, n := range newResults {
+			if newMatched[j] {
+				continue
+			}
+
+			// Match the closest unmatched old TODO.
+			best := -1
+			for i, o := range oldResults {
+				if oldMatched[i] || o.Path != n.Path || !isMatch(o, n) {
+					continue
+				}
+				if best == -1 || abs(o.Line-n.Line) < abs(oldResults[best].Line-n.Line) {
+					best = i
+				}
+			}
+			if best == -1 {
+				continue
+			}
+
+			oldMatched[best] = true
+			newMatched[j] = true
+			if oldResults[best].Line != n.Line {
+				entries = append(entries, &diffEntry{
+					Status:  diffMoved,
+					TODO:    n,
+					OldLine: oldResults[best].Line,
+				})
+			}
+		}
+	}
+
+	match(func(o, n *outTODO) bool {
+		return o.Line == n.Line && o.Text == n.Text
+	})
+	match(func(o, n *outTODO) bool {
+		return normalizeText(o.Text) == normalizeText(n.Text)
+	})
+	// NOTE: Compare only the message so that the comment start and TODO type,
+	// which are often the same, do not make unrelated TODOs look similar.
+	match(func(o, n *outTODO) bool {
+		return o.Type == n.Type && similarity(normalizeText(o.Message), normalizeText(n.Message)) >= minSimilarity
+	})
+
+	for i, o := range oldResults {
+		if !oldMatched[i] {
+			entries = append(entries, &diffEntry{
+				Status: diffRemoved,
+				TODO:   o,
+			})
+		}
+	}
+	for j, n := range newResults {
+		if !newMatched[j] {
+			entries = append(entries, &diffEntry{
+				Status: diffAdded,
+				TODO:   n,
+			})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].TODO.Path != entries[j].TODO.Path {
+			return entries[i].TODO.Path < entries[j].TODO.Path
+		}
+		return entries[i].TODO.Line < entries[j].TODO.Line
+	})
+
+	return entries
+}
+
+// normalizeText normalizes the TODO text for comparison by lower casing it
+// and collapsing whitespace.
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// similarity returns the similarity of the two strings between 0 and 1 based
+// on the Levenshtein distance.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	maxLen := max(len(ra), len(rb))
+	if maxLen == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(maxLen)
+}
+
+// levenshtein returns the Levenshtein edit distance between a and b.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
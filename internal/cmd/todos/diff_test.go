@@ -0,0 +1,217 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/testutils"
+)
+
+func Test_diffResults(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		old, new []*outTODO
+		expected []*diffEntry
+	}{
+		"empty": {
+			expected: nil,
+		},
+		"unchanged": {
+			old: []*outTODO{
+				{Path: "a.go", Line: 1, Text: "// TODO: a"},
+			},
+			new: []*outTODO{
+				{Path: "a.go", Line: 1, Text: "// TODO: a"},
+			},
+			expected: nil,
+		},
+		"added and removed": {
+			old: []*outTODO{
+				{Path: "a.go", Line: 1, Text: "// TODO: remove me"},
+			},
+			new: []*outTODO{
+				{Path: "b.go", Line: 1, Text: "// TODO: remove me"},
+			},
+			expected: []*diffEntry{
+				{
+					Status: diffRemoved,
+					TODO:   &outTODO{Path: "a.go", Line: 1, Text: "// TODO: remove me"},
+				},
+				{
+					Status: diffAdded,
+					TODO:   &outTODO{Path: "b.go", Line: 1, Text: "// TODO: remove me"},
+				},
+			},
+		},
+		"moved": {
+			old: []*outTODO{
+				{Path: "a.go", Line: 1, Text: "// TODO: a"},
+				{Path: "a.go", Line: 5, Text: "// TODO: b"},
+			},
+			new: []*outTODO{
+				{Path: "a.go", Line: 3, Text: "// TODO: a"},
+				{Path: "a.go", Line: 7, Text: "//  todo: B"},
+			},
+			expected: []*diffEntry{
+				{
+					Status:  diffMoved,
+					TODO:    &outTODO{Path: "a.go", Line: 3, Text: "// TODO: a"},
+					OldLine: 1,
+				},
+				{
+					Status:  diffMoved,
+					TODO:    &outTODO{Path: "a.go", Line: 7, Text: "//  todo: B"},
+					OldLine: 5,
+				},
+			},
+		},
+		"moved with small edit": {
+			old: []*outTODO{
+				{Path: "a.go", Line: 10, Type: "TODO", Text: "// TODO: refactor this function", Message: "refactor this function"},
+			},
+			new: []*outTODO{
+				{Path: "a.go", Line: 12, Type: "TODO", Text: "// TODO: refactor this function.", Message: "refactor this function."},
+			},
+			expected: []*diffEntry{
+				{
+					Status: diffMoved,
+					TODO: &outTODO{
+						Path:    "a.go",
+						Line:    12,
+						Type:    "TODO",
+						Text:    "// TODO: refactor this function.",
+						Message: "refactor this function.",
+					},
+					OldLine: 10,
+				},
+			},
+		},
+		"duplicate text matches closest": {
+			old: []*outTODO{
+				{Path: "a.go", Line: 1, Text: "// TODO: dup"},
+				{Path: "a.go", Line: 20, Text: "// TODO: dup"},
+			},
+			new: []*outTODO{
+				{Path: "a.go", Line: 22, Text: "// TODO: dup"},
+			},
+			expected: []*diffEntry{
+				{
+					Status: diffRemoved,
+					TODO:   &outTODO{Path: "a.go", Line: 1, Text: "// TODO: dup"},
+				},
+				{
+					Status:  diffMoved,
+					TODO:    &outTODO{Path: "a.go", Line: 22, Text: "// TODO: dup"},
+					OldLine: 20,
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tc.expected, diffResults(tc.old, tc.new)); diff != "" {
+				t.Errorf("unexpected diff (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_levenshtein(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		a, b     string
+		expected int
+	}{
+		"empty": {
+			a:        "",
+			b:        "",
+			expected: 0,
+		},
+		"insert": {
+			a:        "abc",
+			b:        "abcd",
+			expected: 1,
+		},
+		"kitten": {
+			a:        "kitten",
+			b:        "sitting",
+			expected: 3,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := levenshtein([]rune(tc.a), []rune(tc.b)), tc.expected; got != want {
+				t.Errorf("levenshtein(%q, %q); got: %v, want: %v", tc.a, tc.b, got, want)
+			}
+		})
+	}
+}
+
+func Test_TODOsApp_diff(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path: "old.json",
+			Contents: []byte(`{"path":"a.go","type":"TODO","text":"// TODO: a","label":"","message":"a","line":1,"comment_line":1}
+{"path":"a.go","type":"TODO","text":"// TODO: b","label":"","message":"b","line":2,"comment_line":2}
+`),
+			Mode: 0o600,
+		},
+		{
+			Path: "new.json",
+			Contents: []byte(`{"path":"a.go","type":"TODO","text":"// TODO: a","label":"","message":"a","line":4,"comment_line":4}
+{"path":"a.go","type":"TODO","text":"// TODO: c","label":"","message":"c","line":5,"comment_line":5}
+`),
+			Mode: 0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	app := newTODOsApp()
+	var b strings.Builder
+	app.Writer = &b
+	if err := app.Run([]string{
+		"todos",
+		"diff",
+		filepath.Join(d.Dir(), "old.json"),
+		filepath.Join(d.Dir(), "new.json"),
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `- a.go:2:// TODO: b
+~ a.go:1->4:// TODO: a
++ a.go:5:// TODO: c
+`
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("unexpected output (-want, +got): \n%s", diff)
+	}
+}
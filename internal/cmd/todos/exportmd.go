@@ -0,0 +1,220 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/todos/internal/utils"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+var errOutOfDate = errors.New("out of date")
+
+// mdHeader is the header written at the top of exported markdown files.
+const mdHeader = "<!-- Code generated by todos export-md. DO NOT EDIT. -->\n"
+
+// mdTODO is a TODO in an exported markdown file.
+type mdTODO struct {
+	// Path is the slash separated path of the file relative to the markdown
+	// file.
+	Path string
+
+	// TODO is the TODO.
+	TODO *walker.TODORef
+}
+
+// mdEscaper escapes characters that have special meaning in markdown.
+var mdEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `\<`,
+	`>`, `\>`,
+	`|`, `\|`,
+)
+
+func newExportMDCommand() *cli.Command {
+	flags := sortedFlags(append(walkerFlags(),
+		&cli.BoolFlag{
+			Name:               "check",
+			Usage:              "check that the output file is up to date instead of writing it",
+			DisableDefaultText: true,
+		},
+		localeFlag(defaultLocale),
+		&cli.StringFlag{
+			Name:    "output",
+			Usage:   "write the markdown to `FILE` (- for stdout)",
+			Value:   "TODO.md",
+			Aliases: []string{"o"},
+		},
+	))
+
+	return &cli.Command{
+		Name:      "export-md",
+		Usage:     "Export TODOs to a markdown index file.",
+		ArgsUsage: "[PATH]...",
+		HideHelp:  true,
+		Flags: append(flags,
+			&cli.BoolFlag{
+				Name:               "help",
+				Usage:              "print this help text and exit",
+				Aliases:            []string{"h"},
+				DisableDefaultText: true,
+			},
+		),
+		Action: func(c *cli.Context) error {
+			if c.Bool("help") {
+				utils.Check(cli.ShowCommandHelp(c, c.Command.Name))
+				return nil
+			}
+
+			output := c.String("output")
+			check := c.Bool("check")
+			if check && output == "-" {
+				return fmt.Errorf("%w: --check requires an output file", ErrFlagParse)
+			}
+
+			// NOTE: The generated file doesn't depend on the environment so
+			// that it is the same for everyone that generates or checks it.
+			cat, err := catalogFromContext(c, false)
+			if err != nil {
+				return err
+			}
+
+			opts, err := walkerOptionsFromContext(c)
+			if err != nil {
+				return err
+			}
+
+			baseDir := "."
+			if output != "-" {
+				baseDir = filepath.Dir(output)
+			}
+
+			var found []*mdTODO
+			opts.TODOFunc = func(r *walker.TODORef) error {
+				found = append(found, &mdTODO{
+					Path: relSlashPath(baseDir, r.FileName),
+					TODO: r,
+				})
+				return nil
+			}
+
+			if walker.New(opts).Walk() {
+				return ErrWalk
+			}
+
+			var b bytes.Buffer
+			outMarkdown(&b, found, cat)
+
+			switch {
+			case output == "-":
+				_ = utils.Must(c.App.Writer.Write(b.Bytes()))
+			case check:
+				current, err := os.ReadFile(output)
+				if err != nil && !errors.Is(err, os.ErrNotExist) {
+					return fmt.Errorf("%w: %w", errOutOfDate, err)
+				}
+				if !bytes.Equal(current, b.Bytes()) {
+					return fmt.Errorf("%w: %s; %s", errOutOfDate, output, messageCatalog(c).text(msgRunExportMD))
+				}
+			default:
+				// NOTE: The markdown file is meant to be committed so it is
+				// world readable.
+				//nolint:gosec // G306: Expect WriteFile permissions to be 0600 or less.
+				if err := os.WriteFile(output, b.Bytes(), 0o644); err != nil {
+					return fmt.Errorf("writing %s: %w", output, err)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// relSlashPath returns the slash separated path of fileName relative to
+// baseDir. fileName is returned as is if it can't be made relative.
+func relSlashPath(baseDir, fileName string) string {
+	absBase, err := filepath.Abs(baseDir)
+	if err != nil {
+		return filepath.ToSlash(fileName)
+	}
+	absFile, err := filepath.Abs(fileName)
+	if err != nil {
+		return filepath.ToSlash(fileName)
+	}
+	rel, err := filepath.Rel(absBase, absFile)
+	if err != nil {
+		return filepath.ToSlash(fileName)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// outMarkdown writes a markdown index of the TODOs grouped by directory.
+// The output is sorted by directory, file, and line so that it is stable.
+// Headers are written using the given message catalog.
+func outMarkdown(w io.Writer, found []*mdTODO, cat catalog) {
+	sort.SliceStable(found, func(i, j int) bool {
+		di, dj := path.Dir(found[i].Path), path.Dir(found[j].Path)
+		if di != dj {
+			return di < dj
+		}
+		if found[i].Path != found[j].Path {
+			return found[i].Path < found[j].Path
+		}
+		return found[i].TODO.TODO.Line < found[j].TODO.TODO.Line
+	})
+
+	_ = utils.Must(io.WriteString(w, mdHeader))
+	_ = utils.Must(fmt.Fprintf(w, "\n# %s\n", cat.text(msgTODOs)))
+	if len(found) == 0 {
+		_ = utils.Must(fmt.Fprintf(w, "\n%s\n", cat.text(msgNoTODOs)))
+		return
+	}
+
+	dir := ""
+	for i, t := range found {
+		if d := path.Dir(t.Path); i == 0 || d != dir {
+			dir = d
+			_ = utils.Must(fmt.Fprintf(w, "\n## %s\n\n", mdEscaper.Replace(dir)))
+		}
+
+		todo := t.TODO.TODO
+		text := todo.Type
+		if todo.Label != "" {
+			text += "(" + todo.Label + ")"
+		}
+		if todo.Message != "" {
+			text += ": " + todo.Message
+		}
+		link := (&url.URL{Path: t.Path, Fragment: fmt.Sprintf("L%d", todo.Line)}).String()
+		_ = utils.Must(fmt.Fprintf(w, "- [%s:%d](%s): %s\n",
+			mdEscaper.Replace(path.Base(t.Path)), todo.Line, link, mdEscaper.Replace(text)))
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/testutils"
+	"github.com/ianlewis/todos/internal/todos"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+func Test_outMarkdown(t *testing.T) {
+	t.Parallel()
+
+	ref := func(line int, typ, label, message string) *walker.TODORef {
+		return &walker.TODORef{
+			TODO: &todos.TODO{
+				Type:    typ,
+				Label:   label,
+				Message: message,
+				Line:    line,
+			},
+		}
+	}
+
+	testCases := map[string]struct {
+		found    []*mdTODO
+		locale   string
+		expected string
+	}{
+		"empty": {
+			expected: `<!-- Code generated by todos export-md. DO NOT EDIT. -->
+
+# TODOs
+
+No TODOs found.
+`,
+		},
+		"empty ja": {
+			locale: "ja",
+			expected: `<!-- Code generated by todos export-md. DO NOT EDIT. -->
+
+# TODO 一覧
+
+TODO は見つかりませんでした。
+`,
+		},
+		"sorted": {
+			found: []*mdTODO{
+				{Path: "b/foo.go", TODO: ref(3, "TODO", "", "foo")},
+				{Path: "a/bar.go", TODO: ref(10, "FIXME", "#1", "use *pointers*")},
+				{Path: "a/bar.go", TODO: ref(2, "TODO", "", "")},
+				{Path: "main.go", TODO: ref(1, "TODO", "", "main")},
+			},
+			expected: `<!-- Code generated by todos export-md. DO NOT EDIT. -->
+
+# TODOs
+
+## .
+
+- [main.go:1](main.go#L1): TODO: main
+
+## a
+
+- [bar.go:2](a/bar.go#L2): TODO
+- [bar.go:10](a/bar.go#L10): FIXME(#1): use \*pointers\*
+
+## b
+
+- [foo.go:3](b/foo.go#L3): TODO: foo
+`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+			locale := tc.locale
+			if locale == "" {
+				locale = defaultLocale
+			}
+			outMarkdown(&b, tc.found, catalogs[locale])
+			if diff := cmp.Diff(tc.expected, b.String()); diff != "" {
+				t.Errorf("unexpected output (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_TODOsApp_exportMD(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "foo.go",
+			Contents: []byte("// TODO: foo\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/bar.go",
+			Contents: []byte("// TODO(#1): bar\n"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	output := filepath.Join(d.Dir(), "TODO.md")
+	run := func(args ...string) error {
+		app := newTODOsApp()
+		app.ExitErrHandler = nil
+		return app.Run(append([]string{"todos", "export-md", "--output", output}, args...))
+	}
+
+	if err := run("--check", d.Dir()); !errors.Is(err, errOutOfDate) {
+		t.Fatalf("unexpected error, got: %v, want: %v", err, errOutOfDate)
+	}
+
+	if err := run(d.Dir()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	b, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+	want := `<!-- Code generated by todos export-md. DO NOT EDIT. -->
+
+# TODOs
+
+## .
+
+- [foo.go:1](foo.go#L1): TODO: foo
+
+## sub
+
+- [bar.go:1](sub/bar.go#L1): TODO(#1): bar
+`
+	if diff := cmp.Diff(want, string(b)); diff != "" {
+		t.Errorf("unexpected output (-want, +got): \n%s", diff)
+	}
+
+	if err := run("--check", d.Dir()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests.
+func Test_TODOsApp_exportMD_locale(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "foo.go",
+			Contents: []byte("// TODO: foo\n"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	output := filepath.Join(d.Dir(), "TODO.md")
+	run := func(args ...string) error {
+		app := newTODOsApp()
+		app.ExitErrHandler = nil
+		return app.Run(append([]string{"todos", "export-md", "--output", output}, args...))
+	}
+
+	if err := run(d.Dir()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// NOTE: The file should not depend on the locale of the environment.
+	t.Setenv("LANG", "ja_JP.UTF-8")
+	if err := run("--check", d.Dir()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := run("--check", "--locale", "ja", d.Dir()); !errors.Is(err, errOutOfDate) {
+		t.Errorf("unexpected error, got: %v, want: %v", err, errOutOfDate)
+	}
+}
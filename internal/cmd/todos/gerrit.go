@@ -0,0 +1,189 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+// gerritRobotID is the robot ID of the comments posted to Gerrit.
+const gerritRobotID = "todos"
+
+var errGerrit = errors.New("gerrit")
+
+// gerritRobotComment is a robot comment on a file in a Gerrit change.
+type gerritRobotComment struct {
+	RobotID    string `json:"robot_id"`
+	RobotRunID string `json:"robot_run_id"`
+	Line       int    `json:"line"`
+	Message    string `json:"message"`
+}
+
+// gerritReviewInput is the body of a request to review a Gerrit revision.
+type gerritReviewInput struct {
+	Message       string                           `json:"message"`
+	Tag           string                           `json:"tag"`
+	RobotComments map[string][]*gerritRobotComment `json:"robot_comments"`
+}
+
+// gerritReview posts the TODOs found in a run as robot comments on a Gerrit
+// change.
+type gerritReview struct {
+	// baseURL is the URL of the Gerrit server.
+	baseURL string
+
+	// change is the change ID or number.
+	change string
+
+	// revision is the revision ID or patch set number of the change.
+	revision string
+
+	// username and password are the HTTP credentials of the Gerrit account.
+	username string
+	password string
+
+	// runID identifies the run that created the comments.
+	runID string
+
+	client *http.Client
+
+	comments map[string][]*gerritRobotComment
+	count    int
+}
+
+// gerritFromContext returns a new Gerrit review if the gerrit-change flag is
+// set. The patch flag must also be set so that only the TODOs added by the
+// change are commented on. The walker options' TODO handler is wrapped to
+// collect comments.
+func gerritFromContext(c *cli.Context, o *walker.Options) (*gerritReview, error) {
+	change := c.String("gerrit-change")
+	if change == "" {
+		return nil, nil
+	}
+
+	baseURL := c.String("gerrit-url")
+	if baseURL == "" {
+		return nil, fmt.Errorf("%w: gerrit-url must be set with gerrit-change", ErrFlagParse)
+	}
+	if u, err := url.Parse(baseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("%w: invalid gerrit-url: %v", ErrFlagParse, baseURL)
+	}
+	// NOTE: Only TODOs added by the change should be commented on so the
+	// change must be scanned as a patch rather than the whole tree.
+	if !o.Patches {
+		return nil, fmt.Errorf("%w: patch must be set with gerrit-change", ErrFlagParse)
+	}
+
+	r := &gerritReview{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		change:   change,
+		revision: c.String("gerrit-revision"),
+		runID:    strconv.FormatInt(time.Now().Unix(), 10),
+		// NOTE: Don't let an unresponsive server hang CI forever.
+		client:   &http.Client{Timeout: time.Minute},
+		comments: map[string][]*gerritRobotComment{},
+	}
+	for _, env := range []struct {
+		name string
+		v    *string
+	}{
+		{"GERRIT_USERNAME", &r.username},
+		{"GERRIT_PASSWORD", &r.password},
+	} {
+		*env.v = os.Getenv(env.name)
+		if *env.v == "" {
+			return nil, fmt.Errorf("%w: gerrit-change: %s must be set", ErrFlagParse, env.name)
+		}
+	}
+
+	todoFunc := o.TODOFunc
+	o.TODOFunc = func(ref *walker.TODORef) error {
+		if ref != nil {
+			r.add(ref)
+		}
+		if todoFunc == nil {
+			return nil
+		}
+		return todoFunc(ref)
+	}
+
+	return r, nil
+}
+
+// add adds a robot comment for the TODO.
+func (r *gerritReview) add(ref *walker.TODORef) {
+	path := filepath.ToSlash(filepath.Clean(ref.FileName))
+	r.comments[path] = append(r.comments[path], &gerritRobotComment{
+		RobotID:    gerritRobotID,
+		RobotRunID: r.runID,
+		Line:       ref.TODO.Line,
+		Message:    ref.TODO.Text,
+	})
+	r.count++
+}
+
+// publish posts the robot comments to the change. Nothing is posted if no
+// TODOs were found.
+func (r *gerritReview) publish() error {
+	if r == nil || r.count == 0 {
+		return nil
+	}
+
+	b, err := json.Marshal(&gerritReviewInput{
+		Message:       fmt.Sprintf("Found %d TODOs.", r.count),
+		Tag:           "autogenerated:todos",
+		RobotComments: r.comments,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: encoding request: %w", errGerrit, err)
+	}
+
+	// NOTE: The /a/ prefix is required for authenticated requests.
+	endpoint := fmt.Sprintf("%s/a/changes/%s/revisions/%s/review",
+		r.baseURL, url.PathEscape(r.change), url.PathEscape(r.revision))
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("%w: POST %s: %w", errGerrit, endpoint, err)
+	}
+	req.SetBasicAuth(r.username, r.password)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: POST %s: %w", errGerrit, endpoint, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("%w: POST %s: unexpected status: %s: %s", errGerrit, endpoint, res.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/todos"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+func newTestGerritReview(baseURL string) *gerritReview {
+	return &gerritReview{
+		baseURL:  baseURL,
+		change:   "myproject~main~I0123456789abcdef",
+		revision: "current",
+		username: "bot",
+		password: "secret",
+		runID:    "1",
+		client:   http.DefaultClient,
+		comments: map[string][]*gerritRobotComment{},
+	}
+}
+
+func Test_gerritReview_publish(t *testing.T) {
+	t.Parallel()
+
+	var gotPath, gotUser, gotPassword string
+	var got *gerritReviewInput
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotUser, gotPassword, _ = r.BasicAuth()
+		got = &gerritReviewInput{}
+		if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(")]}'\n{}"))
+	}))
+	defer s.Close()
+
+	r := newTestGerritReview(s.URL)
+	r.add(&walker.TODORef{
+		FileName: "./foo.go",
+		TODO: &todos.TODO{
+			Type: "TODO",
+			Text: "// TODO: foo",
+			Line: 3,
+		},
+	})
+	r.add(&walker.TODORef{
+		FileName: "foo.go",
+		TODO: &todos.TODO{
+			Type: "FIXME",
+			Text: "// FIXME: bar",
+			Line: 5,
+		},
+	})
+	if err := r.publish(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := "/a/changes/myproject~main~I0123456789abcdef/revisions/current/review"; gotPath != want {
+		t.Errorf("unexpected path, got: %q, want: %q", gotPath, want)
+	}
+	if gotUser != "bot" || gotPassword != "secret" {
+		t.Errorf("unexpected credentials, got: %q:%q", gotUser, gotPassword)
+	}
+
+	want := &gerritReviewInput{
+		Message: "Found 2 TODOs.",
+		Tag:     "autogenerated:todos",
+		RobotComments: map[string][]*gerritRobotComment{
+			"foo.go": {
+				{
+					RobotID:    "todos",
+					RobotRunID: "1",
+					Line:       3,
+					Message:    "// TODO: foo",
+				},
+				{
+					RobotID:    "todos",
+					RobotRunID: "1",
+					Line:       5,
+					Message:    "// FIXME: bar",
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected review (-want, +got): \n%s", diff)
+	}
+}
+
+func Test_gerritReview_publishNoTODOs(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	s := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		called = true
+	}))
+	defer s.Close()
+
+	if err := newTestGerritReview(s.URL).publish(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if called {
+		t.Errorf("unexpected request")
+	}
+}
+
+func Test_gerritReview_publishError(t *testing.T) {
+	t.Parallel()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}))
+	defer s.Close()
+
+	r := newTestGerritReview(s.URL)
+	r.add(&walker.TODORef{
+		FileName: "foo.go",
+		TODO: &todos.TODO{
+			Type: "TODO",
+			Text: "// TODO: foo",
+			Line: 1,
+		},
+	})
+	if err := r.publish(); !errors.Is(err, errGerrit) {
+		t.Errorf("unexpected error, got: %v, want: %v", err, errGerrit)
+	}
+}
+
+func Test_gerritFromContext(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		args []string
+		err  string
+	}{
+		"no change": {
+			args: []string{"--patch"},
+		},
+		"no url": {
+			args: []string{"--patch", "--gerrit-change=123"},
+			err:  "gerrit-url must be set with gerrit-change",
+		},
+		"invalid url": {
+			args: []string{"--patch", "--gerrit-change=123", "--gerrit-url=ftp://gerrit.example.com"},
+			err:  "invalid gerrit-url",
+		},
+		"no patch": {
+			args: []string{"--gerrit-change=123", "--gerrit-url=https://gerrit.example.com"},
+			err:  "patch must be set with gerrit-change",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			app := newTODOsApp()
+			c := newContext(app, tc.args)
+			o := &walker.Options{Patches: c.Bool("patch")}
+
+			r, err := gerritFromContext(c, o)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if r != nil {
+					t.Errorf("unexpected review: %+v", r)
+				}
+				return
+			}
+			if !errors.Is(err, ErrFlagParse) {
+				t.Fatalf("unexpected error, got: %v, want: %v", err, ErrFlagParse)
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("unexpected error, got: %q, want: %q", err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/todos/internal/scanner"
+	"github.com/ianlewis/todos/internal/utils"
+)
+
+// extensionMapping is the languages that files with an extension can be
+// detected as.
+type extensionMapping struct {
+	// Extension is the file extension, such as ".go".
+	Extension string `json:"extension"`
+
+	// Languages are the supported languages for the extension.
+	Languages []string `json:"languages"`
+}
+
+var languagesOutTypes = map[string]func(io.Writer, []*extensionMapping){
+	"":      outLanguagesTable,
+	"table": outLanguagesTable,
+	"json":  outLanguagesJSON,
+}
+
+func newLanguagesCommand() *cli.Command {
+	return &cli.Command{
+		Name:     "languages",
+		Usage:    "Print the languages that files are detected as by extension.",
+		HideHelp: true,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Usage:   "output `TYPE` (table, json)",
+				Value:   "table",
+				Aliases: []string{"o"},
+			},
+			&cli.BoolFlag{
+				Name:               "help",
+				Usage:              "print this help text and exit",
+				Aliases:            []string{"h"},
+				DisableDefaultText: true,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.Bool("help") {
+				utils.Check(cli.ShowCommandHelp(c, c.Command.Name))
+				return nil
+			}
+
+			outType := c.String("output")
+			outFunc, ok := languagesOutTypes[outType]
+			if !ok {
+				return fmt.Errorf("%w: invalid output type: %v", ErrFlagParse, outType)
+			}
+
+			outFunc(c.App.Writer, extensionMappings())
+			return nil
+		},
+	}
+}
+
+// extensionMappings returns the extension to language mappings used by the
+// scanner sorted by extension.
+func extensionMappings() []*extensionMapping {
+	var mappings []*extensionMapping
+	for ext, langs := range scanner.ExtensionLanguages() {
+		mappings = append(mappings, &extensionMapping{
+			Extension: ext,
+			Languages: langs,
+		})
+	}
+	sort.Slice(mappings, func(i, j int) bool {
+		return mappings[i].Extension < mappings[j].Extension
+	})
+	return mappings
+}
+
+func outLanguagesTable(w io.Writer, mappings []*extensionMapping) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_ = utils.Must(fmt.Fprintln(tw, "EXTENSION\tLANGUAGES"))
+	for _, m := range mappings {
+		_ = utils.Must(fmt.Fprintf(tw, "%s\t%s\n", m.Extension, strings.Join(m.Languages, ", ")))
+	}
+	utils.Check(tw.Flush())
+}
+
+func outLanguagesJSON(w io.Writer, mappings []*extensionMapping) {
+	for _, m := range mappings {
+		b := utils.Must(json.Marshal(m))
+		_ = utils.Must(w.Write(b))
+		_ = utils.Must(w.Write([]byte("\n")))
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_languagesOutTypes(t *testing.T) {
+	t.Parallel()
+
+	mappings := []*extensionMapping{
+		{Extension: ".go", Languages: []string{"Go"}},
+		{Extension: ".ts", Languages: []string{"TypeScript", "XML"}},
+	}
+
+	testCases := map[string]struct {
+		outType  string
+		expected string
+	}{
+		"table": {
+			outType: "table",
+			expected: `EXTENSION  LANGUAGES
+.go        Go
+.ts        TypeScript, XML
+`,
+		},
+		"json": {
+			outType: "json",
+			expected: `{"extension":".go","languages":["Go"]}
+{"extension":".ts","languages":["TypeScript","XML"]}
+`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+			languagesOutTypes[tc.outType](&b, mappings)
+			if diff := cmp.Diff(tc.expected, b.String()); diff != "" {
+				t.Errorf("unexpected output (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_TODOsApp_languages(t *testing.T) {
+	t.Parallel()
+
+	app := newTODOsApp()
+	var b strings.Builder
+	app.Writer = &b
+	if err := app.Run([]string{"todos", "languages", "--output=json"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `{"extension":".ts","languages":["TypeScript","XML"]}` + "\n"
+	if !strings.Contains(b.String(), want) {
+		t.Errorf("expected output to contain %q", want)
+	}
+}
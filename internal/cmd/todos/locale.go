@@ -0,0 +1,218 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+var errUnsupportedLocale = errors.New("unsupported locale")
+
+// defaultLocale is the locale used if the environment doesn't select a
+// supported locale. It is always the locale of the export-md report unless the
+// locale flag is set.
+const defaultLocale = "en"
+
+// localeEnvVars are the environment variables that select the locale in
+// order of precedence.
+var localeEnvVars = []string{"LC_ALL", "LC_MESSAGES", "LANG"}
+
+// Message keys. Summary column headers use the --by value as their key.
+const (
+	msgTODOs           = "todos"
+	msgTODOCount       = "todo-count"
+	msgNoTODOs         = "no-todos"
+	msgRunExportMD     = "run-export-md"
+	msgSummaryOwner    = "owner"
+	msgSummaryAssignee = "assignee"
+	msgSummaryLabel    = "label"
+	msgSummaryType     = "type"
+)
+
+// Message keys for CLI messages. Error messages replace the text of the error
+// they are for at the start of an error message.
+const (
+	msgWarning       = "warning"
+	msgSkipped       = "skipped"
+	msgLoadedConfig  = "loaded-config"
+	msgErrFlagParse  = "error-flag-parse"
+	msgErrOutOfDate  = "error-out-of-date"
+	msgErrNoMatch    = "error-no-match"
+	msgErrRatchet    = "error-ratchet"
+	msgErrState      = "error-state"
+	msgErrReadResult = "error-read-results"
+)
+
+// catalog maps message keys to localized messages.
+type catalog map[string]string
+
+// catalogs are the message catalogs for each supported locale.
+var catalogs = map[string]catalog{
+	"en": {
+		msgTODOs:           "TODOs",
+		msgTODOCount:       "TODOs",
+		msgNoTODOs:         "No TODOs found.",
+		msgRunExportMD:     "run todos export-md to update it",
+		msgSummaryOwner:    "Owner",
+		msgSummaryAssignee: "Assignee",
+		msgSummaryLabel:    "Label",
+		msgSummaryType:     "Type",
+
+		msgWarning:       "warning: %v",
+		msgSkipped:       "skipped %s",
+		msgLoadedConfig:  "loaded configuration %s",
+		msgErrFlagParse:  "parsing flags",
+		msgErrOutOfDate:  "out of date",
+		msgErrNoMatch:    "no paths match",
+		msgErrRatchet:    "ratchet",
+		msgErrState:      "state file",
+		msgErrReadResult: "reading results",
+	},
+	"ja": {
+		msgTODOs:           "TODO 一覧",
+		msgTODOCount:       "TODO 数",
+		msgNoTODOs:         "TODO は見つかりませんでした。",
+		msgRunExportMD:     "todos export-md を実行して更新してください",
+		msgSummaryOwner:    "担当者",
+		msgSummaryAssignee: "割り当て先",
+		msgSummaryLabel:    "ラベル",
+		msgSummaryType:     "種類",
+
+		msgWarning:       "警告: %v",
+		msgSkipped:       "スキップしました: %s",
+		msgLoadedConfig:  "設定を読み込みました: %s",
+		msgErrFlagParse:  "フラグの解析エラー",
+		msgErrOutOfDate:  "最新ではありません",
+		msgErrNoMatch:    "一致するパスがありません",
+		msgErrRatchet:    "ラチェット",
+		msgErrState:      "状態ファイル",
+		msgErrReadResult: "結果の読み込みエラー",
+	},
+}
+
+// text returns the localized message for key. The message for the default
+// locale is returned if the catalog doesn't have one.
+func (c catalog) text(key string) string {
+	if msg, ok := c[key]; ok {
+		return msg
+	}
+	return catalogs[defaultLocale][key]
+}
+
+// localizedErrors are the errors whose text is localized when it starts an
+// error message.
+var localizedErrors = []struct {
+	err error
+	key string
+}{
+	{ErrFlagParse, msgErrFlagParse},
+	{errOutOfDate, msgErrOutOfDate},
+	{errNoMatch, msgErrNoMatch},
+	{errRatchet, msgErrRatchet},
+	{errState, msgErrState},
+	{errReadResults, msgErrReadResult},
+}
+
+// errorText returns the localized message for the error. Only the text of the
+// error that starts the message is localized. Other text, such as errors
+// returned by the operating system, is not.
+func (c catalog) errorText(err error) string {
+	msg := err.Error()
+	for _, e := range localizedErrors {
+		if prefix := e.err.Error(); strings.HasPrefix(msg, prefix) && errors.Is(err, e.err) {
+			return c.text(e.key) + msg[len(prefix):]
+		}
+	}
+	return msg
+}
+
+// localeFlag returns the flag used to select the locale. def describes the
+// locale used if the flag isn't set.
+func localeFlag(def string) cli.Flag {
+	return &cli.StringFlag{
+		Name:  "locale",
+		Usage: fmt.Sprintf("write reports and messages in `LOCALE` (%s) (default: %s)", strings.Join(supportedLocales(), ", "), def),
+	}
+}
+
+// supportedLocales returns the sorted names of the supported locales.
+func supportedLocales() []string {
+	var locales []string
+	for l := range catalogs {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// catalogFromContext returns the message catalog for the locale given by
+// the locale flag. Unsupported locales given by the flag are an error. If the
+// flag isn't set the locale is given by the environment if useEnv is true and
+// is the default locale otherwise.
+func catalogFromContext(c *cli.Context, useEnv bool) (catalog, error) {
+	if l := c.String("locale"); l != "" {
+		cat, ok := catalogs[parseLocale(l)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %w: %q", ErrFlagParse, errUnsupportedLocale, l)
+		}
+		return cat, nil
+	}
+	if useEnv {
+		return envCatalog(), nil
+	}
+	return catalogs[defaultLocale], nil
+}
+
+// messageCatalog returns the message catalog for CLI messages such as
+// warnings and errors. It is given by the locale flag if it is valid and by the
+// environment otherwise.
+func messageCatalog(c *cli.Context) catalog {
+	if c != nil {
+		if cat, err := catalogFromContext(c, true); err == nil {
+			return cat
+		}
+	}
+	return envCatalog()
+}
+
+// envCatalog returns the message catalog for the locale given by the
+// environment. Unsupported locales fall back to the default locale.
+func envCatalog() catalog {
+	for _, env := range localeEnvVars {
+		if l := os.Getenv(env); l != "" {
+			if cat, ok := catalogs[parseLocale(l)]; ok {
+				return cat
+			}
+			break
+		}
+	}
+	return catalogs[defaultLocale]
+}
+
+// parseLocale returns the language of a locale name such as "ja_JP.UTF-8"
+// or "en-US".
+func parseLocale(l string) string {
+	l, _, _ = strings.Cut(l, ".")
+	l, _, _ = strings.Cut(l, "@")
+	l, _, _ = strings.Cut(l, "_")
+	l, _, _ = strings.Cut(l, "-")
+	return strings.ToLower(l)
+}
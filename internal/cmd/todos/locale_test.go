@@ -0,0 +1,194 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ianlewis/todos/internal/testutils"
+)
+
+func Test_parseLocale(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"ja":          "ja",
+		"ja_JP.UTF-8": "ja",
+		"en-US":       "en",
+		"EN_us":       "en",
+		"de_DE@euro":  "de",
+		"C":           "c",
+		"":            "",
+	}
+
+	for l, expected := range testCases {
+		t.Run(l, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := parseLocale(l), expected; got != want {
+				t.Errorf("unexpected language, got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
+
+func Test_catalogs(t *testing.T) {
+	t.Parallel()
+
+	// NOTE: All locales should have all of the default locale's messages.
+	for l, cat := range catalogs {
+		for key := range catalogs[defaultLocale] {
+			if _, ok := cat[key]; !ok {
+				t.Errorf("locale %q is missing message %q", l, key)
+			}
+		}
+	}
+}
+
+func Test_catalog_errorText(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		locale   string
+		err      error
+		expected string
+	}{
+		"en": {
+			locale:   "en",
+			err:      fmt.Errorf("%w: invalid output type: foo", ErrFlagParse),
+			expected: "parsing flags: invalid output type: foo",
+		},
+		"ja": {
+			locale:   "ja",
+			err:      fmt.Errorf("%w: invalid output type: foo", ErrFlagParse),
+			expected: "フラグの解析エラー: invalid output type: foo",
+		},
+		"ja wrapped": {
+			locale:   "ja",
+			err:      fmt.Errorf("%w: %w", errOutOfDate, os.ErrNotExist),
+			expected: "最新ではありません: file does not exist",
+		},
+		"ja not at start": {
+			locale:   "ja",
+			err:      fmt.Errorf("foo: %w", ErrFlagParse),
+			expected: "foo: parsing flags",
+		},
+		"ja other error": {
+			locale:   "ja",
+			err:      os.ErrNotExist,
+			expected: "file does not exist",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := catalogs[tc.locale].errorText(tc.err), tc.expected; got != want {
+				t.Errorf("unexpected message, got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
+
+func Test_TODOsApp_locale_messages(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "foo.go",
+			Contents: []byte("// TODO: foo\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     ".todos.yml",
+			Contents: []byte("todo_types: [TODO]\n"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	app := newTODOsApp()
+	var b, errb strings.Builder
+	app.Writer = &b
+	app.ErrWriter = &errb
+	app.ExitErrHandler = nil
+	if err := app.Run([]string{"todos", "summary", "--verbose", "--locale", "ja", d.Dir()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := ": 設定を読み込みました: " + filepath.Join(d.Dir(), ".todos.yml") + "\n"
+	if got := errb.String(); !strings.Contains(got, want) {
+		t.Errorf("unexpected messages, got: %q, want: %q", got, want)
+	}
+}
+
+func Test_TODOsApp_locale(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "foo.go",
+			Contents: []byte("// TODO: foo\n"),
+			Mode:     0o600,
+		},
+	}
+
+	testCases := map[string]struct {
+		locale   string
+		expected string
+		err      error
+	}{
+		"en": {
+			locale:   "en_US.UTF-8",
+			expected: "TYPE  TODOS\nTODO  1\n",
+		},
+		"ja": {
+			locale:   "ja_JP.UTF-8",
+			expected: "種類    TODO 数\nTODO  1\n",
+		},
+		"unsupported": {
+			locale: "xx",
+			err:    errUnsupportedLocale,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			d := testutils.NewTempDir(files)
+			defer d.Cleanup()
+
+			app := newTODOsApp()
+			var b strings.Builder
+			app.Writer = &b
+			app.ExitErrHandler = nil
+			err := app.Run([]string{"todos", "summary", "--by=type", "--locale", tc.locale, d.Dir()})
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("unexpected error, got: %v, want: %v", err, tc.err)
+			}
+			if got, want := b.String(), tc.expected; got != want {
+				t.Errorf("unexpected output, got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"sigs.k8s.io/release-utils/version"
+
+	"github.com/ianlewis/todos/internal/scanner"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+var errManifest = errors.New("manifest")
+
+// Scan precisions recorded in the manifest.
+const (
+	precisionExact = "exact"
+	precisionFast  = "fast"
+)
+
+// enryModule is the module that provides language detection data.
+const enryModule = "github.com/go-enry/go-enry/v2"
+
+// manifest describes a run of todos so that results are reproducible and
+// auditable.
+type manifest struct {
+	// Tool is the tool that produced the results.
+	Tool *manifestTool `json:"tool"`
+
+	// Options are the command line flags that were set.
+	Options map[string]any `json:"options"`
+
+	// Paths are the paths that were scanned.
+	Paths []string `json:"paths"`
+
+	// Precision is "exact" if string literals were parsed, or "fast" if they
+	// were skipped with --fast, in which case TODOs in strings may have been
+	// reported.
+	Precision string `json:"precision"`
+
+	// StartTime is the time that the run started.
+	StartTime time.Time `json:"start_time"`
+
+	// EndTime is the time that the run finished.
+	EndTime time.Time `json:"end_time"`
+
+	// Duration is the duration of the run.
+	Duration string `json:"duration"`
+
+	// Counts are counts of the run's results.
+	Counts *manifestCounts `json:"counts"`
+}
+
+// manifestTool describes the tool and the data it was built with.
+type manifestTool struct {
+	// Name is the tool name.
+	Name string `json:"name"`
+
+	// Version is the tool version.
+	Version string `json:"version"`
+
+	// Build is the tool's build information, including the VCS revision it
+	// was built from.
+	Build *version.Info `json:"build"`
+
+	// EnryVersion is the version of the language detection dataset.
+	EnryVersion string `json:"enry_version,omitempty"`
+
+	// Languages is the number of supported languages.
+	Languages int `json:"languages"`
+}
+
+// manifestCounts are counts of the results of a run.
+type manifestCounts struct {
+	// Files is the number of files that were scanned.
+	Files int `json:"files"`
+
+	// TODOs is the number of TODOs that were output.
+	TODOs int `json:"todos"`
+
+	// Skipped is the number of paths that were skipped.
+	Skipped int `json:"skipped"`
+
+	// Errors is the number of errors.
+	Errors int `json:"errors"`
+
+	// Warnings is the number of warnings.
+	Warnings int `json:"warnings"`
+}
+
+// manifestFromContext returns a new manifest for the run if the manifest
+// flag is set. The walker options' handlers are wrapped to count results.
+func manifestFromContext(c *cli.Context, o *walker.Options) *manifest {
+	if c.String("manifest") == "" {
+		return nil
+	}
+
+	versionInfo := version.GetVersionInfo()
+	m := &manifest{
+		Tool: &manifestTool{
+			Name:        c.App.Name,
+			Version:     versionInfo.GitVersion,
+			Build:       &versionInfo,
+			EnryVersion: moduleVersion(enryModule),
+			Languages:   len(scanner.LanguagesConfig),
+		},
+		Options:   map[string]any{},
+		Paths:     o.Paths,
+		Precision: precisionExact,
+		StartTime: time.Now().UTC(),
+		Counts:    &manifestCounts{},
+	}
+
+	if o.SkipStrings {
+		m.Precision = precisionFast
+	}
+
+	for _, f := range c.Command.Flags {
+		name := f.Names()[0]
+		if name == "manifest" || !c.IsSet(name) {
+			continue
+		}
+		switch f.(type) {
+		case *cli.BoolFlag:
+			m.Options[name] = c.Bool(name)
+		case *cli.StringSliceFlag:
+			m.Options[name] = c.StringSlice(name)
+		default:
+			m.Options[name] = c.String(name)
+		}
+	}
+
+	todoFunc := o.TODOFunc
+	o.TODOFunc = func(r *walker.TODORef) error {
+		m.Counts.TODOs++
+		if todoFunc == nil {
+			return nil
+		}
+		return todoFunc(r)
+	}
+	fileFunc := o.FileFunc
+	o.FileFunc = func(r *walker.FileRef) error {
+		m.Counts.Files++
+		if fileFunc == nil {
+			return nil
+		}
+		return fileFunc(r)
+	}
+	skipFunc := o.SkipFunc
+	o.SkipFunc = func(r *walker.SkipReason) error {
+		m.Counts.Skipped++
+		if skipFunc == nil {
+			return nil
+		}
+		return skipFunc(r)
+	}
+	errorFunc := o.ErrorFunc
+	o.ErrorFunc = func(err error) error {
+		m.Counts.Errors++
+		if errorFunc == nil {
+			return nil
+		}
+		return errorFunc(err)
+	}
+	warningFunc := o.WarningFunc
+	o.WarningFunc = func(err error) error {
+		m.Counts.Warnings++
+		if warningFunc == nil {
+			return nil
+		}
+		return warningFunc(err)
+	}
+
+	return m
+}
+
+// moduleVersion returns the version of the module the binary was built with.
+func moduleVersion(path string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == path {
+			return dep.Version
+		}
+	}
+	return ""
+}
+
+// writeManifest records the end of the run and writes the manifest to the
+// file at path.
+func writeManifest(path string, m *manifest) error {
+	if m == nil {
+		return nil
+	}
+
+	m.EndTime = time.Now().UTC()
+	m.Duration = m.EndTime.Sub(m.StartTime).String()
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %w", errManifest, err)
+	}
+	b = append(b, '\n')
+
+	//nolint:gosec // G306: Expect WriteFile permissions to be 0600 or less.
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		return fmt.Errorf("%w: %w", errManifest, err)
+	}
+	return nil
+}
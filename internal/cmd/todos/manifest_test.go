@@ -0,0 +1,143 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/testutils"
+)
+
+func Test_TODOsApp_manifest(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "foo.go",
+			Contents: []byte("// TODO: foo\n// FIXME: foo\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "bar.go",
+			Contents: []byte("// TODO: bar\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "vendor/baz.go",
+			Contents: []byte("// TODO: baz\n"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	manifestPath := filepath.Join(t.TempDir(), "manifest.json")
+
+	app := newTODOsApp()
+	var b strings.Builder
+	app.Writer = &b
+	args := []string{"todos", "--manifest", manifestPath, "--todo-types=TODO", "--exclude=*.txt", d.Dir()}
+	if err := app.Run(args); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	raw, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatalf("reading manifest: %v", err)
+	}
+	var m manifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		t.Fatalf("parsing manifest: %v", err)
+	}
+
+	if got, want := m.Tool.Name, app.Name; got != want {
+		t.Errorf("unexpected tool name, got: %q, want: %q", got, want)
+	}
+	if m.Tool.Languages == 0 {
+		t.Errorf("unexpected # of languages: %v", m.Tool.Languages)
+	}
+	if got, want := m.Precision, precisionExact; got != want {
+		t.Errorf("unexpected precision, got: %q, want: %q", got, want)
+	}
+	if m.EndTime.Before(m.StartTime) {
+		t.Errorf("unexpected end time, got: %v, start time: %v", m.EndTime, m.StartTime)
+	}
+
+	wantOptions := map[string]any{
+		"todo-types": "TODO",
+		"exclude":    []any{"*.txt"},
+	}
+	if diff := cmp.Diff(wantOptions, m.Options); diff != "" {
+		t.Errorf("unexpected options (-want, +got): \n%s", diff)
+	}
+	if diff := cmp.Diff([]string{d.Dir()}, m.Paths); diff != "" {
+		t.Errorf("unexpected paths (-want, +got): \n%s", diff)
+	}
+
+	wantCounts := &manifestCounts{
+		Files:   2,
+		TODOs:   2,
+		Skipped: 1,
+	}
+	if diff := cmp.Diff(wantCounts, m.Counts); diff != "" {
+		t.Errorf("unexpected counts (-want, +got): \n%s", diff)
+	}
+}
+
+func Test_TODOsApp_manifestFast(t *testing.T) {
+	t.Parallel()
+
+	d := testutils.NewTempDir([]*testutils.File{
+		{
+			Path:     "foo.go",
+			Contents: []byte("x := \"// TODO: in a string\"\n"),
+			Mode:     0o600,
+		},
+	})
+	defer d.Cleanup()
+
+	manifestPath := filepath.Join(t.TempDir(), "manifest.json")
+
+	app := newTODOsApp()
+	var b strings.Builder
+	app.Writer = &b
+	if err := app.Run([]string{"todos", "--fast", "--manifest", manifestPath, d.Dir()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	raw, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatalf("reading manifest: %v", err)
+	}
+	var m manifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		t.Fatalf("parsing manifest: %v", err)
+	}
+
+	if got, want := m.Precision, precisionFast; got != want {
+		t.Errorf("unexpected precision, got: %q, want: %q", got, want)
+	}
+	// NOTE: The TODO in the string is reported since strings aren't parsed.
+	if got, want := m.Counts.TODOs, 1; got != want {
+		t.Errorf("unexpected # of TODOs, got: %v, want: %v", got, want)
+	}
+}
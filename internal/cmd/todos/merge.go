@@ -0,0 +1,186 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/todos/internal/todos"
+	"github.com/ianlewis/todos/internal/utils"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+var errReadResults = errors.New("reading results")
+
+func newMergeCommand() *cli.Command {
+	flags := sortedFlags(append(redactFlags(),
+		&cli.StringFlag{
+			Name:    "output",
+			Usage:   "output `TYPE` (default, github, azure, bitbucket, json)",
+			Value:   defaultOutputType(),
+			Aliases: []string{"o"},
+		},
+	))
+
+	return &cli.Command{
+		Name:      "merge",
+		Usage:     "Merge JSON result files.",
+		ArgsUsage: "[FILE]...",
+		HideHelp:  true,
+		Flags: append(flags,
+			&cli.BoolFlag{
+				Name:               "help",
+				Usage:              "print this help text and exit",
+				Aliases:            []string{"h"},
+				DisableDefaultText: true,
+			},
+		),
+		Action: func(c *cli.Context) error {
+			if c.Bool("help") {
+				utils.Check(cli.ShowCommandHelp(c, c.Command.Name))
+				return nil
+			}
+
+			outType := c.String("output")
+			outFunc, ok := outTypes[outType]
+			if !ok {
+				return fmt.Errorf("%w: invalid output type: %v", ErrFlagParse, outType)
+			}
+
+			var results [][]*outTODO
+			for _, path := range c.Args().Slice() {
+				r, err := readResultsFile(path)
+				if err != nil {
+					return err
+				}
+				results = append(results, r)
+			}
+
+			h, err := redactFromContext(c, outFunc(c.App.Writer))
+			if err != nil {
+				return err
+			}
+			for _, o := range mergeResults(results...) {
+				if err := h(o.ref()); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// ref converts the TODO to a walker.TODORef.
+func (o *outTODO) ref() *walker.TODORef {
+	r := &walker.TODORef{
+		FileName: o.Path,
+		TODO: &todos.TODO{
+			Type:        o.Type,
+			Text:        o.Text,
+			Label:       o.Label,
+			Message:     o.Message,
+			Attributes:  o.Attributes,
+			Line:        o.Line,
+			CommentLine: o.CommentLine,
+			EndLine:     o.EndLine,
+
+			CommentOffset:    o.CommentOffset,
+			CommentEndOffset: o.CommentEndOffset,
+			CanonicalLabel:   o.CanonicalLabel,
+			ReferenceKind:    todos.ReferenceKind(o.ReferenceKind),
+		},
+		Fingerprint: o.Fingerprint,
+		Owners:      o.Owners,
+	}
+	if o.GitUser != nil {
+		r.GitUser = &walker.GitUser{
+			Name:  o.GitUser.Name,
+			Email: o.GitUser.Email,
+		}
+	}
+	return r
+}
+
+// readResultsFile reads TODOs from a file with JSON output. If path is "-"
+// then results are read from stdin.
+func readResultsFile(path string) ([]*outTODO, error) {
+	if path == "-" {
+		return readResults(os.Stdin)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errReadResults, err)
+	}
+	defer f.Close()
+
+	r, err := readResults(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+// readResults reads TODOs in the JSON output format.
+func readResults(r io.Reader) ([]*outTODO, error) {
+	var results []*outTODO
+	d := json.NewDecoder(r)
+	for {
+		var o outTODO
+		err := d.Decode(&o)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errReadResults, err)
+		}
+		results = append(results, &o)
+	}
+	return results, nil
+}
+
+// mergeResults merges the results and removes duplicate TODOs with the same
+// path, line, and text. The results are sorted by path and line.
+func mergeResults(results ...[]*outTODO) []*outTODO {
+	seen := map[string]bool{}
+	var merged []*outTODO
+	for _, r := range results {
+		for _, o := range r {
+			key := o.Path + "\x00" + strconv.Itoa(o.Line) + "\x00" + o.Text
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, o)
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].Path != merged[j].Path {
+			return merged[i].Path < merged[j].Path
+		}
+		return merged[i].Line < merged[j].Line
+	})
+
+	return merged
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/todos/internal/testutils"
+)
+
+func Test_mergeResults(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		results  [][]*outTODO
+		expected []*outTODO
+	}{
+		"empty": {
+			results:  nil,
+			expected: nil,
+		},
+		"duplicates": {
+			results: [][]*outTODO{
+				{
+					{Path: "b.go", Line: 1, Text: "// TODO: b"},
+					{Path: "a.go", Line: 2, Text: "// TODO: a2"},
+				},
+				{
+					{Path: "a.go", Line: 2, Text: "// TODO: a2"},
+					{Path: "a.go", Line: 1, Text: "// TODO: a1"},
+				},
+			},
+			expected: []*outTODO{
+				{Path: "a.go", Line: 1, Text: "// TODO: a1"},
+				{Path: "a.go", Line: 2, Text: "// TODO: a2"},
+				{Path: "b.go", Line: 1, Text: "// TODO: b"},
+			},
+		},
+		"same line different text": {
+			results: [][]*outTODO{
+				{
+					{Path: "a.go", Line: 1, Text: "// TODO: a"},
+				},
+				{
+					{Path: "a.go", Line: 1, Text: "// TODO: b"},
+				},
+			},
+			expected: []*outTODO{
+				{Path: "a.go", Line: 1, Text: "// TODO: a"},
+				{Path: "a.go", Line: 1, Text: "// TODO: b"},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tc.expected, mergeResults(tc.results...)); diff != "" {
+				t.Errorf("unexpected results (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_TODOsApp_merge(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path: "a.json",
+			Contents: []byte(`{"path":"b.go","type":"TODO","text":"// TODO: b","label":"","message":"b","line":1,"comment_line":1}
+{"path":"a.go","type":"TODO","text":"// TODO: a","label":"","message":"a","line":3,"comment_line":3}
+`),
+			Mode: 0o600,
+		},
+		{
+			Path: "b.json",
+			Contents: []byte(`{"path":"a.go","type":"TODO","text":"// TODO: a","label":"","message":"a","line":3,"comment_line":3}
+`),
+			Mode: 0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	app := newTODOsApp()
+	var b strings.Builder
+	app.Writer = &b
+	if err := app.Run([]string{
+		"todos",
+		"merge",
+		"--output=json",
+		filepath.Join(d.Dir(), "a.json"),
+		filepath.Join(d.Dir(), "b.json"),
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `{"path":"a.go","type":"TODO","text":"// TODO: a","label":"","message":"a","line":3,"comment_line":3,"comment_offset":0}
+{"path":"b.go","type":"TODO","text":"// TODO: b","label":"","message":"b","line":1,"comment_line":1,"comment_offset":0}
+`
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("unexpected output (-want, +got): \n%s", diff)
+	}
+}
+
+func Test_TODOsApp_merge_invalid(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "a.json",
+			Contents: []byte("not json"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	app := newTODOsApp()
+	app.ExitErrHandler = func(*cli.Context, error) {}
+	err := app.Run([]string{"todos", "merge", filepath.Join(d.Dir(), "a.json")})
+	if !errors.Is(err, errReadResults) {
+		t.Errorf("unexpected error, got: %v, want: %v", err, errReadResults)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+var errNoMatch = errors.New("no paths match")
+
+// globMeta are the characters that make a path argument a glob pattern.
+const globMeta = "*?[{"
+
+// expandPaths expands path arguments that are glob patterns into the paths
+// that match them. This allows globs to be used with shells that don't expand
+// them, such as Windows cmd. Arguments that are not glob patterns or that
+// exist as is are returned unchanged.
+func expandPaths(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		if !strings.ContainsAny(arg, globMeta) {
+			paths = append(paths, arg)
+			continue
+		}
+		if _, err := os.Lstat(arg); err == nil {
+			paths = append(paths, arg)
+			continue
+		}
+
+		matches, err := expandGlob(arg)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("%w: %q", errNoMatch, arg)
+		}
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
+
+// expandGlob returns the paths that match the glob pattern in lexical order.
+// A "**" path element matches zero or more directories. Like most shells,
+// wildcards do not match hidden files and directories.
+func expandGlob(pattern string) ([]string, error) {
+	pattern = path.Clean(filepath.ToSlash(pattern))
+
+	g, err := compilePathGlob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	root := globRoot(pattern)
+	var matches []string
+	err = filepath.WalkDir(filepath.FromSlash(root), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// NOTE: Paths that can't be read don't match.
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if p == filepath.FromSlash(root) {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if g.match(strings.Split(filepath.ToSlash(p), "/")) {
+			matches = append(matches, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("expanding glob %q: %w", pattern, err)
+	}
+	return matches, nil
+}
+
+// globRoot returns the directory made up of the leading path elements of the
+// slash separated glob pattern that contain no glob characters.
+func globRoot(pattern string) string {
+	elems := strings.Split(pattern, "/")
+	var root []string
+	for _, e := range elems[:len(elems)-1] {
+		if strings.ContainsAny(e, globMeta) {
+			break
+		}
+		root = append(root, e)
+	}
+	switch {
+	case len(root) == 0:
+		return "."
+	case len(root) == 1 && root[0] == "":
+		// The pattern is an absolute path.
+		return "/"
+	}
+	return strings.Join(root, "/")
+}
+
+// pathGlob is a glob pattern matched against slash separated path elements.
+// A nil element is a "**" element matching zero or more path elements.
+type pathGlob []glob.Glob
+
+// compilePathGlob compiles the slash separated glob pattern.
+func compilePathGlob(pattern string) (pathGlob, error) {
+	var g pathGlob
+	for _, elem := range strings.Split(pattern, "/") {
+		if elem == "**" {
+			g = append(g, nil)
+			continue
+		}
+		eg, err := glob.Compile(elem, '/')
+		if err != nil {
+			return nil, fmt.Errorf("compiling glob %q: %w", pattern, err)
+		}
+		g = append(g, eg)
+	}
+	return g, nil
+}
+
+// match returns whether the path elements match the glob.
+func (g pathGlob) match(elems []string) bool {
+	if len(g) == 0 {
+		return len(elems) == 0
+	}
+	if g[0] == nil {
+		for i := range len(elems) + 1 {
+			if g[1:].match(elems[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	return len(elems) > 0 && g[0].Match(elems[0]) && g[1:].match(elems[1:])
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/testutils"
+)
+
+func Test_expandPaths(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "main.go",
+			Contents: []byte("// TODO: main"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "src/foo.go",
+			Contents: []byte("// TODO: foo"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "src/foo.py",
+			Contents: []byte("# TODO: foo"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "src/bar/bar.go",
+			Contents: []byte("// TODO: bar"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "src/.hidden/hidden.go",
+			Contents: []byte("// TODO: hidden"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "src/[literal].go",
+			Contents: []byte("// TODO: literal"),
+			Mode:     0o600,
+		},
+	}
+
+	testCases := map[string]struct {
+		args     []string
+		expected []string
+		err      error
+	}{
+		// NOTE: Arguments are relative to the temporary directory and
+		// expected paths are joined with it.
+		"no glob": {
+			args:     []string{"main.go", "src"},
+			expected: []string{"main.go", "src"},
+		},
+		"star": {
+			args: []string{"src/*.go"},
+			expected: []string{
+				filepath.Join("src", "[literal].go"),
+				filepath.Join("src", "foo.go"),
+			},
+		},
+		"double star": {
+			args: []string{"**/*.go"},
+			expected: []string{
+				"main.go",
+				filepath.Join("src", "[literal].go"),
+				filepath.Join("src", "bar", "bar.go"),
+				filepath.Join("src", "foo.go"),
+			},
+		},
+		"alternatives": {
+			args: []string{"src/foo.{go,py}"},
+			expected: []string{
+				filepath.Join("src", "foo.go"),
+				filepath.Join("src", "foo.py"),
+			},
+		},
+		"existing path": {
+			args:     []string{"src/[literal].go"},
+			expected: []string{filepath.Join("src", "[literal].go")},
+		},
+		"no match": {
+			args: []string{"**/*.rs"},
+			err:  errNoMatch,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			d := testutils.NewTempDir(files)
+			defer d.Cleanup()
+
+			args := make([]string, len(tc.args))
+			for i, arg := range tc.args {
+				args[i] = filepath.ToSlash(d.Dir()) + "/" + arg
+			}
+			var expected []string
+			for _, path := range tc.expected {
+				expected = append(expected, filepath.Join(d.Dir(), path))
+			}
+
+			got, err := expandPaths(args)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("unexpected error, got: %v, want: %v", err, tc.err)
+			}
+
+			if diff := cmp.Diff(expected, got); diff != "" {
+				t.Errorf("unexpected paths (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	// NOTE: lib/pq reads the standard PGHOST, PGUSER, PGPASSWORD, etc.
+	// environment variables if they are not given in the connection string.
+	_ "github.com/lib/pq"
+)
+
+var errPostgres = errors.New("postgres")
+
+// postgresTableMatch matches table names that can be used without quoting,
+// optionally qualified with a schema.
+var postgresTableMatch = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// postgresSink appends the results of a run to a PostgreSQL table. The
+// connection string is read from DATABASE_URL and falls back to the standard
+// PostgreSQL environment variables.
+type postgresSink struct {
+	*runRows
+
+	// table is the name of the table.
+	table string
+
+	// dsn is the connection string.
+	dsn string
+}
+
+func newPostgresSink(target string) (sink, error) {
+	if !postgresTableMatch.MatchString(target) {
+		return nil, fmt.Errorf("%w: invalid table name %q", errPostgres, target)
+	}
+	r, err := newRunRows()
+	if err != nil {
+		return nil, err
+	}
+	return &postgresSink{
+		runRows: r,
+		table:   target,
+		dsn:     os.Getenv("DATABASE_URL"),
+	}, nil
+}
+
+// Close implements sink.Close.
+func (s *postgresSink) Close(walkErr bool) error {
+	db, err := sql.Open("postgres", s.dsn)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errPostgres, err)
+	}
+	defer db.Close()
+
+	if err := s.write(db, !walkErr); err != nil {
+		return fmt.Errorf("%w: %s: %w", errPostgres, s.table, err)
+	}
+	return nil
+}
+
+// write creates the table if it doesn't exist and appends the rows of the
+// run in a single transaction.
+func (s *postgresSink) write(db *sql.DB, complete bool) error {
+	if _, err := db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	run_id TEXT NOT NULL,
+	start_time TIMESTAMPTZ NOT NULL,
+	version TEXT NOT NULL,
+	complete BOOLEAN NOT NULL,
+	path TEXT NOT NULL,
+	type TEXT NOT NULL,
+	label TEXT NOT NULL,
+	message TEXT NOT NULL,
+	text TEXT NOT NULL,
+	line INTEGER NOT NULL,
+	fingerprint TEXT NOT NULL,
+	author_name TEXT,
+	author_email TEXT
+)`, s.table)); err != nil {
+		return fmt.Errorf("creating table: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	// NOTE: Rollback does nothing after the transaction is committed.
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	stmt, err := tx.Prepare(fmt.Sprintf(`INSERT INTO %s
+	(run_id, start_time, version, complete, path, type, label, message, text, line, fingerprint, author_name, author_email)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''), NULLIF($13, ''))`, s.table))
+	if err != nil {
+		return fmt.Errorf("preparing insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, r := range s.rows(complete) {
+		if _, err := stmt.Exec(
+			r.RunID, r.StartTime, r.Version, r.Complete, r.Path, r.Type, r.Label,
+			r.Message, r.Text, r.Line, r.Fingerprint, r.AuthorName, r.AuthorEmail,
+		); err != nil {
+			return fmt.Errorf("inserting row: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/ianlewis/todos/internal/todos"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+var errFakeDB = errors.New("fake database error")
+
+// fakeDB is a database/sql driver that records the statements that are
+// executed. Statements that contain failOn fail with errFakeDB.
+type fakeDB struct {
+	failOn    string
+	execs     []string
+	args      [][]driver.Value
+	committed bool
+	rolled    bool
+}
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: db}, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return nil }
+
+func (db *fakeDB) fail(query string) error {
+	if db.failOn != "" && strings.Contains(query, db.failOn) {
+		return errFakeDB
+	}
+	return nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	if err := c.db.fail("PREPARE " + query); err != nil {
+		return nil, err
+	}
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return &fakeTx{db: c.db}, nil }
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if err := s.db.fail(s.query); err != nil {
+		return nil, err
+	}
+	s.db.execs = append(s.db.execs, s.query)
+	s.db.args = append(s.db.args, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, fmt.Errorf("%w: query not supported", errFakeDB)
+}
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (tx *fakeTx) Commit() error {
+	if err := tx.db.fail("COMMIT"); err != nil {
+		return err
+	}
+	tx.db.committed = true
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.db.rolled = true
+	return nil
+}
+
+func Test_newPostgresSink(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		table string
+		err   error
+	}{
+		"table": {
+			table: "todos",
+		},
+		"schema": {
+			table: "public.todos_2",
+		},
+		"underscore": {
+			table: "_todos",
+		},
+		"injection": {
+			table: "todos; DROP TABLE x",
+			err:   errPostgres,
+		},
+		"leading digit": {
+			table: "1todos",
+			err:   errPostgres,
+		},
+		"too many parts": {
+			table: "db.public.todos",
+			err:   errPostgres,
+		},
+		"quoted": {
+			table: `"todos"`,
+			err:   errPostgres,
+		},
+		"empty part": {
+			table: "public.",
+			err:   errPostgres,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newPostgresSink(tc.table)
+			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("unexpected error (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_postgresSink_write(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		failOn    string
+		complete  bool
+		execs     int
+		committed bool
+		err       error
+	}{
+		"complete": {
+			complete:  true,
+			execs:     3,
+			committed: true,
+		},
+		"incomplete": {
+			execs:     3,
+			committed: true,
+		},
+		"create table error": {
+			failOn: "CREATE TABLE",
+			err:    errFakeDB,
+		},
+		"prepare error": {
+			failOn: "PREPARE INSERT",
+			execs:  1,
+			err:    errFakeDB,
+		},
+		"insert error": {
+			failOn: "INSERT INTO",
+			execs:  1,
+			err:    errFakeDB,
+		},
+		"commit error": {
+			failOn: "COMMIT",
+			execs:  3,
+			err:    errFakeDB,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := newRunRows()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			s := &postgresSink{
+				runRows: r,
+				table:   "public.todos",
+			}
+			for _, ref := range []*walker.TODORef{
+				{
+					FileName:    "a.go",
+					TODO:        &todos.TODO{Type: "TODO", Text: "// TODO(alice): a", Label: "alice", Message: "a", Line: 1},
+					GitUser:     &walker.GitUser{Name: "Alice", Email: "alice@example.com"},
+					Fingerprint: "fa",
+				},
+				{
+					FileName:    "b.go",
+					TODO:        &todos.TODO{Type: "FIXME", Text: "// FIXME: b", Message: "b", Line: 2},
+					Fingerprint: "fb",
+				},
+			} {
+				if err := s.Handle(ref); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			fake := &fakeDB{failOn: tc.failOn}
+			db := sql.OpenDB(fake)
+			defer db.Close()
+
+			err = s.write(db, tc.complete)
+			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Fatalf("unexpected error (-want, +got): \n%s", diff)
+			}
+			if got, want := len(fake.execs), tc.execs; got != want {
+				t.Fatalf("unexpected # of statements, got: %v, want: %v\n%v", got, want, fake.execs)
+			}
+			if got, want := fake.committed, tc.committed; got != want {
+				t.Errorf("unexpected commit, got: %v, want: %v", got, want)
+			}
+			if err != nil {
+				return
+			}
+
+			if !strings.HasPrefix(fake.execs[0], "CREATE TABLE IF NOT EXISTS public.todos (") {
+				t.Errorf("unexpected create statement: %q", fake.execs[0])
+			}
+			for _, q := range fake.execs[1:] {
+				if !strings.HasPrefix(q, "INSERT INTO public.todos\n") {
+					t.Errorf("unexpected insert statement: %q", q)
+				}
+			}
+
+			want := [][]driver.Value{
+				{r.id, r.start.Format("2006-01-02T15:04:05Z07:00"), s.rows(tc.complete)[0].Version, tc.complete,
+					"a.go", "TODO", "alice", "a", "// TODO(alice): a", int64(1), "fa", "Alice", "alice@example.com"},
+				{r.id, r.start.Format("2006-01-02T15:04:05Z07:00"), s.rows(tc.complete)[1].Version, tc.complete,
+					"b.go", "FIXME", "", "b", "// FIXME: b", int64(2), "fb", "", ""},
+			}
+			if diff := cmp.Diff(want, fake.args[1:]); diff != "" {
+				t.Errorf("unexpected rows (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
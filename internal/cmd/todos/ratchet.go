@@ -0,0 +1,181 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+var errRatchet = errors.New("ratchet")
+
+// ErrRatchet is returned when the number of TODOs increased since the
+// ratchet state was recorded.
+var ErrRatchet = fmt.Errorf("%w: TODO count increased", errRatchet)
+
+// ratchetState is the number of TODOs of each type in each directory.
+type ratchetState struct {
+	// Counts maps directories to the number of TODOs of each type in them.
+	Counts map[string]map[string]int `json:"counts"`
+}
+
+// ratchet counts the TODOs found in a run so that they can be compared with
+// the counts recorded by a previous run.
+type ratchet struct {
+	// path is the path to the state file.
+	path string
+
+	// state are the counts for the current run.
+	state *ratchetState
+}
+
+// ratchetFromContext returns a new ratchet if the ratchet flag is set. The
+// walker options' TODO handler is wrapped to count TODOs.
+func ratchetFromContext(c *cli.Context, o *walker.Options) (*ratchet, error) {
+	p := c.String("ratchet")
+	if p == "" {
+		return nil, nil
+	}
+	// NOTE: The counts of a shard don't include TODOs in the other shards.
+	if o.ShardCount > 1 {
+		return nil, fmt.Errorf("%w: ratchet cannot be used with shard", ErrFlagParse)
+	}
+
+	r := &ratchet{
+		path:  p,
+		state: &ratchetState{Counts: map[string]map[string]int{}},
+	}
+	todoFunc := o.TODOFunc
+	o.TODOFunc = func(ref *walker.TODORef) error {
+		if ref != nil {
+			dir := filepath.ToSlash(filepath.Dir(ref.FileName))
+			if r.state.Counts[dir] == nil {
+				r.state.Counts[dir] = map[string]int{}
+			}
+			r.state.Counts[dir][ref.TODO.Type]++
+		}
+		if todoFunc == nil {
+			return nil
+		}
+		return todoFunc(ref)
+	}
+	return r, nil
+}
+
+// check compares the counts with the state file and writes each count that
+// increased to w. It returns ErrRatchet if any count increased. Otherwise
+// the state file is updated if the counts changed.
+func (r *ratchet) check(w io.Writer, name string) error {
+	if r == nil {
+		return nil
+	}
+
+	prev, err := readRatchetState(r.path)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: The first run records the counts.
+	if prev == nil {
+		return writeRatchetState(r.path, r.state)
+	}
+
+	increased := false
+	changed := false
+	for _, dir := range sortedKeys(r.state.Counts) {
+		for _, typ := range sortedKeys(r.state.Counts[dir]) {
+			n := r.state.Counts[dir][typ]
+			old := prev.Counts[dir][typ]
+			if n > old {
+				increased = true
+				if _, err := fmt.Fprintf(w, "%s: ratchet: %s: %s increased from %d to %d\n", name, dir, typ, old, n); err != nil {
+					return fmt.Errorf("%w: %w", errRatchet, err)
+				}
+			}
+			if n != old {
+				changed = true
+			}
+		}
+	}
+	if increased {
+		return ErrRatchet
+	}
+
+	// Counts for types and directories that no longer have TODOs also
+	// decreased.
+	for dir, counts := range prev.Counts {
+		for typ, old := range counts {
+			if old > 0 && r.state.Counts[dir][typ] == 0 {
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return writeRatchetState(r.path, r.state)
+}
+
+// sortedKeys returns the keys of the map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// readRatchetState reads the ratchet state from the file at path. It returns
+// nil if the file does not exist.
+func readRatchetState(path string) (*ratchetState, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: %w", errRatchet, err)
+	}
+
+	var s ratchetState
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", errRatchet, path, err)
+	}
+	return &s, nil
+}
+
+// writeRatchetState writes the ratchet state to the file at path.
+func writeRatchetState(path string, s *ratchetState) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %w", errRatchet, err)
+	}
+	b = append(b, '\n')
+
+	//nolint:gosec // G306: Expect WriteFile permissions to be 0600 or less.
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		return fmt.Errorf("%w: %w", errRatchet, err)
+	}
+	return nil
+}
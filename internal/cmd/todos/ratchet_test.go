@@ -0,0 +1,180 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/todos/internal/testutils"
+)
+
+func Test_ratchet_check(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		prev   *ratchetState
+		counts map[string]map[string]int
+
+		expected      string
+		expectedState *ratchetState
+		err           error
+	}{
+		"first run": {
+			counts: map[string]map[string]int{
+				".": {"TODO": 1},
+			},
+			expectedState: &ratchetState{Counts: map[string]map[string]int{
+				".": {"TODO": 1},
+			}},
+		},
+		"unchanged": {
+			prev: &ratchetState{Counts: map[string]map[string]int{
+				".": {"TODO": 1},
+			}},
+			counts: map[string]map[string]int{
+				".": {"TODO": 1},
+			},
+			expectedState: &ratchetState{Counts: map[string]map[string]int{
+				".": {"TODO": 1},
+			}},
+		},
+		"increased": {
+			prev: &ratchetState{Counts: map[string]map[string]int{
+				".":   {"TODO": 1},
+				"sub": {"TODO": 2},
+			}},
+			counts: map[string]map[string]int{
+				".":   {"TODO": 2, "FIXME": 1},
+				"sub": {"TODO": 1},
+			},
+			expected: "todos: ratchet: .: FIXME increased from 0 to 1\n" +
+				"todos: ratchet: .: TODO increased from 1 to 2\n",
+			// NOTE: The state is not updated.
+			expectedState: &ratchetState{Counts: map[string]map[string]int{
+				".":   {"TODO": 1},
+				"sub": {"TODO": 2},
+			}},
+			err: ErrRatchet,
+		},
+		"decreased": {
+			prev: &ratchetState{Counts: map[string]map[string]int{
+				".":   {"TODO": 2},
+				"sub": {"TODO": 2},
+			}},
+			counts: map[string]map[string]int{
+				".": {"TODO": 1},
+			},
+			expectedState: &ratchetState{Counts: map[string]map[string]int{
+				".": {"TODO": 1},
+			}},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "ratchet.json")
+			if tc.prev != nil {
+				testutils.Check(writeRatchetState(path, tc.prev))
+			}
+
+			r := &ratchet{
+				path:  path,
+				state: &ratchetState{Counts: tc.counts},
+			}
+			var b strings.Builder
+			err := r.check(&b, "todos")
+			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Fatalf("unexpected error (-want, +got): \n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expected, b.String()); diff != "" {
+				t.Errorf("unexpected output (-want, +got): \n%s", diff)
+			}
+
+			var got ratchetState
+			testutils.Check(json.Unmarshal(testutils.Must(os.ReadFile(path)), &got))
+			if diff := cmp.Diff(tc.expectedState, &got); diff != "" {
+				t.Errorf("unexpected state (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_TODOsApp_ratchet(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "foo.go",
+			Contents: []byte("// TODO: foo\n"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	path := filepath.Join(d.Dir(), "ratchet.json")
+	run := func() error {
+		app := newTODOsApp()
+		app.Writer = &strings.Builder{}
+		app.ErrWriter = &strings.Builder{}
+		app.ExitErrHandler = nil
+		return app.Run([]string{"todos", "--ratchet", path, d.Dir()})
+	}
+
+	if err := run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testutils.Check(os.WriteFile(filepath.Join(d.Dir(), "bar.go"), []byte("// TODO: bar\n"), 0o600))
+	if diff := cmp.Diff(ErrRatchet, run(), cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("unexpected error (-want, +got): \n%s", diff)
+	}
+}
+
+//nolint:paralleltest // modifies cli.OsExiter
+func Test_TODOsApp_ExitErrHandler_ErrRatchet(t *testing.T) {
+	oldExiter := cli.OsExiter
+	var exitCode *int
+	cli.OsExiter = func(c int) {
+		exitCode = &c
+	}
+	defer func() {
+		cli.OsExiter = oldExiter
+	}()
+
+	app := newTODOsApp()
+	var b strings.Builder
+	app.ErrWriter = &b
+	c := newContext(app, nil)
+	app.ExitErrHandler(c, ErrRatchet)
+
+	if exitCode == nil {
+		t.Fatalf("unexpected exit code, want: %v, got: %v", ExitCodeRatchetError, exitCode)
+	}
+	if diff := cmp.Diff(ExitCodeRatchetError, *exitCode); diff != "" {
+		t.Errorf("unexpected exit code (-want, +got): \n%s", diff)
+	}
+}
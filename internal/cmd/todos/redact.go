@@ -0,0 +1,108 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+var errRedact = errors.New("invalid redact option")
+
+// redactFields are the fields that can be redacted.
+var redactFields = map[string]bool{
+	"author": true,
+	"email":  true,
+}
+
+// redactModes are the supported ways to redact fields.
+var redactModes = map[string]func(string) string{
+	// hash replaces values with a short hash so that TODOs by the same user
+	// can still be grouped.
+	"hash": func(s string) string {
+		if s == "" {
+			return ""
+		}
+		h := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(h[:8])
+	},
+	"drop": func(string) string {
+		return ""
+	},
+}
+
+// redactFlags returns the flags for redacting output.
+func redactFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "redact",
+			Usage: "comma separated list of `FIELDS` to redact from output (author, email)",
+		},
+		&cli.StringFlag{
+			Name:  "redact-mode",
+			Usage: "redact fields using `MODE` (hash, drop)",
+			Value: "hash",
+		},
+	}
+}
+
+// redactFromContext wraps the handler so that the fields given by the redact
+// flags are redacted before output.
+func redactFromContext(c *cli.Context, h walker.TODOHandler) (walker.TODOHandler, error) {
+	fieldsStr := c.String("redact")
+	if fieldsStr == "" {
+		return h, nil
+	}
+
+	modeStr := c.String("redact-mode")
+	mode, ok := redactModes[modeStr]
+	if !ok {
+		return nil, fmt.Errorf("%w: %w: redact-mode: %q", ErrFlagParse, errRedact, modeStr)
+	}
+
+	fields := map[string]bool{}
+	for _, f := range strings.Split(fieldsStr, ",") {
+		f = strings.TrimSpace(f)
+		if !redactFields[f] {
+			return nil, fmt.Errorf("%w: %w: redact: unknown field %q", ErrFlagParse, errRedact, f)
+		}
+		fields[f] = true
+	}
+
+	return func(r *walker.TODORef) error {
+		if r == nil || r.GitUser == nil {
+			return h(r)
+		}
+
+		// NOTE: Copy the ref so that the original is not modified.
+		redacted := *r
+		u := *r.GitUser
+		if fields["author"] {
+			u.Name = mode(u.Name)
+		}
+		if fields["email"] {
+			u.Email = mode(u.Email)
+		}
+		redacted.GitUser = &u
+		return h(&redacted)
+	}, nil
+}
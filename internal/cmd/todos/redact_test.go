@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/ianlewis/todos/internal/todos"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+func Test_redactFromContext(t *testing.T) {
+	t.Parallel()
+
+	ref := &walker.TODORef{
+		FileName: "foo.go",
+		TODO: &todos.TODO{
+			Type: "TODO",
+			Line: 16,
+			Text: "// TODO: this is a message",
+		},
+		GitUser: &walker.GitUser{
+			Name:  "John Doe",
+			Email: "john@doe.com",
+		},
+	}
+
+	testCases := map[string]struct {
+		args     []string
+		expected *walker.GitUser
+		err      error
+	}{
+		"no redact": {
+			args: nil,
+			expected: &walker.GitUser{
+				Name:  "John Doe",
+				Email: "john@doe.com",
+			},
+		},
+		"hash email": {
+			args: []string{"--redact=email"},
+			expected: &walker.GitUser{
+				Name:  "John Doe",
+				Email: redactModes["hash"]("john@doe.com"),
+			},
+		},
+		"drop email and author": {
+			args: []string{"--redact=email,author", "--redact-mode=drop"},
+			expected: &walker.GitUser{
+				Name:  "",
+				Email: "",
+			},
+		},
+		"unknown field": {
+			args: []string{"--redact=foo"},
+			err:  ErrFlagParse,
+		},
+		"unknown mode": {
+			args: []string{"--redact=email", "--redact-mode=foo"},
+			err:  ErrFlagParse,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			app := newTODOsApp()
+			c := newContext(app, tc.args)
+
+			var got *walker.TODORef
+			h, err := redactFromContext(c, func(r *walker.TODORef) error {
+				got = r
+				return nil
+			})
+			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Fatalf("unexpected error (-want, +got): \n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+
+			if err := h(ref); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, got.GitUser); diff != "" {
+				t.Errorf("unexpected user (-want, +got): \n%s", diff)
+			}
+			// The original ref should not be modified.
+			if got, want := ref.GitUser.Email, "john@doe.com"; got != want {
+				t.Errorf("original ref modified, got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
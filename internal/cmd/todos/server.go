@@ -0,0 +1,557 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/todos/internal/scanner"
+	"github.com/ianlewis/todos/internal/todos"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+// JSON-RPC 2.0 error codes.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+
+	// rpcLimitExceeded is a server defined error code for requests that
+	// exceed one of the server's limits.
+	rpcLimitExceeded = -32000
+)
+
+var (
+	errScanTime = errors.New("scan time limit exceeded")
+	errResults  = errors.New("result limit exceeded")
+)
+
+// rpcRequest is a JSON-RPC 2.0 request. Requests without an ID are
+// notifications and are not answered.
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcResponse is a JSON-RPC 2.0 response.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC 2.0 error object.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// serverLanguage is a language returned by the listLanguages method.
+type serverLanguage struct {
+	// Name is the language name that can be passed to scanText.
+	Name string `json:"name"`
+
+	// Extensions are the file extensions that can be detected as the
+	// language.
+	Extensions []string `json:"extensions,omitempty"`
+}
+
+// serverLimits are the limits that allow the server to be run as a shared
+// service. Zero values mean no limit.
+type serverLimits struct {
+	// MaxRequestSize is the maximum size of a request line in bytes.
+	MaxRequestSize int64
+
+	// MaxScanTime is the maximum time a single request may spend scanning.
+	MaxScanTime time.Duration
+
+	// MaxResults is the maximum number of TODOs a single request may return.
+	MaxResults int
+
+	// MaxConcurrent is the maximum number of requests handled at the same
+	// time. Requests are handled one at a time in order if it is one or less.
+	MaxConcurrent int
+}
+
+// serverLimitsFromContext returns the server limits given by the command
+// line flags.
+func serverLimitsFromContext(c *cli.Context) (serverLimits, error) {
+	var l serverLimits
+	if size := c.String("server-max-request-size"); size != "" {
+		n, err := parseSize(size)
+		if err != nil {
+			return l, fmt.Errorf("%w: server-max-request-size: %w", ErrFlagParse, err)
+		}
+		l.MaxRequestSize = n
+	}
+	l.MaxScanTime = c.Duration("server-timeout")
+	l.MaxResults = c.Int("server-max-results")
+	l.MaxConcurrent = c.Int("server-max-concurrent")
+	if l.MaxScanTime < 0 || l.MaxResults < 0 || l.MaxConcurrent < 0 {
+		return l, fmt.Errorf("%w: server limits must not be negative", ErrFlagParse)
+	}
+	return l, nil
+}
+
+// serverComment is a comment returned by the commentAt method.
+type serverComment struct {
+	// Text is the full text of the comment.
+	Text string `json:"text"`
+
+	// Line is the line where the comment starts.
+	Line int `json:"line"`
+
+	// EndLine is the line where the comment ends.
+	EndLine int `json:"end_line"`
+}
+
+// server answers JSON-RPC requests from editor integrations so that they can
+// keep a single process running rather than starting one for each scan.
+type server struct {
+	// opts are the walker options used for scanning files.
+	opts *walker.Options
+
+	// limits are the per-request limits.
+	limits serverLimits
+}
+
+// serverMethods are the methods supported by the server.
+var serverMethods = map[string]func(*server, json.RawMessage) (any, *rpcError){
+	"scanFile":      (*server).scanFile,
+	"scanText":      (*server).scanText,
+	"listLanguages": (*server).listLanguages,
+	"commentAt":     (*server).commentAt,
+}
+
+// serve reads requests, or batches of requests, from r one per line and
+// writes the responses to w one per line until r is closed. Up to
+// limits.MaxConcurrent requests are handled at the same time and responses
+// may then be written in a different order than the requests were read.
+func serve(r io.Reader, w io.Writer, opts *walker.Options, limits serverLimits) error {
+	s := &server{opts: opts, limits: limits}
+	br := bufio.NewReader(r)
+	enc := json.NewEncoder(w)
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		encErr error
+	)
+	sem := make(chan struct{}, max(1, limits.MaxConcurrent))
+	respond := func(resp any) {
+		mu.Lock()
+		defer mu.Unlock()
+		if resp != nil && encErr == nil {
+			if err := enc.Encode(resp); err != nil {
+				encErr = fmt.Errorf("server: %w", err)
+			}
+		}
+	}
+
+	var err error
+	for err == nil {
+		var line []byte
+		var tooLarge bool
+		line, tooLarge, err = readLine(br, limits.MaxRequestSize)
+		if tooLarge {
+			respond(newRPCErrorResponse(nil, rpcLimitExceeded,
+				fmt.Sprintf("request exceeds %d bytes", limits.MaxRequestSize)))
+			continue
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			respond(s.handleLine(line))
+		}()
+	}
+	wg.Wait()
+
+	if encErr != nil {
+		return encErr
+	}
+	if !errors.Is(err, io.EOF) {
+		return fmt.Errorf("server: %w", err)
+	}
+	return nil
+}
+
+// readLine reads a line from br. If the line is longer than maxSize bytes,
+// and maxSize is greater than zero, the rest of the line is discarded and
+// tooLarge is true.
+func readLine(br *bufio.Reader, maxSize int64) ([]byte, bool, error) {
+	var line []byte
+	tooLarge := false
+	for {
+		chunk, err := br.ReadSlice('\n')
+		if !tooLarge {
+			line = append(line, chunk...)
+			if maxSize > 0 && int64(len(bytes.TrimRight(line, "\r\n"))) > maxSize {
+				tooLarge = true
+				line = nil
+			}
+		}
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return line, tooLarge, err
+		}
+	}
+}
+
+// handleLine handles a single request or a batch of requests. It returns nil
+// if there is nothing to respond with.
+func (s *server) handleLine(line []byte) any {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil
+	}
+	if line[0] != '[' {
+		if resp := s.handle(line); resp != nil {
+			return resp
+		}
+		return nil
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(line, &batch); err != nil {
+		return newRPCErrorResponse(nil, rpcParseError, err.Error())
+	}
+	if len(batch) == 0 {
+		return newRPCErrorResponse(nil, rpcInvalidRequest, "empty batch")
+	}
+	var resps []*rpcResponse
+	for _, raw := range batch {
+		if resp := s.handle(raw); resp != nil {
+			resps = append(resps, resp)
+		}
+	}
+	if len(resps) == 0 {
+		return nil
+	}
+	return resps
+}
+
+// handle handles a single request. It returns nil for notifications.
+func (s *server) handle(raw []byte) *rpcResponse {
+	var req rpcRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return newRPCErrorResponse(nil, rpcParseError, err.Error())
+		}
+		return newRPCErrorResponse(nil, rpcInvalidRequest, err.Error())
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return newRPCErrorResponse(req.ID, rpcInvalidRequest, "invalid JSON-RPC 2.0 request")
+	}
+
+	var result any
+	var rerr *rpcError
+	if m, ok := serverMethods[req.Method]; ok {
+		result, rerr = m(s, req.Params)
+	} else {
+		rerr = &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+	}
+
+	if len(req.ID) == 0 {
+		return nil
+	}
+	if rerr != nil {
+		return &rpcResponse{JSONRPC: "2.0", ID: req.ID, Error: rerr}
+	}
+	return &rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+// newRPCErrorResponse returns an error response for the request with the
+// given ID.
+func newRPCErrorResponse(id json.RawMessage, code int, msg string) *rpcResponse {
+	return &rpcResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   &rpcError{Code: code, Message: msg},
+	}
+}
+
+// decodeParams decodes the request params into v.
+func decodeParams(params json.RawMessage, v any) *rpcError {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// scanFile scans the file at the path given in the params with the same
+// options as the command line and returns the TODOs found.
+func (s *server) scanFile(params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		Path string `json:"path"`
+	}
+	if rerr := decodeParams(params, &p); rerr != nil {
+		return nil, rerr
+	}
+	if p.Path == "" {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: "path is required"}
+	}
+	// NOTE: Only files can be scanned so that a request can't walk a whole
+	// file system.
+	info, err := os.Stat(p.Path)
+	if err != nil {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+	}
+	if info.IsDir() {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: fmt.Sprintf("path is a directory: %s", p.Path)}
+	}
+
+	// NOTE: The options are copied so that requests don't affect each other.
+	o := *s.opts
+	o.Paths = []string{p.Path}
+
+	// NOTE: The walk is stopped when a limit is exceeded.
+	deadline := s.deadline()
+	o.Deadline = deadline
+	found := []*outTODO{}
+	var limitErr error
+	stop := func() error {
+		if limitErr = s.checkLimits(deadline, len(found)); limitErr != nil {
+			return fs.SkipAll
+		}
+		return nil
+	}
+	o.TODOFunc = func(r *walker.TODORef) error {
+		if r != nil {
+			found = append(found, newOutTODO(r))
+		}
+		return stop()
+	}
+	fileFunc := o.FileFunc
+	o.FileFunc = func(r *walker.FileRef) error {
+		if err := stop(); err != nil {
+			return err
+		}
+		if fileFunc == nil {
+			return nil
+		}
+		return fileFunc(r)
+	}
+	var errs []string
+	o.ErrorFunc = func(err error) error {
+		if errors.Is(err, walker.ErrDeadline) {
+			limitErr = s.scanTimeError()
+			return fs.SkipAll
+		}
+		errs = append(errs, err.Error())
+		return nil
+	}
+	walker.New(&o).Walk()
+
+	if limitErr != nil {
+		return nil, newLimitError(limitErr)
+	}
+	if len(errs) > 0 {
+		return nil, &rpcError{Code: rpcInternalError, Message: strings.Join(errs, "; ")}
+	}
+	return found, nil
+}
+
+// scanText scans the text given in the params and returns the TODOs found.
+// The language is detected from the path and text unless it is given.
+func (s *server) scanText(params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		Text     string `json:"text"`
+		Path     string `json:"path"`
+		Language string `json:"language"`
+	}
+	if rerr := decodeParams(params, &p); rerr != nil {
+		return nil, rerr
+	}
+	if p.Language != "" {
+		if _, ok := scanner.LanguagesConfig[p.Language]; !ok {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: fmt.Sprintf("unsupported language: %s", p.Language)}
+		}
+	}
+
+	// NOTE: JSON strings are always UTF-8.
+	sc, err := scanner.FromBytesAs(p.Path, []byte(p.Text), "UTF-8", p.Language)
+	if err != nil {
+		return nil, &rpcError{Code: rpcInternalError, Message: err.Error()}
+	}
+	found := []*outTODO{}
+	if sc == nil {
+		return found, nil
+	}
+
+	deadline := s.deadline()
+	var cs todos.CommentScanner = sc
+	if !deadline.IsZero() {
+		cs = walker.WithDeadline(cs, deadline)
+	}
+	t := todos.NewTODOScanner(cs, s.opts.Config)
+	for t.Scan() {
+		found = append(found, newOutTODO(&walker.TODORef{
+			FileName: p.Path,
+			TODO:     t.Next(),
+		}))
+		if err := s.checkLimits(deadline, len(found)); err != nil {
+			return nil, newLimitError(err)
+		}
+	}
+	if err := t.Err(); err != nil {
+		if errors.Is(err, walker.ErrDeadline) {
+			return nil, newLimitError(s.scanTimeError())
+		}
+		return nil, &rpcError{Code: rpcInternalError, Message: err.Error()}
+	}
+	return found, nil
+}
+
+// commentAt returns the comment that contains the line given in the params
+// or null if the line is not part of a comment. The text is scanned if it is
+// given, such as for an unsaved buffer, and otherwise the file at the path is
+// read.
+func (s *server) commentAt(params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		Path     string  `json:"path"`
+		Line     int     `json:"line"`
+		Text     *string `json:"text"`
+		Language string  `json:"language"`
+	}
+	if rerr := decodeParams(params, &p); rerr != nil {
+		return nil, rerr
+	}
+	if p.Line < 1 {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: "line must be at least 1"}
+	}
+	if p.Language != "" {
+		if _, ok := scanner.LanguagesConfig[p.Language]; !ok {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: fmt.Sprintf("unsupported language: %s", p.Language)}
+		}
+	}
+
+	// NOTE: JSON strings are always UTF-8.
+	contents, charset := []byte{}, "UTF-8"
+	if p.Text != nil {
+		contents = []byte(*p.Text)
+	} else {
+		if p.Path == "" {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: "path or text is required"}
+		}
+		b, err := os.ReadFile(p.Path)
+		if err != nil {
+			return nil, &rpcError{Code: rpcInternalError, Message: err.Error()}
+		}
+		contents, charset = b, s.opts.Charset
+	}
+
+	sc, err := scanner.FromBytesAs(p.Path, contents, charset, p.Language)
+	if err != nil {
+		return nil, &rpcError{Code: rpcInternalError, Message: err.Error()}
+	}
+	if sc == nil {
+		return (*serverComment)(nil), nil
+	}
+	c, err := scanner.CommentAt(sc, p.Line)
+	if err != nil {
+		return nil, &rpcError{Code: rpcInternalError, Message: err.Error()}
+	}
+	if c == nil {
+		return (*serverComment)(nil), nil
+	}
+	return &serverComment{
+		Text:    c.Text,
+		Line:    c.Line,
+		EndLine: c.EndLine(),
+	}, nil
+}
+
+// deadline returns the time by which a request starting now must finish
+// scanning or the zero time if there is no scan time limit.
+func (s *server) deadline() time.Time {
+	if s.limits.MaxScanTime <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(s.limits.MaxScanTime)
+}
+
+// checkLimits returns an error if the deadline has passed or n is more than
+// the maximum number of results.
+func (s *server) checkLimits(deadline time.Time, n int) error {
+	if !deadline.IsZero() && time.Now().After(deadline) {
+		return s.scanTimeError()
+	}
+	if s.limits.MaxResults > 0 && n > s.limits.MaxResults {
+		return fmt.Errorf("%w: %d", errResults, s.limits.MaxResults)
+	}
+	return nil
+}
+
+// scanTimeError returns the error for a request that exceeded the scan time
+// limit.
+func (s *server) scanTimeError() error {
+	return fmt.Errorf("%w: %s", errScanTime, s.limits.MaxScanTime)
+}
+
+// newLimitError returns the error object for a request that exceeded a
+// limit.
+func newLimitError(err error) *rpcError {
+	return &rpcError{Code: rpcLimitExceeded, Message: err.Error()}
+}
+
+// listLanguages returns the supported languages sorted by name along with
+// the extensions that are detected as them.
+func (s *server) listLanguages(_ json.RawMessage) (any, *rpcError) {
+	exts := map[string][]string{}
+	for ext, langs := range scanner.ExtensionLanguages() {
+		for _, lang := range langs {
+			exts[lang] = append(exts[lang], ext)
+		}
+	}
+
+	langs := []*serverLanguage{}
+	for name := range scanner.LanguagesConfig {
+		sort.Strings(exts[name])
+		langs = append(langs, &serverLanguage{
+			Name:       name,
+			Extensions: exts[name],
+		})
+	}
+	sort.Slice(langs, func(i, j int) bool {
+		return langs[i].Name < langs[j].Name
+	})
+	return langs, nil
+}
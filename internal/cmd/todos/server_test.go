@@ -0,0 +1,407 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/ianlewis/todos/internal/testutils"
+	"github.com/ianlewis/todos/internal/todos"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+func Test_serve(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		input    string
+		limits   serverLimits
+		expected string
+	}{
+		"scan text": {
+			input:    `{"jsonrpc":"2.0","id":1,"method":"scanText","params":{"text":"// TODO(foo): bar\n","path":"foo.go"}}` + "\n",
+			expected: `{"jsonrpc":"2.0","id":1,"result":[{"path":"foo.go","type":"TODO","text":"// TODO(foo): bar","label":"foo","message":"bar","line":1,"comment_line":1,"comment_offset":0,"comment_end_offset":17}]}` + "\n",
+		},
+		"scan text language": {
+			input:    `{"jsonrpc":"2.0","id":"a","method":"scanText","params":{"text":"# TODO: bar","language":"Python"}}`,
+			expected: `{"jsonrpc":"2.0","id":"a","result":[{"path":"","type":"TODO","text":"# TODO: bar","label":"","message":"bar","line":1,"comment_line":1,"comment_offset":0,"comment_end_offset":11}]}` + "\n",
+		},
+		"scan text unsupported": {
+			input:    `{"jsonrpc":"2.0","id":1,"method":"scanText","params":{"text":"TODO: bar","path":"notes.txt"}}`,
+			expected: `{"jsonrpc":"2.0","id":1,"result":[]}` + "\n",
+		},
+		"unknown language": {
+			input:    `{"jsonrpc":"2.0","id":1,"method":"scanText","params":{"text":"","language":"Foo"}}`,
+			expected: `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"unsupported language: Foo"}}` + "\n",
+		},
+		"scan file no path": {
+			input:    `{"jsonrpc":"2.0","id":1,"method":"scanFile","params":{}}`,
+			expected: `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"path is required"}}` + "\n",
+		},
+		"notification": {
+			input:    `{"jsonrpc":"2.0","method":"listLanguages"}`,
+			expected: "",
+		},
+		"batch": {
+			input:    `[{"jsonrpc":"2.0","id":1,"method":"scanText","params":{"text":"","path":"foo.go"}},{"jsonrpc":"2.0","method":"listLanguages"},{"jsonrpc":"2.0","id":2,"method":"foo"}]` + "\n",
+			expected: `[{"jsonrpc":"2.0","id":1,"result":[]},{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"method not found: foo"}}]` + "\n",
+		},
+		"empty batch": {
+			input:    "[]\n",
+			expected: `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"empty batch"}}` + "\n",
+		},
+		"parse error": {
+			input:    "{foo\n",
+			expected: `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"invalid character 'f' looking for beginning of object key string"}}` + "\n",
+		},
+		"invalid request": {
+			input:    `{"id":1,"method":"listLanguages"}` + "\n",
+			expected: `{"jsonrpc":"2.0","id":1,"error":{"code":-32600,"message":"invalid JSON-RPC 2.0 request"}}` + "\n",
+		},
+		"comment at": {
+			input:    `{"jsonrpc":"2.0","id":1,"method":"commentAt","params":{"text":"package foo\n\n/*\nTODO: bar\n*/\n","path":"foo.go","line":4}}` + "\n",
+			expected: `{"jsonrpc":"2.0","id":1,"result":{"text":"/*\nTODO: bar\n*/","line":3,"end_line":5}}` + "\n",
+		},
+		"comment at code": {
+			input:    `{"jsonrpc":"2.0","id":1,"method":"commentAt","params":{"text":"package foo\n","path":"foo.go","line":1}}` + "\n",
+			expected: `{"jsonrpc":"2.0","id":1,"result":null}` + "\n",
+		},
+		"comment at invalid line": {
+			input:    `{"jsonrpc":"2.0","id":1,"method":"commentAt","params":{"text":"","line":0}}` + "\n",
+			expected: `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"line must be at least 1"}}` + "\n",
+		},
+		"request too large": {
+			input:    `{"jsonrpc":"2.0","id":1,"method":"scanText","params":{"text":"// TODO: bar","path":"foo.go"}}` + "\n" + `{"jsonrpc":"2.0","id":2,"method":"foo"}` + "\n",
+			limits:   serverLimits{MaxRequestSize: 50},
+			expected: `{"jsonrpc":"2.0","id":null,"error":{"code":-32000,"message":"request exceeds 50 bytes"}}` + "\n" + `{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"method not found: foo"}}` + "\n",
+		},
+		"result limit": {
+			input:    `{"jsonrpc":"2.0","id":1,"method":"scanText","params":{"text":"// TODO: foo\n// TODO: bar\n","path":"foo.go"}}` + "\n",
+			limits:   serverLimits{MaxResults: 1},
+			expected: `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"result limit exceeded: 1"}}` + "\n",
+		},
+		"multiple requests": {
+			input:    "\n" + `{"jsonrpc":"2.0","id":1,"method":"foo"}` + "\n" + `{"jsonrpc":"2.0","id":2,"method":"bar"}` + "\n",
+			expected: `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found: foo"}}` + "\n" + `{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"method not found: bar"}}` + "\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := &walker.Options{
+				Config: &todos.Config{
+					Types: []string{"TODO"},
+				},
+				Charset: "UTF-8",
+			}
+
+			var b strings.Builder
+			if err := serve(strings.NewReader(tc.input), &b, opts, tc.limits); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, b.String()); diff != "" {
+				t.Errorf("unexpected output (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_serve_concurrent(t *testing.T) {
+	t.Parallel()
+
+	var input strings.Builder
+	var want []int
+	for i := range 20 {
+		fmt.Fprintf(&input, `{"jsonrpc":"2.0","id":%d,"method":"scanText","params":{"text":"// TODO: foo","path":"foo.go"}}`+"\n", i)
+		want = append(want, i)
+	}
+
+	opts := &walker.Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+	}
+
+	var b strings.Builder
+	if err := serve(strings.NewReader(input.String()), &b, opts, serverLimits{MaxConcurrent: 4}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// NOTE: Responses may be written in any order.
+	var got []int
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		var resp struct {
+			ID     int        `json:"id"`
+			Result []*outTODO `json:"result"`
+		}
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(resp.Result) != 1 {
+			t.Errorf("unexpected result for %d: %v", resp.ID, resp.Result)
+		}
+		got = append(got, resp.ID)
+	}
+	sort.Ints(got)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected IDs (-want, +got): \n%s", diff)
+	}
+}
+
+func Test_server_checkLimits(t *testing.T) {
+	t.Parallel()
+
+	s := &server{
+		limits: serverLimits{
+			MaxScanTime: time.Second,
+			MaxResults:  2,
+		},
+	}
+
+	testCases := map[string]struct {
+		deadline time.Time
+		n        int
+		err      error
+	}{
+		"within limits": {
+			deadline: time.Now().Add(time.Hour),
+			n:        2,
+		},
+		"no deadline": {
+			n: 1,
+		},
+		"deadline passed": {
+			deadline: time.Now().Add(-time.Second),
+			n:        1,
+			err:      errScanTime,
+		},
+		"too many results": {
+			deadline: time.Now().Add(time.Hour),
+			n:        3,
+			err:      errResults,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := s.checkLimits(tc.deadline, tc.n)
+			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("unexpected error (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_server_scanFileResultLimit(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "foo.go",
+			Contents: []byte("// TODO: foo\n// TODO: bar\n"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	s := &server{
+		opts: &walker.Options{
+			Config: &todos.Config{
+				Types: []string{"TODO"},
+			},
+			Charset: "UTF-8",
+		},
+		limits: serverLimits{MaxResults: 1},
+	}
+	params, err := json.Marshal(map[string]string{"path": filepath.Join(d.Dir(), "foo.go")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, rerr := s.scanFile(params)
+	want := &rpcError{Code: rpcLimitExceeded, Message: "result limit exceeded: 1"}
+	if diff := cmp.Diff(want, rerr); diff != "" {
+		t.Errorf("unexpected error (-want, +got): \n%s", diff)
+	}
+}
+
+func Test_server_scanFileDirectory(t *testing.T) {
+	t.Parallel()
+
+	d := testutils.NewTempDir(nil)
+	defer d.Cleanup()
+
+	s := &server{
+		opts: &walker.Options{
+			Config: &todos.Config{
+				Types: []string{"TODO"},
+			},
+			Charset: "UTF-8",
+		},
+	}
+	params, err := json.Marshal(map[string]string{"path": d.Dir()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, rerr := s.scanFile(params)
+	want := &rpcError{Code: rpcInvalidParams, Message: "path is a directory: " + d.Dir()}
+	if diff := cmp.Diff(want, rerr); diff != "" {
+		t.Errorf("unexpected error (-want, +got): \n%s", diff)
+	}
+}
+
+func Test_server_scanTimeLimit(t *testing.T) {
+	t.Parallel()
+
+	// NOTE: The input has many comments but no TODOs so the scan time limit
+	// must be checked while scanning rather than when TODOs are found.
+	text := strings.Repeat("// comment\n", 100000)
+
+	files := []*testutils.File{
+		{
+			Path:     "large.go",
+			Contents: []byte(text),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	t.Cleanup(d.Cleanup)
+
+	s := &server{
+		opts: &walker.Options{
+			Config: &todos.Config{
+				Types: []string{"TODO"},
+			},
+			Charset: "UTF-8",
+		},
+		limits: serverLimits{MaxScanTime: time.Nanosecond},
+	}
+
+	testCases := map[string]struct {
+		method func(*server, json.RawMessage) (any, *rpcError)
+		params map[string]string
+	}{
+		"scanFile": {
+			method: (*server).scanFile,
+			params: map[string]string{"path": filepath.Join(d.Dir(), "large.go")},
+		},
+		"scanText": {
+			method: (*server).scanText,
+			params: map[string]string{"path": "large.go", "text": text},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			params, err := json.Marshal(tc.params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, rerr := tc.method(s, params)
+			want := &rpcError{Code: rpcLimitExceeded, Message: "scan time limit exceeded: 1ns"}
+			if diff := cmp.Diff(want, rerr); diff != "" {
+				t.Errorf("unexpected error (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_server_listLanguages(t *testing.T) {
+	t.Parallel()
+
+	s := &server{opts: &walker.Options{}}
+	result, rerr := s.listLanguages(nil)
+	if rerr != nil {
+		t.Fatalf("unexpected error: %v", rerr.Message)
+	}
+
+	var got *serverLanguage
+	for _, l := range result.([]*serverLanguage) {
+		if l.Name == "Go" {
+			got = l
+		}
+	}
+	want := &serverLanguage{Name: "Go", Extensions: []string{".go"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected language (-want, +got): \n%s", diff)
+	}
+}
+
+func Test_TODOsApp_server(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "foo.go",
+			Contents: []byte("package foo\n\n// TODO: foo\n"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	path := filepath.Join(d.Dir(), "foo.go")
+	params, err := json.Marshal(map[string]string{"path": path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	app := newTODOsApp()
+	var b strings.Builder
+	app.Reader = strings.NewReader(fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"scanFile","params":%s}`, params))
+	app.Writer = &b
+	if err := app.Run([]string{"todos", "--server", "--todo-types=TODO"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got struct {
+		ID     int        `json:"id"`
+		Result []*outTODO `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := len(got.Result), 1; got != want {
+		t.Fatalf("unexpected # of TODOs, got: %v, want: %v", got, want)
+	}
+	if got, want := got.Result[0].Path, path; got != want {
+		t.Errorf("unexpected path, got: %q, want: %q", got, want)
+	}
+	if got, want := got.Result[0].Line, 3; got != want {
+		t.Errorf("unexpected line, got: %v, want: %v", got, want)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/release-utils/version"
+
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+// sink stores the TODOs found by a run somewhere other than the output, such
+// as in a database. Sinks are given as output types of the form TYPE:TARGET.
+type sink interface {
+	// Handle is passed each TODO as it is found.
+	Handle(r *walker.TODORef) error
+
+	// Close is called after the walk finishes. walkErr is true if there were
+	// errors during the walk and the results may be incomplete.
+	Close(walkErr bool) error
+}
+
+// sinkTypes are the output types that store results in a sink. The function
+// is passed the target of the output type.
+var sinkTypes = map[string]func(target string) (sink, error){
+	"bigquery": newBigQuerySink,
+	"postgres": newPostgresSink,
+	"sqlite":   newSQLiteSink,
+	"webhook":  newWebhookSink,
+}
+
+// sinkFromOutput returns the sink for an output type of the form TYPE:TARGET.
+// It returns false if the output type is not a sink.
+func sinkFromOutput(outType string) (sink, bool, error) {
+	typ, target, ok := strings.Cut(outType, ":")
+	if !ok {
+		return nil, false, nil
+	}
+	newSink, ok := sinkTypes[typ]
+	if !ok {
+		return nil, false, nil
+	}
+	if target == "" {
+		return nil, true, fmt.Errorf("%w: output %s: target is required", ErrFlagParse, typ)
+	}
+	s, err := newSink(target)
+	if err != nil {
+		return nil, true, fmt.Errorf("%w: output %s: %w", ErrFlagParse, typ, err)
+	}
+	return s, true, nil
+}
+
+// sinkRow is a TODO found by a run for sinks that append the results of all
+// runs to a single table.
+type sinkRow struct {
+	RunID       string `json:"run_id"`
+	StartTime   string `json:"start_time"`
+	Version     string `json:"version"`
+	Complete    bool   `json:"complete"`
+	Path        string `json:"path"`
+	Type        string `json:"type"`
+	Label       string `json:"label"`
+	Message     string `json:"message"`
+	Text        string `json:"text"`
+	Line        int    `json:"line"`
+	Fingerprint string `json:"fingerprint"`
+	AuthorName  string `json:"author_name,omitempty"`
+	AuthorEmail string `json:"author_email,omitempty"`
+}
+
+// runRows collects the TODOs found by a run so that they can be appended to a
+// table as rows when the run finishes.
+type runRows struct {
+	// id is a random identifier for the run.
+	id string
+
+	// start is the time that the run started.
+	start time.Time
+
+	// todos are the TODOs found by the run.
+	todos []*outTODO
+}
+
+func newRunRows() (*runRows, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("generating run ID: %w", err)
+	}
+	return &runRows{
+		id:    hex.EncodeToString(b),
+		start: time.Now().UTC(),
+	}, nil
+}
+
+// Handle implements sink.Handle.
+func (r *runRows) Handle(ref *walker.TODORef) error {
+	if ref != nil {
+		r.todos = append(r.todos, newOutTODO(ref))
+	}
+	return nil
+}
+
+// rows returns the rows for the TODOs found by the run.
+func (r *runRows) rows(complete bool) []*sinkRow {
+	v := version.GetVersionInfo().GitVersion
+	rows := make([]*sinkRow, 0, len(r.todos))
+	for _, t := range r.todos {
+		row := &sinkRow{
+			RunID:       r.id,
+			StartTime:   r.start.Format(time.RFC3339),
+			Version:     v,
+			Complete:    complete,
+			Path:        filepath.ToSlash(t.Path),
+			Type:        t.Type,
+			Label:       t.Label,
+			Message:     t.Message,
+			Text:        t.Text,
+			Line:        t.Line,
+			Fingerprint: t.Fingerprint,
+		}
+		if t.GitUser != nil {
+			row.AuthorName = t.GitUser.Name
+			row.AuthorEmail = t.GitUser.Email
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/ianlewis/todos/internal/todos"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+func Test_sinkFromOutput(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		output string
+		isSink bool
+		err    error
+	}{
+		"not a sink": {
+			output: "json",
+		},
+		"unknown type": {
+			output: "foo:bar",
+		},
+		"sqlite": {
+			output: "sqlite:todos.db",
+			isSink: true,
+		},
+		"postgres": {
+			output: "postgres:todos.runs",
+			isSink: true,
+		},
+		"invalid postgres table": {
+			output: "postgres:todos; DROP TABLE x",
+			isSink: true,
+			err:    ErrFlagParse,
+		},
+		"invalid bigquery table": {
+			output: "bigquery:todos",
+			isSink: true,
+			err:    ErrFlagParse,
+		},
+		"webhook": {
+			output: "webhook:https://example.com/hooks/todos",
+			isSink: true,
+		},
+		"invalid webhook URL": {
+			output: "webhook:example.com",
+			isSink: true,
+			err:    ErrFlagParse,
+		},
+		"no target": {
+			output: "sqlite:",
+			isSink: true,
+			err:    ErrFlagParse,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, isSink, err := sinkFromOutput(tc.output)
+			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("unexpected error (-want, +got): \n%s", diff)
+			}
+			if got, want := isSink, tc.isSink; got != want {
+				t.Errorf("unexpected isSink, got: %v, want: %v", got, want)
+			}
+			if got, want := s != nil, tc.isSink && tc.err == nil; got != want {
+				t.Errorf("unexpected sink, got: %v, want: %v", s, want)
+			}
+		})
+	}
+}
+
+func Test_runRows(t *testing.T) {
+	t.Parallel()
+
+	r, err := newRunRows()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, ref := range []*walker.TODORef{
+		{
+			FileName:    "a.go",
+			TODO:        &todos.TODO{Type: "TODO", Text: "// TODO(alice): a", Label: "alice", Message: "a", Line: 1},
+			GitUser:     &walker.GitUser{Name: "Alice", Email: "alice@example.com"},
+			Fingerprint: "fa",
+		},
+		nil,
+		{
+			FileName:    "b.go",
+			TODO:        &todos.TODO{Type: "FIXME", Text: "// FIXME: b", Message: "b", Line: 2},
+			Fingerprint: "fb",
+		},
+	} {
+		if err := r.Handle(ref); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	want := []*sinkRow{
+		{
+			Path:        "a.go",
+			Type:        "TODO",
+			Label:       "alice",
+			Message:     "a",
+			Text:        "// TODO(alice): a",
+			Line:        1,
+			Fingerprint: "fa",
+			AuthorName:  "Alice",
+			AuthorEmail: "alice@example.com",
+		},
+		{
+			Path:        "b.go",
+			Type:        "FIXME",
+			Message:     "b",
+			Text:        "// FIXME: b",
+			Line:        2,
+			Fingerprint: "fb",
+		},
+	}
+	got := r.rows(true)
+	for _, row := range got {
+		if row.RunID != r.id || row.StartTime == "" || !row.Complete {
+			t.Errorf("unexpected run columns: %q, %q, %v", row.RunID, row.StartTime, row.Complete)
+		}
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(sinkRow{}, "RunID", "StartTime", "Version", "Complete")); diff != "" {
+		t.Errorf("unexpected rows (-want, +got): \n%s", diff)
+	}
+}
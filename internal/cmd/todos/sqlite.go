@@ -0,0 +1,186 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	// NOTE: The pure Go driver is used so that cgo is not required.
+	_ "modernc.org/sqlite"
+	"sigs.k8s.io/release-utils/version"
+)
+
+var errSQLite = errors.New("sqlite")
+
+// sqliteSchema creates the tables that results are written to. Each run adds
+// a row to runs and rows for the files containing TODOs, the TODOs, and any
+// new git authors.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id INTEGER PRIMARY KEY,
+	start_time TEXT NOT NULL,
+	end_time TEXT NOT NULL,
+	version TEXT NOT NULL,
+	complete INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS files (
+	id INTEGER PRIMARY KEY,
+	run_id INTEGER NOT NULL REFERENCES runs(id),
+	path TEXT NOT NULL,
+	UNIQUE (run_id, path)
+);
+CREATE TABLE IF NOT EXISTS authors (
+	id INTEGER PRIMARY KEY,
+	name TEXT NOT NULL,
+	email TEXT NOT NULL,
+	UNIQUE (name, email)
+);
+CREATE TABLE IF NOT EXISTS todos (
+	id INTEGER PRIMARY KEY,
+	run_id INTEGER NOT NULL REFERENCES runs(id),
+	file_id INTEGER NOT NULL REFERENCES files(id),
+	author_id INTEGER REFERENCES authors(id),
+	type TEXT NOT NULL,
+	label TEXT NOT NULL,
+	message TEXT NOT NULL,
+	text TEXT NOT NULL,
+	line INTEGER NOT NULL,
+	comment_line INTEGER NOT NULL,
+	fingerprint TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS todos_run_id ON todos (run_id);
+`
+
+// sqliteSink writes the results of a run to a SQLite database. The TODOs are
+// kept in memory and written in a single transaction when the sink is closed
+// so that a failed run doesn't leave partial results.
+type sqliteSink struct {
+	*runRows
+
+	// path is the path to the database file.
+	path string
+}
+
+func newSQLiteSink(target string) (sink, error) {
+	r, err := newRunRows()
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteSink{
+		runRows: r,
+		path:    target,
+	}, nil
+}
+
+// Close implements sink.Close.
+func (s *sqliteSink) Close(walkErr bool) error {
+	db, err := sql.Open("sqlite", s.path)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errSQLite, err)
+	}
+	defer db.Close()
+
+	if err := s.write(db, !walkErr); err != nil {
+		return fmt.Errorf("%w: %s: %w", errSQLite, s.path, err)
+	}
+	return nil
+}
+
+// write writes the run and its TODOs to the database.
+func (s *sqliteSink) write(db *sql.DB, complete bool) error {
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("creating schema: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	// NOTE: Rollback does nothing after the transaction is committed.
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	res, err := tx.Exec(
+		"INSERT INTO runs (start_time, end_time, version, complete) VALUES (?, ?, ?, ?)",
+		s.start.Format(time.RFC3339),
+		time.Now().UTC().Format(time.RFC3339),
+		version.GetVersionInfo().GitVersion,
+		complete,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting run: %w", err)
+	}
+	runID, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("inserting run: %w", err)
+	}
+
+	files := map[string]int64{}
+	for _, t := range s.todos {
+		fileID, ok := files[t.Path]
+		if !ok {
+			res, err := tx.Exec("INSERT INTO files (run_id, path) VALUES (?, ?)", runID, t.Path)
+			if err != nil {
+				return fmt.Errorf("inserting file: %w", err)
+			}
+			if fileID, err = res.LastInsertId(); err != nil {
+				return fmt.Errorf("inserting file: %w", err)
+			}
+			files[t.Path] = fileID
+		}
+
+		var authorID *int64
+		if t.GitUser != nil {
+			id, err := sqliteAuthor(tx, t.GitUser)
+			if err != nil {
+				return err
+			}
+			authorID = &id
+		}
+
+		if _, err := tx.Exec(
+			`INSERT INTO todos (run_id, file_id, author_id, type, label, message, text, line, comment_line, fingerprint)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			runID, fileID, authorID, t.Type, t.Label, t.Message,
+			t.Text, t.Line, t.CommentLine, t.Fingerprint,
+		); err != nil {
+			return fmt.Errorf("inserting TODO: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+// sqliteAuthor returns the ID of the author, adding it if it is new.
+func sqliteAuthor(tx *sql.Tx, u *outUser) (int64, error) {
+	if _, err := tx.Exec(
+		"INSERT INTO authors (name, email) VALUES (?, ?) ON CONFLICT (name, email) DO NOTHING",
+		u.Name, u.Email,
+	); err != nil {
+		return 0, fmt.Errorf("inserting author: %w", err)
+	}
+	var id int64
+	if err := tx.QueryRow("SELECT id FROM authors WHERE name = ? AND email = ?", u.Name, u.Email).Scan(&id); err != nil {
+		return 0, fmt.Errorf("selecting author: %w", err)
+	}
+	return id, nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/testutils"
+	"github.com/ianlewis/todos/internal/todos"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+// queryRows returns the rows of the query with each row's columns joined by
+// "|".
+func queryRows(t *testing.T, db *sql.DB, query string) []string {
+	t.Helper()
+
+	rows, err := db.Query(query)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for rows.Next() {
+		vals := make([]sql.NullString, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		strs := make([]string, len(vals))
+		for i, v := range vals {
+			strs[i] = v.String
+		}
+		got = append(got, strings.Join(strs, "|"))
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return got
+}
+
+func Test_sqliteSink(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "todos.db")
+	user := &walker.GitUser{Name: "Alice", Email: "alice@example.com"}
+
+	// NOTE: Each run appends to the database.
+	for _, run := range [][]*walker.TODORef{
+		{
+			{
+				FileName:    "a.go",
+				TODO:        &todos.TODO{Type: "TODO", Text: "// TODO: a", Message: "a", Line: 1, CommentLine: 1},
+				GitUser:     user,
+				Fingerprint: "fa",
+			},
+			{
+				FileName:    "a.go",
+				TODO:        &todos.TODO{Type: "FIXME", Text: "// FIXME(x): b", Label: "x", Message: "b", Line: 3, CommentLine: 3},
+				Fingerprint: "fb",
+			},
+		},
+		{
+			{
+				FileName:    "a.go",
+				TODO:        &todos.TODO{Type: "TODO", Text: "// TODO: a", Message: "a", Line: 2, CommentLine: 2},
+				GitUser:     user,
+				Fingerprint: "fa",
+			},
+		},
+	} {
+		s, err := newSQLiteSink(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, r := range run {
+			if err := s.Handle(r); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if err := s.Close(false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	if diff := cmp.Diff([]string{"1|1", "2|1"}, queryRows(t, db, "SELECT id, complete FROM runs ORDER BY id")); diff != "" {
+		t.Errorf("unexpected runs (-want, +got): \n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"1|1|a.go", "2|2|a.go"}, queryRows(t, db, "SELECT id, run_id, path FROM files ORDER BY id")); diff != "" {
+		t.Errorf("unexpected files (-want, +got): \n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"1|Alice|alice@example.com"}, queryRows(t, db, "SELECT id, name, email FROM authors")); diff != "" {
+		t.Errorf("unexpected authors (-want, +got): \n%s", diff)
+	}
+	want := []string{
+		"1|1|1|TODO||a|// TODO: a|1|1|fa",
+		"1|1||FIXME|x|b|// FIXME(x): b|3|3|fb",
+		"2|2|1|TODO||a|// TODO: a|2|2|fa",
+	}
+	got := queryRows(t, db, `SELECT run_id, file_id, author_id, type, label, message, text, line, comment_line, fingerprint
+		FROM todos ORDER BY id`)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected TODOs (-want, +got): \n%s", diff)
+	}
+}
+
+func Test_TODOsApp_sqlite(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "foo.go",
+			Contents: []byte("package foo\n\n// TODO: foo\n"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	dbPath := filepath.Join(t.TempDir(), "todos.db")
+	app := newTODOsApp()
+	var b strings.Builder
+	app.Writer = &b
+	if err := app.Run([]string{"todos", "--output=sqlite:" + dbPath, "--todo-types=TODO", d.Dir()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := b.String(); got != "" {
+		t.Errorf("unexpected output: %q", got)
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	want := []string{filepath.Join(d.Dir(), "foo.go") + "|3|// TODO: foo"}
+	got := queryRows(t, db, "SELECT files.path, line, text FROM todos JOIN files ON files.id = todos.file_id")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected TODOs (-want, +got): \n%s", diff)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+var errState = errors.New("state file")
+
+// stateRecord is a line of the state file. The state file is a sequence of
+// records of the files and TODOs reported by the walk, each batch followed by
+// a checkpoint record, so that checkpoints only need to append the results
+// reported since the previous checkpoint.
+type stateRecord struct {
+	File       *walker.FileRef `json:"file,omitempty"`
+	TODO       *walker.TODORef `json:"todo,omitempty"`
+	Checkpoint *walker.State   `json:"checkpoint,omitempty"`
+}
+
+// stateFromContext configures the walker to save checkpoints to the file
+// given by --state-file and to resume from the file if it exists.
+func stateFromContext(c *cli.Context, o *walker.Options) error {
+	p := c.String("state-file")
+	if p == "" {
+		return nil
+	}
+
+	s, size, err := readState(p)
+	if err != nil {
+		return fmt.Errorf("%w: state-file: %w", ErrFlagParse, err)
+	}
+
+	// NOTE: The walker also checks that the state matches but it is checked
+	// here so that the state file isn't removed when the walk is rejected.
+	if s != nil {
+		if !slices.Equal(s.Paths, o.Paths) {
+			return fmt.Errorf("%w: state-file: %w: paths %q do not match %q", ErrFlagParse, errState, s.Paths, o.Paths)
+		}
+		if s.Options != walker.OptionsHash(o) {
+			return fmt.Errorf("%w: state-file: %w: options do not match the options of the resumed walk", ErrFlagParse, errState)
+		}
+	}
+
+	// NOTE: Remove records after the last checkpoint, which may have been
+	// partially written when the walk was interrupted.
+	if err := os.Truncate(p, size); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: state-file: %w: %w", ErrFlagParse, errState, err)
+	}
+
+	o.Resume = s
+	o.StateFunc = func(s *walker.State) error {
+		return writeState(p, s)
+	}
+	return nil
+}
+
+// readState reads the walk state from the file at path. It returns the state
+// at the last checkpoint in the file and the size of the file up to the end of
+// the checkpoint. It returns nil if the file does not exist or has no
+// checkpoints.
+func readState(path string) (*walker.State, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, 0, nil
+		}
+		return nil, 0, fmt.Errorf("%w: %w", errState, err)
+	}
+	defer f.Close()
+
+	var state *walker.State
+	var pending walker.State
+	var offset, size int64
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				// NOTE: A line without a newline was partially written.
+				break
+			}
+			return nil, 0, fmt.Errorf("%w: %s: %w", errState, path, err)
+		}
+		offset += int64(len(line))
+
+		var rec stateRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, 0, fmt.Errorf("%w: %s: %w", errState, path, err)
+		}
+		switch {
+		case rec.File != nil:
+			pending.Files = append(pending.Files, rec.File)
+		case rec.TODO != nil:
+			pending.TODOs = append(pending.TODOs, rec.TODO)
+		case rec.Checkpoint != nil:
+			if state == nil {
+				state = &walker.State{}
+			}
+			rec.Checkpoint.Files = pending.Files
+			rec.Checkpoint.TODOs = pending.TODOs
+			state.Append(rec.Checkpoint)
+			pending = walker.State{}
+			size = offset
+		default:
+			return nil, 0, fmt.Errorf("%w: %s: invalid record: %s", errState, path, bytes.TrimSpace(line))
+		}
+	}
+	return state, size, nil
+}
+
+// writeState appends the results of the checkpoint to the state file at path
+// followed by the checkpoint.
+func writeState(path string, s *walker.State) error {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	for _, f := range s.Files {
+		if err := enc.Encode(&stateRecord{File: f}); err != nil {
+			return fmt.Errorf("%w: %w", errState, err)
+		}
+	}
+	for _, r := range s.TODOs {
+		if err := enc.Encode(&stateRecord{TODO: r}); err != nil {
+			return fmt.Errorf("%w: %w", errState, err)
+		}
+	}
+	if err := enc.Encode(&stateRecord{Checkpoint: &walker.State{
+		Paths:     s.Paths,
+		Options:   s.Options,
+		PathIndex: s.PathIndex,
+		Last:      s.Last,
+	}}); err != nil {
+		return fmt.Errorf("%w: %w", errState, err)
+	}
+
+	// NOTE: The checkpoint is written in a single write at the end of the
+	// file. If the walk is interrupted while writing, the partial checkpoint
+	// is ignored when the state is read.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errState, err)
+	}
+	if _, err := f.Write(b.Bytes()); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("%w: %w", errState, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("%w: %w", errState, err)
+	}
+	return nil
+}
+
+// removeState removes the state file after the walk completes.
+func removeState(path string) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %w", errState, err)
+	}
+	return nil
+}
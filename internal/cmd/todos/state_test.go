@@ -0,0 +1,269 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/testutils"
+	"github.com/ianlewis/todos/internal/todos"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+func Test_writeState(t *testing.T) {
+	t.Parallel()
+
+	d := testutils.NewTempDir(nil)
+	defer d.Cleanup()
+
+	p := filepath.Join(d.Dir(), "state.json")
+	foo := &walker.TODORef{
+		FileName: "foo/bar.go",
+		TODO: &todos.TODO{
+			Type: "TODO",
+			Text: "// TODO: foo",
+			Line: 1,
+		},
+	}
+	bar := &walker.TODORef{
+		FileName: "foo/baz.go",
+		TODO: &todos.TODO{
+			Type: "TODO",
+			Text: "// TODO: bar",
+			Line: 2,
+		},
+	}
+
+	// NOTE: Each checkpoint only holds the TODOs found since the previous
+	// checkpoint.
+	for _, s := range []*walker.State{
+		{
+			Paths:   []string{"."},
+			Options: "opts",
+			Last:    "foo/bar.go",
+			TODOs:   []*walker.TODORef{foo},
+		},
+		{
+			Paths:   []string{"."},
+			Options: "opts",
+			Last:    "foo/baz.go",
+			TODOs:   []*walker.TODORef{bar},
+		},
+	} {
+		if err := writeState(p, s); err != nil {
+			t.Fatalf("writeState: %v", err)
+		}
+	}
+
+	got, size, err := readState(p)
+	if err != nil {
+		t.Fatalf("readState: %v", err)
+	}
+	want := &walker.State{
+		Paths:   []string{"."},
+		Options: "opts",
+		Last:    "foo/baz.go",
+		TODOs:   []*walker.TODORef{foo, bar},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected state (-want +got):\n%s", diff)
+	}
+
+	fi, err := os.Stat(p)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if got, want := size, fi.Size(); got != want {
+		t.Errorf("unexpected size, got: %v, want: %v", got, want)
+	}
+}
+
+func Test_readState(t *testing.T) {
+	t.Parallel()
+
+	checkpoint := `{"checkpoint":{"paths":["."],"options":"opts","path_index":0,"last":"a.go"}}` + "\n"
+	todo := `{"todo":{"path":"a.go","type":"TODO","text":"// TODO: a","label":"","message":"a","line":1,"comment_line":0,"comment_offset":0}}` + "\n"
+
+	testCases := map[string]struct {
+		contents string
+		missing  bool
+		last     string
+		todos    int
+		size     int
+		err      error
+	}{
+		"missing": {
+			missing: true,
+		},
+		"empty": {},
+		"checkpoint": {
+			contents: todo + checkpoint,
+			last:     "a.go",
+			todos:    1,
+			size:     len(todo + checkpoint),
+		},
+		"records after checkpoint": {
+			contents: todo + checkpoint + todo,
+			last:     "a.go",
+			todos:    1,
+			size:     len(todo + checkpoint),
+		},
+		"partial record": {
+			contents: todo + checkpoint + todo[:10],
+			last:     "a.go",
+			todos:    1,
+			size:     len(todo + checkpoint),
+		},
+		"no checkpoint": {
+			contents: todo,
+		},
+		"invalid": {
+			contents: "not json\n",
+			err:      errState,
+		},
+		"unknown record": {
+			contents: "{}\n",
+			err:      errState,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var files []*testutils.File
+			if !tc.missing {
+				files = append(files, &testutils.File{
+					Path:     "state.json",
+					Contents: []byte(tc.contents),
+					Mode:     0o600,
+				})
+			}
+			d := testutils.NewTempDir(files)
+			t.Cleanup(d.Cleanup)
+
+			s, size, err := readState(filepath.Join(d.Dir(), "state.json"))
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("unexpected error, got: %v, want: %v", err, tc.err)
+			}
+			if err != nil {
+				return
+			}
+			if got, want := size, int64(tc.size); got != want {
+				t.Errorf("unexpected size, got: %v, want: %v", got, want)
+			}
+			if tc.last == "" {
+				if s != nil {
+					t.Errorf("unexpected state: %+v", s)
+				}
+				return
+			}
+			if s == nil {
+				t.Fatalf("no state")
+			}
+			if got, want := s.Last, tc.last; got != want {
+				t.Errorf("unexpected last file, got: %q, want: %q", got, want)
+			}
+			if got, want := len(s.TODOs), tc.todos; got != want {
+				t.Errorf("unexpected # of TODOs, got: %v, want: %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_TODOsApp_stateFile(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "src/a.go",
+			Contents: []byte("// TODO: a"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "src/b.go",
+			Contents: []byte("// TODO: b"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	src := filepath.Join(d.Dir(), "src")
+	p := filepath.Join(d.Dir(), "state.json")
+	args := []string{"--state-file", p, src}
+
+	opts, _, err := todosOptionsFromContext(newContext(newTODOsApp(), args))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Resume from a walk that was interrupted after scanning a.go.
+	if err := writeState(p, &walker.State{
+		Paths:   []string{src},
+		Options: walker.OptionsHash(opts),
+		Last:    "a.go",
+		TODOs: []*walker.TODORef{
+			{
+				FileName: filepath.Join(src, "a.go"),
+				TODO: &todos.TODO{
+					Type:    "TODO",
+					Text:    "// TODO: resumed",
+					Message: "resumed",
+					Line:    1,
+				},
+			},
+		},
+	}); err != nil {
+		t.Fatalf("writeState: %v", err)
+	}
+
+	// Resuming with different options is an error and keeps the state.
+	var errb strings.Builder
+	app := newTODOsApp()
+	app.ErrWriter = &errb
+	app.ExitErrHandler = nil
+	if err := app.Run(append([]string{"todos", "--todo-types=FIXME"}, args...)); !errors.Is(err, ErrFlagParse) {
+		t.Fatalf("unexpected error, got: %v, want: %v", err, ErrFlagParse)
+	}
+	if _, err := os.Stat(p); err != nil {
+		t.Fatalf("state file removed: %v", err)
+	}
+
+	var b bytes.Buffer
+	app = newTODOsApp()
+	app.Writer = &b
+	if err := app.Run(append([]string{"todos"}, args...)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := filepath.Join(src, "a.go") + ":1:// TODO: resumed\n" +
+		filepath.Join(src, "b.go") + ":1:// TODO: b\n"
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+
+	if _, err := os.Stat(p); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("state file not removed: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+// runStats are counters aggregated over the files scanned in a run.
+type runStats struct {
+	// Files is the number of files that were scanned.
+	Files int
+
+	// Bytes is the number of bytes that were scanned.
+	Bytes int
+
+	// Comments is the number of comments found.
+	Comments int
+
+	// Transitions is the number of scanner state transitions.
+	Transitions int
+
+	// TODOs is the number of TODOs found.
+	TODOs int
+
+	// Duration is the total time spent scanning files.
+	Duration time.Duration
+
+	// Slowest is the file that took the longest to scan.
+	Slowest string
+
+	// SlowestDuration is the time taken to scan the slowest file.
+	SlowestDuration time.Duration
+}
+
+// statsFromContext returns new stats for the run if the stats flag is set.
+// The walker options' file result handler is wrapped to aggregate results.
+func statsFromContext(c *cli.Context, o *walker.Options) *runStats {
+	if !c.Bool("stats") {
+		return nil
+	}
+
+	s := &runStats{}
+	resultFunc := o.FileResultFunc
+	o.FileResultFunc = func(r *walker.FileResult) error {
+		s.Files++
+		s.Bytes += r.Stats.Bytes
+		s.Comments += r.Stats.Comments
+		s.Transitions += r.Stats.Transitions
+		s.TODOs += len(r.TODOs)
+		s.Duration += r.Duration
+		if s.Slowest == "" || r.Duration > s.SlowestDuration {
+			s.Slowest = r.FileName
+			s.SlowestDuration = r.Duration
+		}
+		if resultFunc == nil {
+			return nil
+		}
+		return resultFunc(r)
+	}
+
+	return s
+}
+
+// writeStats writes the stats to w.
+func writeStats(w io.Writer, s *runStats) error {
+	if s == nil {
+		return nil
+	}
+
+	type stat struct {
+		key   string
+		value any
+	}
+	lines := []stat{
+		{"files", s.Files},
+		{"bytes", s.Bytes},
+		{"comments", s.Comments},
+		{"transitions", s.Transitions},
+		{"todos", s.TODOs},
+		{"duration", s.Duration},
+	}
+	if s.Slowest != "" {
+		lines = append(lines, stat{"slowest", fmt.Sprintf("%s (%s)", s.Slowest, s.SlowestDuration)})
+	}
+
+	for _, l := range lines {
+		if _, err := fmt.Fprintf(w, "%s: %v\n", l.key, l.value); err != nil {
+			return fmt.Errorf("writing stats: %w", err)
+		}
+	}
+	return nil
+}
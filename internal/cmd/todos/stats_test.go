@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/testutils"
+)
+
+func Test_TODOsApp_stats(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "foo.go",
+			Contents: []byte("// TODO: foo\n// FIXME: foo\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "bar.go",
+			Contents: []byte("x := \"// TODO: not a comment\"\n"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	app := newTODOsApp()
+	var out, errOut strings.Builder
+	app.Writer = &out
+	app.ErrWriter = &errOut
+	if err := app.Run([]string{"todos", "--stats", "--todo-types=TODO", d.Dir()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(errOut.String()), "\n") {
+		key, value, _ := strings.Cut(line, ": ")
+		got[key] = value
+	}
+	// NOTE: Durations vary between runs.
+	for _, key := range []string{"duration", "slowest"} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("missing stat %q", key)
+		}
+		delete(got, key)
+	}
+
+	want := map[string]string{
+		"files":       "2",
+		"bytes":       "57",
+		"comments":    "2",
+		"transitions": "6",
+		"todos":       "1",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected stats (-want, +got): \n%s", diff)
+	}
+	if got, want := strings.Count(out.String(), "\n"), 1; got != want {
+		t.Errorf("unexpected # of output lines, got: %v, want: %v", got, want)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/todos/internal/todos"
+	"github.com/ianlewis/todos/internal/utils"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+// noneKey is the summary key used for TODOs without a value for the key.
+const noneKey = "(none)"
+
+// summaryCount is the number of TODOs for a summary key.
+type summaryCount struct {
+	// Key is the value the TODOs are grouped by (e.g. the owner).
+	Key string `json:"key"`
+
+	// Count is the number of TODOs.
+	Count int `json:"count"`
+}
+
+// summaryKeys returns the keys that a TODO is counted under for each
+// supported --by value.
+var summaryKeys = map[string]func(*walker.TODORef) []string{
+	"assignee": func(r *walker.TODORef) []string {
+		if a := todos.Assignee(r.TODO.Label); a != "" {
+			return []string{a}
+		}
+		return []string{noneKey}
+	},
+	"owner": func(r *walker.TODORef) []string {
+		if len(r.Owners) == 0 {
+			return []string{noneKey}
+		}
+		return r.Owners
+	},
+	"label": func(r *walker.TODORef) []string {
+		// NOTE: Equivalent issue references are counted together.
+		if r.TODO.CanonicalLabel != "" {
+			return []string{r.TODO.CanonicalLabel}
+		}
+		if r.TODO.Label == "" {
+			return []string{noneKey}
+		}
+		return []string{r.TODO.Label}
+	},
+	"type": func(r *walker.TODORef) []string {
+		return []string{r.TODO.Type}
+	},
+}
+
+var summaryOutTypes = map[string]func(io.Writer, catalog, string, []*summaryCount){
+	"":         outSummaryTable,
+	"table":    outSummaryTable,
+	"json":     outSummaryJSON,
+	"markdown": outSummaryMarkdown,
+}
+
+func newSummaryCommand() *cli.Command {
+	flags := sortedFlags(append(walkerFlags(),
+		&cli.StringFlag{
+			Name:  "by",
+			Usage: "group TODOs by `KEY` (owner, assignee, label, type)",
+			Value: "owner",
+		},
+		localeFlag("from LANG"),
+		&cli.StringFlag{
+			Name:    "output",
+			Usage:   "output `TYPE` (table, json, markdown)",
+			Value:   "table",
+			Aliases: []string{"o"},
+		},
+	))
+
+	return &cli.Command{
+		Name:      "summary",
+		Usage:     "Summarize the number of TODOs.",
+		ArgsUsage: "[PATH]...",
+		HideHelp:  true,
+		Flags: append(flags,
+			&cli.BoolFlag{
+				Name:               "help",
+				Usage:              "print this help text and exit",
+				Aliases:            []string{"h"},
+				DisableDefaultText: true,
+			},
+		),
+		Action: func(c *cli.Context) error {
+			if c.Bool("help") {
+				utils.Check(cli.ShowCommandHelp(c, c.Command.Name))
+				return nil
+			}
+
+			by := c.String("by")
+			keyFunc, ok := summaryKeys[by]
+			if !ok {
+				return fmt.Errorf("%w: invalid summary key: %v", ErrFlagParse, by)
+			}
+
+			outType := c.String("output")
+			outFunc, ok := summaryOutTypes[outType]
+			if !ok {
+				return fmt.Errorf("%w: invalid output type: %v", ErrFlagParse, outType)
+			}
+
+			cat, err := catalogFromContext(c, true)
+			if err != nil {
+				return err
+			}
+
+			opts, err := walkerOptionsFromContext(c)
+			if err != nil {
+				return err
+			}
+			if by == "owner" {
+				opts.Owners = true
+			}
+
+			counts := map[string]int{}
+			opts.TODOFunc = func(r *walker.TODORef) error {
+				for _, k := range keyFunc(r) {
+					counts[k]++
+				}
+				return nil
+			}
+
+			walkErr := walker.New(opts).Walk()
+			outFunc(c.App.Writer, cat, by, sortedCounts(counts))
+			if walkErr {
+				return ErrWalk
+			}
+			return nil
+		},
+	}
+}
+
+// sortedCounts returns the counts sorted by count in descending order and
+// then by key.
+func sortedCounts(counts map[string]int) []*summaryCount {
+	var sorted []*summaryCount
+	for k, n := range counts {
+		sorted = append(sorted, &summaryCount{
+			Key:   k,
+			Count: n,
+		})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Key < sorted[j].Key
+	})
+	return sorted
+}
+
+func outSummaryTable(w io.Writer, cat catalog, by string, counts []*summaryCount) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_ = utils.Must(fmt.Fprintf(tw, "%s\t%s\n", strings.ToUpper(cat.text(by)), strings.ToUpper(cat.text(msgTODOCount))))
+	for _, c := range counts {
+		_ = utils.Must(fmt.Fprintf(tw, "%s\t%d\n", c.Key, c.Count))
+	}
+	utils.Check(tw.Flush())
+}
+
+func outSummaryJSON(w io.Writer, _ catalog, _ string, counts []*summaryCount) {
+	for _, c := range counts {
+		b := utils.Must(json.Marshal(c))
+		_ = utils.Must(w.Write(b))
+		_ = utils.Must(w.Write([]byte("\n")))
+	}
+}
+
+func outSummaryMarkdown(w io.Writer, cat catalog, by string, counts []*summaryCount) {
+	_ = utils.Must(fmt.Fprintf(w, "| %s | %s |\n", cat.text(by), cat.text(msgTODOCount)))
+	_ = utils.Must(fmt.Fprintln(w, "| --- | ---: |"))
+	for _, c := range counts {
+		// NOTE: Escape pipes so that they don't break the table.
+		_ = utils.Must(fmt.Fprintf(w, "| %s | %d |\n", strings.ReplaceAll(c.Key, "|", "\\|"), c.Count))
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/testutils"
+)
+
+func Test_sortedCounts(t *testing.T) {
+	t.Parallel()
+
+	got := sortedCounts(map[string]int{
+		"@b": 1,
+		"@a": 1,
+		"@c": 3,
+	})
+	want := []*summaryCount{
+		{Key: "@c", Count: 3},
+		{Key: "@a", Count: 1},
+		{Key: "@b", Count: 1},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected counts (-want, +got): \n%s", diff)
+	}
+}
+
+func Test_summaryOutTypes(t *testing.T) {
+	t.Parallel()
+
+	counts := []*summaryCount{
+		{Key: "@frontend-team", Count: 12},
+		{Key: "@a|b", Count: 3},
+	}
+
+	testCases := map[string]struct {
+		outType  string
+		locale   string
+		expected string
+	}{
+		"table": {
+			outType: "table",
+			locale:  "en",
+			expected: `OWNER           TODOS
+@frontend-team  12
+@a|b            3
+`,
+		},
+		"json": {
+			outType: "json",
+			locale:  "en",
+			expected: `{"key":"@frontend-team","count":12}
+{"key":"@a|b","count":3}
+`,
+		},
+		"markdown": {
+			outType: "markdown",
+			locale:  "en",
+			expected: `| Owner | TODOs |
+| --- | ---: |
+| @frontend-team | 12 |
+| @a\|b | 3 |
+`,
+		},
+		"markdown ja": {
+			outType: "markdown",
+			locale:  "ja",
+			expected: `| 担当者 | TODO 数 |
+| --- | ---: |
+| @frontend-team | 12 |
+| @a\|b | 3 |
+`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+			summaryOutTypes[tc.outType](&b, catalogs[tc.locale], "owner", counts)
+			if diff := cmp.Diff(tc.expected, b.String()); diff != "" {
+				t.Errorf("unexpected output (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_TODOsApp_summary(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "foo.go",
+			Contents: []byte("// TODO: foo\n// FIXME: foo\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "bar.go",
+			Contents: []byte("// TODO: bar\n"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	app := newTODOsApp()
+	var b strings.Builder
+	app.Writer = &b
+	if err := app.Run([]string{"todos", "summary", "--by=type", "--output=json", d.Dir()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `{"key":"TODO","count":2}
+{"key":"FIXME","count":1}
+`
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("unexpected output (-want, +got): \n%s", diff)
+	}
+}
+
+func Test_TODOsApp_summaryByCanonicalLabel(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path: "foo.go",
+			Contents: []byte(`// TODO(#123): short reference
+// TODO(github.com/ianlewis/todos/issues/123): url without scheme
+// TODO(https://github.com/ianlewis/todos/issues/123): url
+// TODO(alice): user
+`),
+			Mode: 0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	app := newTODOsApp()
+	var b strings.Builder
+	app.Writer = &b
+	args := []string{"todos", "summary", "--by=label", "--output=json", "--repo=ianlewis/todos", d.Dir()}
+	if err := app.Run(args); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `{"key":"ianlewis/todos#123","count":3}
+{"key":"alice","count":1}
+`
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("unexpected output (-want, +got): \n%s", diff)
+	}
+}
+
+func Test_TODOsApp_summaryByAssignee(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path: "foo.go",
+			Contents: []byte(`// TODO(alice): user
+// TODO(@alice): mention
+// TODO(bob): other user
+// TODO(#123): issue
+`),
+			Mode: 0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	app := newTODOsApp()
+	var b strings.Builder
+	app.Writer = &b
+	if err := app.Run([]string{"todos", "summary", "--by=assignee", "--output=json", d.Dir()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `{"key":"alice","count":2}
+{"key":"(none)","count":1}
+{"key":"bob","count":1}
+`
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("unexpected output (-want, +got): \n%s", diff)
+	}
+}
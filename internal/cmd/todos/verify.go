@@ -0,0 +1,205 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/todos/internal/utils"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+// verifyStatus is the status of a recorded TODO reference.
+type verifyStatus string
+
+const (
+	verifyExists  verifyStatus = "exists"
+	verifyMoved   verifyStatus = "moved"
+	verifyMissing verifyStatus = "missing"
+)
+
+// verifyEntry is the status of a recorded TODO reference.
+type verifyEntry struct {
+	// Status is whether the TODO still exists.
+	Status verifyStatus `json:"status"`
+
+	// TODO is the recorded TODO.
+	TODO *outTODO `json:"todo"`
+
+	// Line is the current line of the TODO if it still exists.
+	Line int `json:"line,omitempty"`
+}
+
+func newVerifyCommand() *cli.Command {
+	flags := sortedFlags(append(walkerFlags(),
+		&cli.StringFlag{
+			Name:    "output",
+			Usage:   "output `TYPE` (default, json)",
+			Value:   "default",
+			Aliases: []string{"o"},
+		},
+	))
+
+	return &cli.Command{
+		Name:      "verify",
+		Usage:     "Check whether TODOs in a JSON result file still exist.",
+		ArgsUsage: "REFS",
+		HideHelp:  true,
+		Flags: append(flags,
+			&cli.BoolFlag{
+				Name:               "help",
+				Usage:              "print this help text and exit",
+				Aliases:            []string{"h"},
+				DisableDefaultText: true,
+			},
+		),
+		Action: func(c *cli.Context) error {
+			if c.Bool("help") {
+				utils.Check(cli.ShowCommandHelp(c, c.Command.Name))
+				return nil
+			}
+
+			outType := c.String("output")
+			outFunc, ok := verifyOutTypes[outType]
+			if !ok {
+				return fmt.Errorf("%w: invalid output type: %v", ErrFlagParse, outType)
+			}
+
+			if c.NArg() != 1 {
+				return fmt.Errorf("%w: expected 1 argument, got %d", ErrFlagParse, c.NArg())
+			}
+
+			refs, err := readResultsFile(c.Args().Get(0))
+			if err != nil {
+				return err
+			}
+
+			opts, err := walkerOptionsFromContext(c)
+			if err != nil {
+				return err
+			}
+
+			// NOTE: Only the files of the references are scanned. Files that
+			// no longer exist are not walked so that their TODOs are reported
+			// as missing rather than as errors.
+			opts.Paths = nil
+			seen := map[string]bool{}
+			for _, r := range refs {
+				if seen[r.Path] {
+					continue
+				}
+				seen[r.Path] = true
+				if _, err := os.Stat(r.Path); errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+				opts.Paths = append(opts.Paths, r.Path)
+			}
+
+			var current []*outTODO
+			opts.TODOFunc = func(r *walker.TODORef) error {
+				if r != nil {
+					current = append(current, newOutTODO(r))
+				}
+				return nil
+			}
+
+			walkErr := false
+			if len(opts.Paths) > 0 {
+				walkErr = walker.New(opts).Walk()
+			}
+
+			for _, e := range verifyRefs(refs, current) {
+				outFunc(c.App.Writer, e)
+			}
+			if walkErr {
+				return ErrWalk
+			}
+			return nil
+		},
+	}
+}
+
+var verifyOutTypes = map[string]func(io.Writer, *verifyEntry){
+	"":        outVerifyCLI,
+	"default": outVerifyCLI,
+	"json":    outVerifyJSON,
+}
+
+func outVerifyCLI(w io.Writer, e *verifyEntry) {
+	switch e.Status {
+	case verifyExists:
+		_ = utils.Must(fmt.Fprintf(w, "%s %s:%d:%s\n", color.GreenString("="), e.TODO.Path, e.Line, e.TODO.Text))
+	case verifyMoved:
+		_ = utils.Must(fmt.Fprintf(w, "%s %s:%d->%d:%s\n",
+			color.YellowString("~"), e.TODO.Path, e.TODO.Line, e.Line, e.TODO.Text))
+	case verifyMissing:
+		_ = utils.Must(fmt.Fprintf(w, "%s %s:%d:%s\n", color.RedString("-"), e.TODO.Path, e.TODO.Line, e.TODO.Text))
+	}
+}
+
+func outVerifyJSON(w io.Writer, e *verifyEntry) {
+	b := utils.Must(json.Marshal(e))
+	_ = utils.Must(w.Write(b))
+	_ = utils.Must(w.Write([]byte("\n")))
+}
+
+// verifyRefs returns the status of each recorded reference given the TODOs
+// currently in the referenced files. References are matched by fingerprint,
+// or by path and text if they have no fingerprint, to the closest unmatched
+// current TODO. The entries are in the same order as the references.
+func verifyRefs(refs, current []*outTODO) []*verifyEntry {
+	matched := make([]bool, len(current))
+	entries := make([]*verifyEntry, 0, len(refs))
+	for _, ref := range refs {
+		best := -1
+		for i, c := range current {
+			if matched[i] || c.Path != ref.Path {
+				continue
+			}
+			if ref.Fingerprint != "" && c.Fingerprint != ref.Fingerprint {
+				continue
+			}
+			if ref.Fingerprint == "" && c.Text != ref.Text {
+				continue
+			}
+			if best == -1 || abs(c.Line-ref.Line) < abs(current[best].Line-ref.Line) {
+				best = i
+			}
+		}
+
+		e := &verifyEntry{
+			Status: verifyMissing,
+			TODO:   ref,
+		}
+		if best != -1 {
+			matched[best] = true
+			e.Line = current[best].Line
+			e.Status = verifyExists
+			if e.Line != ref.Line {
+				e.Status = verifyMoved
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
@@ -0,0 +1,168 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/testutils"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+func Test_verifyRefs(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		refs, current []*outTODO
+		expected      []*verifyEntry
+	}{
+		"empty": {
+			expected: []*verifyEntry{},
+		},
+		"exists": {
+			refs: []*outTODO{
+				{Path: "a.go", Line: 1, Text: "// TODO: a", Fingerprint: "fa"},
+			},
+			current: []*outTODO{
+				{Path: "a.go", Line: 1, Text: "// TODO: a", Fingerprint: "fa"},
+			},
+			expected: []*verifyEntry{
+				{
+					Status: verifyExists,
+					TODO:   &outTODO{Path: "a.go", Line: 1, Text: "// TODO: a", Fingerprint: "fa"},
+					Line:   1,
+				},
+			},
+		},
+		"moved": {
+			refs: []*outTODO{
+				{Path: "a.go", Line: 1, Text: "// TODO: a", Fingerprint: "fa"},
+			},
+			current: []*outTODO{
+				{Path: "a.go", Line: 4, Text: "// TODO: a", Fingerprint: "fa"},
+			},
+			expected: []*verifyEntry{
+				{
+					Status: verifyMoved,
+					TODO:   &outTODO{Path: "a.go", Line: 1, Text: "// TODO: a", Fingerprint: "fa"},
+					Line:   4,
+				},
+			},
+		},
+		"missing": {
+			refs: []*outTODO{
+				{Path: "a.go", Line: 1, Text: "// TODO: a", Fingerprint: "fa"},
+				{Path: "b.go", Line: 1, Text: "// TODO: b", Fingerprint: "fb"},
+			},
+			current: []*outTODO{
+				{Path: "a.go", Line: 1, Text: "// TODO: a changed", Fingerprint: "fc"},
+			},
+			expected: []*verifyEntry{
+				{
+					Status: verifyMissing,
+					TODO:   &outTODO{Path: "a.go", Line: 1, Text: "// TODO: a", Fingerprint: "fa"},
+				},
+				{
+					Status: verifyMissing,
+					TODO:   &outTODO{Path: "b.go", Line: 1, Text: "// TODO: b", Fingerprint: "fb"},
+				},
+			},
+		},
+		"no fingerprint": {
+			refs: []*outTODO{
+				{Path: "a.go", Line: 1, Text: "// TODO: a"},
+				{Path: "a.go", Line: 9, Text: "// TODO: a"},
+			},
+			current: []*outTODO{
+				{Path: "a.go", Line: 2, Text: "// TODO: a", Fingerprint: "fa0"},
+				{Path: "a.go", Line: 10, Text: "// TODO: a", Fingerprint: "fa1"},
+			},
+			expected: []*verifyEntry{
+				{
+					Status: verifyMoved,
+					TODO:   &outTODO{Path: "a.go", Line: 1, Text: "// TODO: a"},
+					Line:   2,
+				},
+				{
+					Status: verifyMoved,
+					TODO:   &outTODO{Path: "a.go", Line: 9, Text: "// TODO: a"},
+					Line:   10,
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := verifyRefs(tc.refs, tc.current)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected entries (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_TODOsApp_verify(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "a.go",
+			Contents: []byte("package a\n\n// TODO: a\n// TODO: b\n"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	path := filepath.Join(d.Dir(), "a.go")
+	gone := filepath.Join(d.Dir(), "gone.go")
+	var refs strings.Builder
+	enc := json.NewEncoder(&refs)
+	for _, ref := range []*outTODO{
+		{Path: path, Line: 1, Text: "// TODO: a", Fingerprint: walker.Fingerprint(path, "// TODO: a", 0)},
+		{Path: path, Line: 4, Text: "// TODO: b", Fingerprint: walker.Fingerprint(path, "// TODO: b", 0)},
+		{Path: path, Line: 5, Text: "// TODO: c", Fingerprint: walker.Fingerprint(path, "// TODO: c", 0)},
+		{Path: gone, Line: 1, Text: "// TODO: d", Fingerprint: walker.Fingerprint(gone, "// TODO: d", 0)},
+	} {
+		testutils.Check(enc.Encode(ref))
+	}
+	refsPath := filepath.Join(d.Dir(), "refs.json")
+	testutils.Check(os.WriteFile(refsPath, []byte(refs.String()), 0o600))
+
+	app := newTODOsApp()
+	var b strings.Builder
+	app.Writer = &b
+	if err := app.Run([]string{"todos", "verify", "--todo-types=TODO", refsPath}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "~ " + path + ":1->3:// TODO: a\n" +
+		"= " + path + ":4:// TODO: b\n" +
+		"- " + path + ":5:// TODO: c\n" +
+		"- " + gone + ":1:// TODO: d\n"
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("unexpected output (-want, +got): \n%s", diff)
+	}
+}
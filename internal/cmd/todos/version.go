@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/urfave/cli/v2"
+	"sigs.k8s.io/release-utils/version"
+
+	"github.com/ianlewis/todos/internal/utils"
+)
+
+func newVersionCommand() *cli.Command {
+	return &cli.Command{
+		Name:     "version",
+		Usage:    "Print version and build information.",
+		HideHelp: true,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:               "json",
+				Usage:              "print version information as JSON",
+				DisableDefaultText: true,
+			},
+			&cli.BoolFlag{
+				Name:               "help",
+				Usage:              "print this help text and exit",
+				Aliases:            []string{"h"},
+				DisableDefaultText: true,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.Bool("help") {
+				utils.Check(cli.ShowCommandHelp(c, c.Command.Name))
+				return nil
+			}
+
+			if c.Bool("json") {
+				return printVersionJSON(c.App.Writer)
+			}
+			printVersion(c.App.Writer, c.App.Name)
+			return nil
+		},
+	}
+}
+
+// printVersion prints human readable version information.
+func printVersion(w io.Writer, name string) {
+	versionInfo := version.GetVersionInfo()
+	_ = utils.Must(fmt.Fprintf(w, `%s %s
+Copyright (c) Google LLC
+
+%s`, name, versionInfo.GitVersion, versionInfo.String()))
+}
+
+// printVersionJSON prints version and build information, including the VCS
+// revision the binary was built from, as JSON.
+func printVersionJSON(w io.Writer) error {
+	versionInfo := version.GetVersionInfo()
+	s, err := versionInfo.JSONString()
+	if err != nil {
+		return fmt.Errorf("version: %w", err)
+	}
+	_ = utils.Must(fmt.Fprintln(w, s))
+	return nil
+}
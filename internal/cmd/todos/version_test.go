@@ -0,0 +1,61 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/release-utils/version"
+)
+
+func Test_TODOsApp_versionCommand(t *testing.T) {
+	t.Parallel()
+
+	app := newTODOsApp()
+	var b strings.Builder
+	app.Writer = &b
+	if err := app.Run([]string{"todos", "version"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	versionTitle := app.Name + " devel"
+	if !strings.HasPrefix(b.String(), versionTitle) {
+		t.Fatalf("expected %q in output: \n%q", versionTitle, b.String())
+	}
+}
+
+func Test_TODOsApp_versionJSON(t *testing.T) {
+	t.Parallel()
+
+	app := newTODOsApp()
+	var b strings.Builder
+	app.Writer = &b
+	if err := app.Run([]string{"todos", "version", "--json"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var info version.Info
+	if err := json.Unmarshal([]byte(b.String()), &info); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, b.String())
+	}
+	if got, want := info.GitVersion, "devel"; got != want {
+		t.Errorf("unexpected version, got: %q, want: %q", got, want)
+	}
+	if info.GoVersion == "" {
+		t.Errorf("expected go version in output: \n%s", b.String())
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"sigs.k8s.io/release-utils/version"
+
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+var errWebhook = errors.New("webhook")
+
+// webhookSignatureHeader is the header holding the HMAC-SHA256 signature of
+// the request body.
+const webhookSignatureHeader = "X-Todos-Signature-256"
+
+// webhookPayload is the body of a webhook request when the results are not
+// chunked.
+type webhookPayload struct {
+	RunID     string     `json:"run_id"`
+	StartTime string     `json:"start_time"`
+	Version   string     `json:"version"`
+	Complete  bool       `json:"complete"`
+	TODOs     []*outTODO `json:"todos"`
+}
+
+// webhookSink POSTs the results of a run to a URL when the run finishes. If
+// TODOS_WEBHOOK_SECRET is set requests are signed with it. If
+// TODOS_WEBHOOK_CHUNK_SIZE is set the TODOs are streamed as NDJSON in requests
+// of at most that many TODOs while the run is in progress.
+type webhookSink struct {
+	*runRows
+
+	// url is the webhook URL.
+	url string
+
+	// secret is the key used to sign requests.
+	secret []byte
+
+	// chunkSize is the maximum number of TODOs per request. Results are sent
+	// in a single JSON request if it is zero.
+	chunkSize int
+
+	client *http.Client
+
+	// chunks is the number of chunks sent.
+	chunks int
+
+	// err is the error that stopped the walk while sending a chunk.
+	err error
+}
+
+func newWebhookSink(target string) (sink, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errWebhook, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: %q: must be an http or https URL", errWebhook, target)
+	}
+
+	var chunkSize int
+	if v := os.Getenv("TODOS_WEBHOOK_CHUNK_SIZE"); v != "" {
+		chunkSize, err = strconv.Atoi(v)
+		if err != nil || chunkSize < 1 {
+			return nil, fmt.Errorf("%w: TODOS_WEBHOOK_CHUNK_SIZE: invalid chunk size %q", errWebhook, v)
+		}
+	}
+
+	r, err := newRunRows()
+	if err != nil {
+		return nil, err
+	}
+	return &webhookSink{
+		runRows:   r,
+		url:       target,
+		secret:    []byte(os.Getenv("TODOS_WEBHOOK_SECRET")),
+		chunkSize: chunkSize,
+		// NOTE: Don't let an unresponsive webhook hang CI forever.
+		client: &http.Client{Timeout: time.Minute},
+	}, nil
+}
+
+// Handle implements sink.Handle. When the results are chunked each chunk is
+// sent as soon as it is full.
+func (s *webhookSink) Handle(ref *walker.TODORef) error {
+	if err := s.runRows.Handle(ref); err != nil {
+		return err
+	}
+	if s.chunkSize == 0 || len(s.todos) < s.chunkSize {
+		return nil
+	}
+	if err := s.sendChunk(false, false); err != nil {
+		// NOTE: Stop the walk. The error is returned by Close.
+		s.err = err
+		return fs.SkipAll
+	}
+	return nil
+}
+
+// Close implements sink.Close.
+func (s *webhookSink) Close(walkErr bool) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if s.chunkSize > 0 {
+		// NOTE: The final chunk is sent even if it is empty so that
+		//       subscribers are notified that the run finished.
+		return s.sendChunk(true, !walkErr)
+	}
+
+	b, err := json.Marshal(&webhookPayload{
+		RunID:     s.id,
+		StartTime: s.start.Format(time.RFC3339),
+		Version:   version.GetVersionInfo().GitVersion,
+		Complete:  !walkErr,
+		TODOs:     append([]*outTODO{}, s.todos...),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: encoding request: %w", errWebhook, err)
+	}
+	return s.post("application/json", b, nil)
+}
+
+// sendChunk sends the TODOs found since the last chunk as NDJSON.
+func (s *webhookSink) sendChunk(final, complete bool) error {
+	var buf bytes.Buffer
+	for _, t := range s.todos {
+		b, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Errorf("%w: encoding request: %w", errWebhook, err)
+		}
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+	s.todos = nil
+	s.chunks++
+
+	header := http.Header{
+		"X-Todos-Chunk":      {strconv.Itoa(s.chunks)},
+		"X-Todos-Start-Time": {s.start.Format(time.RFC3339)},
+	}
+	if final {
+		header.Set("X-Todos-Final", "true")
+		header.Set("X-Todos-Complete", strconv.FormatBool(complete))
+	}
+	return s.post("application/x-ndjson", buf.Bytes(), header)
+}
+
+// post sends a request with the given body to the webhook URL.
+func (s *webhookSink) post(contentType string, body []byte, header http.Header) error {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: POST %s: %w", errWebhook, s.url, err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "todos/"+version.GetVersionInfo().GitVersion)
+	req.Header.Set("X-Todos-Run-Id", s.id)
+	if len(s.secret) > 0 {
+		req.Header.Set(webhookSignatureHeader, webhookSignature(s.secret, body))
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: POST %s: %w", errWebhook, s.url, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("%w: POST %s: unexpected status: %s: %s", errWebhook, s.url, res.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// webhookSignature returns the value of the signature header for the body.
+func webhookSignature(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,282 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/ianlewis/todos/internal/testutils"
+	"github.com/ianlewis/todos/internal/todos"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+// webhookRequest is a request received by a test webhook.
+type webhookRequest struct {
+	ContentType string
+	Chunk       string
+	Final       string
+	Complete    string
+	Signed      bool
+	Messages    []string
+}
+
+func Test_webhookSink(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		n         int
+		chunkSize int
+		secret    string
+		walkErr   bool
+		status    int
+		expected  []webhookRequest
+		err       error
+	}{
+		"json": {
+			n:      2,
+			status: http.StatusOK,
+			expected: []webhookRequest{
+				{ContentType: "application/json", Complete: "true", Messages: []string{"0", "1"}},
+			},
+		},
+		"no todos": {
+			status: http.StatusOK,
+			expected: []webhookRequest{
+				{ContentType: "application/json", Complete: "true"},
+			},
+		},
+		"walk error": {
+			n:       1,
+			walkErr: true,
+			status:  http.StatusOK,
+			expected: []webhookRequest{
+				{ContentType: "application/json", Complete: "false", Messages: []string{"0"}},
+			},
+		},
+		"signed": {
+			n:      1,
+			secret: "s3cret",
+			status: http.StatusOK,
+			expected: []webhookRequest{
+				{ContentType: "application/json", Complete: "true", Signed: true, Messages: []string{"0"}},
+			},
+		},
+		"chunked": {
+			n:         3,
+			chunkSize: 2,
+			secret:    "s3cret",
+			status:    http.StatusNoContent,
+			expected: []webhookRequest{
+				{ContentType: "application/x-ndjson", Chunk: "1", Signed: true, Messages: []string{"0", "1"}},
+				{ContentType: "application/x-ndjson", Chunk: "2", Final: "true", Complete: "true", Signed: true, Messages: []string{"2"}},
+			},
+		},
+		"chunked full": {
+			n:         4,
+			chunkSize: 2,
+			walkErr:   true,
+			status:    http.StatusOK,
+			expected: []webhookRequest{
+				{ContentType: "application/x-ndjson", Chunk: "1", Messages: []string{"0", "1"}},
+				{ContentType: "application/x-ndjson", Chunk: "2", Messages: []string{"2", "3"}},
+				{ContentType: "application/x-ndjson", Chunk: "3", Final: "true", Complete: "false"},
+			},
+		},
+		"chunked no todos": {
+			chunkSize: 2,
+			status:    http.StatusOK,
+			expected: []webhookRequest{
+				{ContentType: "application/x-ndjson", Chunk: "1", Final: "true", Complete: "true"},
+			},
+		},
+		"bad status": {
+			n:         3,
+			chunkSize: 2,
+			status:    http.StatusInternalServerError,
+			expected: []webhookRequest{
+				{ContentType: "application/x-ndjson", Chunk: "1", Messages: []string{"0", "1"}},
+			},
+			err: errWebhook,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := newRunRows()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var mu sync.Mutex
+			var got []webhookRequest
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				body, err := io.ReadAll(req.Body)
+				if err != nil {
+					t.Errorf("reading request: %v", err)
+				}
+				if got, want := req.Header.Get("X-Todos-Run-Id"), r.id; got != want {
+					t.Errorf("unexpected run ID, got: %q, want: %q", got, want)
+				}
+
+				wr := webhookRequest{
+					ContentType: req.Header.Get("Content-Type"),
+					Chunk:       req.Header.Get("X-Todos-Chunk"),
+					Final:       req.Header.Get("X-Todos-Final"),
+					Complete:    req.Header.Get("X-Todos-Complete"),
+				}
+				if sig := req.Header.Get(webhookSignatureHeader); sig != "" {
+					if want := webhookSignature([]byte(tc.secret), body); sig != want {
+						t.Errorf("unexpected signature, got: %q, want: %q", sig, want)
+					}
+					wr.Signed = true
+				}
+
+				if wr.Chunk == "" {
+					var p webhookPayload
+					if err := json.Unmarshal(body, &p); err != nil {
+						t.Errorf("decoding request: %v", err)
+					}
+					if got, want := p.RunID, r.id; got != want {
+						t.Errorf("unexpected run ID, got: %q, want: %q", got, want)
+					}
+					if p.TODOs == nil {
+						t.Errorf("todos is null")
+					}
+					wr.Complete = strconv.FormatBool(p.Complete)
+					for _, o := range p.TODOs {
+						wr.Messages = append(wr.Messages, o.Message)
+					}
+				} else {
+					s := bufio.NewScanner(bytes.NewReader(body))
+					for s.Scan() {
+						var o outTODO
+						if err := json.Unmarshal(s.Bytes(), &o); err != nil {
+							t.Errorf("decoding request: %v", err)
+						}
+						wr.Messages = append(wr.Messages, o.Message)
+					}
+				}
+
+				mu.Lock()
+				got = append(got, wr)
+				mu.Unlock()
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			s := &webhookSink{
+				runRows:   r,
+				url:       srv.URL,
+				secret:    []byte(tc.secret),
+				chunkSize: tc.chunkSize,
+				client:    srv.Client(),
+			}
+			for i := range tc.n {
+				err := s.Handle(&walker.TODORef{
+					FileName: "a.go",
+					TODO:     &todos.TODO{Type: "TODO", Text: "// TODO: a", Message: string(rune('0' + i)), Line: i + 1},
+				})
+				if errors.Is(err, fs.SkipAll) {
+					// NOTE: The walk would stop here.
+					break
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			err = s.Close(tc.walkErr)
+			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("unexpected error (-want, +got): \n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected requests (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_webhookSignature(t *testing.T) {
+	t.Parallel()
+
+	// NOTE: The signature can be checked with:
+	//       printf 'hello' | openssl dgst -sha256 -hmac 's3cret'
+	got := webhookSignature([]byte("s3cret"), []byte("hello"))
+	want := "sha256=e5a01537481fa0b2c697f787c7aff885412cf0760d08e08502259b39d2d6ae68"
+	if got != want {
+		t.Errorf("unexpected signature, got: %q, want: %q", got, want)
+	}
+}
+
+func Test_TODOsApp_webhook(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "foo.go",
+			Contents: []byte("package foo\n\n// TODO: foo\n"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	defer d.Cleanup()
+
+	var p webhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		if err := json.NewDecoder(req.Body).Decode(&p); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	app := newTODOsApp()
+	var b strings.Builder
+	app.Writer = &b
+	if err := app.Run([]string{"todos", "--output=webhook:" + srv.URL, "--todo-types=TODO", d.Dir()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := b.String(); got != "" {
+		t.Errorf("unexpected output: %q", got)
+	}
+
+	if !p.Complete {
+		t.Errorf("run is not complete")
+	}
+	want := []string{"// TODO: foo"}
+	var got []string
+	for _, o := range p.TODOs {
+		got = append(got, o.Text)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected TODOs (-want, +got): \n%s", diff)
+	}
+}
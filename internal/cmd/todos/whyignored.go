@@ -0,0 +1,200 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/todos/internal/utils"
+	"github.com/ianlewis/todos/internal/walker"
+)
+
+// ErrNotIgnored is returned by why-ignored when none of the paths are
+// ignored.
+var ErrNotIgnored = errors.New("no paths are ignored")
+
+// whyIgnoredEntry is the reason that a path is ignored.
+type whyIgnoredEntry struct {
+	// Path is the path as it was given.
+	Path string `json:"path"`
+
+	// Ignored is whether the walker skips the path.
+	Ignored bool `json:"ignored"`
+
+	// Rule is the rule that caused the path to be skipped.
+	Rule string `json:"rule,omitempty"`
+
+	// Match is the path that matched the rule. This may be a parent directory
+	// of Path.
+	Match string `json:"match,omitempty"`
+
+	// Pattern is the glob or regular expression that matched, if any.
+	Pattern string `json:"pattern,omitempty"`
+}
+
+func newWhyIgnoredCommand() *cli.Command {
+	flags := sortedFlags(append(walkerFlags(),
+		&cli.BoolFlag{
+			Name:               "non-matching",
+			Usage:              "also print paths that are not ignored",
+			Aliases:            []string{"n"},
+			DisableDefaultText: true,
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Usage:   "output `TYPE` (default, json)",
+			Value:   "default",
+			Aliases: []string{"o"},
+		},
+		&cli.StringFlag{
+			Name:  "root",
+			Usage: "evaluate paths as if walking `DIR`",
+			Value: ".",
+		},
+		&cli.BoolFlag{
+			Name:               "stdin",
+			Usage:              "read paths from stdin, one per line",
+			DisableDefaultText: true,
+		},
+	))
+
+	return &cli.Command{
+		Name:      "why-ignored",
+		Usage:     "Print the rule that causes each path to be skipped.",
+		ArgsUsage: "PATH...",
+		HideHelp:  true,
+		Flags: append(flags,
+			&cli.BoolFlag{
+				Name:               "help",
+				Usage:              "print this help text and exit",
+				Aliases:            []string{"h"},
+				DisableDefaultText: true,
+			},
+		),
+		Action: func(c *cli.Context) error {
+			if c.Bool("help") {
+				utils.Check(cli.ShowCommandHelp(c, c.Command.Name))
+				return nil
+			}
+
+			outType := c.String("output")
+			outFunc, ok := whyIgnoredOutTypes[outType]
+			if !ok {
+				return fmt.Errorf("%w: invalid output type: %v", ErrFlagParse, outType)
+			}
+
+			paths := c.Args().Slice()
+			if c.Bool("stdin") {
+				if len(paths) > 0 {
+					return fmt.Errorf("%w: paths cannot be given with --stdin", ErrFlagParse)
+				}
+				var err error
+				paths, err = readPathLines(c.App.Reader)
+				if err != nil {
+					return err
+				}
+			} else if len(paths) == 0 {
+				return fmt.Errorf("%w: expected at least 1 path", ErrFlagParse)
+			}
+
+			opts, err := walkerOptionsFromContext(c)
+			if err != nil {
+				return err
+			}
+			// NOTE: Paths that are walked are never skipped so the paths are
+			// explained relative to the root rather than walked themselves.
+			opts.Paths = []string{c.String("root")}
+
+			return whyIgnored(c.App.Writer, walker.New(opts), paths, c.Bool("non-matching"), outFunc)
+		},
+	}
+}
+
+var whyIgnoredOutTypes = map[string]func(io.Writer, *whyIgnoredEntry){
+	"":        outWhyIgnoredCLI,
+	"default": outWhyIgnoredCLI,
+	"json":    outWhyIgnoredJSON,
+}
+
+// whyIgnored prints the rule that causes the walker to skip each of the
+// paths. Paths that are not skipped are only printed if nonMatching is true.
+// It returns ErrNotIgnored if none of the paths are skipped.
+func whyIgnored(
+	out io.Writer,
+	w *walker.TODOWalker,
+	paths []string,
+	nonMatching bool,
+	outFunc func(io.Writer, *whyIgnoredEntry),
+) error {
+	ignored := false
+	for _, path := range paths {
+		r, err := w.Explain(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		e := &whyIgnoredEntry{
+			Path: path,
+		}
+		if r != nil {
+			ignored = true
+			e.Ignored = true
+			e.Rule = string(r.Rule)
+			e.Match = r.Path
+			e.Pattern = r.Pattern
+		}
+		if e.Ignored || nonMatching {
+			outFunc(out, e)
+		}
+	}
+
+	if !ignored {
+		return ErrNotIgnored
+	}
+	return nil
+}
+
+// readPathLines reads non-empty lines from r.
+func readPathLines(r io.Reader) ([]string, error) {
+	var paths []string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		if line := s.Text(); line != "" {
+			paths = append(paths, line)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("reading paths: %w", err)
+	}
+	return paths, nil
+}
+
+// outWhyIgnoredCLI prints the entry in the same format as
+// `git check-ignore -v`. The source is the rule and the path that matched it.
+func outWhyIgnoredCLI(w io.Writer, e *whyIgnoredEntry) {
+	_ = utils.Must(fmt.Fprintf(w, "%s:%s:%s\t%s\n", e.Rule, e.Match, e.Pattern, e.Path))
+}
+
+func outWhyIgnoredJSON(w io.Writer, e *whyIgnoredEntry) {
+	b := utils.Must(json.Marshal(e))
+	_ = utils.Must(w.Write(b))
+	_ = utils.Must(w.Write([]byte("\n")))
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/todos/internal/testutils"
+)
+
+func Test_TODOsApp_whyIgnored(t *testing.T) {
+	t.Parallel()
+
+	files := []*testutils.File{
+		{
+			Path:     "foo.go",
+			Contents: []byte("// TODO: foo"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "foo_test.go",
+			Contents: []byte("// TODO: test"),
+			Mode:     0o600,
+		},
+		{
+			Path:     ".hidden.go",
+			Contents: []byte("// TODO: hidden"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "vendor/bar.go",
+			Contents: []byte("// TODO: bar"),
+			Mode:     0o600,
+		},
+	}
+
+	d := testutils.NewTempDir(files)
+	t.Cleanup(d.Cleanup)
+
+	foo := filepath.Join(d.Dir(), "foo.go")
+	test := filepath.Join(d.Dir(), "foo_test.go")
+	hidden := filepath.Join(d.Dir(), ".hidden.go")
+	vendored := filepath.Join(d.Dir(), "vendor", "bar.go")
+
+	testCases := map[string]struct {
+		args     []string
+		stdin    string
+		expected string
+		err      error
+	}{
+		"ignored": {
+			args: []string{"--exclude=*_test.go", test, hidden, vendored},
+			expected: "exclude:foo_test.go:*_test.go\t" + test + "\n" +
+				"hidden:.hidden.go:\t" + hidden + "\n" +
+				"vendored:vendor:(^|/)vendors?/\t" + vendored + "\n",
+		},
+		"not ignored": {
+			args:     []string{foo, hidden},
+			expected: "hidden:.hidden.go:\t" + hidden + "\n",
+		},
+		"non-matching": {
+			args: []string{"-n", foo, hidden},
+			expected: "::\t" + foo + "\n" +
+				"hidden:.hidden.go:\t" + hidden + "\n",
+		},
+		"none ignored": {
+			args: []string{foo},
+			err:  ErrNotIgnored,
+		},
+		"json": {
+			args: []string{"--output=json", "-n", foo, vendored},
+			expected: `{"path":"` + foo + `","ignored":false}` + "\n" +
+				`{"path":"` + vendored + `","ignored":true,"rule":"vendored","match":"vendor","pattern":"(^|/)vendors?/"}` + "\n",
+		},
+		"stdin": {
+			args:     []string{"--stdin"},
+			stdin:    foo + "\n\n" + hidden + "\n",
+			expected: "hidden:.hidden.go:\t" + hidden + "\n",
+		},
+		"stdin with paths": {
+			args: []string{"--stdin", foo},
+			err:  ErrFlagParse,
+		},
+		"no paths": {
+			err: ErrFlagParse,
+		},
+		"invalid output": {
+			args: []string{"--output=foo", foo},
+			err:  ErrFlagParse,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			app := newTODOsApp()
+			app.ExitErrHandler = nil
+			var b strings.Builder
+			app.Writer = &b
+			app.ErrWriter = &strings.Builder{}
+			app.Reader = strings.NewReader(tc.stdin)
+
+			args := append([]string{"todos", "why-ignored", "--root", d.Dir(), "--exclude-hidden"}, tc.args...)
+			err := app.Run(args)
+			if diff := cmp.Diff(tc.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Fatalf("unexpected error (-want, +got): \n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.expected, b.String()); diff != "" {
+				t.Errorf("unexpected output (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // modifies cli.OsExiter
+func Test_TODOsApp_ExitErrHandler_ErrNotIgnored(t *testing.T) {
+	oldExiter := cli.OsExiter
+	var exitCode *int
+	cli.OsExiter = func(c int) {
+		exitCode = &c
+	}
+	defer func() {
+		cli.OsExiter = oldExiter
+	}()
+
+	app := newTODOsApp()
+	var b strings.Builder
+	app.ErrWriter = &b
+	c := newContext(app, nil)
+	app.ExitErrHandler(c, ErrNotIgnored)
+
+	if exitCode == nil {
+		t.Fatalf("unexpected exit code, want: %v, got: %v", ExitCodeNotIgnored, exitCode)
+	}
+	if diff := cmp.Diff(ExitCodeNotIgnored, *exitCode); diff != "" {
+		t.Errorf("unexpected exit code (-want, +got): \n%s", diff)
+	}
+	if diff := cmp.Diff("", b.String()); diff != "" {
+		t.Errorf("unexpected error output (-want, +got): \n%s", diff)
+	}
+}
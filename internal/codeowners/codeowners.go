@@ -0,0 +1,144 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package codeowners implements parsing and matching of CODEOWNERS files.
+package codeowners
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// Locations are the locations, relative to the repository root, that are
+// searched for a CODEOWNERS file in order.
+var Locations = []string{
+	filepath.Join(".github", "CODEOWNERS"),
+	"CODEOWNERS",
+	filepath.Join("docs", "CODEOWNERS"),
+}
+
+var errParse = errors.New("parsing CODEOWNERS")
+
+// Rule is a single CODEOWNERS rule.
+type Rule struct {
+	// Pattern is the file pattern.
+	Pattern string
+
+	// Owners are the owners of files that match the pattern. It may be empty
+	// if the matching files have no owners.
+	Owners []string
+
+	// matcher matches paths using gitignore semantics.
+	matcher gitignore.Pattern
+
+	// depth is the number of path components matching paths must have for
+	// patterns ending in "/*" or zero for other patterns.
+	depth int
+}
+
+// match returns whether the rule matches the slash separated path.
+func (r *Rule) match(path []string) bool {
+	// NOTE: Unlike gitignore, patterns ending in "/*" only match files
+	// directly in the directory and not in its subdirectories.
+	if r.depth > 0 && len(path) != r.depth {
+		return false
+	}
+	return r.matcher.Match(path, false) == gitignore.Exclude
+}
+
+// File is a parsed CODEOWNERS file.
+type File struct {
+	// Rules are the rules in the order they appear in the file.
+	Rules []*Rule
+}
+
+// Parse parses a CODEOWNERS file.
+func Parse(r io.Reader) (*File, error) {
+	f := &File{}
+	s := bufio.NewScanner(r)
+	lineNo := 0
+	for s.Scan() {
+		lineNo++
+		line := s.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		pattern := fields[0]
+		if strings.HasPrefix(pattern, "!") {
+			return nil, fmt.Errorf("%w: line %d: negated patterns are not supported", errParse, lineNo)
+		}
+
+		rule := &Rule{
+			Pattern: pattern,
+			Owners:  fields[1:],
+			matcher: gitignore.ParsePattern(pattern, nil),
+		}
+		if strings.HasSuffix(pattern, "/*") && !strings.HasSuffix(pattern, "/**/*") {
+			rule.depth = strings.Count(strings.Trim(pattern, "/"), "/") + 1
+		}
+		f.Rules = append(f.Rules, rule)
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", errParse, err)
+	}
+	return f, nil
+}
+
+// Owners returns the owners of the file at the given path relative to the
+// repository root. The last matching rule takes precedence. It returns nil if
+// no rule matches.
+func (f *File) Owners(path string) []string {
+	parts := strings.Split(filepath.ToSlash(filepath.Clean(path)), "/")
+	for i := len(f.Rules) - 1; i >= 0; i-- {
+		if f.Rules[i].match(parts) {
+			return f.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// Find finds and parses the CODEOWNERS file for the repository at root. It
+// returns nil if no CODEOWNERS file is found.
+func Find(root string) (*File, error) {
+	for _, loc := range Locations {
+		p := filepath.Join(root, loc)
+		fh, err := os.Open(p)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("%w: %w", errParse, err)
+		}
+		defer fh.Close()
+
+		f, err := Parse(fh)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		return f, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codeowners
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/testutils"
+)
+
+const testCodeowners = `# Default owners.
+*       @global-owner
+
+*.js    @js-owner # JavaScript
+/build/logs/ @doctocat
+docs/*  docs@example.com
+apps/   @octocat
+/apps/github
+`
+
+func TestFile_Owners(t *testing.T) {
+	t.Parallel()
+
+	f, err := Parse(strings.NewReader(testCodeowners))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := map[string]struct {
+		path     string
+		expected []string
+	}{
+		"default": {
+			path:     "main.go",
+			expected: []string{"@global-owner"},
+		},
+		"extension": {
+			path:     "src/app.js",
+			expected: []string{"@js-owner"},
+		},
+		"anchored directory": {
+			path:     "build/logs/2024/out.log",
+			expected: []string{"@doctocat"},
+		},
+		"direct children": {
+			path:     "docs/getting-started.md",
+			expected: []string{"docs@example.com"},
+		},
+		"nested children": {
+			path:     "docs/build-app/troubleshooting.md",
+			expected: []string{"@global-owner"},
+		},
+		"directory anywhere": {
+			path:     "foo/apps/main.go",
+			expected: []string{"@octocat"},
+		},
+		"no owners": {
+			path:     "apps/github/main.go",
+			expected: []string{},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tc.expected, f.Owners(tc.path)); diff != "" {
+				t.Errorf("unexpected owners for %q (-want, +got): \n%s", tc.path, diff)
+			}
+		})
+	}
+}
+
+func TestFile_Owners_noMatch(t *testing.T) {
+	t.Parallel()
+
+	f, err := Parse(strings.NewReader("*.js @js-owner\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := f.Owners("main.go"); got != nil {
+		t.Errorf("unexpected owners: %v", got)
+	}
+}
+
+func TestParse_negated(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Parse(strings.NewReader("!*.js @js-owner\n")); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	d := testutils.NewTempDir([]*testutils.File{
+		{
+			Path:     "docs/CODEOWNERS",
+			Contents: []byte("* @docs-owner\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     ".github/CODEOWNERS",
+			Contents: []byte("* @github-owner\n"),
+			Mode:     0o600,
+		},
+	})
+	defer d.Cleanup()
+
+	f, err := Find(d.Dir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"@github-owner"}, f.Owners("main.go")); diff != "" {
+		t.Errorf("unexpected owners (-want, +got): \n%s", diff)
+	}
+}
+
+func TestFind_notFound(t *testing.T) {
+	t.Parallel()
+
+	d := testutils.NewTempDir(nil)
+	defer d.Cleanup()
+
+	f, err := Find(d.Dir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f != nil {
+		t.Errorf("unexpected file: %v", f)
+	}
+}
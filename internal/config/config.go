@@ -0,0 +1,151 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package config implements loading of .todos.yml configuration files.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v1"
+)
+
+// FileName is the name of configuration files.
+const FileName = ".todos.yml"
+
+var errConfig = errors.New("config")
+
+// Config is a configuration file. Configuration files apply to the directory
+// they are in and all of its subdirectories.
+type Config struct {
+	// Types overrides the TODO types for the directory.
+	Types []string `yaml:"types"`
+
+	// AddTypes are TODO types that are added to the inherited TODO types.
+	AddTypes []string `yaml:"add_types"`
+
+	// Exclude are globs for files that should be excluded.
+	Exclude []string `yaml:"exclude"`
+
+	// ExcludeDir are globs for directories that should be excluded.
+	ExcludeDir []string `yaml:"exclude_dir"`
+
+	// Tests are globs for test files that are added to the well-known test
+	// file patterns.
+	Tests []string `yaml:"tests"`
+
+	// TestDirs are globs for test directories that are added to the
+	// well-known test directory patterns.
+	TestDirs []string `yaml:"test_dirs"`
+
+	// Languages sets how files are scanned by their detected language name,
+	// such as "JSON" or "YAML". Languages are scanned by default.
+	Languages map[string]LanguagePolicy `yaml:"languages"`
+
+	// Extensions maps file extensions, such as ".ts", to the language that
+	// files with the extension are scanned as instead of the detected
+	// language.
+	Extensions map[string]string `yaml:"extensions"`
+
+	// Detect are rules that detect the language of files from their contents.
+	// Rules are evaluated in order before the built-in language detection
+	// and the first matching rule is used. Rules in subdirectories are
+	// evaluated before rules inherited from parent directories.
+	Detect []DetectRule `yaml:"detect"`
+
+	// NearMissWarnings enables or disables warnings about TODOs whose type
+	// only differs from one of the TODO types by case. It is inherited from
+	// the parent directory if not set.
+	NearMissWarnings *bool `yaml:"near_miss_warnings"`
+}
+
+// LanguagePolicy is how files of a language are scanned.
+type LanguagePolicy string
+
+const (
+	// LanguageScan scans files of the language as usual. It can also be
+	// given as true.
+	LanguageScan LanguagePolicy = "scan"
+
+	// LanguageSkip never scans files of the language. It can also be given
+	// as false.
+	LanguageSkip LanguagePolicy = "skip"
+
+	// LanguageText scans files of the language as plain text, even if the
+	// language is not supported.
+	LanguageText LanguagePolicy = "text"
+)
+
+// SetYAML implements yaml.Setter. Booleans are accepted for compatibility
+// with configuration files that enable or disable languages.
+func (p *LanguagePolicy) SetYAML(_ string, value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		*p = LanguageSkip
+		if v {
+			*p = LanguageScan
+		}
+	case string:
+		*p = LanguagePolicy(strings.ToLower(v))
+	default:
+		return false
+	}
+	return true
+}
+
+// DetectRule detects the language of files whose contents match a pattern.
+type DetectRule struct {
+	// Extensions limits the rule to files with the given extensions, such as
+	// ".ts". The rule applies to all files if it is empty.
+	Extensions []string `yaml:"extensions"`
+
+	// Pattern is a regular expression that is matched against the start of
+	// the file's contents.
+	Pattern string `yaml:"pattern"`
+
+	// Language is the language that matching files are scanned as.
+	Language string `yaml:"language"`
+}
+
+// Parse parses a configuration file.
+func Parse(b []byte) (*Config, error) {
+	var c Config
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("%w: %w", errConfig, err)
+	}
+	return &c, nil
+}
+
+// Load loads the configuration file in the directory dir. It returns nil if
+// the directory has no configuration file.
+func Load(dir string) (*Config, error) {
+	p := filepath.Join(dir, FileName)
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: %w", errConfig, err)
+	}
+
+	c, err := Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+	return c, nil
+}
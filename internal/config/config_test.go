@@ -0,0 +1,119 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/testutils"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		src      string
+		expected *Config
+		err      bool
+	}{
+		"empty": {
+			src:      "",
+			expected: &Config{},
+		},
+		"full": {
+			src: `types: [TODO, FIXME]
+add_types:
+  - NOTE
+exclude:
+  - "*.pb.go"
+exclude_dir:
+  - testdata
+tests: ["*_it.go"]
+test_dirs: [e2e]
+languages:
+  JSON: false
+  YAML: true
+  Brainfuck: skip
+  Text: Text
+near_miss_warnings: true
+`,
+			expected: &Config{
+				Types:      []string{"TODO", "FIXME"},
+				AddTypes:   []string{"NOTE"},
+				Exclude:    []string{"*.pb.go"},
+				ExcludeDir: []string{"testdata"},
+				Tests:      []string{"*_it.go"},
+				TestDirs:   []string{"e2e"},
+				Languages: map[string]LanguagePolicy{
+					"JSON":      LanguageSkip,
+					"YAML":      LanguageScan,
+					"Brainfuck": LanguageSkip,
+					"Text":      LanguageText,
+				},
+				NearMissWarnings: testutils.AsPtr(true),
+			},
+		},
+		"invalid": {
+			src: "types: [TODO",
+			err: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse([]byte(tc.src))
+			if (err != nil) != tc.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected config (-want, +got): \n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	d := testutils.NewTempDir([]*testutils.File{
+		{
+			Path:     "sub/" + FileName,
+			Contents: []byte("add_types: [NOTE]\n"),
+			Mode:     0o600,
+		},
+	})
+	defer d.Cleanup()
+
+	c, err := Load(d.Dir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c != nil {
+		t.Errorf("unexpected config: %v", c)
+	}
+
+	c, err = Load(filepath.Join(d.Dir(), "sub"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(&Config{AddTypes: []string{"NOTE"}}, c); diff != "" {
+		t.Errorf("unexpected config (-want, +got): \n%s", diff)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package extract converts documents in binary formats, such as word
+// processor documents, into text that can be scanned for TODOs. Extractors
+// are registered for file extensions by files that are only built with build
+// tags so that the default build doesn't depend on them.
+package extract
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Extractor converts the contents of a document into text.
+type Extractor interface {
+	// Extract returns the UTF-8 text of the document with each paragraph on
+	// its own line.
+	Extract(contents []byte) ([]byte, error)
+}
+
+// ExtractorFunc is a function that implements Extractor.
+type ExtractorFunc func(contents []byte) ([]byte, error)
+
+// Extract implements Extractor.Extract.
+func (f ExtractorFunc) Extract(contents []byte) ([]byte, error) {
+	return f(contents)
+}
+
+// extractors are the registered extractors by lowercase file extension.
+var extractors = map[string]Extractor{}
+
+// Register registers the extractor for files with the extension ext, such as
+// ".docx". It is meant to be called from init functions and panics if an
+// extractor is already registered for the extension.
+func Register(ext string, e Extractor) {
+	ext = strings.ToLower(ext)
+	if _, ok := extractors[ext]; ok {
+		panic(fmt.Sprintf("extract: extractor for %q already registered", ext))
+	}
+	extractors[ext] = e
+}
+
+// ForFile returns the extractor for the file with the given name or nil if
+// no extractor is registered for its extension.
+func ForFile(name string) Extractor {
+	return extractors[strings.ToLower(filepath.Ext(name))]
+}
+
+// Extensions returns the sorted file extensions that have registered
+// extractors.
+func Extensions() []string {
+	exts := make([]string, 0, len(extractors))
+	for ext := range extractors {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+	return exts
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extract
+
+import (
+	"testing"
+)
+
+func TestForFile(t *testing.T) {
+	t.Parallel()
+
+	e := ExtractorFunc(func(contents []byte) ([]byte, error) {
+		return contents, nil
+	})
+	Register(".Test-Extract", e)
+
+	testCases := map[string]struct {
+		name     string
+		expected bool
+	}{
+		"registered": {
+			name:     "doc.test-extract",
+			expected: true,
+		},
+		"case insensitive": {
+			name:     "dir/DOC.TEST-EXTRACT",
+			expected: true,
+		},
+		"not registered": {
+			name:     "doc.txt",
+			expected: false,
+		},
+		"no extension": {
+			name:     "test-extract",
+			expected: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := ForFile(tc.name) != nil, tc.expected; got != want {
+				t.Errorf("unexpected extractor for %q, got: %v, want: %v", tc.name, got, want)
+			}
+		})
+	}
+}
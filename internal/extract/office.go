@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build todos_office
+
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var errDocument = errors.New("invalid document")
+
+func init() {
+	// NOTE: Office Open XML and OpenDocument files are zip archives with
+	// the text of the document in an XML file.
+	Register(".docx", &officeExtractor{part: "word/document.xml"})
+	Register(".odt", &officeExtractor{part: "content.xml"})
+}
+
+// officeExtractor extracts the text of word processor documents that are zip
+// archives containing an XML file. Each paragraph and heading element in the
+// XML file is written on its own line.
+type officeExtractor struct {
+	// part is the name of the XML file in the archive.
+	part string
+}
+
+// Extract implements Extractor.Extract.
+func (e *officeExtractor) Extract(contents []byte) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(contents), int64(len(contents)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errDocument, err)
+	}
+	f, err := zr.Open(e.part)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errDocument, err)
+	}
+	defer f.Close()
+
+	var b bytes.Buffer
+	d := xml.NewDecoder(f)
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errDocument, err)
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			b.Write(t)
+		case xml.EndElement:
+			// NOTE: Both formats name paragraph elements "p" in their
+			// own namespaces. OpenDocument headings are "h" elements.
+			if t.Name.Local == "p" || t.Name.Local == "h" {
+				b.WriteByte('\n')
+			}
+		}
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build todos_office
+
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func newArchive(t *testing.T, name, contents string) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	w, err := zw.Create(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := w.Write([]byte(contents)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return b.Bytes()
+}
+
+func TestOfficeExtractor(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		file     string
+		part     string
+		contents string
+		expected string
+		err      bool
+	}{
+		"docx": {
+			file: "design.docx",
+			part: "word/document.xml",
+			contents: `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+				`<w:p><w:r><w:t>Design</w:t></w:r></w:p>` +
+				`<w:p><w:r><w:t>TODO(#1): </w:t></w:r><w:r><w:t>finish</w:t></w:r></w:p>` +
+				`</w:body></w:document>`,
+			expected: "Design\nTODO(#1): finish\n",
+		},
+		"odt": {
+			file: "design.odt",
+			part: "content.xml",
+			contents: `<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"><office:body><office:text>` +
+				`<text:h>Design</text:h><text:p>TODO: finish</text:p>` +
+				`</office:text></office:body></office:document-content>`,
+			expected: "Design\nTODO: finish\n",
+		},
+		"missing part": {
+			file:     "design.docx",
+			part:     "content.xml",
+			contents: "<foo/>",
+			err:      true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			e := ForFile(tc.file)
+			if e == nil {
+				t.Fatalf("no extractor for %q", tc.file)
+			}
+			got, err := e.Extract(newArchive(t, tc.part, tc.contents))
+			if (err != nil) != tc.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, string(got)); diff != "" {
+				t.Errorf("unexpected text (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := ForFile("design.docx").Extract([]byte("not a zip")); err == nil {
+		t.Errorf("expected error for invalid archive")
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package natlang detects the natural language of short texts such as TODO
+// messages.
+package natlang
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Undetermined is the ISO 639-2 code for text whose language could not be
+// detected.
+const Undetermined = "und"
+
+// minLetters is the minimum number of letters needed to detect the language
+// of text written in the Latin script. Shorter texts, such as "fix this", are
+// too ambiguous.
+const minLetters = 12
+
+// scriptLanguages are the languages detected by the script that they are
+// written in. Han characters are Chinese unless the text also contains kana.
+var scriptLanguages = []struct {
+	table *unicode.RangeTable
+	lang  string
+}{
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Hangul, "ko"},
+	{unicode.Han, "zh"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Greek, "el"},
+	{unicode.Arabic, "ar"},
+	{unicode.Hebrew, "he"},
+	{unicode.Thai, "th"},
+	{unicode.Devanagari, "hi"},
+}
+
+// model is a trigram model for a language written in the Latin script.
+type model struct {
+	lang string
+
+	// logProbs are the log probabilities of the trigrams in the samples.
+	logProbs map[string]float64
+
+	// unseen is the log probability of trigrams not in the samples.
+	unseen float64
+}
+
+var (
+	modelsOnce sync.Once
+	models     []*model
+)
+
+// loadModels builds the trigram models from the samples.
+func loadModels() []*model {
+	modelsOnce.Do(func() {
+		langs := make([]string, 0, len(samples))
+		for lang := range samples {
+			langs = append(langs, lang)
+		}
+		sort.Strings(langs)
+
+		for _, lang := range langs {
+			counts := map[string]int{}
+			total := 0
+			for _, t := range trigrams(samples[lang]) {
+				counts[t]++
+				total++
+			}
+			// NOTE: Add-one smoothing so that unseen trigrams don't rule
+			// out a language.
+			denom := float64(total + len(counts) + 1)
+			m := &model{
+				lang:     lang,
+				logProbs: make(map[string]float64, len(counts)),
+				unseen:   math.Log(1 / denom),
+			}
+			for t, n := range counts {
+				m.logProbs[t] = math.Log(float64(n+1) / denom)
+			}
+			models = append(models, m)
+		}
+	})
+	return models
+}
+
+// Languages returns the sorted ISO 639-1 codes of the languages that can be
+// detected.
+func Languages() []string {
+	langs := map[string]bool{}
+	for _, s := range scriptLanguages {
+		langs[s.lang] = true
+	}
+	for lang := range samples {
+		langs[lang] = true
+	}
+	var sorted []string
+	for lang := range langs {
+		sorted = append(sorted, lang)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// Detect returns the ISO 639-1 code of the natural language that the text is
+// written in, such as "en" or "ja". It returns Undetermined if the language
+// can't be detected, for example because the text is too short.
+func Detect(text string) string {
+	var latin, other int
+	scripts := make([]int, len(scriptLanguages))
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		if unicode.Is(unicode.Latin, r) {
+			latin++
+			continue
+		}
+		other++
+		for i, s := range scriptLanguages {
+			if unicode.Is(s.table, r) {
+				scripts[i]++
+				break
+			}
+		}
+	}
+
+	if other > latin {
+		// NOTE: Japanese text is mostly Han characters but Chinese text
+		//       doesn't use kana.
+		best := -1
+		for i, s := range scriptLanguages {
+			if scripts[i] == 0 {
+				continue
+			}
+			if s.lang == "ja" {
+				return "ja"
+			}
+			if best < 0 || scripts[i] > scripts[best] {
+				best = i
+			}
+		}
+		if best < 0 {
+			return Undetermined
+		}
+		return scriptLanguages[best].lang
+	}
+
+	if latin < minLetters {
+		return Undetermined
+	}
+
+	ts := trigrams(text)
+	bestLang := Undetermined
+	bestScore := math.Inf(-1)
+	for _, m := range loadModels() {
+		score := 0.0
+		for _, t := range ts {
+			if p, ok := m.logProbs[t]; ok {
+				score += p
+			} else {
+				score += m.unseen
+			}
+		}
+		if score > bestScore {
+			bestLang, bestScore = m.lang, score
+		}
+	}
+	return bestLang
+}
+
+// trigrams returns the trigrams of the words in the lower case text. Words
+// are padded with spaces so that trigrams at the start and end of words are
+// distinct.
+func trigrams(text string) []string {
+	var ts []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		rs := []rune(" " + w + " ")
+		for i := 0; i+3 <= len(rs); i++ {
+			ts = append(ts, string(rs[i:i+3]))
+		}
+	}
+	return ts
+}
+
+// Words returns the number of words in the text. Each Han, Hiragana, and
+// Katakana character is counted as a word since Chinese and Japanese
+// don't separate words with spaces. Other words are runs of letters and
+// digits.
+func Words(text string) int {
+	n := 0
+	inWord := false
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana):
+			n++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || (inWord && (r == '\'' || r == '’')):
+			if !inWord {
+				n++
+			}
+			inWord = true
+		default:
+			inWord = false
+		}
+	}
+	return n
+}
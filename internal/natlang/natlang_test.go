@@ -0,0 +1,162 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package natlang
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		text     string
+		expected string
+	}{
+		"empty": {
+			text:     "",
+			expected: Undetermined,
+		},
+		"too short": {
+			text:     "fix this",
+			expected: Undetermined,
+		},
+		"symbols": {
+			text:     "#123 !!! ???",
+			expected: Undetermined,
+		},
+		"english": {
+			text:     "remove this workaround after the next release",
+			expected: "en",
+		},
+		"german": {
+			text:     "diese Methode muss noch überarbeitet werden",
+			expected: "de",
+		},
+		"french": {
+			text:     "il faut encore tester cette partie avec des données réelles",
+			expected: "fr",
+		},
+		"spanish": {
+			text:     "hay que revisar esta parte cuando tengamos tiempo",
+			expected: "es",
+		},
+		"italian": {
+			text:     "bisogna ancora controllare questo valore prima di salvarlo",
+			expected: "it",
+		},
+		"dutch": {
+			text:     "deze code moet nog worden opgeschoond voor de volgende versie",
+			expected: "nl",
+		},
+		"portuguese": {
+			text:     "ainda precisamos melhorar o desempenho desta consulta",
+			expected: "pt",
+		},
+		"japanese": {
+			text:     "エラー処理を追加する",
+			expected: "ja",
+		},
+		"japanese kanji and kana": {
+			text:     "後で修正する",
+			expected: "ja",
+		},
+		"chinese": {
+			text:     "以后删除这个函数",
+			expected: "zh",
+		},
+		"korean": {
+			text:     "나중에 수정",
+			expected: "ko",
+		},
+		"russian": {
+			text:     "исправить позже",
+			expected: "ru",
+		},
+		"mixed mostly japanese": {
+			text:     "API の仕様を確認する",
+			expected: "ja",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := Detect(tc.text), tc.expected; got != want {
+				t.Errorf("unexpected language, got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	t.Parallel()
+
+	want := []string{"ar", "de", "el", "en", "es", "fr", "he", "hi", "it", "ja", "ko", "nl", "pt", "ru", "th", "zh"}
+	if diff := cmp.Diff(want, Languages()); diff != "" {
+		t.Errorf("unexpected languages (-want +got):\n%s", diff)
+	}
+}
+
+func TestWords(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		text     string
+		expected int
+	}{
+		"empty": {
+			text:     "",
+			expected: 0,
+		},
+		"comment markers": {
+			text:     "// ---",
+			expected: 0,
+		},
+		"english": {
+			text:     "// don't remove this (yet)",
+			expected: 4,
+		},
+		"numbers": {
+			text:     "# retry 3 times",
+			expected: 3,
+		},
+		"japanese": {
+			text:     "// 後で修正",
+			expected: 4,
+		},
+		"mixed": {
+			text:     "/* API の仕様 */",
+			expected: 4,
+		},
+		"korean": {
+			text:     "// 나중에 수정",
+			expected: 2,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := Words(tc.text), tc.expected; got != want {
+				t.Errorf("unexpected words, got: %d, want: %d", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package natlang
+
+// samples are the texts that the trigram models of languages written in the
+// Latin script are built from. They are written in the style of code
+// comments.
+var samples = map[string]string{
+	"de": `Diese Funktion sollte entfernt werden, sobald die neue Version
+veröffentlicht ist. Wir müssen noch prüfen, ob der Fehler auch unter Windows
+auftritt. Die Werte werden hier nicht richtig berechnet, weil die Zeitzone
+fehlt. Bitte die Konfiguration aus der Datei lesen und nicht fest einbauen.
+Das ist nur eine vorübergehende Lösung für den Test. Später sollten wir die
+Daten zwischenspeichern, damit die Abfrage schneller wird. Hier fehlt noch
+die Fehlerbehandlung, wenn die Verbindung zum Server abbricht. Die Schnittstelle
+ist zu kompliziert und muss vereinfacht werden. Überprüfen, warum der Zähler
+manchmal negativ ist. Diese Klasse wird von keinem anderen Modul verwendet.`,
+
+	"en": `This function should be removed once the new version is released.
+We still need to check whether the bug also happens on Windows. The values are
+not calculated correctly here because the time zone is missing. Please read
+the configuration from the file instead of hard coding it. This is only a
+temporary workaround for the test. Later we should cache the data so that the
+query is faster. Error handling is still missing when the connection to the
+server is lost. The interface is too complicated and needs to be simplified.
+Figure out why the counter is sometimes negative. This class is not used by
+any other module. Update the documentation with the new behavior.`,
+
+	"es": `Esta función debería eliminarse cuando se publique la nueva versión.
+Todavía tenemos que comprobar si el error también ocurre en Windows. Los
+valores no se calculan correctamente aquí porque falta la zona horaria. Por
+favor, leer la configuración del archivo en lugar de escribirla en el código.
+Esto es solo una solución temporal para la prueba. Más adelante deberíamos
+guardar los datos en caché para que la consulta sea más rápida. Todavía falta
+el manejo de errores cuando se pierde la conexión con el servidor. La interfaz
+es demasiado complicada y hay que simplificarla. Averiguar por qué el contador
+a veces es negativo. Esta clase no la usa ningún otro módulo.`,
+
+	"fr": `Cette fonction devrait être supprimée dès que la nouvelle version sera
+publiée. Nous devons encore vérifier si le bogue se produit aussi sous Windows.
+Les valeurs ne sont pas calculées correctement ici parce que le fuseau horaire
+manque. Merci de lire la configuration depuis le fichier au lieu de la coder en
+dur. Ce n'est qu'une solution temporaire pour le test. Plus tard, nous devrions
+mettre les données en cache pour que la requête soit plus rapide. La gestion
+des erreurs manque encore quand la connexion au serveur est perdue. L'interface
+est trop compliquée et doit être simplifiée. Comprendre pourquoi le compteur
+est parfois négatif. Cette classe n'est utilisée par aucun autre module.`,
+
+	"it": `Questa funzione dovrebbe essere rimossa quando verrà pubblicata la nuova
+versione. Dobbiamo ancora verificare se l'errore si verifica anche su Windows.
+I valori non vengono calcolati correttamente qui perché manca il fuso orario.
+Per favore leggere la configurazione dal file invece di scriverla nel codice.
+Questa è solo una soluzione temporanea per il test. Più avanti dovremmo
+memorizzare i dati nella cache in modo che la richiesta sia più veloce. Manca
+ancora la gestione degli errori quando si perde la connessione con il server.
+L'interfaccia è troppo complicata e deve essere semplificata. Capire perché il
+contatore a volte è negativo. Questa classe non è usata da nessun altro modulo.`,
+
+	"nl": `Deze functie moet worden verwijderd zodra de nieuwe versie is
+uitgebracht. We moeten nog controleren of de fout ook onder Windows optreedt.
+De waarden worden hier niet goed berekend omdat de tijdzone ontbreekt. Lees de
+configuratie uit het bestand in plaats van deze in de code vast te leggen. Dit
+is alleen een tijdelijke oplossing voor de test. Later moeten we de gegevens
+in de cache opslaan zodat de zoekopdracht sneller is. De foutafhandeling
+ontbreekt nog wanneer de verbinding met de server wordt verbroken. De
+interface is te ingewikkeld en moet worden vereenvoudigd. Uitzoeken waarom de
+teller soms negatief is. Deze klasse wordt door geen enkele andere module
+gebruikt.`,
+
+	"pt": `Esta função deve ser removida quando a nova versão for lançada. Ainda
+precisamos verificar se o erro também acontece no Windows. Os valores não são
+calculados corretamente aqui porque falta o fuso horário. Por favor, ler a
+configuração do arquivo em vez de deixá-la fixa no código. Isto é apenas uma
+solução temporária para o teste. Mais tarde devemos guardar os dados em cache
+para que a consulta fique mais rápida. Ainda falta o tratamento de erros
+quando a conexão com o servidor é perdida. A interface é complicada demais e
+precisa ser simplificada. Descobrir por que o contador às vezes é negativo.
+Esta classe não é usada por nenhum outro módulo.`,
+}
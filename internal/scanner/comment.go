@@ -14,14 +14,65 @@
 
 package scanner
 
+import "regexp"
+
+// lineEndMatch matches Unix, Windows, and classic Mac OS line endings.
+var lineEndMatch = regexp.MustCompile("\r\n|\r|\n")
+
 // Comment is a generic Comment implementation.
 type Comment struct {
 	Text      string
 	Line      int
 	Multiline bool
+
+	// Doc indicates that the comment is a documentation comment with its own
+	// start sequence, such as R's roxygen comments.
+	Doc bool
+
+	// Offset is the byte offset of the start of the comment in the original
+	// contents before they were decoded.
+	Offset int
+
+	// EndOffset is the byte offset of the end of the comment in the original
+	// contents. The original bytes of the comment are
+	// contents[Offset:EndOffset].
+	EndOffset int
+
+	// Before are the lines preceding the comment. They are only set if
+	// context lines were enabled with SetContextLines.
+	Before []string
+
+	// After are the lines following the comment. They are only set if
+	// context lines were enabled with SetContextLines.
+	After []string
+
+	// Truncated indicates that lines of the comment's text were truncated
+	// because they were longer than the limit set with SetMaxLineLength.
+	Truncated bool
+}
+
+// EndLine returns the line where the comment ends.
+func (c *Comment) EndLine() int {
+	return c.Line + len(lineEndMatch.FindAllStringIndex(c.Text, -1))
 }
 
 // String implements fmt.Stringer.String.
 func (c *Comment) String() string {
 	return c.Text
 }
+
+// CommentAt scans comments with s until it finds the comment that contains
+// line and returns it. It returns nil if line is not part of a comment. s
+// should not have been used to scan comments already.
+func CommentAt(s *CommentScanner, line int) (*Comment, error) {
+	for s.Scan() {
+		c := s.Next()
+		if c.Line > line {
+			break
+		}
+		if line <= c.EndLine() {
+			return c, nil
+		}
+	}
+	return nil, s.Err()
+}
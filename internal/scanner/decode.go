@@ -0,0 +1,122 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scanner
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/transform"
+)
+
+// byteOrderMark is the UTF-8 encoding of the byte order mark U+FEFF.
+var byteOrderMark = []byte("\uFEFF")
+
+// offsetAnchor is a pair of offsets that are known to correspond.
+type offsetAnchor struct {
+	decoded int
+	raw     int
+}
+
+// offsetMap maps byte offsets in decoded contents to byte offsets in the
+// contents they were decoded from. It is a list of anchors sorted by decoded
+// offset. Offsets between anchors are one byte per byte from the previous
+// anchor. A nil offsetMap maps offsets to themselves.
+type offsetMap []offsetAnchor
+
+// raw returns the offset in the original contents for the decoded offset.
+func (m offsetMap) raw(decoded int) int {
+	i := sort.Search(len(m), func(i int) bool { return m[i].decoded > decoded }) - 1
+	if i < 0 {
+		return decoded
+	}
+	return m[i].raw + decoded - m[i].decoded
+}
+
+// add records that the decoded offset corresponds to the raw offset. Anchors
+// that can already be inferred are not added.
+func (m offsetMap) add(decoded, raw int) offsetMap {
+	if m.raw(decoded) == raw {
+		return m
+	}
+	return append(m, offsetAnchor{decoded: decoded, raw: raw})
+}
+
+// decode decodes b one rune at a time so that the offset of each decoded
+// rune in b is known. It returns the decoded contents and their offsets.
+func decode(d *encoding.Decoder, b []byte) ([]byte, offsetMap, error) {
+	out := make([]byte, 0, len(b))
+	var m offsetMap
+	var buf [utf8.UTFMax]byte
+
+	n := 1
+	for raw := 0; raw < len(b); {
+		// NOTE: The destination buffer is grown until a rune fits so that
+		// only one rune is decoded at a time.
+		nDst, nSrc, err := d.Transform(buf[:n], b[raw:], true)
+		if errors.Is(err, transform.ErrShortDst) && nDst == 0 && nSrc == 0 && n < len(buf) {
+			n++
+			continue
+		}
+		if err != nil && !errors.Is(err, transform.ErrShortDst) {
+			return nil, nil, fmt.Errorf("%w: %w", errDecodeCharset, err)
+		}
+		if nDst == 0 && nSrc == 0 {
+			return nil, nil, fmt.Errorf("%w: no progress at offset %d", errDecodeCharset, raw)
+		}
+
+		out = append(out, buf[:nDst]...)
+		raw += nSrc
+		m = m.add(len(out), raw)
+		n = 1
+	}
+	return out, m, nil
+}
+
+// sanitize removes byte order marks anywhere in the decoded contents b and
+// replaces invalid UTF-8 sequences, including encoded lone surrogates, with
+// the Unicode replacement character. It returns the sanitized contents, the
+// number of bytes that were removed or replaced, and the offsets of the
+// sanitized contents in b.
+func sanitize(b []byte) ([]byte, int, offsetMap) {
+	if utf8.Valid(b) && !bytes.Contains(b, byteOrderMark) {
+		return b, 0, nil
+	}
+
+	out := make([]byte, 0, len(b))
+	var m offsetMap
+	replaced := 0
+	for i := 0; i < len(b); {
+		r, size := utf8.DecodeRune(b[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			// NOTE: utf8.DecodeRune treats encoded surrogate halves as
+			// invalid so they are replaced one byte at a time.
+			out = utf8.AppendRune(out, utf8.RuneError)
+			replaced++
+		case r == '\uFEFF':
+			replaced += size
+		default:
+			out = append(out, b[i:i+size]...)
+		}
+		i += size
+		m = m.add(len(out), i)
+	}
+	return out, replaced, m
+}
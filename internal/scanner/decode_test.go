@@ -0,0 +1,213 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scanner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/text/encoding/ianaindex"
+)
+
+func TestSanitize(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		input    string
+		expected string
+		replaced int
+	}{
+		"valid": {
+			input:    "// TODO: foo\n",
+			expected: "// TODO: foo\n",
+		},
+		"leading bom": {
+			input:    "\uFEFF// TODO: foo\n",
+			expected: "// TODO: foo\n",
+			replaced: 3,
+		},
+		"bom in middle": {
+			input:    "package foo\n\uFEFF// TODO: foo\n",
+			expected: "package foo\n// TODO: foo\n",
+			replaced: 3,
+		},
+		"invalid byte": {
+			input:    "// TODO: f\xffoo\n",
+			expected: "// TODO: f\uFFFDoo\n",
+			replaced: 1,
+		},
+		"truncated sequence": {
+			input:    "// TODO: f\xe2\x82oo\n",
+			expected: "// TODO: f\uFFFD\uFFFDoo\n",
+			replaced: 2,
+		},
+		"lone surrogate": {
+			input:    "// TODO: f\xed\xa0\x80oo\n",
+			expected: "// TODO: f\uFFFD\uFFFD\uFFFDoo\n",
+			replaced: 3,
+		},
+		"replacement character": {
+			input:    "// TODO: f\uFFFDoo\n",
+			expected: "// TODO: f\uFFFDoo\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, replaced, _ := sanitize([]byte(tc.input))
+			if diff := cmp.Diff(tc.expected, string(got)); diff != "" {
+				t.Errorf("unexpected output (-want +got):\n%s", diff)
+			}
+			if got, want := replaced, tc.replaced; got != want {
+				t.Errorf("unexpected replaced count, got: %d, want: %d", got, want)
+			}
+		})
+	}
+}
+
+func TestFromBytes_invalid(t *testing.T) {
+	t.Parallel()
+
+	s, err := FromBytes("foo.go", []byte("package foo\n\uFEFF// TODO: f\xffoo\n"), "UTF-8")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s == nil {
+		t.Fatal("expected scanner")
+	}
+
+	var got []string
+	for s.Scan() {
+		got = append(got, s.Next().Text)
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"// TODO: f\uFFFDoo"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected comments (-want +got):\n%s", diff)
+	}
+	if got, want := s.Replaced(), 4; got != want {
+		t.Errorf("unexpected replaced count, got: %d, want: %d", got, want)
+	}
+}
+
+func TestFromBytes_offsets(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		contents []byte
+		charset  string
+		expected []string
+	}{
+		"utf-8": {
+			contents: []byte("package foo\n// TODO: foo\n/* TODO: bar */\n"),
+			charset:  "UTF-8",
+			expected: []string{"// TODO: foo", "/* TODO: bar */"},
+		},
+		"utf-8 sanitized": {
+			contents: []byte("\uFEFFpackage foo\n// TODO: f\xffoo\n/* TODO: b\uFEFFar */\n"),
+			charset:  "UTF-8",
+			expected: []string{"// TODO: f\xffoo", "/* TODO: b\uFEFFar */"},
+		},
+		"shift_jis": {
+			// "// TODO: 日本語\n// TODO: foo\n"
+			contents: []byte("// TODO: \x93\xfa\x96\x7b\x8c\xea\n// TODO: foo\n"),
+			charset:  "Shift_JIS",
+			expected: []string{"// TODO: \x93\xfa\x96\x7b\x8c\xea", "// TODO: foo"},
+		},
+		"windows-1252": {
+			// "// TODO: café\n/* TODO: naïve */\n"
+			contents: []byte("// TODO: caf\xe9\n/* TODO: na\xefve */\n"),
+			charset:  "windows-1252",
+			expected: []string{"// TODO: caf\xe9", "/* TODO: na\xefve */"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := FromBytes("foo.go", tc.contents, tc.charset)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s == nil {
+				t.Fatal("expected scanner")
+			}
+
+			var got []string
+			for s.Scan() {
+				c := s.Next()
+				got = append(got, string(tc.contents[c.Offset:c.EndOffset]))
+			}
+			if err := s.Err(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected raw comments (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		contents []byte
+		charset  string
+	}{
+		"shift_jis": {
+			contents: []byte("// TODO: \x93\xfa\x96\x7b\x8c\xea\n"),
+			charset:  "Shift_JIS",
+		},
+		"windows-1252": {
+			contents: []byte("// TODO: caf\xe9\n"),
+			charset:  "windows-1252",
+		},
+		"iso-2022-jp": {
+			contents: []byte("// TODO: \x1b$BF|K\\8l\x1b(B\n"),
+			charset:  "ISO-2022-JP",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			e, err := ianaindex.IANA.Encoding(tc.charset)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want, err := e.NewDecoder().Bytes(tc.contents)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, _, err := decode(e.NewDecoder(), tc.contents)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(string(want), string(got)); diff != "" {
+				t.Errorf("unexpected output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-enry/go-enry/v2"
+	"github.com/go-enry/go-enry/v2/data"
+	"github.com/saintfish/chardet"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+
+	"github.com/ianlewis/todos/internal/vendoring"
+)
+
+// minifiedLineLength is the average line length above which JavaScript and
+// CSS files are considered minified. This is the same heuristic used by
+// linguist.
+const minifiedLineLength = 110
+
+// Detection is the result of detecting the language and kind of a file.
+type Detection struct {
+	// Language is the detected language name. It is empty if the language
+	// could not be detected.
+	Language string
+
+	// LanguageDetection describes how the language was detected. It is nil
+	// if the language could not be detected.
+	LanguageDetection *LanguageDetection
+
+	// Binary indicates that the file contents are binary.
+	Binary bool
+
+	// Generated indicates that the file is generated.
+	Generated bool
+
+	// Vendored indicates that the file is in a vendored directory.
+	Vendored bool
+
+	// Minified indicates that the file is minified JavaScript or CSS.
+	Minified bool
+
+	// Config is the scanner configuration for the language. It is nil if the
+	// language is not supported.
+	Config *Config
+}
+
+// Detect detects the language and kind of the file with the given name and
+// contents. It uses the same detection as FromBytes so that other tools can
+// get consistent results without scanning the file.
+func Detect(fileName string, rawContents []byte, charset string) (*Detection, error) {
+	d := &Detection{
+		Binary:    enry.IsBinary(rawContents),
+		Generated: enry.IsGenerated(fileName, rawContents),
+		// NOTE: linguist regexs only match paths with *nix path separators.
+		Vendored: vendoring.IsVendor(filepath.ToSlash(fileName)),
+	}
+	if d.Binary {
+		return d, nil
+	}
+
+	decodedContents, _, _, err := decodeContents(rawContents, charset)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Language, d.LanguageDetection = detectLanguage(fileName, decodedContents)
+	d.Config = LanguagesConfig[d.Language]
+	d.Minified = isMinified(d.Language, decodedContents)
+	return d, nil
+}
+
+// decodeContents decodes the contents from the charset to UTF-8 and replaces
+// invalid sequences. It returns the decoded contents, the number of bytes
+// that were replaced, and the maps from decoded offsets to offsets in the
+// original contents.
+func decodeContents(rawContents []byte, charset string) ([]byte, int, []offsetMap, error) {
+	if charset == "detect" {
+		// Detect the character set.
+		det := chardet.NewTextDetector()
+		result, err := det.DetectBest(rawContents)
+		if err != nil {
+			return nil, 0, nil, fmt.Errorf("%w: %w", errDetectCharset, err)
+		}
+
+		charset = result.Charset
+	}
+
+	// If given ascii (latin1) then treat it as UTF-8 since they
+	// are compatible.
+	if charset == "ISO-8859-1" {
+		charset = "UTF-8"
+	}
+	// See: https://github.com/saintfish/chardet/issues/2
+	if charset == "GB-18030" {
+		charset = "GB18030"
+	}
+
+	e, err := ianaindex.IANA.Encoding(charset)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("%w: %s: %w", errDecodeCharset, charset, err)
+	}
+	if e == nil {
+		return nil, 0, nil, fmt.Errorf("%w: %s: unsupported character set", errDecodeCharset, charset)
+	}
+
+	// NOTE: Decoding is error tolerant. If the contents can't be decoded
+	// they are treated as UTF-8 and invalid sequences are replaced rather
+	// than aborting the whole file. UTF-8 contents are not decoded so that
+	// invalid sequences are counted by sanitize.
+	decodedContents := rawContents
+	var decodedOffsets offsetMap
+	if e != unicode.UTF8 {
+		if decodedContents, decodedOffsets, err = decode(e.NewDecoder(), rawContents); err != nil {
+			decodedContents = rawContents
+			decodedOffsets = nil
+		}
+	}
+	decodedContents, replaced, sanitizedOffsets := sanitize(decodedContents)
+	return decodedContents, replaced, []offsetMap{sanitizedOffsets, decodedOffsets}, nil
+}
+
+// contentHeuristics detect the language of files with ambiguous extensions
+// before falling back to enry. They return an empty string if the language
+// could not be determined.
+var contentHeuristics = map[string]func([]byte) string{
+	".m": detectM,
+}
+
+var (
+	// objectiveCMarkers match lines that only appear in Objective-C files.
+	objectiveCMarkers = regexp.MustCompile(`(?m)^[ \t]*(?:#import\b|#include\b|@interface\b|@implementation\b|@protocol\b|@end\b|//|/\*)`)
+
+	// matlabMarkers match lines that only appear in MATLAB files.
+	matlabMarkers = regexp.MustCompile(`(?m)^[ \t]*(?:%|function\b|classdef\b)`)
+)
+
+// detectM returns the language of a .m file by counting the lines that are
+// specific to Objective-C or MATLAB. enry often detects files with only C
+// style comments as MATLAB.
+func detectM(decodedContents []byte) string {
+	objc := len(objectiveCMarkers.FindAllIndex(decodedContents, -1))
+	matlab := len(matlabMarkers.FindAllIndex(decodedContents, -1))
+	switch {
+	case objc > matlab:
+		return "Objective-C"
+	case matlab > objc:
+		return "MATLAB"
+	}
+	return ""
+}
+
+// Detection methods reported by LanguageDetection.
+const (
+	// MethodOverride is used for languages that were given rather than
+	// detected.
+	MethodOverride = "override"
+
+	// MethodHeuristic is used for languages detected by the local content
+	// heuristics for ambiguous extensions.
+	MethodHeuristic = "heuristic"
+
+	// MethodClassifier is used for languages that enry chose as the most
+	// likely of several candidates using its Bayesian classifier.
+	MethodClassifier = "classifier"
+)
+
+// LanguageDetection describes how the language of a file was detected.
+type LanguageDetection struct {
+	// Method is the strategy that detected the language, such as "filename",
+	// "extension", "shebang", "content", or "classifier".
+	Method string
+
+	// Candidates are the languages that the file could have been detected as
+	// before Method chose between them. It only includes the detected
+	// language if there was no ambiguity.
+	Candidates []string
+
+	// Confident indicates that Method identified the language rather than
+	// guessing the most likely candidate.
+	Confident bool
+}
+
+// languageStrategies are enry's default strategies in order along with the
+// methods reported for them.
+var languageStrategies = []struct {
+	method   string
+	strategy enry.Strategy
+}{
+	{"modeline", enry.GetLanguagesByModeline},
+	{"filename", enry.GetLanguagesByFilename},
+	{"shebang", enry.GetLanguagesByShebang},
+	{"extension", enry.GetLanguagesByExtension},
+	{"xml", enry.GetLanguagesByXML},
+	{"manpage", enry.GetLanguagesByManpage},
+	{"content", enry.GetLanguagesByContent},
+	{MethodClassifier, enry.GetLanguagesByClassifier},
+}
+
+// detectLanguage returns the programming language of the file with the given
+// name and decoded contents along with how it was detected. The detection is
+// nil if the language could not be detected.
+func detectLanguage(fileName string, decodedContents []byte) (string, *LanguageDetection) {
+	if lang, ok := filenameLanguages[filepath.Base(fileName)]; ok {
+		return lang, &LanguageDetection{Method: "filename", Candidates: []string{lang}, Confident: true}
+	}
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if lang, ok := extensionLanguages[ext]; ok {
+		return lang, &LanguageDetection{Method: "extension", Candidates: []string{lang}, Confident: true}
+	}
+	if h, found := contentHeuristics[ext]; found {
+		if lang := h(decodedContents); lang != "" {
+			return lang, &LanguageDetection{
+				Method:     MethodHeuristic,
+				Candidates: enry.GetLanguagesByExtension(fileName, decodedContents, nil),
+			}
+		}
+	}
+	return detectEnryLanguage(fileName, decodedContents)
+}
+
+// detectEnryLanguage detects the language of the file in the same way as
+// enry.GetLanguage but also returns how the language was detected.
+func detectEnryLanguage(fileName string, decodedContents []byte) (string, *LanguageDetection) {
+	var candidates, langs []string
+	var method string
+	for _, s := range languageStrategies {
+		found := s.strategy(fileName, decodedContents, langs)
+		if len(found) == 0 {
+			continue
+		}
+		if len(langs) == 0 {
+			candidates = found
+		} else {
+			candidates = langs
+		}
+		method = s.method
+		if len(found) == 1 {
+			return found[0], &LanguageDetection{
+				Method:     method,
+				Candidates: candidates,
+				Confident:  method != MethodClassifier,
+			}
+		}
+		langs = found
+	}
+
+	// NOTE: Like enry, use the first of the remaining languages. The
+	// classifier sorts them by probability so it is the most likely.
+	for _, lang := range langs {
+		if lang != "" {
+			return lang, &LanguageDetection{Method: method, Candidates: candidates}
+		}
+	}
+	return "", nil
+}
+
+// ExtensionLanguages returns the supported languages that files with each
+// extension can be detected as. Files with extensions that have more than one
+// language are disambiguated by their contents.
+func ExtensionLanguages() map[string][]string {
+	m := map[string][]string{}
+	for ext, langs := range data.LanguagesByExtension {
+		for _, lang := range langs {
+			if _, ok := LanguagesConfig[lang]; ok {
+				m[ext] = append(m[ext], lang)
+			}
+		}
+	}
+	for ext, lang := range extensionLanguages {
+		m[ext] = []string{lang}
+	}
+	return m
+}
+
+// isMinified returns whether the decoded contents of a file in the given
+// language are minified.
+func isMinified(lang string, decodedContents []byte) bool {
+	if lang != "JavaScript" && lang != "CSS" {
+		return false
+	}
+	lines := bytes.Split(decodedContents, []byte("\n"))
+	return len(decodedContents)/len(lines) > minifiedLineLength
+}
@@ -0,0 +1,242 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scanner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		fileName string
+		contents []byte
+		expected *Detection
+	}{
+		"go": {
+			fileName: "main.go",
+			contents: []byte("package main\n"),
+			expected: &Detection{
+				Language: "Go",
+				LanguageDetection: &LanguageDetection{
+					Method:     "extension",
+					Candidates: []string{"Go"},
+					Confident:  true,
+				},
+				Config: LanguagesConfig["Go"],
+			},
+		},
+		"shebang": {
+			fileName: "script",
+			contents: []byte("#!/bin/bash\necho foo\n"),
+			expected: &Detection{
+				Language: "Shell",
+				LanguageDetection: &LanguageDetection{
+					Method:     "shebang",
+					Candidates: []string{"Shell"},
+					Confident:  true,
+				},
+				Config: LanguagesConfig["Shell"],
+			},
+		},
+		"binary": {
+			fileName: "main.go",
+			contents: []byte("\x00\x01\x02\x03"),
+			expected: &Detection{
+				Binary: true,
+			},
+		},
+		"generated": {
+			fileName: "package-lock.json",
+			contents: []byte("{}"),
+			expected: &Detection{
+				Language: "JSON",
+				LanguageDetection: &LanguageDetection{
+					Method:     "content",
+					Candidates: []string{"JSON", "OASv2-json", "OASv3-json"},
+					Confident:  true,
+				},
+				Generated: true,
+				Config:    LanguagesConfig["JSON"],
+			},
+		},
+		"vendored": {
+			fileName: "vendor/lib.go",
+			contents: []byte("package lib\n"),
+			expected: &Detection{
+				Language: "Go",
+				LanguageDetection: &LanguageDetection{
+					Method:     "extension",
+					Candidates: []string{"Go"},
+					Confident:  true,
+				},
+				Vendored: true,
+				Config:   LanguagesConfig["Go"],
+			},
+		},
+		"minified": {
+			fileName: "app.js",
+			contents: []byte("var a=1;" + strings.Repeat("a+=1;", 50)),
+			expected: &Detection{
+				Language: "JavaScript",
+				LanguageDetection: &LanguageDetection{
+					Method:     "extension",
+					Candidates: []string{"JavaScript"},
+					Confident:  true,
+				},
+				// NOTE: Minified files are also considered generated.
+				Generated: true,
+				Minified:  true,
+				Config:    LanguagesConfig["JavaScript"],
+			},
+		},
+		"unsupported": {
+			fileName: "notes.txt",
+			contents: []byte("Read me.\n"),
+			expected: &Detection{
+				Language: "Text",
+				LanguageDetection: &LanguageDetection{
+					Method:     "content",
+					Candidates: []string{"Adblock Filter List", "Text", "Vim Help File"},
+					Confident:  true,
+				},
+			},
+		},
+		"content": {
+			fileName: "app.ts",
+			contents: []byte("let x: number = 1;\n"),
+			expected: &Detection{
+				Language: "TypeScript",
+				LanguageDetection: &LanguageDetection{
+					Method:     "content",
+					Candidates: []string{"TypeScript", "XML"},
+					Confident:  true,
+				},
+				Config: LanguagesConfig["TypeScript"],
+			},
+		},
+		"classifier": {
+			fileName: "script.pl",
+			contents: []byte("print 1;\n"),
+			expected: &Detection{
+				Language: "Perl",
+				LanguageDetection: &LanguageDetection{
+					Method:     "classifier",
+					Candidates: []string{"Perl", "Prolog", "Raku"},
+				},
+				Config: LanguagesConfig["Perl"],
+			},
+		},
+		"heuristic": {
+			fileName: "foo.m",
+			contents: []byte("#import <Foundation/Foundation.h>\n"),
+			expected: &Detection{
+				Language: "Objective-C",
+				LanguageDetection: &LanguageDetection{
+					Method:     "heuristic",
+					Candidates: []string{"Limbo", "M", "MATLAB", "MUF", "Mathematica", "Mercury", "Objective-C"},
+				},
+				Config: LanguagesConfig["Objective-C"],
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Detect(tc.fileName, tc.contents, "UTF-8")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			// NOTE: Configs are compared by identity since they contain funcs.
+			sameConfig := cmp.Comparer(func(x, y *Config) bool { return x == y })
+			if diff := cmp.Diff(tc.expected, got, sameConfig); diff != "" {
+				t.Errorf("unexpected detection (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExtensionLanguages(t *testing.T) {
+	t.Parallel()
+
+	langs := ExtensionLanguages()
+
+	testCases := map[string][]string{
+		".go":       {"Go"},
+		".ts":       {"TypeScript", "XML"},
+		".gdshader": {"GLSL"},
+		".txt":      nil,
+	}
+
+	for ext, expected := range testCases {
+		t.Run(ext, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(expected, langs[ext]); diff != "" {
+				t.Errorf("unexpected languages (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDetect_m(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		contents string
+		expected string
+	}{
+		"matlab function": {
+			contents: "function y = f(x)\n% TODO: foo\ny = x;\nend\n",
+			expected: "MATLAB",
+		},
+		"matlab script": {
+			contents: "x = [1 2 3];\ndisp(x)\n",
+			expected: "MATLAB",
+		},
+		"objective-c import": {
+			contents: "#import <Foundation/Foundation.h>\n// TODO: foo\n",
+			expected: "Objective-C",
+		},
+		"objective-c line comment": {
+			contents: "// TODO: only a comment\n",
+			expected: "Objective-C",
+		},
+		"objective-c block comment": {
+			contents: "/* TODO: only a comment */\n",
+			expected: "Objective-C",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Detect("foo.m", []byte(tc.contents), "UTF-8")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Language != tc.expected {
+				t.Errorf("unexpected language, got: %q, want: %q", got.Language, tc.expected)
+			}
+		})
+	}
+}
@@ -70,9 +70,110 @@ var (
 			AtLineStart: false,
 		},
 	}
+
+	// htmlStrings are C-style strings and CDATA sections. Comments inside
+	// CDATA sections are not markup so they are treated as strings.
+	htmlStrings = append([]StringConfig{
+		{
+			Start:      []rune("<![CDATA["),
+			End:        []rune("]]>"),
+			EscapeFunc: NoEscape,
+		},
+	}, cStrings...)
+
+	// xmlStrings are htmlStrings and XML processing instructions (including
+	// the XML declaration) which can't contain comments.
+	// NOTE: Comments in DTD internal subsets are real comments and quoted
+	// entity values are already strings.
+	xmlStrings = append([]StringConfig{
+		{
+			Start:      []rune("<?"),
+			End:        []rune("?>"),
+			EscapeFunc: NoEscape,
+		},
+	}, htmlStrings...)
 )
 
+// filenameLanguages maps file names that are not recognized by enry to
+// supported languages.
+var filenameLanguages = map[string]string{
+	// Dune only recognizes dune-project files.
+	"dune":           "Dune",
+	"dune-workspace": "Dune",
+
+	// Ignore files that are not in enry's Ignore List file names.
+	".gcloudignore": "Ignore List",
+	".helmignore":   "Ignore List",
+}
+
+// extensionLanguages maps file extensions that are not recognized by enry to
+// supported languages.
+var extensionLanguages = map[string]string{
+	// Godot shading language.
+	".gdshader": "GLSL",
+	// Unity compute shaders.
+	".compute": "HLSL",
+	// Unreal Engine shaders.
+	".usf": "HLSL",
+	".ush": "HLSL",
+	// MySQL is not distinguished from other SQL dialects by enry.
+	".mysql": "MySQL",
+	// NOTE: enry detects .sps files as Scheme which is not supported.
+	".sps": "SPSS",
+}
+
+// PlainText is the configuration used to scan files as plain text. Only sh
+// style line comments are recognized.
+var PlainText = &Config{
+	LineComments: hashLineComments,
+}
+
+// Text is the configuration used to scan files for TODOs on whole lines
+// without recognizing comments.
+var Text = &Config{
+	WholeLines: true,
+}
+
 var LanguagesConfig = map[string]*Config{
+	"ABAP": {
+		LineComments: []LineCommentConfig{
+			{Start: []rune{'*'}, AtLineStart: true},
+			{Start: []rune{'"'}},
+		},
+		MultilineComments: nil,
+		Strings: []StringConfig{
+			// Text field literals
+			{
+				Start:      []rune{'\''},
+				End:        []rune{'\''},
+				EscapeFunc: DoubleEscape,
+			},
+			// Text string literals
+			{
+				Start:      []rune{'`'},
+				End:        []rune{'`'},
+				EscapeFunc: DoubleEscape,
+			},
+			// String templates
+			{
+				Start:      []rune{'|'},
+				End:        []rune{'|'},
+				EscapeFunc: CharEscape('\\'),
+			},
+		},
+	},
+	"Apex": {
+		LineComments:      cLineComments,
+		MultilineComments: cBlockComments,
+		// NOTE: Apex only supports single quoted strings.
+		Strings: []StringConfig{
+			{
+				Start:      []rune{'\''},
+				End:        []rune{'\''},
+				EscapeFunc: CharEscape('\\'),
+			},
+		},
+	},
 	"Assembly": {
 		LineComments: []LineCommentConfig{
 			{
@@ -99,6 +200,17 @@ var LanguagesConfig = map[string]*Config{
 			},
 		},
 	},
+	"Awk": {
+		LineComments:      hashLineComments,
+		MultilineComments: nil,
+		Strings: []StringConfig{
+			{
+				Start:      []rune{'"'},
+				End:        []rune{'"'},
+				EscapeFunc: CharEscape('\\'),
+			},
+		},
+	},
 	"C": {
 		LineComments:      cLineComments,
 		MultilineComments: cBlockComments,
@@ -114,6 +226,25 @@ var LanguagesConfig = map[string]*Config{
 		MultilineComments: cBlockComments,
 		Strings:           cStrings,
 	},
+	"Cairo": {
+		// NOTE: Cairo 1.0 only supports line comments.
+		LineComments:      cLineComments,
+		MultilineComments: nil,
+		Strings: []StringConfig{
+			// ByteArray strings
+			{
+				Start:      []rune{'"'},
+				End:        []rune{'"'},
+				EscapeFunc: CharEscape('\\'),
+			},
+			// Short strings
+			{
+				Start:      []rune{'\''},
+				End:        []rune{'\''},
+				EscapeFunc: CharEscape('\\'),
+			},
+		},
+	},
 	"Clojure": {
 		LineComments: []LineCommentConfig{
 			{Start: []rune{';'}},
@@ -139,6 +270,25 @@ var LanguagesConfig = map[string]*Config{
 		MultilineComments: nil,
 		Strings:           cStrings,
 	},
+	"Dune": {
+		LineComments: []LineCommentConfig{
+			{Start: []rune{';'}},
+		},
+		MultilineComments: []MultilineCommentConfig{
+			{
+				Start:       []rune("#|"),
+				End:         []rune("|#"),
+				AtLineStart: false,
+			},
+		},
+		Strings: []StringConfig{
+			{
+				Start:      []rune{'"'},
+				End:        []rune{'"'},
+				EscapeFunc: CharEscape('\\'),
+			},
+		},
+	},
 	"Elixir": {
 		LineComments: hashLineComments,
 		// Support function documentation.
@@ -195,9 +345,43 @@ var LanguagesConfig = map[string]*Config{
 		MultilineComments: nil,
 		Strings:           cStrings,
 	},
+	"F#": {
+		LineComments: cLineComments,
+		MultilineComments: []MultilineCommentConfig{
+			{
+				Start:       []rune("(*"),
+				End:         []rune("*)"),
+				AtLineStart: false,
+				Nested:      true,
+				// NOTE: "(*)" is the multiplication operator.
+				Except: []rune("(*)"),
+			},
+		},
+		// NOTE: Character literals are not supported because the single quote
+		// is also used for generic type parameters (e.g. 'T).
+		Strings: []StringConfig{
+			// NOTE: Triple-quoted strings must come first so that they take
+			// precedence over normal strings.
+			{
+				Start:      []rune("\"\"\""),
+				End:        []rune("\"\"\""),
+				EscapeFunc: NoEscape,
+			},
+			{
+				Start:      []rune{'"'},
+				End:        []rune{'"'},
+				EscapeFunc: CharEscape('\\'),
+			},
+		},
+	},
 	"Fortran": {
+		// NOTE: Fixed form comments are denoted by a character in the first
+		// column.
 		LineComments: []LineCommentConfig{
 			{Start: []rune{'!'}},
+			{Start: []rune{'C'}, AtLineStart: true},
+			{Start: []rune{'c'}, AtLineStart: true},
+			{Start: []rune{'*'}, AtLineStart: true},
 		},
 		MultilineComments: nil,
 		Strings: []StringConfig{
@@ -229,6 +413,41 @@ var LanguagesConfig = map[string]*Config{
 			},
 		},
 	},
+	"GDScript": {
+		// NOTE: Unlike Python, triple-quoted strings are not treated as
+		// comments.
+		LineComments:      hashLineComments,
+		MultilineComments: nil,
+		Strings: []StringConfig{
+			// NOTE: Triple-quoted strings must come first so that they take
+			// precedence over normal strings.
+			{
+				Start:      []rune("\"\"\""),
+				End:        []rune("\"\"\""),
+				EscapeFunc: CharEscape('\\'),
+			},
+			{
+				Start:      []rune("'''"),
+				End:        []rune("'''"),
+				EscapeFunc: CharEscape('\\'),
+			},
+			{
+				Start:      []rune{'"'},
+				End:        []rune{'"'},
+				EscapeFunc: CharEscape('\\'),
+			},
+			{
+				Start:      []rune{'\''},
+				End:        []rune{'\''},
+				EscapeFunc: CharEscape('\\'),
+			},
+		},
+	},
+	"GLSL": {
+		LineComments:      cLineComments,
+		MultilineComments: cBlockComments,
+		Strings:           cStrings,
+	},
 	"Go": {
 		LineComments:      cLineComments,
 		MultilineComments: cBlockComments,
@@ -288,10 +507,15 @@ var LanguagesConfig = map[string]*Config{
 			},
 		},
 	},
+	"HLSL": {
+		LineComments:      cLineComments,
+		MultilineComments: cBlockComments,
+		Strings:           cStrings,
+	},
 	"HTML": {
 		LineComments:      nil,
 		MultilineComments: xmlBlockComments,
-		Strings:           cStrings,
+		Strings:           htmlStrings,
 	},
 	"HTML+ERB": {
 		LineComments: nil,
@@ -324,6 +548,16 @@ var LanguagesConfig = map[string]*Config{
 		},
 		Strings: cStrings,
 	},
+	"Ignore List": {
+		// NOTE: Only lines starting with # are comments. A # anywhere else is
+		// part of a pattern.
+		LineComments: []LineCommentConfig{
+			{
+				Start:       []rune("#"),
+				AtLineStart: true,
+			},
+		},
+	},
 	"JSON": {
 		// NOTE: Some JSON parsers support comments.
 		LineComments: []LineCommentConfig{
@@ -387,6 +621,68 @@ var LanguagesConfig = map[string]*Config{
 		MultilineComments: nil,
 		Strings:           cStrings,
 	},
+	"Move": {
+		LineComments:      cLineComments,
+		MultilineComments: cBlockComments,
+		// NOTE: Move doesn't have character literals.
+		Strings: []StringConfig{
+			{
+				Start:      []rune{'"'},
+				End:        []rune{'"'},
+				EscapeFunc: CharEscape('\\'),
+			},
+		},
+	},
+	"MySQL": {
+		LineComments: []LineCommentConfig{
+			{
+				Start: []rune("--"),
+			},
+			{
+				Start: []rune{'#'},
+			},
+		},
+		MultilineComments: cBlockComments,
+		// NOTE: Doubled quotes are handled as adjacent strings.
+		Strings: []StringConfig{
+			{
+				Start:      []rune{'"'},
+				End:        []rune{'"'},
+				EscapeFunc: CharEscape('\\'),
+			},
+			{
+				Start:      []rune{'\''},
+				End:        []rune{'\''},
+				EscapeFunc: CharEscape('\\'),
+			},
+			// Quoted identifiers.
+			{
+				Start:      []rune{'`'},
+				End:        []rune{'`'},
+				EscapeFunc: DoubleEscape,
+			},
+		},
+	},
+	"OCaml": {
+		LineComments: nil,
+		MultilineComments: []MultilineCommentConfig{
+			{
+				Start:       []rune("(*"),
+				End:         []rune("*)"),
+				AtLineStart: false,
+				Nested:      true,
+			},
+		},
+		// NOTE: Character literals are not supported because the single quote
+		// is also used for type variables (e.g. 'a).
+		Strings: []StringConfig{
+			{
+				Start:      []rune{'"'},
+				End:        []rune{'"'},
+				EscapeFunc: CharEscape('\\'),
+			},
+		},
+	},
 	"Objective-C": {
 		LineComments:      cLineComments,
 		MultilineComments: cBlockComments,
@@ -433,6 +729,105 @@ var LanguagesConfig = map[string]*Config{
 		MultilineComments: cBlockComments,
 		Strings:           cStrings,
 	},
+	"PLSQL": {
+		LineComments: []LineCommentConfig{
+			{
+				Start: []rune("--"),
+			},
+		},
+		MultilineComments: cBlockComments,
+		Strings: []StringConfig{
+			// Alternative quoting mechanism (e.g. q'[It's a string]').
+			// NOTE: Only the most common delimiters are supported.
+			{
+				Start:      []rune("q'["),
+				End:        []rune("]'"),
+				EscapeFunc: NoEscape,
+			},
+			{
+				Start:      []rune("q'{"),
+				End:        []rune("}'"),
+				EscapeFunc: NoEscape,
+			},
+			{
+				Start:      []rune("q'<"),
+				End:        []rune(">'"),
+				EscapeFunc: NoEscape,
+			},
+			{
+				Start:      []rune("q'("),
+				End:        []rune(")'"),
+				EscapeFunc: NoEscape,
+			},
+			{
+				Start:      []rune("q'!"),
+				End:        []rune("!'"),
+				EscapeFunc: NoEscape,
+			},
+			{
+				Start:      []rune("Q'["),
+				End:        []rune("]'"),
+				EscapeFunc: NoEscape,
+			},
+			{
+				Start:      []rune("Q'{"),
+				End:        []rune("}'"),
+				EscapeFunc: NoEscape,
+			},
+			{
+				Start:      []rune("Q'<"),
+				End:        []rune(">'"),
+				EscapeFunc: NoEscape,
+			},
+			{
+				Start:      []rune("Q'("),
+				End:        []rune(")'"),
+				EscapeFunc: NoEscape,
+			},
+			{
+				Start:      []rune("Q'!"),
+				End:        []rune("!'"),
+				EscapeFunc: NoEscape,
+			},
+			{
+				Start:      []rune{'"'},
+				End:        []rune{'"'},
+				EscapeFunc: DoubleEscape,
+			},
+			{
+				Start:      []rune{'\''},
+				End:        []rune{'\''},
+				EscapeFunc: DoubleEscape,
+			},
+		},
+	},
+	"PLpgSQL": {
+		LineComments: []LineCommentConfig{
+			{
+				Start: []rune("--"),
+			},
+		},
+		MultilineComments: cBlockComments,
+		Strings: []StringConfig{
+			// Dollar-quoted strings (e.g. $$It's a string$$ or $fn$...$fn$).
+			{
+				Start:      []rune{'$'},
+				End:        []rune{'$'},
+				EscapeFunc: NoEscape,
+				Tagged:     true,
+			},
+			{
+				Start:      []rune{'"'},
+				End:        []rune{'"'},
+				EscapeFunc: DoubleEscape,
+			},
+			{
+				Start:      []rune{'\''},
+				End:        []rune{'\''},
+				EscapeFunc: DoubleEscape,
+			},
+		},
+	},
 	"Perl": {
 		LineComments: hashLineComments,
 		MultilineComments: []MultilineCommentConfig{
@@ -483,10 +878,56 @@ var LanguagesConfig = map[string]*Config{
 		Strings: cStrings,
 	},
 	"R": {
-		LineComments:      hashLineComments,
+		LineComments: []LineCommentConfig{
+			// roxygen documentation comments.
+			{
+				Start: []rune("#'"),
+				Doc:   true,
+			},
+			{
+				Start: []rune("#"),
+			},
+		},
 		MultilineComments: nil,
 		Strings:           cStrings,
 	},
+	// NOTE: R Markdown and Quarto documents are Markdown with HTML comments
+	// and fenced code chunks. Only R and Python chunks are scanned.
+	"RMarkdown": {
+		LineComments:      nil,
+		MultilineComments: xmlBlockComments,
+		Strings:           nil,
+		Embedded: []EmbeddedConfig{
+			{
+				Start:    []rune("```{r"),
+				End:      []rune("```"),
+				Language: "R",
+			},
+			{
+				Start:    []rune("```{R"),
+				End:      []rune("```"),
+				Language: "R",
+			},
+			{
+				Start:    []rune("```{python"),
+				End:      []rune("```"),
+				Language: "Python",
+			},
+		},
+	},
+	"Reason": {
+		LineComments:      cLineComments,
+		MultilineComments: cBlockComments,
+		// NOTE: Character literals are not supported because the single quote
+		// is also used for type variables (e.g. 'a).
+		Strings: []StringConfig{
+			{
+				Start:      []rune{'"'},
+				End:        []rune{'"'},
+				EscapeFunc: CharEscape('\\'),
+			},
+		},
+	},
 	"Ruby": {
 		LineComments: hashLineComments,
 		MultilineComments: []MultilineCommentConfig{
@@ -517,6 +958,59 @@ var LanguagesConfig = map[string]*Config{
 		MultilineComments: cBlockComments,
 		Strings:           cStrings,
 	},
+	"SAS": {
+		LineComments:      nil,
+		MultilineComments: cBlockComments,
+		// NOTE: A "*" that starts a line in the middle of a statement is also
+		// treated as a comment.
+		StatementComments: []StatementCommentConfig{
+			{
+				Start: []rune("*"),
+				End:   []rune(";"),
+			},
+			// Macro comments.
+			{
+				Start: []rune("%*"),
+				End:   []rune(";"),
+			},
+		},
+		Strings: []StringConfig{
+			{
+				Start:      []rune{'"'},
+				End:        []rune{'"'},
+				EscapeFunc: DoubleEscape,
+			},
+			{
+				Start:      []rune{'\''},
+				End:        []rune{'\''},
+				EscapeFunc: DoubleEscape,
+			},
+		},
+	},
+	"SPSS": {
+		LineComments:      nil,
+		MultilineComments: nil,
+		StatementComments: []StatementCommentConfig{
+			{
+				Start:          []rune("*"),
+				End:            []rune("."),
+				AtLineEnd:      true,
+				EndAtBlankLine: true,
+			},
+		},
+		Strings: []StringConfig{
+			{
+				Start:      []rune{'"'},
+				End:        []rune{'"'},
+				EscapeFunc: DoubleEscape,
+			},
+			{
+				Start:      []rune{'\''},
+				End:        []rune{'\''},
+				EscapeFunc: DoubleEscape,
+			},
+		},
+	},
 	"SQL": {
 		LineComments: []LineCommentConfig{
 			{
@@ -541,11 +1035,48 @@ var LanguagesConfig = map[string]*Config{
 		MultilineComments: cBlockComments,
 		Strings:           cStrings,
 	},
+	"ShaderLab": {
+		LineComments:      cLineComments,
+		MultilineComments: cBlockComments,
+		Strings:           cStrings,
+	},
 	"Shell": {
 		LineComments:      hashLineComments,
 		MultilineComments: nil,
 		Strings:           cStrings,
 	},
+	"Solidity": {
+		// NOTE: NatSpec comments (/// and /**) are handled as normal comments.
+		LineComments:      cLineComments,
+		MultilineComments: cBlockComments,
+		Strings:           cStrings,
+	},
+	"Stata": {
+		LineComments: []LineCommentConfig{
+			{
+				Start: []rune("//"),
+			},
+			{
+				Start:       []rune("*"),
+				AtLineStart: true,
+				AllowIndent: true,
+			},
+		},
+		MultilineComments: cBlockComments,
+		Strings: []StringConfig{
+			// Compound double quotes.
+			{
+				Start:      []rune("`\""),
+				End:        []rune("\"'"),
+				EscapeFunc: NoEscape,
+			},
+			{
+				Start:      []rune{'"'},
+				End:        []rune{'"'},
+				EscapeFunc: NoEscape,
+			},
+		},
+	},
 	"Swift": {
 		LineComments:      cLineComments,
 		MultilineComments: cBlockComments,
@@ -562,6 +1093,49 @@ var LanguagesConfig = map[string]*Config{
 		MultilineComments: nil,
 		Strings:           cStrings,
 	},
+	"TSQL": {
+		LineComments: []LineCommentConfig{
+			{
+				Start: []rune("--"),
+			},
+		},
+		MultilineComments: cBlockComments,
+		Strings: []StringConfig{
+			// Bracket-quoted identifiers (e.g. [My -- Column]).
+			{
+				Start:      []rune{'['},
+				End:        []rune{']'},
+				EscapeFunc: DoubleEscape,
+			},
+			{
+				Start:      []rune{'"'},
+				End:        []rune{'"'},
+				EscapeFunc: DoubleEscape,
+			},
+			{
+				Start:      []rune{'\''},
+				End:        []rune{'\''},
+				EscapeFunc: DoubleEscape,
+			},
+		},
+	},
+	"Tcl": {
+		// NOTE: Comments are only recognized at the start of a command.
+		LineComments: []LineCommentConfig{
+			{Start: []rune{'#'}, AtLineStart: true, AllowIndent: true},
+			{Start: []rune(";#")},
+		},
+		MultilineComments: nil,
+		// NOTE: Braces are not treated as strings because they also delimit
+		// script bodies which can contain comments.
+		Strings: []StringConfig{
+			{
+				Start:      []rune{'"'},
+				End:        []rune{'"'},
+				EscapeFunc: CharEscape('\\'),
+			},
+		},
+	},
 	"TeX": {
 		LineComments: []LineCommentConfig{
 			{
@@ -638,11 +1212,18 @@ var LanguagesConfig = map[string]*Config{
 	"XML": {
 		LineComments:      nil,
 		MultilineComments: xmlBlockComments,
-		Strings:           cStrings,
+		Strings:           xmlStrings,
 	},
 	"YAML": {
 		LineComments:      hashLineComments,
 		MultilineComments: nil,
 		Strings:           cStrings,
 	},
+	"sed": {
+		LineComments: []LineCommentConfig{
+			{Start: []rune{'#'}, AtLineStart: true, AllowIndent: true},
+		},
+		MultilineComments: nil,
+		Strings:           nil,
+	},
 }
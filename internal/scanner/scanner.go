@@ -21,16 +21,20 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/go-enry/go-enry/v2"
 	"github.com/ianlewis/runeio"
-	"github.com/saintfish/chardet"
-	"golang.org/x/text/encoding/ianaindex"
 
 	"github.com/ianlewis/todos/internal/utils"
 )
 
+// maxTagLength is the maximum length of the tag of tagged strings.
+const maxTagLength = 64
+
 var (
 	// errDetectCharset is an error detecting a charset.
 	errDetectCharset = errors.New("detect charset")
@@ -43,11 +47,29 @@ type StringConfig struct {
 	Start      []rune
 	End        []rune
 	EscapeFunc EscapeFunc
+
+	// Tagged indicates that Start is followed by an optional tag and End,
+	// and that the string ends with the same sequence, such as PostgreSQL's
+	// dollar-quoted strings ($tag$...$tag$).
+	Tagged bool
 }
 
 type LineCommentConfig struct {
 	// Start is the starting sequence for the line comment.
 	Start []rune
+
+	// AtLineStart indicates that the line comment must start at the
+	// beginning of a line.
+	AtLineStart bool
+
+	// AllowIndent indicates that a line comment with AtLineStart may be
+	// preceded by whitespace on the line.
+	AllowIndent bool
+
+	// Doc indicates that the line comment is a documentation comment. Doc
+	// comments must be listed before line comments whose start sequence is a
+	// prefix of theirs.
+	Doc bool
 }
 
 type MultilineCommentConfig struct {
@@ -60,6 +82,52 @@ type MultilineCommentConfig struct {
 	// AtLineStart indicates that the multiline comment must start at the
 	// beginning of a line.
 	AtLineStart bool
+
+	// Nested indicates that multiline comments can be nested, such as
+	// OCaml's "(* a (* b *) c *)". The comment only ends when every nested
+	// comment has ended.
+	Nested bool
+
+	// Except is a sequence beginning with Start that doesn't start a
+	// comment, such as F#'s "(*)" multiplication operator.
+	Except []rune
+}
+
+// StatementCommentConfig is configuration for comments that are statements,
+// such as SAS's "* comment;". They start at the start of a statement and end
+// with the statement's terminator, which can be on a later line.
+type StatementCommentConfig struct {
+	// Start is the starting sequence for the comment. It starts a comment at
+	// the start of a line, optionally preceded by whitespace, or when it
+	// follows the end of a previous statement on the same line.
+	Start []rune
+
+	// End is the statement terminator that ends the comment.
+	End []rune
+
+	// AtLineEnd indicates that End only ends the comment at the end of a
+	// line, such as SPSS's "* comment." which can contain periods.
+	AtLineEnd bool
+
+	// EndAtBlankLine indicates that a blank line also ends the comment, such
+	// as in SPSS where a blank line ends a command.
+	EndAtBlankLine bool
+}
+
+// EmbeddedConfig is configuration for blocks of code in another language
+// that are embedded in a document, such as code chunks in R Markdown.
+type EmbeddedConfig struct {
+	// Start is the sequence that starts a block of code. It must be at the
+	// start of a line and the rest of the line is skipped.
+	Start []rune
+
+	// End is the sequence that ends a block of code. It must be at the start
+	// of a line.
+	End []rune
+
+	// Language is the language of the code. Code in the block is scanned
+	// with the language's configuration in LanguagesConfig.
+	Language string
 }
 
 // Config is configuration for a generic comment scanner.
@@ -67,6 +135,17 @@ type Config struct {
 	LineComments      []LineCommentConfig
 	MultilineComments []MultilineCommentConfig
 	Strings           []StringConfig
+
+	// StatementComments are comments that end with a statement terminator.
+	StatementComments []StatementCommentConfig
+
+	// Embedded are the blocks of code that can be embedded in the contents.
+	// The rest of the contents is scanned with this configuration.
+	Embedded []EmbeddedConfig
+
+	// WholeLines indicates that every line that isn't blank is a line
+	// comment. Comment and string configuration is ignored.
+	WholeLines bool
 }
 
 // FromFile returns an appropriate CommentScanner for the given file. The
@@ -83,58 +162,67 @@ func FromFile(f *os.File, charset string) (*CommentScanner, error) {
 // FromBytes returns an appropriate CommentScanner for the given contents. The
 // language is auto-detected and a relevant configuration is used to initialize the scanner.
 func FromBytes(fileName string, rawContents []byte, charset string) (*CommentScanner, error) {
+	return FromBytesAs(fileName, rawContents, charset, "")
+}
+
+// FromBytesAs returns a CommentScanner for the given contents that scans them
+// as the given language instead of auto-detecting it. The language is
+// auto-detected as in FromBytes if lang is empty.
+func FromBytesAs(fileName string, rawContents []byte, charset, lang string) (*CommentScanner, error) {
+	return FromBytesWith(fileName, rawContents, charset, lang, nil)
+}
+
+// FromBytesWith is like FromBytesAs but the contents are scanned with the
+// configuration that config returns for the language if it isn't nil, even if
+// the language is not supported or wasn't detected. config is called with an
+// empty language for contents whose language wasn't detected. config may be
+// nil.
+func FromBytesWith(
+	fileName string,
+	rawContents []byte,
+	charset, lang string,
+	config func(lang string) *Config,
+) (*CommentScanner, error) {
 	// Ignore binary files.
 	if enry.IsBinary(rawContents) {
 		return nil, nil
 	}
 
-	if charset == "detect" {
-		// Detect the character set.
-		det := chardet.NewTextDetector()
-		result, err := det.DetectBest(rawContents)
-		if err != nil {
-			return nil, fmt.Errorf("%w: %w", errDetectCharset, err)
-		}
-
-		charset = result.Charset
-	}
-
-	// If given ascii (latin1) then treat it as UTF-8 since they
-	// are compatible.
-	if charset == "ISO-8859-1" {
-		charset = "UTF-8"
-	}
-	// See: https://github.com/saintfish/chardet/issues/2
-	if charset == "GB-18030" {
-		charset = "GB18030"
-	}
-
-	e, err := ianaindex.IANA.Encoding(charset)
+	decodedContents, replaced, offsets, err := decodeContents(rawContents, charset)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s: %w", errDecodeCharset, charset, err)
-	}
-	if e == nil {
-		return nil, fmt.Errorf("%w: %s: unsupported character set", errDecodeCharset, charset)
-	}
-
-	decodedContents, err := e.NewDecoder().Bytes(rawContents)
-	if err != nil {
-		return nil, fmt.Errorf("%w: %s: %w", errDecodeCharset, charset, err)
+		return nil, err
 	}
 
 	// Detect the programming language.
-	lang := enry.GetLanguage(fileName, decodedContents)
-	if lang == enry.OtherLanguage {
-		return nil, nil
+	var detection *LanguageDetection
+	if lang == "" {
+		lang, detection = detectLanguage(fileName, decodedContents)
+	} else {
+		detection = &LanguageDetection{Method: MethodOverride, Candidates: []string{lang}, Confident: true}
+	}
+	var c *Config
+	if config != nil {
+		c = config(lang)
 	}
+	if c == nil {
+		if lang == enry.OtherLanguage {
+			return nil, nil
+		}
 
-	// Detect the language encoding.
-	config, ok := LanguagesConfig[lang]
-	if !ok {
-		return nil, nil
+		// Detect the language encoding.
+		var ok bool
+		c, ok = LanguagesConfig[lang]
+		if !ok {
+			return nil, nil
+		}
 	}
 
-	return New(bytes.NewReader(decodedContents), config), nil
+	s := New(bytes.NewReader(decodedContents), c)
+	s.language = lang
+	s.detection = detection
+	s.replaced = replaced
+	s.offsets = offsets
+	return s, nil
 }
 
 // New returns a new CommentScanner that scans code returned by r with the given Config.
@@ -144,8 +232,10 @@ func New(r io.Reader, c *Config) *CommentScanner {
 		reader: runeio.NewReader(bufio.NewReader(r)),
 
 		// Starting state
-		state: &stateCode{},
-		line:  1, // NOTE: lines are 1 indexed
+		state:       &stateCode{},
+		atLineStart: true,
+		lineIndent:  true,
+		line:        1, // NOTE: lines are 1 indexed
 	}
 }
 
@@ -154,13 +244,98 @@ type CommentScanner struct {
 	reader *runeio.RuneReader
 	config *Config
 
+	// language is the detected language name if known.
+	language string
+
+	// detection describes how language was detected.
+	detection *LanguageDetection
+
+	// replaced is the number of bytes that were removed or replaced because
+	// they were invalid when decoding the contents.
+	replaced int
+
+	// offsets map offsets in the scanned contents back to offsets in the
+	// original contents. They are applied in order.
+	offsets []offsetMap
+
+	// offset is the byte offset of the next rune in the scanned contents.
+	offset int
+
+	// skipShebang indicates that a shebang line at the start of the contents
+	// should not be returned as a comment.
+	skipShebang bool
+
+	// skipStrings indicates that string literals are not tracked.
+	skipStrings bool
+
+	// contextLines is the number of lines before and after each comment
+	// that are returned with it.
+	contextLines int
+
+	// lines are the complete lines read so far when context lines are
+	// enabled.
+	lines []string
+
+	// lineText is the text of the current line when context lines are
+	// enabled.
+	lineText strings.Builder
+
+	// pending are comments that are waiting for their following context
+	// lines to be read.
+	pending []*Comment
+
+	// done indicates that there are no more comments to scan.
+	done bool
+
+	// text is the text of the comment currently being scanned. It is reused
+	// for each comment.
+	text []byte
+
+	// maxLineLength is the maximum number of bytes of each line of a
+	// comment's text that are captured. There is no limit if it is zero.
+	maxLineLength int
+
+	// lineLength is the number of bytes of the current line of the comment
+	// text.
+	lineLength int
+
+	// truncated indicates that the comment currently being scanned has been
+	// truncated.
+	truncated bool
+
+	// reuse indicates that comment is reused for each comment returned by
+	// Next.
+	reuse bool
+
+	// comment is the comment returned by Next when reuse is enabled.
+	comment Comment
+
+	// stats are the scanner's instrumentation counters.
+	stats Stats
+
 	// state is the current state-machine state.
 	state state
 
+	// document is the configuration of the document while s.config is the
+	// configuration of the embedded code currently being scanned. It is nil
+	// outside of embedded code.
+	document *Config
+
+	// embedded is the embedded code currently being scanned.
+	embedded *EmbeddedConfig
+
 	// atLineStart indicates whether the next character is at the start of the
 	// line.
 	atLineStart bool
 
+	// lineIndent indicates whether only whitespace has been read since the
+	// start of the line.
+	lineIndent bool
+
+	// lastRune is the last non-whitespace rune read on the current line. It
+	// is zero if only whitespace has been read.
+	lastRune rune
+
 	// line is the current line in the input.
 	line int
 
@@ -171,11 +346,142 @@ type CommentScanner struct {
 	err error
 }
 
-// Config returns the scanners configuration.
+// Config returns the scanners configuration. Configurations for embedded
+// code are listed in its Embedded field.
 func (s *CommentScanner) Config() *Config {
+	if s.document != nil {
+		return s.document
+	}
 	return s.config
 }
 
+// Language returns the name of the language detected by FromFile or
+// FromBytes. It returns an empty string for scanners created with New.
+func (s *CommentScanner) Language() string {
+	return s.language
+}
+
+// LanguageDetection returns how the language was detected by FromFile or
+// FromBytes. It returns nil for scanners created with New.
+func (s *CommentScanner) LanguageDetection() *LanguageDetection {
+	return s.detection
+}
+
+// Replaced returns the number of bytes that were removed or replaced because
+// they were invalid when decoding the contents.
+func (s *CommentScanner) Replaced() int {
+	return s.replaced
+}
+
+// SetSkipShebang sets whether a shebang line (e.g. "#!/bin/bash") at the
+// start of the contents is skipped rather than returned as a comment. The
+// shebang is still used for language detection by FromFile and FromBytes.
+func (s *CommentScanner) SetSkipShebang(skip bool) {
+	s.skipShebang = skip
+}
+
+// SetSkipStrings sets whether string literals are tracked. Skipping strings
+// makes scanning faster but comment start sequences inside of strings are
+// then returned as comments. Strings that share their start sequence with a
+// line comment (e.g. in Vim Script) are still handled.
+func (s *CommentScanner) SetSkipStrings(skip bool) {
+	s.skipStrings = skip
+}
+
+// SetContextLines sets the number of lines before and after each comment
+// that are returned in the comment's Before and After fields. Context lines
+// are not returned if n is zero.
+func (s *CommentScanner) SetContextLines(n int) {
+	s.contextLines = n
+}
+
+// SetMaxLineLength sets the maximum number of bytes of each line of a
+// comment's text that are captured. The rest of the line is scanned, and
+// line numbers and offsets are tracked as usual, but its text is dropped and
+// the comment's Truncated field is set. There is no limit if n is zero.
+func (s *CommentScanner) SetMaxLineLength(n int) {
+	s.maxLineLength = n
+}
+
+// SetReuseComments sets whether a single Comment is reused for all comments
+// to reduce allocations when scanning many comments. When enabled, the
+// Comment returned by Next is overwritten by the next call to Scan so
+// callers that retain comments must copy them. Comments are not reused when
+// context lines are enabled.
+func (s *CommentScanner) SetReuseComments(reuse bool) {
+	s.reuse = reuse
+}
+
+// emit sets the comment to be returned by Next.
+func (s *CommentScanner) emit(c Comment) {
+	if s.reuse && s.contextLines == 0 {
+		s.comment = c
+		s.next = &s.comment
+		return
+	}
+	next := c
+	s.next = &next
+}
+
+// resetText sets the text of the comment currently being scanned to prefix.
+func (s *CommentScanner) resetText(prefix []rune) {
+	s.text = s.text[:0]
+	s.lineLength = 0
+	s.truncated = false
+	for _, rn := range prefix {
+		s.appendText(rn)
+	}
+}
+
+// appendText appends the rune to the text of the comment currently being
+// scanned. Runes past the maximum line length are dropped.
+func (s *CommentScanner) appendText(rn rune) {
+	switch {
+	case rn == '\n' || rn == '\r':
+		s.lineLength = 0
+	case s.maxLineLength > 0 && s.lineLength >= s.maxLineLength:
+		s.truncated = true
+		return
+	default:
+		s.lineLength += utf8.RuneLen(rn)
+	}
+	s.text = utf8.AppendRune(s.text, rn)
+}
+
+// isShebang returns whether the comment is a shebang line.
+func (s *CommentScanner) isShebang(c *Comment) bool {
+	return c.Line == 1 && c.Offset == 0 && strings.HasPrefix(c.Text, "#!")
+}
+
+// rawOffset returns the offset in the original contents for the given offset
+// in the scanned contents.
+func (s *CommentScanner) rawOffset(offset int) int {
+	for _, m := range s.offsets {
+		offset = m.raw(offset)
+	}
+	return offset
+}
+
+// Stats are instrumentation counters describing the work done by a
+// CommentScanner. They can help diagnose inputs that are slow to scan.
+type Stats struct {
+	// Transitions is the number of state machine transitions.
+	Transitions int `json:"transitions"`
+
+	// Bytes is the number of bytes of decoded contents that were scanned.
+	Bytes int `json:"bytes"`
+
+	// Comments is the number of comments found.
+	Comments int `json:"comments"`
+}
+
+// Stats returns the scanner's instrumentation counters.
+func (s *CommentScanner) Stats() Stats {
+	st := s.stats
+	st.Bytes = s.offset
+	return st
+}
+
 // Next returns the next Comment.
 func (s *CommentScanner) Next() *Comment {
 	return s.next
@@ -189,41 +495,126 @@ func (s *CommentScanner) Err() error {
 	return s.err
 }
 
-// Scan implements a simple state machine to parse comments out of generic
-// code.
+// Scan scans for the next comment. It returns true if there is a comment to
+// be returned by Next.
 func (s *CommentScanner) Scan() bool {
+	if s.contextLines == 0 {
+		return s.scan()
+	}
+
 	for {
-		if s.err != nil {
+		// NOTE: Comments are returned once the lines following them have
+		// been read.
+		if len(s.pending) > 0 && (s.done || s.line > s.pending[0].EndLine()+s.contextLines) {
+			s.next = s.pending[0]
+			s.pending = s.pending[1:]
+			end := min(s.next.EndLine()+s.contextLines, len(s.lines))
+			s.next.After = slices.Clone(s.lines[min(s.next.EndLine(), end):end])
+			return true
+		}
+		if s.done {
 			return false
 		}
-		switch st := s.state.(type) {
-		case *stateCode:
-			s.state, s.err = s.processCode(st)
-		case *stateString:
-			s.state, s.err = s.processString(st)
-		case *stateLineComment:
-			s.state, s.err = s.processLineComment(st)
-			if _, ok := s.state.(*stateLineComment); !ok {
-				return true
-			}
-		case *stateLineCommentOrString:
-			var hasComment bool
-			hasComment, s.state, s.err = s.processLineCommentOrString(st)
-			if hasComment {
-				return true
+
+		if !s.scan() {
+			s.done = true
+			if s.lineText.Len() > 0 {
+				s.lines = append(s.lines, strings.TrimSuffix(s.lineText.String(), "\r"))
+				s.lineText.Reset()
 			}
-		case *stateMultilineComment:
-			s.state, s.err = s.processMultilineComment(st)
-			if _, ok := s.state.(*stateMultilineComment); !ok {
-				return true
+			continue
+		}
+
+		c := s.next
+		c.Before = slices.Clone(s.lines[max(c.Line-1-s.contextLines, 0) : c.Line-1])
+		s.pending = append(s.pending, c)
+	}
+}
+
+// stateHandler processes the scanner's current state. It returns the next
+// state and whether a comment was found.
+type stateHandler func(s *CommentScanner, st state) (state, bool, error)
+
+// stateHandlers is the transition table of the scanner's state machine. It is
+// indexed by stateKind.
+var stateHandlers = [numStateKinds]stateHandler{
+	kindCode: func(s *CommentScanner, st state) (state, bool, error) {
+		next, err := s.processCode(st.(*stateCode))
+		return next, false, err
+	},
+	kindString: func(s *CommentScanner, st state) (state, bool, error) {
+		next, err := s.processString(st.(*stateString))
+		return next, false, err
+	},
+	kindLineComment: func(s *CommentScanner, st state) (state, bool, error) {
+		next, err := s.processLineComment(st.(*stateLineComment))
+		return next, next.kind() != kindLineComment, err
+	},
+	kindLineCommentOrString: func(s *CommentScanner, st state) (state, bool, error) {
+		found, next, err := s.processLineCommentOrString(st.(*stateLineCommentOrString))
+		return next, found, err
+	},
+	kindMultilineComment: func(s *CommentScanner, st state) (state, bool, error) {
+		next, err := s.processMultilineComment(st.(*stateMultilineComment))
+		return next, next.kind() != kindMultilineComment, err
+	},
+	kindStatementComment: func(s *CommentScanner, st state) (state, bool, error) {
+		next, err := s.processStatementComment(st.(*stateStatementComment))
+		return next, next.kind() != kindStatementComment, err
+	},
+}
+
+// scan implements a simple state machine to parse comments out of generic
+// code. Transitions are counted in the scanner's Stats.
+func (s *CommentScanner) scan() bool {
+	for s.err == nil {
+		from := s.state.kind()
+		next, found, err := stateHandlers[from](s, s.state)
+		s.state, s.err = next, err
+		if next.kind() != from {
+			s.stats.Transitions++
+		}
+		if !found {
+			continue
+		}
+		if s.skipShebang && s.err == nil && s.isShebang(s.next) {
+			s.next = nil
+			continue
+		}
+		s.stats.Comments++
+		return true
+	}
+
+	// NOTE: The scanner can stop before reading the last runes if they are
+	// too short to start a comment. Read them so they are counted.
+	if errors.Is(s.err, io.EOF) {
+		for {
+			if _, err := s.nextRune(); err != nil {
+				break
 			}
 		}
 	}
+	return false
 }
 
 // processCode processes source code and returns the next state.
 func (s *CommentScanner) processCode(st *stateCode) (state, error) {
+	if s.config.WholeLines {
+		return s.processLines(st)
+	}
+
 	for {
+		// Check for the start or end of embedded code.
+		if s.lineIndent && (s.embedded != nil || len(s.config.Embedded) > 0) {
+			switched, err := s.switchEmbedded()
+			if err != nil {
+				return st, err
+			}
+			if switched {
+				continue
+			}
+		}
+
 		// Check for line comment
 		m, err := s.lineMatch()
 		if err != nil {
@@ -248,7 +639,7 @@ func (s *CommentScanner) processCode(st *stateCode) (state, error) {
 					}
 				}
 
-				return &stateLineComment{}, nil
+				return &stateLineComment{doc: m.Doc}, nil
 			}
 		}
 
@@ -262,17 +653,45 @@ func (s *CommentScanner) processCode(st *stateCode) (state, error) {
 			}
 		}
 
+		// Check for statement comments.
+		smIndex, sm, err := s.statementMatch()
+		if err != nil {
+			return st, err
+		}
+		if sm != nil {
+			return &stateStatementComment{
+				line:  s.line,
+				index: smIndex,
+			}, nil
+		}
+
 		// Check for strings.
 		for i, strs := range s.config.Strings {
+			if s.skipStrings {
+				break
+			}
 			eq, err := s.peekEqual(strs.Start)
 			if err != nil {
 				return st, err
 			}
-			if eq {
+			if !eq {
+				continue
+			}
+			if !strs.Tagged {
 				return &stateString{
 					index: i,
 				}, nil
 			}
+			tag, err := s.peekTag(&strs)
+			if err != nil {
+				return st, err
+			}
+			if tag != nil {
+				return &stateString{
+					index: i,
+					tag:   tag,
+				}, nil
+			}
 		}
 
 		// Process the next rune.
@@ -282,15 +701,91 @@ func (s *CommentScanner) processCode(st *stateCode) (state, error) {
 	}
 }
 
+// processLines processes contents where every line is a line comment and
+// returns the next state.
+func (s *CommentScanner) processLines(st *stateCode) (state, error) {
+	for {
+		if s.atLineStart {
+			lineEnd, err := s.isLineEnd()
+			if err != nil {
+				return st, err
+			}
+			if !lineEnd {
+				return &stateLineComment{}, nil
+			}
+		}
+
+		if _, err := s.nextRune(); err != nil {
+			return st, err
+		}
+	}
+}
+
+// switchEmbedded switches the scanner's configuration if embedded code starts
+// or ends at the current position. It returns whether the configuration was
+// switched.
+func (s *CommentScanner) switchEmbedded() (bool, error) {
+	if s.embedded != nil {
+		eq, err := s.peekEqual(s.embedded.End)
+		if err != nil || !eq {
+			return false, err
+		}
+		if err := s.discard(len(s.embedded.End)); err != nil {
+			return false, err
+		}
+		s.config, s.document, s.embedded = s.document, nil, nil
+		return true, nil
+	}
+
+	for i := range s.config.Embedded {
+		e := &s.config.Embedded[i]
+		config, ok := LanguagesConfig[e.Language]
+		if !ok {
+			continue
+		}
+		eq, err := s.peekEqual(e.Start)
+		if err != nil {
+			return false, err
+		}
+		if !eq {
+			continue
+		}
+
+		// Skip the rest of the line, which holds options for the code.
+		for {
+			lineEnd, err := s.isLineEnd()
+			if err != nil {
+				return false, err
+			}
+			if lineEnd {
+				break
+			}
+			if _, err := s.nextRune(); err != nil {
+				return false, err
+			}
+		}
+		s.config, s.document, s.embedded = config, s.config, e
+		return true, nil
+	}
+	return false, nil
+}
+
 func (s *CommentScanner) lineMatch() (*LineCommentConfig, error) {
 	// Check for line comment
-	for _, m := range s.config.LineComments {
+	// NOTE: Configs are returned by index to avoid allocating a copy for
+	// each rune.
+	for i := range s.config.LineComments {
+		m := &s.config.LineComments[i]
+		if m.AtLineStart && !s.atLineStart && (!m.AllowIndent || !s.lineIndent) {
+			continue
+		}
+
 		eq, err := s.peekEqual(m.Start)
 		if err != nil {
 			return nil, err
 		}
 		if eq {
-			return &m, nil
+			return m, nil
 		}
 	}
 	return nil, nil
@@ -298,43 +793,118 @@ func (s *CommentScanner) lineMatch() (*LineCommentConfig, error) {
 
 func (s *CommentScanner) multiLineMatch() (int, *MultilineCommentConfig, error) {
 	// Check for multiline comment
-	for i, mlConfig := range s.config.MultilineComments {
-		if eq, err := s.peekEqual(mlConfig.Start); err == nil && eq {
-			return i, &mlConfig, nil
-		} else if err != nil {
+	for i := range s.config.MultilineComments {
+		mlConfig := &s.config.MultilineComments[i]
+		eq, err := s.multiLineStart(mlConfig)
+		if err != nil {
+			return 0, nil, err
+		}
+		if eq {
+			return i, mlConfig, nil
+		}
+	}
+	return 0, nil, nil
+}
+
+// multiLineStart returns whether the next runes start the multiline comment.
+func (s *CommentScanner) multiLineStart(mm *MultilineCommentConfig) (bool, error) {
+	eq, err := s.peekEqual(mm.Start)
+	if err != nil || !eq || len(mm.Except) == 0 {
+		return eq, err
+	}
+	except, err := s.peekExcept(mm)
+	if err != nil {
+		return false, err
+	}
+	return !except, nil
+}
+
+// peekExcept returns whether the next runes are the multiline comment's
+// Except sequence.
+func (s *CommentScanner) peekExcept(mm *MultilineCommentConfig) (bool, error) {
+	// NOTE: Except is longer than Start so there may not be enough runes
+	// left to match it.
+	r, err := s.reader.Peek(len(mm.Except))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("reading rune: %w", err)
+	}
+	return utils.SliceEqual(r, mm.Except), nil
+}
+
+func (s *CommentScanner) statementMatch() (int, *StatementCommentConfig, error) {
+	// Check for statement comment
+	for i := range s.config.StatementComments {
+		smConfig := &s.config.StatementComments[i]
+		// NOTE: Statements start at the start of a line or after the
+		// terminator of the previous statement.
+		if !s.lineIndent && s.lastRune != smConfig.End[len(smConfig.End)-1] {
+			continue
+		}
+		eq, err := s.peekEqual(smConfig.Start)
+		if err != nil {
 			return 0, nil, err
 		}
+		if eq {
+			return i, smConfig, nil
+		}
 	}
 	return 0, nil, nil
 }
 
+// peekTag returns the start sequence of the tagged string at the current
+// position, including the tag. It returns nil if the runes are not the start
+// of a tagged string.
+func (s *CommentScanner) peekTag(c *StringConfig) ([]rune, error) {
+	r, err := s.reader.Peek(len(c.Start) + maxTagLength + len(c.End))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("reading rune: %w", err)
+	}
+	for i := len(c.Start); i < len(r); i++ {
+		if utils.SliceEqual(r[i:min(len(r), i+len(c.End))], c.End) {
+			return slices.Clone(r[:i+len(c.End)]), nil
+		}
+		// NOTE: Tags are identifiers so that positional parameters such as
+		// $1 are not treated as strings.
+		rn := r[i]
+		if rn != '_' && !unicode.IsLetter(rn) && (i == len(c.Start) || !unicode.IsDigit(rn)) {
+			return nil, nil
+		}
+	}
+	return nil, nil
+}
+
 // processString processes strings and returns the next state.
 func (s *CommentScanner) processString(st *stateString) (state, error) {
+	start, end := s.config.Strings[st.index].Start, s.config.Strings[st.index].End
+	if st.tag != nil {
+		start, end = st.tag, st.tag
+	}
+
 	// Discard the string start characters.
-	if _, err := s.reader.Discard(len(s.config.Strings[st.index].Start)); err != nil {
+	if err := s.discard(len(start)); err != nil {
 		return st, fmt.Errorf("parsing string: %w", err)
 	}
 
 	for {
 		// Handle escaped characters.
-		escaped, err := s.config.Strings[st.index].EscapeFunc(s, s.config.Strings[st.index].End)
+		escaped, err := s.config.Strings[st.index].EscapeFunc(s, end)
 		// There may still be characters to process so continue if we get EOF.
 		if err != nil && !errors.Is(err, io.EOF) {
 			return st, err
 		}
 		if len(escaped) > 0 {
 			// Skip the escaped characters.
-			if _, err := s.reader.Discard(len(escaped)); err != nil {
+			if err := s.discard(len(escaped)); err != nil {
 				return st, fmt.Errorf("parsing string: %w", err)
 			}
 		} else {
 			// Look for the end of the string.
-			stringEnd, err := s.peekEqual(s.config.Strings[st.index].End)
+			stringEnd, err := s.peekEqual(end)
 			if err != nil {
 				return st, fmt.Errorf("parsing string: %w", err)
 			}
 			if stringEnd {
-				if _, err := s.reader.Discard(len(s.config.Strings[st.index].End)); err != nil {
+				if err := s.discard(len(end)); err != nil {
 					return st, fmt.Errorf("parsing string: %w", err)
 				}
 				return &stateCode{}, nil
@@ -349,18 +919,23 @@ func (s *CommentScanner) processString(st *stateString) (state, error) {
 
 // processLineComment processes line comments and returns the next state.
 func (s *CommentScanner) processLineComment(st *stateLineComment) (state, error) {
-	var b strings.Builder
+	start := s.offset
+	s.resetText(nil)
 	for {
 		lineEnd, err := s.isLineEnd()
 		if err != nil {
 			return st, err
 		}
 		if lineEnd {
-			s.next = &Comment{
-				Text:      b.String(),
+			s.emit(Comment{
+				Text:      string(s.text),
 				Line:      s.line,
 				Multiline: false,
-			}
+				Doc:       st.doc,
+				Offset:    s.rawOffset(start),
+				EndOffset: s.rawOffset(s.offset),
+				Truncated: s.truncated,
+			})
 			return &stateCode{}, nil
 		}
 
@@ -369,25 +944,22 @@ func (s *CommentScanner) processLineComment(st *stateLineComment) (state, error)
 			return st, err
 		}
 
-		_, err = b.WriteRune(rn)
-		if err != nil {
-			return st, fmt.Errorf("writing rune %q: %w", rn, err)
-		}
+		s.appendText(rn)
 	}
 }
 
 // processLineCommentOrString processes strings or line comments when they have
 // the same start character. e.g. Vim Script.
 func (s *CommentScanner) processLineCommentOrString(st *stateLineCommentOrString) (bool, state, error) {
+	start := s.offset
+
 	// Discard the string start characters.
-	if _, err := s.reader.Discard(len(s.config.Strings[st.index].Start)); err != nil {
+	if err := s.discard(len(s.config.Strings[st.index].Start)); err != nil {
 		return false, st, fmt.Errorf("parsing string: %w", err)
 	}
 
-	// b is used to build the line comment text.
-	var b strings.Builder
-	// Add the opening to the builder since we want it in the output if this is a comment.
-	b.WriteString(string(s.config.Strings[st.index].Start))
+	// Add the opening to the text since we want it in the output if this is a comment.
+	s.resetText(s.config.Strings[st.index].Start)
 	for {
 		lineEnd, err := s.isLineEnd()
 		if err != nil {
@@ -398,11 +970,14 @@ func (s *CommentScanner) processLineCommentOrString(st *stateLineCommentOrString
 		// then it must be a comment. Languages where line comments and strings
 		// share the same character cannot implement multi-line strings.
 		if lineEnd {
-			s.next = &Comment{
-				Text:      b.String(),
+			s.emit(Comment{
+				Text:      string(s.text),
 				Line:      s.line,
 				Multiline: false,
-			}
+				Offset:    s.rawOffset(start),
+				EndOffset: s.rawOffset(s.offset),
+				Truncated: s.truncated,
+			})
 			return true, &stateCode{}, nil
 		}
 
@@ -414,14 +989,13 @@ func (s *CommentScanner) processLineCommentOrString(st *stateLineCommentOrString
 		}
 		if len(escaped) > 0 {
 			// Skip the escaped characters.
-			if _, discardErr := s.reader.Discard(len(escaped)); discardErr != nil {
+			if discardErr := s.discard(len(escaped)); discardErr != nil {
 				return false, st, fmt.Errorf("parsing string: %w", discardErr)
 			}
 
 			// Write the escaped characters in case this is a comment.
-			_, err = b.WriteString(string(escaped))
-			if err != nil {
-				return false, st, fmt.Errorf("writing runes %q: %w", escaped, err)
+			for _, rn := range escaped {
+				s.appendText(rn)
 			}
 			continue
 		}
@@ -432,7 +1006,7 @@ func (s *CommentScanner) processLineCommentOrString(st *stateLineCommentOrString
 			return false, st, fmt.Errorf("parsing string: %w", err)
 		}
 		if stringEnd {
-			if _, discardErr := s.reader.Discard(len(s.config.Strings[st.index].End)); discardErr != nil {
+			if discardErr := s.discard(len(s.config.Strings[st.index].End)); discardErr != nil {
 				return false, st, fmt.Errorf("parsing string: %w", discardErr)
 			}
 			return false, &stateCode{}, nil
@@ -443,43 +1017,86 @@ func (s *CommentScanner) processLineCommentOrString(st *stateLineCommentOrString
 			return false, st, fmt.Errorf("parsing string: %w", err)
 		}
 
-		_, err = b.WriteRune(rn)
-		if err != nil {
-			return false, st, fmt.Errorf("writing rune %q: %w", rn, err)
-		}
+		s.appendText(rn)
 	}
 }
 
 // processMultilineComment processes multi-line comments and returns the next state.
 func (s *CommentScanner) processMultilineComment(st *stateMultilineComment) (state, error) {
 	mm := s.config.MultilineComments[st.index]
+	start := s.offset
 
 	// Discard the opening since we don't want to parse it. It could be the same as the closing.
-	if _, errDiscard := s.reader.Discard(len(mm.Start)); errDiscard != nil {
+	if errDiscard := s.discard(len(mm.Start)); errDiscard != nil {
 		return st, fmt.Errorf("parsing code: %w", errDiscard)
 	}
 
-	var b strings.Builder
-
-	// Add the opening to the builder since we want it in the output.
-	b.WriteString(string(mm.Start))
+	// Add the opening to the text since we want it in the output.
+	s.resetText(mm.Start)
 	for {
+		// Text that doesn't start a comment doesn't end it either.
+		if len(mm.Except) > 0 {
+			except, err := s.peekExcept(&mm)
+			if err != nil {
+				return st, err
+			}
+			if except {
+				if errDiscard := s.discard(len(mm.Except)); errDiscard != nil {
+					return st, fmt.Errorf("parsing multi-line comment: %w", errDiscard)
+				}
+				for _, rn := range mm.Except {
+					s.appendText(rn)
+				}
+				continue
+			}
+		}
+
+		// Look for the start of a nested comment.
+		if mm.Nested {
+			mlStart, err := s.peekEqual(mm.Start)
+			if err != nil {
+				return st, err
+			}
+			if mlStart {
+				if errDiscard := s.discard(len(mm.Start)); errDiscard != nil {
+					return st, fmt.Errorf("parsing multi-line comment: %w", errDiscard)
+				}
+				for _, rn := range mm.Start {
+					s.appendText(rn)
+				}
+				st.depth++
+				continue
+			}
+		}
+
 		// Look for the end of the comment.
 		mlEnd, err := s.peekEqual(mm.End)
 		if err != nil {
 			return st, err
 		}
 		if mlEnd && (!mm.AtLineStart || s.atLineStart) {
-			if _, errDiscard := s.reader.Discard(len(mm.End)); errDiscard != nil {
+			if errDiscard := s.discard(len(mm.End)); errDiscard != nil {
 				return st, fmt.Errorf("parsing multi-line comment: %w", errDiscard)
 			}
-			// Add the ending to the builder.
-			b.WriteString(string(mm.End))
-			s.next = &Comment{
-				Text:      b.String(),
+			// Only the end of the outermost comment ends the comment.
+			if st.depth > 0 {
+				for _, rn := range mm.End {
+					s.appendText(rn)
+				}
+				st.depth--
+				continue
+			}
+
+			// Add the ending to the text.
+			s.text = append(s.text, string(mm.End)...)
+			s.emit(Comment{
+				Text:      string(s.text),
 				Line:      st.line,
 				Multiline: true,
-			}
+				Offset:    s.rawOffset(start),
+				EndOffset: s.rawOffset(s.offset),
+				Truncated: s.truncated,
+			})
 			return &stateCode{}, nil
 		}
 
@@ -488,27 +1105,145 @@ func (s *CommentScanner) processMultilineComment(st *stateMultilineComment) (sta
 			return st, err
 		}
 
-		_, err = b.WriteRune(rn)
+		s.appendText(rn)
+	}
+}
+
+// processStatementComment processes statement comments and returns the next
+// state. Comments that are not terminated end at the end of the last line
+// that isn't blank.
+func (s *CommentScanner) processStatementComment(st *stateStatementComment) (state, error) {
+	sm := s.config.StatementComments[st.index]
+	start := s.offset
+
+	emit := func(textLen, end int) {
+		s.emit(Comment{
+			Text:      string(s.text[:textLen]),
+			Line:      st.line,
+			Multiline: true,
+			Offset:    s.rawOffset(start),
+			EndOffset: s.rawOffset(end),
+			Truncated: s.truncated,
+		})
+	}
+
+	// Discard the opening so that it isn't matched as the end.
+	if errDiscard := s.discard(len(sm.Start)); errDiscard != nil {
+		return st, fmt.Errorf("parsing code: %w", errDiscard)
+	}
+
+	// Add the opening to the text since we want it in the output.
+	s.resetText(sm.Start)
+
+	// lineEndLen and lineEnd are the length of the text and the offset at
+	// the end of the last line that isn't blank.
+	lineEndLen, lineEnd := len(s.text), s.offset
+	for {
+		atLineEnd, err := s.isLineEnd()
+		if err != nil {
+			return st, err
+		}
+		if atLineEnd {
+			blank := s.lineIndent && s.line > st.line
+			_, peekErr := s.reader.Peek(1)
+			eof := errors.Is(peekErr, io.EOF)
+			if blank && (eof || sm.EndAtBlankLine) {
+				emit(lineEndLen, lineEnd)
+				return &stateCode{}, nil
+			}
+			if eof {
+				emit(len(s.text), s.offset)
+				return &stateCode{}, nil
+			}
+			// NOTE: Windows line endings end the line at the carriage return.
+			if !blank && !bytes.HasSuffix(s.text, []byte{'\r'}) {
+				lineEndLen, lineEnd = len(s.text), s.offset
+			}
+		}
+
+		// Look for the end of the comment.
+		smEnd, err := s.peekEqual(sm.End)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return st, err
+		}
+		if smEnd {
+			if errDiscard := s.discard(len(sm.End)); errDiscard != nil {
+				return st, fmt.Errorf("parsing statement comment: %w", errDiscard)
+			}
+			for _, rn := range sm.End {
+				s.appendText(rn)
+			}
+
+			endOfLine, err := s.isLineEnd()
+			if err != nil {
+				return st, err
+			}
+			if !sm.AtLineEnd || endOfLine {
+				emit(len(s.text), s.offset)
+				return &stateCode{}, nil
+			}
+			continue
+		}
+
+		rn, err := s.nextRune()
 		if err != nil {
-			return st, fmt.Errorf("writing rune %q: %w", rn, err)
+			return st, err
 		}
+
+		s.appendText(rn)
 	}
 }
 
 func (s *CommentScanner) nextRune() (rune, error) {
-	rn, _, err := s.reader.ReadRune()
+	rn, size, err := s.reader.ReadRune()
 	if err != nil {
 		return rn, fmt.Errorf("reading rune: %w", err)
 	}
-	if rn == '\n' {
+	s.offset += size
+
+	newline := rn == '\n'
+	if rn == '\r' {
+		// NOTE: A carriage return that is not followed by a newline is a
+		// classic Mac OS line ending. Errors are returned by the next read.
+		crlf, _ := s.peekEqual([]rune{'\n'})
+		newline = !crlf
+	}
+
+	if s.contextLines > 0 {
+		if newline {
+			s.lines = append(s.lines, strings.TrimSuffix(s.lineText.String(), "\r"))
+			s.lineText.Reset()
+		} else if s.maxLineLength == 0 || s.lineText.Len() < s.maxLineLength {
+			s.lineText.WriteRune(rn)
+		}
+	}
+	switch {
+	case newline:
 		s.line++
 		s.atLineStart = true
-	} else {
+		s.lineIndent = true
+		s.lastRune = 0
+	case rn == ' ' || rn == '\t':
+		s.atLineStart = false
+	default:
 		s.atLineStart = false
+		s.lineIndent = false
+		s.lastRune = rn
 	}
 	return rn, nil
 }
 
+// discard discards the next n runes. Discarded runes are tracked the same way
+// as runes read by nextRune.
+func (s *CommentScanner) discard(n int) error {
+	for range n {
+		if _, err := s.nextRune(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *CommentScanner) isLineEnd() (bool, error) {
 	nixNL, err := s.peekEqual([]rune{'\n'})
 	if errors.Is(err, io.EOF) {
@@ -523,13 +1258,9 @@ func (s *CommentScanner) isLineEnd() (bool, error) {
 		return true, nil
 	}
 
-	winNL, err := s.peekEqual([]rune{'\r', '\n'})
-	if errors.Is(err, io.EOF) {
-		// NOTE: We may only have one character left so return false so it can
-		// be processed.
-		return false, nil
-	}
-	return winNL, err
+	// NOTE: Both Windows (\r\n) and classic Mac OS (\r) line endings start
+	// with a carriage return.
+	return s.peekEqual([]rune{'\r'})
 }
 
 func (s *CommentScanner) peekEqual(val []rune) (bool, error) {
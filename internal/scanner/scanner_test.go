@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"golang.org/x/text/encoding/ianaindex"
 
 	"github.com/ianlewis/todos/internal/testutils"
@@ -49,6 +50,88 @@ var scannerTestCases = []*struct {
 		line int
 	}
 }{
+	// ABAP
+	{
+		name: "comments.abap",
+		src: `REPORT ztodo.
+
+			" TODO is a report.
+			WRITE 'Hello " World'. " Random comment
+			WRITE 'It''s " not a comment'.
+			WRITE |" not a comment \| either|.
+			WRITE ` + "`" + `" not a comment` + "`" + `.`,
+		config: "ABAP",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "\" TODO is a report.",
+				line: 3,
+			},
+			{
+				text: "\" Random comment",
+				line: 4,
+			},
+		},
+	},
+
+	{
+		name: "line_start_comments.abap",
+		src: `* file comment
+REPORT ztodo.
+  DATA(x) = 2 * 3. " Random comment
+* TODO: some task.`,
+		config: "ABAP",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "* file comment",
+				line: 1,
+			},
+			{
+				text: "\" Random comment",
+				line: 3,
+			},
+			{
+				text: "* TODO: some task.",
+				line: 4,
+			},
+		},
+	},
+
+	// Apex
+	{
+		name: "comments.cls",
+		src: `// file comment
+
+			/* TODO is a class. */
+			public class Todo {
+				String x = '// Random comment'; // Random comment
+				String y = '\'// Random comment';
+			}`,
+		config: "Apex",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "// file comment",
+				line: 1,
+			},
+			{
+				text: "/* TODO is a class. */",
+				line: 3,
+			},
+			{
+				text: "// Random comment",
+				line: 5,
+			},
+		},
+	},
+
 	// Assembly
 	{
 		name: "line_comments.s",
@@ -167,6 +250,66 @@ var scannerTestCases = []*struct {
 		},
 	},
 
+	// Cairo
+	{
+		name: "comments.cairo",
+		src: `// file comment
+
+			// TODO is a function.
+			fn todo() -> felt252 {
+				let x: ByteArray = "// Random comment"; // Random comment
+				'\'// Random comment'
+			}`,
+		config: "Cairo",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "// file comment",
+				line: 1,
+			},
+			{
+				text: "// TODO is a function.",
+				line: 3,
+			},
+			{
+				text: "// Random comment",
+				line: 5,
+			},
+		},
+	},
+
+	// Awk
+	{
+		name: "comments.awk",
+		src: `# file comment
+
+			# TODO is a rule.
+			/todo/ {
+				print "# Random comment" # Random comment
+				print "\"# Random comment"
+			}`,
+		config: "Awk",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "# file comment",
+				line: 1,
+			},
+			{
+				text: "# TODO is a rule.",
+				line: 3,
+			},
+			{
+				text: "# Random comment",
+				line: 5,
+			},
+		},
+	},
+
 	// Clojure
 	{
 		name: "line_comments.clj",
@@ -325,6 +468,42 @@ var scannerTestCases = []*struct {
 		},
 	},
 
+	// Dune
+	{
+		name: "dune",
+		src: `; file comment
+
+			; TODO is a library.
+			(library
+				(name todo) ; Random comment
+				(flags "; not a comment")
+				#| block
+				comment |#
+			)`,
+		config: "Dune",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "; file comment",
+				line: 1,
+			},
+			{
+				text: "; TODO is a library.",
+				line: 3,
+			},
+			{
+				text: "; Random comment",
+				line: 5,
+			},
+			{
+				text: "#| block\n\t\t\t\tcomment |#",
+				line: 7,
+			},
+		},
+	},
+
 	// Elixir
 	{
 		name: "line_comments.ex",
@@ -661,7 +840,141 @@ var scannerTestCases = []*struct {
 		},
 	},
 
+	// F#
+	{
+		name: "comments.fs",
+		src: `// file comment
+
+			(* TODO is a function. *)
+			let todo (x: 'T) =
+				printfn "// Random comment" // Random comment
+				printfn """(* not a comment "quoted" *)"""
+			(*
+			multi-line comment
+			*)`,
+		config: "F#",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "// file comment",
+				line: 1,
+			},
+			{
+				text: "(* TODO is a function. *)",
+				line: 3,
+			},
+			{
+				text: "// Random comment",
+				line: 5,
+			},
+			{
+				text: "(*\n\t\t\tmulti-line comment\n\t\t\t*)",
+				line: 7,
+			},
+		},
+	},
+	{
+		name: "nested_comments.fs",
+		src: `(* a (* b *) TODO: c *)
+			let x = 1 (* (* (* deep *) *) TODO: d *) // after
+			(* outer
+			(* inner *)
+			TODO: e
+			*)`,
+		config: "F#",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "(* a (* b *) TODO: c *)",
+				line: 1,
+			},
+			{
+				text: "(* (* (* deep *) *) TODO: d *)",
+				line: 2,
+			},
+			{
+				text: "// after",
+				line: 2,
+			},
+			{
+				text: "(* outer\n\t\t\t(* inner *)\n\t\t\tTODO: e\n\t\t\t*)",
+				line: 3,
+			},
+		},
+	},
+	{
+		name: "multiplication_operator.fs",
+		src: `let xs = List.fold (*) 1 [1;2]
+			// TODO: after fold
+			(* fold with (*) *)`,
+		config: "F#",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "// TODO: after fold",
+				line: 2,
+			},
+			{
+				text: "(* fold with (*) *)",
+				line: 3,
+			},
+		},
+	},
+	{
+		name: "escaped_string.fs",
+		src: `// file comment
+			let x = "\"// Random comment"
+			let y = 'a'`,
+		config: "F#",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "// file comment",
+				line: 1,
+			},
+		},
+	},
+
 	// Fortran
+	{
+		name: "fixed_form_comments.f",
+		src: `C file comment
+c TODO is a program.
+      PROGRAM TODO
+      CALL FOO ! Random comment
+* another comment
+      END`,
+		config: "Fortran",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "C file comment",
+				line: 1,
+			},
+			{
+				text: "c TODO is a program.",
+				line: 2,
+			},
+			{
+				text: "! Random comment",
+				line: 4,
+			},
+			{
+				text: "* another comment",
+				line: 5,
+			},
+		},
+	},
 	{
 		name: "line_comments.f90",
 		src: `! file comment
@@ -723,63 +1036,126 @@ var scannerTestCases = []*struct {
 		},
 	},
 
-	// Go
+	// GDScript
 	{
-		name: "line_comments.go",
-		src: `package foo
-			// package comment
+		name: "comments.gd",
+		src: `# file comment
+			extends Node
 
-			// TODO is a function.
-			func TODO() {
-				return // Random comment
-			}`,
-		config: "Go",
+			## TODO is a function.
+			func todo():
+				var x = "# Random comment" # Random comment
+				var y = """
+				# not a comment
+				"""
+				return x + y`,
+		config: "GDScript",
 		comments: []struct {
 			text string
 			line int
 		}{
 			{
-				text: "// package comment",
-				line: 2,
+				text: "# file comment",
+				line: 1,
 			},
 			{
-				text: "// TODO is a function.",
+				text: "## TODO is a function.",
 				line: 4,
 			},
 			{
-				text: "// Random comment",
+				text: "# Random comment",
 				line: 6,
 			},
 		},
 	},
+
+	// GLSL
 	{
-		name: "comments_in_string.go",
-		src: `package foo
-			// package comment
+		name: "comments.glsl",
+		src: `// file comment
+			#version 330 core
 
-			// TODO is a function.
-			func TODO() string {
-				x := "// Random comment"
-				y := '// Random comment'
-				return x + y
+			/* TODO is a function. */
+			void main() {
+				gl_FragColor = vec4(1.0); // Random comment
 			}`,
-		config: "Go",
+		config: "GLSL",
 		comments: []struct {
 			text string
 			line int
 		}{
 			{
-				text: "// package comment",
-				line: 2,
+				text: "// file comment",
+				line: 1,
 			},
 			{
-				text: "// TODO is a function.",
+				text: "/* TODO is a function. */",
 				line: 4,
 			},
+			{
+				text: "// Random comment",
+				line: 6,
+			},
 		},
 	},
+
+	// Go
 	{
-		name: "escaped_string.go",
+		name: "line_comments.go",
+		src: `package foo
+			// package comment
+
+			// TODO is a function.
+			func TODO() {
+				return // Random comment
+			}`,
+		config: "Go",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "// package comment",
+				line: 2,
+			},
+			{
+				text: "// TODO is a function.",
+				line: 4,
+			},
+			{
+				text: "// Random comment",
+				line: 6,
+			},
+		},
+	},
+	{
+		name: "comments_in_string.go",
+		src: `package foo
+			// package comment
+
+			// TODO is a function.
+			func TODO() string {
+				x := "// Random comment"
+				y := '// Random comment'
+				return x + y
+			}`,
+		config: "Go",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "// package comment",
+				line: 2,
+			},
+			{
+				text: "// TODO is a function.",
+				line: 4,
+			},
+		},
+	},
+	{
+		name: "escaped_string.go",
 		src: `package foo
 			// package comment
 
@@ -1017,6 +1393,36 @@ var scannerTestCases = []*struct {
 		},
 	},
 
+	// HLSL
+	{
+		name: "comments.hlsl",
+		src: `// file comment
+			#include "// not a comment"
+
+			/* TODO is a function. */
+			float4 main() : SV_Target {
+				return float4(1, 1, 1, 1); // Random comment
+			}`,
+		config: "HLSL",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "// file comment",
+				line: 1,
+			},
+			{
+				text: "/* TODO is a function. */",
+				line: 4,
+			},
+			{
+				text: "// Random comment",
+				line: 6,
+			},
+		},
+	},
+
 	// HTML
 	{
 		name: "multi_line.html",
@@ -1075,6 +1481,27 @@ var scannerTestCases = []*struct {
 		},
 	},
 
+	// Ignore List
+	{
+		name: "ignore_list.gitignore",
+		src: `# TODO: ignore build outputs.
+/build/
+foo#bar # not a comment
+  # not a comment
+\#not-a-comment
+`,
+		config: "Ignore List",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "# TODO: ignore build outputs.",
+				line: 1,
+			},
+		},
+	},
+
 	// Kotlin
 	{
 		name: "line_comments.kt",
@@ -1189,6 +1616,96 @@ var scannerTestCases = []*struct {
 		},
 	},
 
+	// Move
+	{
+		name: "comments.move",
+		src: `// file comment
+
+			/// TODO is a function.
+			module 0x1::todo {
+				fun todo(): vector<u8> {
+					b"// Random comment" // Random comment
+				}
+				/* block comment */
+			}`,
+		config: "Move",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "// file comment",
+				line: 1,
+			},
+			{
+				text: "/// TODO is a function.",
+				line: 3,
+			},
+			{
+				text: "// Random comment",
+				line: 6,
+			},
+			{
+				text: "/* block comment */",
+				line: 8,
+			},
+		},
+	},
+
+	// OCaml
+	{
+		name: "comments.ml",
+		src: `(* file comment *)
+
+			(* TODO is a function. *)
+			let todo (x : 'a) =
+				print_string "(* Random comment *)"; (* Random comment *)
+			(*
+			multi-line comment
+			*)`,
+		config: "OCaml",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "(* file comment *)",
+				line: 1,
+			},
+			{
+				text: "(* TODO is a function. *)",
+				line: 3,
+			},
+			{
+				text: "(* Random comment *)",
+				line: 5,
+			},
+			{
+				text: "(*\n\t\t\tmulti-line comment\n\t\t\t*)",
+				line: 6,
+			},
+		},
+	},
+	{
+		name: "nested_comments.ml",
+		src: `(* a (* b *) TODO: c *)
+			let x = 1 (* (* inner *) TODO: d *)`,
+		config: "OCaml",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "(* a (* b *) TODO: c *)",
+				line: 1,
+			},
+			{
+				text: "(* (* inner *) TODO: d *)",
+				line: 2,
+			},
+		},
+	},
+
 	// Pascal
 	{
 		name: "line_comments.pas",
@@ -1367,6 +1884,39 @@ var scannerTestCases = []*struct {
 		},
 	},
 
+	// PL/SQL
+	{
+		name: "comments.pls",
+		src: `-- file comment
+
+			/* TODO is a procedure. */
+			CREATE PROCEDURE todo IS
+			BEGIN
+				DBMS_OUTPUT.PUT_LINE('It''s -- not a comment'); -- Random comment
+				DBMS_OUTPUT.PUT_LINE(q'[It's -- not a comment]');
+				DBMS_OUTPUT.PUT_LINE(Q'{It's /* not a comment */}');
+				DBMS_OUTPUT.PUT_LINE(q'!It's -- not a comment!');
+			END;`,
+		config: "PLSQL",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "-- file comment",
+				line: 1,
+			},
+			{
+				text: "/* TODO is a procedure. */",
+				line: 3,
+			},
+			{
+				text: "-- Random comment",
+				line: 6,
+			},
+		},
+	},
+
 	// PowerShell
 	{
 		name: "line_comments.ps1",
@@ -2059,6 +2609,103 @@ var scannerTestCases = []*struct {
 		},
 	},
 
+	{
+		name: "roxygen.r",
+		src: `#' TODO is a function
+			#' @export
+			TODO <- function() {
+				print("#' not a comment") # Random comment
+			`,
+		config: "R",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "#' TODO is a function",
+				line: 1,
+			},
+			{
+				text: "#' @export",
+				line: 2,
+			},
+			{
+				text: "# Random comment",
+				line: 4,
+			},
+		},
+	},
+
+	// R Markdown
+	{
+		name: "chunks.rmd",
+		src: "# Heading\n" +
+			"\n" +
+			"<!-- TODO: prose -->\n" +
+			"Text with a # and \"quote.\n" +
+			"\n" +
+			"```{r setup, include=FALSE}\n" +
+			"# TODO: r\n" +
+			"x <- \"# not a comment\"\n" +
+			"```\n" +
+			"\n" +
+			"```{bash}\n" +
+			"# not a comment\n" +
+			"```\n" +
+			"\n" +
+			"```{python}\n" +
+			"# TODO: python\n" +
+			"```\n" +
+			"# Not a comment either\n",
+		config: "RMarkdown",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "<!-- TODO: prose -->",
+				line: 3,
+			},
+			{
+				text: "# TODO: r",
+				line: 7,
+			},
+			{
+				text: "# TODO: python",
+				line: 16,
+			},
+		},
+	},
+
+	// Reason
+	{
+		name: "comments.re",
+		src: `// file comment
+
+			/* TODO is a function. */
+			let todo = (x: 'a) => {
+				Js.log("// Random comment"); // Random comment
+			};`,
+		config: "Reason",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "// file comment",
+				line: 1,
+			},
+			{
+				text: "/* TODO is a function. */",
+				line: 3,
+			},
+			{
+				text: "// Random comment",
+				line: 5,
+			},
+		},
+	},
+
 	// Ruby
 	{
 		name: "raw_string.rb",
@@ -2328,6 +2975,36 @@ TODO is a function.
 		},
 	},
 
+	// ShaderLab
+	{
+		name: "comments.shader",
+		src: `// file comment
+			Shader "Custom/Todo // not a comment" {
+				/* TODO is a sub-shader. */
+				SubShader {
+					Pass { } // Random comment
+				}
+			}`,
+		config: "ShaderLab",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "// file comment",
+				line: 1,
+			},
+			{
+				text: "/* TODO is a sub-shader. */",
+				line: 3,
+			},
+			{
+				text: "// Random comment",
+				line: 5,
+			},
+		},
+	},
+
 	// Shell
 	{
 		name: "line_comments.sh",
@@ -2392,11 +3069,150 @@ TODO is a function.
 		},
 	},
 
-	// SQL
+	// Solidity
 	{
-		name: "line_comments.sql",
-		src: `-- file comment
-
+		name: "comments.sol",
+		src: `// SPDX-License-Identifier: MIT
+
+			/// @notice TODO is a contract.
+			contract Todo {
+				/**
+				 * @dev FIXME: audit this.
+				 */
+				string x = "// Random comment"; // Random comment
+				string y = '\'// Random comment';
+			}`,
+		config: "Solidity",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "// SPDX-License-Identifier: MIT",
+				line: 1,
+			},
+			{
+				text: "/// @notice TODO is a contract.",
+				line: 3,
+			},
+			{
+				text: "/**\n\t\t\t\t * @dev FIXME: audit this.\n\t\t\t\t */",
+				line: 5,
+			},
+			{
+				text: "// Random comment",
+				line: 8,
+			},
+		},
+	},
+
+	// SAS
+	{
+		name: "comments.sas",
+		src: `* file comment;
+			/* TODO is a data set. */
+			data todo; * Random
+				comment;
+				x = 1 * 2;
+				y = "* not a comment;";
+				%* macro comment;
+			run;`,
+		config: "SAS",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "* file comment;",
+				line: 1,
+			},
+			{
+				text: "/* TODO is a data set. */",
+				line: 2,
+			},
+			{
+				text: "* Random\n\t\t\t\tcomment;",
+				line: 3,
+			},
+			{
+				text: "%* macro comment;",
+				line: 7,
+			},
+		},
+	},
+
+	// SPSS
+	{
+		name: "comments.sps",
+		src: `* file comment.
+			* TODO: version 1.2 is
+			   required.
+			COMPUTE x = 2 * y.
+			STRING s (A8) "* not a comment.".
+			* unterminated comment`,
+		config: "SPSS",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "* file comment.",
+				line: 1,
+			},
+			{
+				text: "* TODO: version 1.2 is\n\t\t\t   required.",
+				line: 2,
+			},
+			{
+				text: "* unterminated comment",
+				line: 6,
+			},
+		},
+	},
+
+	{
+		name: "blank_line.sps",
+		src: "* TODO: comment ended\r\n" +
+			"  by a blank line\r\n" +
+			"\r\n" +
+			"FREQUENCIES VARIABLES=x.\r\n" +
+			"* SAS style comment;\n" +
+			"\n",
+		config: "SPSS",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "* TODO: comment ended\r\n  by a blank line",
+				line: 1,
+			},
+			{
+				text: "* SAS style comment;",
+				line: 5,
+			},
+		},
+	},
+	{
+		name:   "unterminated.sas",
+		src:    "data x;\n* unterminated comment\n  \n",
+		config: "SAS",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "* unterminated comment",
+				line: 2,
+			},
+		},
+	},
+
+	// SQL
+	{
+		name: "line_comments.sql",
+		src: `-- file comment
+
 			-- TODO is a table.
 			SELECT * from TODO
 			WHERE
@@ -2527,6 +3343,190 @@ TODO is a function.
 		},
 	},
 
+	// MySQL
+	{
+		name: "comments.mysql",
+		src: `-- file comment
+
+			# TODO is a table.
+			SELECT * from TODO
+			WHERE
+				x = "# not a comment" AND
+				y = 'it\'s -- not a comment' AND
+				` + "`# not a comment`" + ` = 1 # Random comment
+			LIMIT 1;`,
+		config: "MySQL",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "-- file comment",
+				line: 1,
+			},
+			{
+				text: "# TODO is a table.",
+				line: 3,
+			},
+			{
+				text: "# Random comment",
+				line: 8,
+			},
+		},
+	},
+
+	// PL/pgSQL
+	{
+		name: "dollar_quoted.plpgsql",
+		src: `-- file comment
+			CREATE FUNCTION todo(integer) RETURNS integer AS $$
+				SELECT 'it''s -- not a comment';
+			$$ LANGUAGE sql;
+
+			CREATE FUNCTION fixme() RETURNS text AS $fn$
+				SELECT $$ -- not a comment $$; -- still not a comment
+			$fn$ LANGUAGE sql;
+
+			SELECT $1 -- TODO: parameter
+			/* TODO: block comment */`,
+		config: "PLpgSQL",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "-- file comment",
+				line: 1,
+			},
+			{
+				text: "-- TODO: parameter",
+				line: 10,
+			},
+			{
+				text: "/* TODO: block comment */",
+				line: 11,
+			},
+		},
+	},
+
+	// Stata
+	{
+		name: "comments.do",
+		src: `* file comment
+			// TODO is a program.
+			program define todo
+				display 2 * 3 // Random comment
+				display "// not a comment"
+				display ` + "`\"\"// not a comment\"\"'" + `
+				/* multi-line
+				comment */
+			end`,
+		config: "Stata",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "* file comment",
+				line: 1,
+			},
+			{
+				text: "// TODO is a program.",
+				line: 2,
+			},
+			{
+				text: "// Random comment",
+				line: 4,
+			},
+			{
+				text: "/* multi-line\n\t\t\t\tcomment */",
+				line: 7,
+			},
+		},
+	},
+
+	// T-SQL
+	{
+		name: "bracket_identifiers.tsql",
+		src: `-- file comment
+
+			-- TODO is a table.
+			SELECT [--not a comment], [a]]--b] FROM TODO
+			WHERE
+				x = '-- not a comment' -- Random comment`,
+		config: "TSQL",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "-- file comment",
+				line: 1,
+			},
+			{
+				text: "-- TODO is a table.",
+				line: 3,
+			},
+			{
+				text: "-- Random comment",
+				line: 6,
+			},
+		},
+	},
+
+	// Tcl
+	{
+		name: "comments.tcl",
+		src: `# file comment
+
+			# TODO is a procedure.
+			proc todo {} {
+				# Random comment
+				puts "# not a comment"
+				puts "\"# not a comment"
+			}`,
+		config: "Tcl",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "# file comment",
+				line: 1,
+			},
+			{
+				text: "# TODO is a procedure.",
+				line: 3,
+			},
+			{
+				text: "# Random comment",
+				line: 5,
+			},
+		},
+	},
+
+	{
+		name: "command_start_comments.tcl",
+		src: `# file comment
+			set x #not-a-comment
+			set y 1 ;# Random comment
+			set z "" # not a comment`,
+		config: "Tcl",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "# file comment",
+				line: 1,
+			},
+			{
+				text: ";# Random comment",
+				line: 3,
+			},
+		},
+	},
+
 	// TeX
 	{
 		name: "line_comments.tex",
@@ -2550,6 +3550,50 @@ TODO is a function.
 		},
 	},
 
+	// sed
+	{
+		name: "comments.sed",
+		src: `# file comment
+
+			# TODO is a substitution.
+			s/foo/bar/g`,
+		config: "sed",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "# file comment",
+				line: 1,
+			},
+			{
+				text: "# TODO is a substitution.",
+				line: 3,
+			},
+		},
+	},
+
+	{
+		name: "command_start_comments.sed",
+		src: `# file comment
+			s/#/x/g
+			  # indented comment`,
+		config: "sed",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "# file comment",
+				line: 1,
+			},
+			{
+				text: "# indented comment",
+				line: 3,
+			},
+		},
+	},
+
 	// VBA
 	{
 		name: "line_comments.vba",
@@ -2692,44 +3736,544 @@ End Module`,
 				line: 7,
 			},
 		},
-	},
+	},
+
+	// XML
+	{
+		name: "cdata.xml",
+		src: `<?xml version="1.0"?>
+		<!-- file comment -->
+		<config attr="<!-- not a comment -->">
+			<script><![CDATA[
+				<!-- TODO: not a comment -->
+			]]></script>
+		</config>
+		<!-- extra comment -->`,
+		config: "XML",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "<!-- file comment -->",
+				line: 2,
+			},
+			{
+				text: "<!-- extra comment -->",
+				line: 8,
+			},
+		},
+	},
+	{
+		name: "processing_instructions.xml",
+		src: `<?xml version="1.0"?>
+		<?xml-stylesheet href="<!-- not a comment -->"?>
+		<!DOCTYPE config [
+			<!-- DTD comment -->
+			<!ENTITY foo "<!-- not a comment -->">
+		]>
+		<?app <!-- not a comment --> ?>
+		<config/>`,
+		config: "XML",
+		comments: []struct {
+			text string
+			line int
+		}{
+			{
+				text: "<!-- DTD comment -->",
+				line: 4,
+			},
+		},
+	},
+}
+
+func TestCommentScanner(t *testing.T) {
+	t.Parallel()
+
+	for i := range scannerTestCases {
+		tc := scannerTestCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			s := New(strings.NewReader(tc.src), LanguagesConfig[tc.config])
+
+			var comments []*Comment
+			for s.Scan() {
+				comments = append(comments, s.Next())
+			}
+			if err := s.Err(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if want, got := len(tc.comments), len(comments); want != got {
+				t.Fatalf("unexpected # of comments, want: %v, got: %v\n\ncomments:\n%q", want, got, comments)
+			}
+
+			for i := range tc.comments {
+				if want, got := tc.comments[i].text, comments[i].String(); want != got {
+					t.Errorf("unexpected text, want: %q, got: %q", want, got)
+				}
+
+				if want, got := tc.comments[i].line, comments[i].Line; want != got {
+					t.Errorf("unexpected line, want: %d, got: %d", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCommentScanner_Doc(t *testing.T) {
+	t.Parallel()
+
+	src := "#' Documentation\n# Comment\nx <- 1 #' Trailing\n"
+	s := New(strings.NewReader(src), LanguagesConfig["R"])
+
+	var got []bool
+	for s.Scan() {
+		got = append(got, s.Next().Doc)
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff([]bool{true, false, true}, got); diff != "" {
+		t.Errorf("unexpected doc comments (-want +got):\n%s", diff)
+	}
+}
+
+func TestCommentScanner_SetSkipShebang(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		src      string
+		skip     bool
+		expected []string
+	}{
+		"shebang": {
+			src:      "#!/bin/bash\n# TODO: foo\n",
+			expected: []string{"#!/bin/bash", "# TODO: foo"},
+		},
+		"skip shebang": {
+			src:      "#!/bin/bash\n# TODO: foo\n",
+			skip:     true,
+			expected: []string{"# TODO: foo"},
+		},
+		"skip no shebang": {
+			src:      "# TODO: foo\n",
+			skip:     true,
+			expected: []string{"# TODO: foo"},
+		},
+		"skip not first line": {
+			src:      "echo foo\n#!/bin/bash\n",
+			skip:     true,
+			expected: []string{"#!/bin/bash"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := New(strings.NewReader(tc.src), LanguagesConfig["Shell"])
+			s.SetSkipShebang(tc.skip)
+
+			var got []string
+			for s.Scan() {
+				got = append(got, s.Next().String())
+			}
+			if err := s.Err(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected comments (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCommentScanner_SetMaxLineLength(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("x", 100)
+
+	testCases := map[string]struct {
+		src      string
+		config   string
+		n        int
+		expected []*Comment
+	}{
+		"no limit": {
+			src: "// TODO: " + long + "\n",
+			expected: []*Comment{
+				{
+					Text:      "// TODO: " + long,
+					Line:      1,
+					EndOffset: 109,
+				},
+			},
+		},
+		"under limit": {
+			src: "// TODO: foo\n",
+			n:   16,
+			expected: []*Comment{
+				{
+					Text:      "// TODO: foo",
+					Line:      1,
+					EndOffset: 12,
+				},
+			},
+		},
+		"line comment": {
+			src: "// TODO: " + long + "\nx := 1 // next\n",
+			n:   16,
+			expected: []*Comment{
+				{
+					Text:      "// TODO: xxxxxxx",
+					Line:      1,
+					EndOffset: 109,
+					Truncated: true,
+				},
+				{
+					Text:      "// next",
+					Line:      2,
+					Offset:    117,
+					EndOffset: 124,
+				},
+			},
+		},
+		"multiline comment": {
+			src: "/* TODO: " + long + "\n * FIXME: foo */ // next\n",
+			n:   16,
+			expected: []*Comment{
+				{
+					Text:      "/* TODO: xxxxxxx\n * FIXME: foo */",
+					Line:      1,
+					Multiline: true,
+					EndOffset: 126,
+					Truncated: true,
+				},
+				{
+					Text:      "// next",
+					Line:      2,
+					Offset:    127,
+					EndOffset: 134,
+				},
+			},
+		},
+		"nested comment": {
+			src:    "(* " + strings.Repeat("(*", 50) + strings.Repeat("*)", 50) + " TODO: foo *)\n",
+			config: "OCaml",
+			n:      16,
+			expected: []*Comment{
+				{
+					Text:      "(* (*(*(*(*(*(*(*)",
+					Line:      1,
+					Multiline: true,
+					EndOffset: 216,
+					Truncated: true,
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			config := tc.config
+			if config == "" {
+				config = "Go"
+			}
+			s := New(strings.NewReader(tc.src), LanguagesConfig[config])
+			s.SetMaxLineLength(tc.n)
+
+			var got []*Comment
+			for s.Scan() {
+				got = append(got, s.Next())
+			}
+			if err := s.Err(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected comments (-want +got):\n%s", diff)
+			}
+		})
+	}
 }
 
-func TestCommentScanner(t *testing.T) {
+func TestCommentScanner_SetSkipStrings(t *testing.T) {
 	t.Parallel()
 
-	for i := range scannerTestCases {
-		tc := scannerTestCases[i]
+	src := "x := \"// not a comment\" // comment\n"
 
-		t.Run(tc.name, func(t *testing.T) {
+	testCases := map[string]struct {
+		skip     bool
+		expected []string
+	}{
+		"strings": {
+			expected: []string{"// comment"},
+		},
+		"skip strings": {
+			skip:     true,
+			expected: []string{"// not a comment\" // comment"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			s := New(strings.NewReader(tc.src), LanguagesConfig[tc.config])
 
-			var comments []*Comment
+			s := New(strings.NewReader(src), LanguagesConfig["Go"])
+			s.SetSkipStrings(tc.skip)
+
+			var got []string
 			for s.Scan() {
-				comments = append(comments, s.Next())
+				got = append(got, s.Next().String())
 			}
 			if err := s.Err(); err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
 
-			if want, got := len(tc.comments), len(comments); want != got {
-				t.Fatalf("unexpected # of comments, want: %v, got: %v\n\ncomments:\n%q", want, got, comments)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected comments (-want +got):\n%s", diff)
 			}
+		})
+	}
+}
 
-			for i := range tc.comments {
-				if want, got := tc.comments[i].text, comments[i].String(); want != got {
-					t.Errorf("unexpected text, want: %q, got: %q", want, got)
-				}
+func TestCommentScanner_SetContextLines(t *testing.T) {
+	t.Parallel()
 
-				if want, got := tc.comments[i].line, comments[i].Line; want != got {
-					t.Errorf("unexpected line, want: %d, got: %d", want, got)
-				}
+	src := `package main
+
+import "fmt"
+
+// Line comment
+func main() {
+	/* Multiline
+	comment */
+	fmt.Println("foo") // Trailing comment
+}`
+
+	testCases := map[string]struct {
+		n        int
+		expected []*Comment
+	}{
+		"none": {
+			n: 0,
+			expected: []*Comment{
+				{Text: "// Line comment", Line: 5},
+				{Text: "/* Multiline\n\tcomment */", Line: 7, Multiline: true},
+				{Text: "// Trailing comment", Line: 9},
+			},
+		},
+		"one": {
+			n: 1,
+			expected: []*Comment{
+				{
+					Text:   "// Line comment",
+					Line:   5,
+					Before: []string{""},
+					After:  []string{"func main() {"},
+				},
+				{
+					Text:      "/* Multiline\n\tcomment */",
+					Line:      7,
+					Multiline: true,
+					Before:    []string{"func main() {"},
+					After:     []string{"\tfmt.Println(\"foo\") // Trailing comment"},
+				},
+				{
+					Text:   "// Trailing comment",
+					Line:   9,
+					Before: []string{"\tcomment */"},
+					After:  []string{"}"},
+				},
+			},
+		},
+		"more than file": {
+			n: 5,
+			expected: []*Comment{
+				{
+					Text:   "// Line comment",
+					Line:   5,
+					Before: []string{"package main", "", "import \"fmt\"", ""},
+					After: []string{
+						"func main() {",
+						"\t/* Multiline",
+						"\tcomment */",
+						"\tfmt.Println(\"foo\") // Trailing comment",
+						"}",
+					},
+				},
+				{
+					Text:      "/* Multiline\n\tcomment */",
+					Line:      7,
+					Multiline: true,
+					Before: []string{
+						"",
+						"import \"fmt\"",
+						"",
+						"// Line comment",
+						"func main() {",
+					},
+					After: []string{
+						"\tfmt.Println(\"foo\") // Trailing comment",
+						"}",
+					},
+				},
+				{
+					Text: "// Trailing comment",
+					Line: 9,
+					Before: []string{
+						"",
+						"// Line comment",
+						"func main() {",
+						"\t/* Multiline",
+						"\tcomment */",
+					},
+					After: []string{"}"},
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := New(strings.NewReader(src), LanguagesConfig["Go"])
+			s.SetContextLines(tc.n)
+
+			var got []*Comment
+			for s.Scan() {
+				got = append(got, s.Next())
+			}
+			if err := s.Err(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ignoreOffsets := cmpopts.IgnoreFields(Comment{}, "Offset", "EndOffset")
+			if diff := cmp.Diff(tc.expected, got, ignoreOffsets); diff != "" {
+				t.Errorf("unexpected comments (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCommentScanner_SetReuseComments(t *testing.T) {
+	t.Parallel()
+
+	src := "// first\n/* second */\n// third"
+
+	s := New(strings.NewReader(src), LanguagesConfig["Go"])
+	s.SetReuseComments(true)
+
+	var texts []string
+	var first *Comment
+	for s.Scan() {
+		c := s.Next()
+		if first == nil {
+			first = c
+		}
+		if c != first {
+			t.Errorf("comment was not reused: %p != %p", c, first)
+		}
+		texts = append(texts, c.Text)
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"// first", "/* second */", "// third"}
+	if diff := cmp.Diff(want, texts); diff != "" {
+		t.Errorf("unexpected comments (-want +got):\n%s", diff)
+	}
+}
+
+func TestCommentAt(t *testing.T) {
+	t.Parallel()
+
+	src := "package foo\n\n// first\n/*\nsecond\n*/\nfunc foo() {} // third\n"
+
+	testCases := map[string]struct {
+		line     int
+		expected string
+	}{
+		"code": {
+			line: 1,
+		},
+		"line comment": {
+			line:     3,
+			expected: "// first",
+		},
+		"multiline start": {
+			line:     4,
+			expected: "/*\nsecond\n*/",
+		},
+		"multiline middle": {
+			line:     5,
+			expected: "/*\nsecond\n*/",
+		},
+		"multiline end": {
+			line:     6,
+			expected: "/*\nsecond\n*/",
+		},
+		"trailing comment": {
+			line:     7,
+			expected: "// third",
+		},
+		"past end": {
+			line: 10,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := New(strings.NewReader(src), LanguagesConfig["Go"])
+			c, err := CommentAt(s, tc.line)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got string
+			if c != nil {
+				got = c.Text
+			}
+			if got != tc.expected {
+				t.Errorf("unexpected comment, got: %q, want: %q", got, tc.expected)
 			}
 		})
 	}
 }
 
+func TestCommentScanner_Stats(t *testing.T) {
+	t.Parallel()
+
+	src := "x := \"// not a comment\" // line\n/* block */\n"
+
+	s := New(strings.NewReader(src), LanguagesConfig["Go"])
+	for s.Scan() {
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// NOTE: The scanner transitions code -> string -> code -> line comment
+	// -> code -> multiline comment -> code.
+	want := Stats{
+		Transitions: 6,
+		Bytes:       len(src),
+		Comments:    2,
+	}
+	if diff := cmp.Diff(want, s.Stats()); diff != "" {
+		t.Errorf("unexpected stats (-want +got):\n%s", diff)
+	}
+}
+
 var scannerRegressionTestCases = []*struct {
 	name   string
 	src    string
@@ -2746,8 +4290,9 @@ var scannerRegressionTestCases = []*struct {
 		},
 		expectedComments: []*Comment{
 			{
-				Text: "// last line",
-				Line: 1,
+				Text:      "// last line",
+				Line:      1,
+				EndOffset: 12,
 			},
 		},
 	},
@@ -2771,6 +4316,87 @@ var scannerRegressionTestCases = []*struct {
 
 		expectedComments: nil,
 	},
+	{
+		name: "line_start_first_line.foo",
+		src:  "# first line\nx # not a comment\n#last line",
+		config: &Config{
+			LineComments: []LineCommentConfig{
+				{
+					Start:       []rune("#"),
+					AtLineStart: true,
+				},
+			},
+		},
+		expectedComments: []*Comment{
+			{
+				Text:      "# first line",
+				Line:      1,
+				EndOffset: 12,
+			},
+			{
+				Text:      "#last line",
+				Line:      3,
+				Offset:    31,
+				EndOffset: 41,
+			},
+		},
+	},
+	{
+		name: "line_start_after_string.foo",
+		src:  "\"\" # not a comment\n  # indented",
+		config: &Config{
+			LineComments: []LineCommentConfig{
+				{
+					Start:       []rune("#"),
+					AtLineStart: true,
+					AllowIndent: true,
+				},
+			},
+			Strings: []StringConfig{
+				{
+					Start:      []rune{'"'},
+					End:        []rune{'"'},
+					EscapeFunc: NoEscape,
+				},
+			},
+		},
+		expectedComments: []*Comment{
+			{
+				Text:      "# indented",
+				Line:      2,
+				Offset:    21,
+				EndOffset: 31,
+			},
+		},
+	},
+	{
+		name: "classic_mac_newlines.go",
+		src:  "// first\rx := 1\r/* multi\rline */\r\n// last",
+		config: &Config{
+			LineComments:      cLineComments,
+			MultilineComments: cBlockComments,
+		},
+		expectedComments: []*Comment{
+			{
+				Text:      "// first",
+				Line:      1,
+				EndOffset: 8,
+			},
+			{
+				Text:      "/* multi\rline */",
+				Line:      3,
+				Multiline: true,
+				Offset:    16,
+				EndOffset: 32,
+			},
+			{
+				Text:      "// last",
+				Line:      5,
+				Offset:    34,
+				EndOffset: 41,
+			},
+		},
+	},
 }
 
 func TestCommentScanner_regression(t *testing.T) {
@@ -2810,8 +4436,24 @@ func BenchmarkCommentScanner(b *testing.B) {
 	for i := range scannerTestCases {
 		tc := scannerTestCases[i]
 		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				s := New(strings.NewReader(tc.src), LanguagesConfig[tc.config])
+				for s.Scan() {
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkCommentScanner_reuse(b *testing.B) {
+	for i := range scannerTestCases {
+		tc := scannerTestCases[i]
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
 			for i := 0; i < b.N; i++ {
 				s := New(strings.NewReader(tc.src), LanguagesConfig[tc.config])
+				s.SetReuseComments(true)
 				for s.Scan() {
 				}
 			}
@@ -3001,12 +4643,17 @@ func TestFromFile(t *testing.T) {
 			}
 
 			var config *Config
+			var lang string
 			if s != nil {
 				config = s.Config()
+				lang = s.Language()
 			}
 			if got, want := config, LanguagesConfig[tc.expectedConfig]; got != want {
 				t.Fatalf("unexpected config, got: %#v, want: %#v", got, want)
 			}
+			if got, want := lang, tc.expectedConfig; got != want {
+				t.Errorf("unexpected language, got: %q, want: %q", got, want)
+			}
 		})
 	}
 }
@@ -3037,12 +4684,219 @@ func TestFromBytes(t *testing.T) {
 			}
 
 			var config *Config
+			var lang string
+			if s != nil {
+				config = s.Config()
+				lang = s.Language()
+			}
+			if got, want := config, LanguagesConfig[tc.expectedConfig]; got != want {
+				t.Fatalf("unexpected config, got: %#v, want: %#v", got, want)
+			}
+			if got, want := lang, tc.expectedConfig; got != want {
+				t.Errorf("unexpected language, got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
+
+func TestFromBytes_languageMapping(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		fileName       string
+		expectedConfig string
+	}{
+		"dune": {
+			fileName:       "dune",
+			expectedConfig: "Dune",
+		},
+		"dune-workspace": {
+			fileName:       "dune-workspace",
+			expectedConfig: "Dune",
+		},
+		"dune-project": {
+			fileName:       "dune-project",
+			expectedConfig: "Dune",
+		},
+		"dune in sub-directory": {
+			fileName:       "path/to/dune",
+			expectedConfig: "Dune",
+		},
+		"gitignore": {
+			fileName:       ".gitignore",
+			expectedConfig: "Ignore List",
+		},
+		"dockerignore": {
+			fileName:       ".dockerignore",
+			expectedConfig: "Ignore List",
+		},
+		"npmignore": {
+			fileName:       ".npmignore",
+			expectedConfig: "Ignore List",
+		},
+		"gcloudignore": {
+			fileName:       ".gcloudignore",
+			expectedConfig: "Ignore List",
+		},
+		"helmignore in sub-directory": {
+			fileName:       "charts/foo/.helmignore",
+			expectedConfig: "Ignore List",
+		},
+		"godot shader": {
+			fileName:       "foo.gdshader",
+			expectedConfig: "GLSL",
+		},
+		"unity compute shader": {
+			fileName:       "foo.compute",
+			expectedConfig: "HLSL",
+		},
+		"unreal shader": {
+			fileName:       "foo.usf",
+			expectedConfig: "HLSL",
+		},
+		"unreal shader upper case": {
+			fileName:       "FOO.USH",
+			expectedConfig: "HLSL",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := FromBytes(tc.fileName, []byte("; comment\n(library (name foo))"), "UTF-8")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var config *Config
+			var lang string
 			if s != nil {
 				config = s.Config()
+				lang = s.Language()
 			}
 			if got, want := config, LanguagesConfig[tc.expectedConfig]; got != want {
 				t.Fatalf("unexpected config, got: %#v, want: %#v", got, want)
 			}
+			if got, want := lang, tc.expectedConfig; got != want {
+				t.Errorf("unexpected language, got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
+
+func TestFromBytesAs(t *testing.T) {
+	t.Parallel()
+
+	// NOTE: Qt translation files are detected as XML.
+	contents := []byte("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n<!DOCTYPE TS>\n<TS version=\"2.1\">\n</TS>\n")
+
+	testCases := map[string]struct {
+		lang     string
+		expected string
+		method   string
+	}{
+		"detected": {
+			lang:     "",
+			expected: "XML",
+			method:   "content",
+		},
+		"override": {
+			lang:     "TypeScript",
+			expected: "TypeScript",
+			method:   MethodOverride,
+		},
+		"unsupported": {
+			lang:     "Text",
+			expected: "",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := FromBytesAs("foo.ts", contents, "UTF-8", tc.lang)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got, method string
+			if s != nil {
+				got = s.Language()
+				method = s.LanguageDetection().Method
+			}
+			if got != tc.expected {
+				t.Errorf("unexpected language, got: %q, want: %q", got, tc.expected)
+			}
+			if method != tc.method {
+				t.Errorf("unexpected detection method, got: %q, want: %q", method, tc.method)
+			}
+		})
+	}
+}
+
+func TestFromBytesWith(t *testing.T) {
+	t.Parallel()
+
+	config := func(lang string) *Config {
+		switch lang {
+		case "Text", "Go":
+			return PlainText
+		case "":
+			return Text
+		}
+		return nil
+	}
+
+	testCases := map[string]struct {
+		fileName string
+		src      string
+		expected []string
+	}{
+		"unsupported": {
+			fileName: "notes.txt",
+			src:      "# TODO: text\n// not a comment\n",
+			expected: []string{"# TODO: text"},
+		},
+		"supported": {
+			fileName: "code.go",
+			src:      "# TODO: text\n// not a comment\n",
+			expected: []string{"# TODO: text"},
+		},
+		"not text": {
+			fileName: "script.py",
+			src:      "# TODO: python\n",
+			expected: []string{"# TODO: python"},
+		},
+		"not detected": {
+			fileName: "unknown",
+			src:      "TODO: line\n\n  // TODO: comment\r\nlast",
+			expected: []string{"TODO: line", "  // TODO: comment", "last"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := FromBytesWith(tc.fileName, []byte(tc.src), "UTF-8", "", config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s == nil {
+				t.Fatalf("unexpected nil scanner")
+			}
+
+			var got []string
+			for s.Scan() {
+				got = append(got, s.Next().Text)
+			}
+			if err := s.Err(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected comments (-want +got):\n%s", diff)
+			}
 		})
 	}
 }
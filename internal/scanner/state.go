@@ -14,17 +14,35 @@
 
 package scanner
 
+// stateKind identifies a state in the scanner's transition table.
+type stateKind int
+
+const (
+	kindCode stateKind = iota
+	kindString
+	kindLineComment
+	kindLineCommentOrString
+	kindMultilineComment
+	kindStatementComment
+
+	// numStateKinds is the number of state kinds.
+	numStateKinds
+)
+
 type state interface {
-	stateMustImplement()
+	kind() stateKind
 }
 
 type stateCode struct{}
 
-func (s *stateCode) stateMustImplement() {}
+func (s *stateCode) kind() stateKind { return kindCode }
 
-type stateLineComment struct{}
+type stateLineComment struct {
+	// doc indicates that the line comment is a documentation comment.
+	doc bool
+}
 
-func (s *stateLineComment) stateMustImplement() {}
+func (s *stateLineComment) kind() stateKind { return kindLineComment }
 
 type stateMultilineComment struct {
 	// line is the line of the start of the multi-line comment.
@@ -32,9 +50,23 @@ type stateMultilineComment struct {
 
 	// index is the index for the type of multiline comment.
 	index int
+
+	// depth is the number of nested comments inside the comment that have
+	// not ended.
+	depth int
+}
+
+func (s *stateMultilineComment) kind() stateKind { return kindMultilineComment }
+
+type stateStatementComment struct {
+	// line is the line of the start of the statement comment.
+	line int
+
+	// index is the index for the type of statement comment.
+	index int
 }
 
-func (s *stateMultilineComment) stateMustImplement() {}
+func (s *stateStatementComment) kind() stateKind { return kindStatementComment }
 
 // stateLineCommentOrString implements the special case when strings and line
 // comments start with the same character. e.g. Vim Script.
@@ -43,11 +75,15 @@ type stateLineCommentOrString struct {
 	index int
 }
 
-func (s *stateLineCommentOrString) stateMustImplement() {}
+func (s *stateLineCommentOrString) kind() stateKind { return kindLineCommentOrString }
 
 type stateString struct {
 	// index is the index for the type of string.
 	index int
+
+	// tag is the start and end sequence of tagged strings, including the
+	// tag. It is nil for other strings.
+	tag []rune
 }
 
-func (s *stateString) stateMustImplement() {}
+func (s *stateString) kind() stateKind { return kindString }
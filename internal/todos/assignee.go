@@ -0,0 +1,46 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package todos
+
+import (
+	"regexp"
+	"strings"
+)
+
+// assigneeMatch matches labels that look like user names or email
+// addresses, optionally prefixed by "@".
+var assigneeMatch = regexp.MustCompile(`^@?(\w[\w.\-+@]*)$`)
+
+// numberMatch matches labels that are only a number, such as bug IDs.
+var numberMatch = regexp.MustCompile(`^\d+$`)
+
+// Assignee returns the user that a TODO with the label is assigned to by
+// convention, such as "alice" for "TODO(alice)", "TODO(@alice)", or
+// "TODO(assignee=alice)". It returns an empty string for labels that are
+// issue references, URLs, numbers, other attributes, or that contain spaces.
+func Assignee(label string) string {
+	label = strings.TrimSpace(label)
+	if attrs := ParseAttributes(label); attrs != nil {
+		label = attrs["assignee"]
+	}
+	if ParseReference(label, "") != nil {
+		return ""
+	}
+	match := assigneeMatch.FindStringSubmatch(label)
+	if match == nil || numberMatch.MatchString(match[1]) {
+		return ""
+	}
+	return match[1]
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package todos
+
+import (
+	"testing"
+)
+
+func TestAssignee(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		label    string
+		expected string
+	}{
+		"empty": {
+			label:    "",
+			expected: "",
+		},
+		"user": {
+			label:    "alice",
+			expected: "alice",
+		},
+		"mention": {
+			label:    "@alice",
+			expected: "alice",
+		},
+		"dotted": {
+			label:    "alice.smith",
+			expected: "alice.smith",
+		},
+		"email": {
+			label:    "alice@example.com",
+			expected: "alice@example.com",
+		},
+		"space": {
+			label:    " alice ",
+			expected: "alice",
+		},
+		"issue": {
+			label:    "#123",
+			expected: "",
+		},
+		"repo issue": {
+			label:    "ianlewis/todos#123",
+			expected: "",
+		},
+		"url": {
+			label:    "https://example.com/issues/123",
+			expected: "",
+		},
+		"bug number": {
+			label:    "123",
+			expected: "",
+		},
+		"assignee attribute": {
+			label:    "priority=p1, assignee=@alice",
+			expected: "alice",
+		},
+		"other attributes": {
+			label:    "owner=alice",
+			expected: "",
+		},
+		"sentence": {
+			label:    "after the release",
+			expected: "",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Assignee(tc.label); got != tc.expected {
+				t.Errorf("unexpected assignee, got: %q, want: %q", got, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package todos
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PriorityKeyword maps a keyword in TODO messages to a priority.
+type PriorityKeyword struct {
+	// Keyword is one or more words, such as "data loss".
+	Keyword string
+
+	// Priority is the priority of TODOs whose message contains the keyword,
+	// such as "high".
+	Priority string
+}
+
+// DefaultPriorityKeywords are the keywords used to infer priorities if none
+// are configured.
+var DefaultPriorityKeywords = []PriorityKeyword{
+	{Keyword: "security", Priority: "high"},
+	{Keyword: "vulnerability", Priority: "high"},
+	{Keyword: "data loss", Priority: "high"},
+	{Keyword: "urgent", Priority: "high"},
+	{Keyword: "critical", Priority: "high"},
+	{Keyword: "crash", Priority: "high"},
+	{Keyword: "bug", Priority: "medium"},
+	{Keyword: "performance", Priority: "medium"},
+	{Keyword: "cleanup", Priority: "low"},
+	{Keyword: "refactor", Priority: "low"},
+}
+
+// InferPriority returns the priority of the first of the keywords that
+// appears in the message as whole words, ignoring case. It returns an empty
+// string if none of the keywords appear in the message.
+func InferPriority(message string, keywords []PriorityKeyword) string {
+	if len(keywords) == 0 {
+		return ""
+	}
+	message = strings.ToLower(message)
+	for _, k := range keywords {
+		if k.Keyword != "" && containsWords(message, strings.ToLower(k.Keyword)) {
+			return k.Priority
+		}
+	}
+	return ""
+}
+
+// containsWords returns whether s contains words at word boundaries.
+func containsWords(s, words string) bool {
+	for i := 0; i <= len(s)-len(words); {
+		j := strings.Index(s[i:], words)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(words)
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+		i = start + 1
+	}
+	return false
+}
+
+// isWordRune returns whether r is part of a word.
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
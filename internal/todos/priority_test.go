@@ -0,0 +1,140 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package todos
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/scanner"
+)
+
+func TestInferPriority(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		message  string
+		keywords []PriorityKeyword
+		expected string
+	}{
+		"no keywords": {
+			message: "fix security issue",
+		},
+		"default": {
+			message:  "fix security issue",
+			keywords: DefaultPriorityKeywords,
+			expected: "high",
+		},
+		"case": {
+			message:  "URGENT: fix before release",
+			keywords: DefaultPriorityKeywords,
+			expected: "high",
+		},
+		"phrase": {
+			message:  "this can cause data loss on restart",
+			keywords: DefaultPriorityKeywords,
+			expected: "high",
+		},
+		"phrase split": {
+			message:  "data is not lost",
+			keywords: DefaultPriorityKeywords,
+		},
+		"no match": {
+			message:  "rename this function",
+			keywords: DefaultPriorityKeywords,
+		},
+		"part of word": {
+			message:  "remove debugging output",
+			keywords: DefaultPriorityKeywords,
+		},
+		"punctuation": {
+			message:  "(bug) off by one",
+			keywords: DefaultPriorityKeywords,
+			expected: "medium",
+		},
+		"later occurrence": {
+			message:  "bugs: a bug in parsing",
+			keywords: []PriorityKeyword{{Keyword: "bug", Priority: "p2"}},
+			expected: "p2",
+		},
+		"first keyword wins": {
+			message: "refactor to fix the security bug",
+			keywords: []PriorityKeyword{
+				{Keyword: "refactor", Priority: "p3"},
+				{Keyword: "security", Priority: "p0"},
+			},
+			expected: "p3",
+		},
+		"unicode": {
+			message:  "Sicherheitslücke schließen",
+			keywords: []PriorityKeyword{{Keyword: "schließen", Priority: "p1"}},
+			expected: "p1",
+		},
+		"empty keyword": {
+			message:  "fix it",
+			keywords: []PriorityKeyword{{Keyword: "", Priority: "p1"}},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := InferPriority(tc.message, tc.keywords), tc.expected; got != want {
+				t.Errorf("unexpected priority, got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
+
+func TestTODOScanner_PriorityKeywords(t *testing.T) {
+	t.Parallel()
+
+	s := NewTODOScanner(&testScanner{
+		comments: []*scanner.Comment{
+			{
+				Text: "// TODO: fix security issue",
+				Line: 1,
+			},
+			{
+				Text: "// TODO-BEGIN(alice): urgent cleanup",
+				Line: 2,
+			},
+			{
+				Text: "// TODO-END",
+				Line: 3,
+			},
+			{
+				Text: "// TODO: rename",
+				Line: 4,
+			},
+		},
+	}, &Config{
+		Types:            []string{"TODO"},
+		PriorityKeywords: DefaultPriorityKeywords,
+	})
+
+	var got []string
+	for s.Scan() {
+		got = append(got, s.Next().InferredPriority)
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"high", "high", ""}, got); diff != "" {
+		t.Errorf("unexpected priorities (-want +got):\n%s", diff)
+	}
+}
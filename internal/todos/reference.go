@@ -0,0 +1,128 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package todos
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ReferenceKind is the kind of GitHub item that a label refers to.
+type ReferenceKind string
+
+const (
+	// ReferenceIssue is a reference to an issue. Short references such as
+	// "#123" are also issue references since GitHub resolves them to either
+	// an issue or a pull request.
+	ReferenceIssue ReferenceKind = "issue"
+
+	// ReferencePullRequest is a reference to a pull request.
+	ReferencePullRequest ReferenceKind = "pull_request"
+
+	// ReferenceDiscussion is a reference to a discussion.
+	ReferenceDiscussion ReferenceKind = "discussion"
+)
+
+// referenceURLKinds maps GitHub URL path segments to reference kinds.
+var referenceURLKinds = map[string]ReferenceKind{
+	"issues":      ReferenceIssue,
+	"pull":        ReferencePullRequest,
+	"discussions": ReferenceDiscussion,
+}
+
+// referenceURLMatch matches GitHub issue, pull request, and discussion URLs
+// with or without a scheme.
+var referenceURLMatch = regexp.MustCompile(
+	`^(?:https?://)?(?:www\.)?github\.com/([\w.\-]+)/([\w.\-]+)/(issues|pull|discussions)/(\d+)(?:[/?#].*)?$`)
+
+// referenceShortMatch matches short references such as "#123" and
+// "owner/repo#123".
+var referenceShortMatch = regexp.MustCompile(`^(?:([\w.\-]+)/([\w.\-]+))?#(\d+)$`)
+
+// Reference is a reference to a GitHub issue, pull request, or discussion.
+type Reference struct {
+	// Owner is the lower case repository owner. It is empty for short
+	// references that could not be resolved to a repository.
+	Owner string
+
+	// Repo is the lower case repository name.
+	Repo string
+
+	// Number is the issue, pull request, or discussion number. Numbers are
+	// shared between them in a repository.
+	Number int
+
+	// Kind is the kind of item referred to.
+	Kind ReferenceKind
+}
+
+// String returns the canonical form of the reference, "owner/repo#123", or
+// "#123" if the repository is not known.
+func (r *Reference) String() string {
+	num := "#" + strconv.Itoa(r.Number)
+	if r.Owner == "" || r.Repo == "" {
+		return num
+	}
+	return r.Owner + "/" + r.Repo + num
+}
+
+// ParseReference parses a label that refers to a GitHub issue, pull request,
+// or discussion. URLs such as "https://github.com/owner/repo/pull/123" or
+// "github.com/owner/repo/discussions/123" and references such as
+// "owner/repo#123" are recognized. Short references such as "#123" are
+// resolved against repo ("owner/repo") if it is not empty. It returns nil if
+// the label is not a reference.
+func ParseReference(label, repo string) *Reference {
+	label = strings.TrimSpace(label)
+
+	var owner, name, num string
+	kind := ReferenceIssue
+	if match := referenceURLMatch.FindStringSubmatch(label); match != nil {
+		owner, name, num = match[1], match[2], match[4]
+		kind = referenceURLKinds[match[3]]
+	} else if match := referenceShortMatch.FindStringSubmatch(label); match != nil {
+		owner, name, num = match[1], match[2], match[3]
+		if owner == "" {
+			owner, name, _ = strings.Cut(repo, "/")
+		}
+	} else {
+		return nil
+	}
+
+	n, err := strconv.Atoi(num)
+	if err != nil {
+		return nil
+	}
+
+	// NOTE: GitHub owner and repository names are case-insensitive.
+	return &Reference{
+		Owner:  strings.ToLower(owner),
+		Repo:   strings.ToLower(name),
+		Number: n,
+		Kind:   kind,
+	}
+}
+
+// CanonicalLabel returns the canonical reference for labels that refer to a
+// GitHub issue, pull request, or discussion. See ParseReference. It returns
+// an empty string if the label is not a reference.
+func CanonicalLabel(label, repo string) string {
+	ref := ParseReference(label, repo)
+	if ref == nil {
+		return ""
+	}
+	return ref.String()
+}
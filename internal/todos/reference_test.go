@@ -0,0 +1,167 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package todos
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseReference(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		label    string
+		repo     string
+		expected *Reference
+	}{
+		"not a reference": {
+			label: "alice",
+		},
+		"short": {
+			label: "#123",
+			expected: &Reference{
+				Number: 123,
+				Kind:   ReferenceIssue,
+			},
+		},
+		"short with repo": {
+			label: "#123",
+			repo:  "owner/repo",
+			expected: &Reference{
+				Owner:  "owner",
+				Repo:   "repo",
+				Number: 123,
+				Kind:   ReferenceIssue,
+			},
+		},
+		"issue": {
+			label: "https://github.com/owner/repo/issues/123",
+			expected: &Reference{
+				Owner:  "owner",
+				Repo:   "repo",
+				Number: 123,
+				Kind:   ReferenceIssue,
+			},
+		},
+		"pull request": {
+			label: "github.com/owner/repo/pull/55",
+			expected: &Reference{
+				Owner:  "owner",
+				Repo:   "repo",
+				Number: 55,
+				Kind:   ReferencePullRequest,
+			},
+		},
+		"discussion": {
+			label: "https://github.com/Owner/Repo/discussions/10#discussioncomment-1",
+			expected: &Reference{
+				Owner:  "owner",
+				Repo:   "repo",
+				Number: 10,
+				Kind:   ReferenceDiscussion,
+			},
+		},
+		"unknown path": {
+			label: "https://github.com/owner/repo/wiki/10",
+		},
+		"number too large": {
+			label: "#99999999999999999999999",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := ParseReference(tc.label, tc.repo)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected reference (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCanonicalLabel(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		label    string
+		repo     string
+		expected string
+	}{
+		"empty": {
+			label: "",
+		},
+		"not an issue": {
+			label: "alice",
+		},
+		"short ref": {
+			label:    "#123",
+			expected: "#123",
+		},
+		"short ref with repo": {
+			label:    "#123",
+			repo:     "Owner/Repo",
+			expected: "owner/repo#123",
+		},
+		"repo ref": {
+			label:    "owner/repo#123",
+			repo:     "other/repo",
+			expected: "owner/repo#123",
+		},
+		"url": {
+			label:    "https://github.com/Owner/Repo/issues/123",
+			expected: "owner/repo#123",
+		},
+		"url without scheme": {
+			label:    "github.com/owner/repo/issues/123",
+			expected: "owner/repo#123",
+		},
+		"pull request url": {
+			label:    "https://www.github.com/owner/repo/pull/123/files",
+			expected: "owner/repo#123",
+		},
+		"discussion url": {
+			label:    "github.com/owner/repo/discussions/10",
+			expected: "owner/repo#10",
+		},
+		"url with fragment": {
+			label:    "https://github.com/owner/repo/issues/123#issuecomment-1",
+			expected: "owner/repo#123",
+		},
+		"leading zeros": {
+			label:    " #0123 ",
+			expected: "#123",
+		},
+		"other host": {
+			label: "https://gitlab.com/owner/repo/issues/123",
+		},
+		"not a number": {
+			label: "#abc",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := CanonicalLabel(tc.label, tc.repo), tc.expected; got != want {
+				t.Errorf("unexpected canonical label, got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
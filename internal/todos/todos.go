@@ -16,6 +16,7 @@ package todos
 
 import (
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/ianlewis/todos/internal/scanner"
@@ -52,19 +53,77 @@ type TODO struct {
 	// Label is the label part (the part in parenthesis)
 	Label string
 
+	// CanonicalLabel is the canonical issue reference if the label refers to
+	// an issue so that equivalent references compare equal. It is empty for
+	// other labels. See CanonicalLabel.
+	CanonicalLabel string
+
+	// ReferenceKind is the kind of item the label refers to if it is an issue
+	// reference. It is empty for other labels.
+	ReferenceKind ReferenceKind
+
 	// Message is the comment message (the part after the parenthesis).
 	Message string
 
+	// Attributes are key=value pairs parsed from the label. See
+	// ParseAttributes.
+	Attributes map[string]string
+
+	// InferredPriority is the priority inferred from keywords in the
+	// message. It is empty if no priority keywords are configured or none
+	// match. See InferPriority.
+	InferredPriority string
+
 	// Line is the line number where todo was found..
 	Line int
 
 	// CommentLine is the line where the comment starts.
 	CommentLine int
+
+	// CommentOffset is the byte offset of the start of the comment in the
+	// original file contents.
+	CommentOffset int
+
+	// CommentEndOffset is the byte offset of the end of the comment in the
+	// original file contents.
+	CommentEndOffset int
+
+	// EndLine is the line of the matching end marker for TODOs that begin a
+	// region (e.g. TODO-BEGIN ... TODO-END). It is zero for other TODOs.
+	EndLine int
+
+	// Unbalanced is true for region markers that have no matching begin or
+	// end marker.
+	Unbalanced bool
 }
 
 // Config is configuration for the TODOScanner.
 type Config struct {
 	Types []string
+
+	// NearMisses enables finding TODOs whose type matches one of the Types
+	// except for case (e.g. "Todo" when only "TODO" is configured). They are
+	// returned by TODOScanner.NearMisses rather than by Scan.
+	NearMisses bool
+
+	// RequireDelimiter requires a colon after the TODO type or label (e.g.
+	// "TODO: msg" or "TODO(label): msg") so that bare TODO words in prose
+	// are not matched. Region markers (e.g. "TODO-BEGIN") are still matched.
+	RequireDelimiter bool
+
+	// MatchAnywhere matches TODOs that follow other text in a comment (e.g.
+	// "// package comment, TODO: msg"). TODOs after other text must be at
+	// the start of a word and be followed by a delimiter or label, or end the
+	// comment, so that TODO words in prose are not matched.
+	MatchAnywhere bool
+
+	// Repo is the GitHub repository ("owner/repo") that short issue
+	// references such as "#123" in labels refer to.
+	Repo string
+
+	// PriorityKeywords are used to infer the priority of TODOs from their
+	// messages. Priorities are not inferred if it is empty.
+	PriorityKeywords []PriorityKeyword
 }
 
 // CommentScanner is a type that scans code text for comments.
@@ -83,12 +142,74 @@ type CommentScanner interface {
 	Err() error
 }
 
+// lineEndMatch matches Unix, Windows, and classic Mac OS line endings.
+var lineEndMatch = regexp.MustCompile("\r\n|\r|\n")
+
+// attrKeyMatch matches valid attribute keys.
+var attrKeyMatch = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+
+// ParseAttributes parses comma separated key=value attributes from a TODO
+// label such as "assignee=alice, due=2025-01-01". Parts of the label that
+// are not key=value pairs are ignored. It returns nil if the label has no
+// attributes.
+func ParseAttributes(label string) map[string]string {
+	var attrs map[string]string
+	for _, part := range strings.Split(label, ",") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if !attrKeyMatch.MatchString(key) {
+			continue
+		}
+		if attrs == nil {
+			attrs = map[string]string{}
+		}
+		attrs[key] = strings.TrimSpace(value)
+	}
+	return attrs
+}
+
+// delimiterMatch matches the text following the TODO type if it has a colon
+// delimiter after the type or label, or is a region marker.
+var delimiterMatch = regexp.MustCompile(`^(?:(?:\(.*\))?\s*:|-(?i:` + regionBegin + `|` + regionEnd + `)\b)`)
+
+// Region marker kinds.
+const (
+	regionBegin = "BEGIN"
+	regionEnd   = "END"
+)
+
 // TODOScanner scans for TODO comments.
 type TODOScanner struct {
 	next           []*TODO
 	s              CommentScanner
+	types          []string
+	repo           string
+	priorities     []PriorityKeyword
 	lineMatch      []*regexp.Regexp
 	multilineMatch *regexp.Regexp
+	regionMatch    *regexp.Regexp
+
+	// nearMissLineMatch and nearMissMultilineMatch match TODO types case
+	// insensitively. They are nil if near misses are not enabled.
+	nearMissLineMatch      []*regexp.Regexp
+	nearMissMultilineMatch *regexp.Regexp
+
+	// requireDelimiter indicates that TODOs must have a colon delimiter.
+	requireDelimiter bool
+
+	// nearMisses are the TODOs found whose type matched one of the types
+	// except for case.
+	nearMisses []*TODO
+
+	// regions is the stack of currently open regions.
+	regions []*TODO
+
+	// pending are TODOs found inside open regions. They are held until the
+	// outermost region is closed so that TODOs are returned in order.
+	pending []*TODO
 }
 
 // NewTODOScanner returns a new TODOScanner.
@@ -97,17 +218,43 @@ func NewTODOScanner(s CommentScanner, config *Config) *TODOScanner {
 		s: s,
 	}
 
-	sConfig := s.Config()
-	var commentStarts []string
-	for _, c := range sConfig.LineComments {
-		commentStarts = append(commentStarts, "(?:"+regexp.QuoteMeta(string(c.Start))+")+")
+	// NOTE: Comments in embedded code use the embedded language's comment
+	// start sequences.
+	sConfigs := []*scanner.Config{s.Config()}
+	for _, e := range s.Config().Embedded {
+		if c, ok := scanner.LanguagesConfig[e.Language]; ok {
+			sConfigs = append(sConfigs, c)
+		}
 	}
-	commentStartMatch := strings.Join(commentStarts, "|")
 
+	var commentStarts []string
 	var multilineStarts []string
-	for _, c := range sConfig.MultilineComments {
-		multilineStarts = append(multilineStarts, "(?:"+regexp.QuoteMeta(string(c.Start))+")+")
+	for _, sConfig := range sConfigs {
+		for _, c := range sConfig.LineComments {
+			start := "(?:" + regexp.QuoteMeta(string(c.Start)) + ")+"
+			if !slices.Contains(commentStarts, start) {
+				commentStarts = append(commentStarts, start)
+			}
+		}
+		var starts [][]rune
+		for _, c := range sConfig.MultilineComments {
+			starts = append(starts, c.Start)
+		}
+		for _, c := range sConfig.StatementComments {
+			starts = append(starts, c.Start)
+		}
+		for _, rs := range starts {
+			start := "(?:" + regexp.QuoteMeta(string(rs)) + ")+"
+			if !slices.Contains(multilineStarts, start) {
+				multilineStarts = append(multilineStarts, start)
+			}
+		}
 	}
+	// NOTE: Whole lines may start with any punctuation, such as "#" or "-".
+	if s.Config().WholeLines {
+		commentStarts = append(commentStarts, `[^\w\s]*`)
+	}
+	commentStartMatch := strings.Join(commentStarts, "|")
 	multiStartMatch := strings.Join(multilineStarts, "|")
 
 	if config == nil {
@@ -137,12 +284,39 @@ func NewTODOScanner(s CommentScanner, config *Config) *TODOScanner {
 		`\((.*)\)\s*[:\-/]*\s*(.*)`, // With label (match[0][6]) and message (match[0][7])
 	}, "|")
 
-	snr.lineMatch = []*regexp.Regexp{
-		regexp.MustCompile(`^\s*(` + commentStartMatch + `)\s*@?(` + typesMatch + `)(` + msgMatch + `)$`),
-		regexp.MustCompile(`^\s*(` + commentStartMatch + `)@?(` + typesMatch + `)(` + msgMatch2 + `)$`),
+	// leadMatch matches leading text before the TODO type. It must end with
+	// a non-word character so that the type is at the start of a word.
+	leadMatch := ""
+	if config.MatchAnywhere {
+		leadMatch = `(?:.*?\W)?\s*`
 	}
-	snr.multilineMatch = regexp.MustCompile(
-		`^(` + multiStartMatch + `\s*|\s*\*?\s*)?@?(` + typesMatch + `)(` + msgMatch + `)$`)
+
+	// NOTE: Leading text is only allowed for TODOs with a delimiter or label,
+	// or at the end of the comment, so it is not added to the second line
+	// comment expression that matches TODOs followed by any message.
+	lineMatch := func(typesMatch string) []*regexp.Regexp {
+		return []*regexp.Regexp{
+			regexp.MustCompile(`^\s*(` + commentStartMatch + `)\s*` + leadMatch + `@?(` + typesMatch + `)(` + msgMatch + `)$`),
+			regexp.MustCompile(`^\s*(` + commentStartMatch + `)@?(` + typesMatch + `)(` + msgMatch2 + `)$`),
+		}
+	}
+	multilineMatch := func(typesMatch string) *regexp.Regexp {
+		return regexp.MustCompile(
+			`^((?:` + multiStartMatch + `)\s*|\s*\*?\s*)?` + leadMatch + `@?(` + typesMatch + `)(` + msgMatch + `)$`)
+	}
+
+	snr.types = config.Types
+	snr.repo = config.Repo
+	snr.priorities = config.PriorityKeywords
+	snr.requireDelimiter = config.RequireDelimiter
+	snr.lineMatch = lineMatch(typesMatch)
+	snr.multilineMatch = multilineMatch(typesMatch)
+	if config.NearMisses {
+		snr.nearMissLineMatch = lineMatch(`(?i:` + typesMatch + `)`)
+		snr.nearMissMultilineMatch = multilineMatch(`(?i:` + typesMatch + `)`)
+	}
+	snr.regionMatch = regexp.MustCompile(
+		`(` + typesMatch + `)-(?i:(` + regionBegin + `|` + regionEnd + `))(?:\(([^)]*)\))?\s*[:\-/]*\s*(.*)$`)
 
 	return snr
 }
@@ -160,28 +334,124 @@ func (t *TODOScanner) Scan() bool {
 		next := t.s.Next()
 
 		if next.Multiline {
-			matches := t.findMultilineMatches(next)
-			t.next = append(t.next, matches...)
-			if len(t.next) > 0 {
-				return true
+			for _, match := range t.findMultilineMatches(next, t.multilineMatch) {
+				t.add(match)
+			}
+			if t.nearMissMultilineMatch != nil {
+				for _, match := range t.findMultilineMatches(next, t.nearMissMultilineMatch) {
+					t.addNearMiss(match)
+				}
 			}
 		} else {
-			match := t.findLineMatch(next)
+			match := t.findLineMatch(next, t.lineMatch)
 			if match != nil {
-				t.next = append(t.next, match)
-				return true
+				t.add(match)
+			} else if t.nearMissLineMatch != nil {
+				if match := t.findLineMatch(next, t.nearMissLineMatch); match != nil {
+					t.addNearMiss(match)
+				}
 			}
 		}
+		if len(t.next) > 0 {
+			return true
+		}
+	}
+
+	// Return regions that were never closed.
+	for _, region := range t.regions {
+		region.Unbalanced = true
+	}
+	t.regions = nil
+	t.next = append(t.next, t.pending...)
+	t.pending = nil
+
+	return len(t.next) > 0
+}
+
+// add adds a found TODO, pairing region begin and end markers.
+func (t *TODOScanner) add(todo *TODO) {
+	kind := t.parseRegion(todo)
+	todo.InferredPriority = InferPriority(todo.Message, t.priorities)
+
+	switch kind {
+	case regionBegin:
+		t.regions = append(t.regions, todo)
+		t.pending = append(t.pending, todo)
+	case regionEnd:
+		if len(t.regions) == 0 {
+			todo.Unbalanced = true
+			t.next = append(t.next, todo)
+			return
+		}
+		t.regions[len(t.regions)-1].EndLine = todo.Line
+		t.regions = t.regions[:len(t.regions)-1]
+		if len(t.regions) == 0 {
+			t.next = append(t.next, t.pending...)
+			t.pending = nil
+		}
+	default:
+		if len(t.regions) > 0 {
+			t.pending = append(t.pending, todo)
+		} else {
+			t.next = append(t.next, todo)
+		}
 	}
-	return false
 }
 
-// findMultilineMatch returns the TODO for the comment if it was found.
-func (t *TODOScanner) findMultilineMatches(c *scanner.Comment) []*TODO {
+// addNearMiss adds a TODO found by matching types case insensitively if its
+// type is not one of the configured types.
+func (t *TODOScanner) addNearMiss(todo *TODO) {
+	if !slices.Contains(t.types, todo.Type) {
+		t.nearMisses = append(t.nearMisses, todo)
+	}
+}
+
+// delimited returns whether the text following the TODO type is delimited
+// as required by the Config.
+func (t *TODOScanner) delimited(rest string) bool {
+	return !t.requireDelimiter || delimiterMatch.MatchString(rest)
+}
+
+// setReference sets the TODO's canonical label and reference kind if its
+// label is an issue reference.
+func (t *TODOScanner) setReference(todo *TODO) {
+	todo.CanonicalLabel, todo.ReferenceKind = "", ""
+	if ref := ParseReference(todo.Label, t.repo); ref != nil {
+		todo.CanonicalLabel = ref.String()
+		todo.ReferenceKind = ref.Kind
+	}
+}
+
+// parseRegion returns the region marker kind if the TODO is a region marker
+// and updates its label and message. It returns an empty string otherwise.
+func (t *TODOScanner) parseRegion(todo *TODO) string {
+	match := t.regionMatch.FindStringSubmatchIndex(todo.Text)
+	// NOTE: The marker must directly follow the TODO type.
+	if match == nil || match[2] != strings.Index(todo.Text, todo.Type) {
+		return ""
+	}
+
+	group := func(i int) string {
+		if match[2*i] < 0 {
+			return ""
+		}
+		return todo.Text[match[2*i]:match[2*i+1]]
+	}
+
+	todo.Label = strings.TrimSpace(group(3))
+	t.setReference(todo)
+	todo.Message = strings.TrimSpace(group(4))
+	todo.Attributes = ParseAttributes(todo.Label)
+	return strings.ToUpper(group(2))
+}
+
+// findMultilineMatch returns the TODOs matched by multilineMatch in the
+// comment.
+func (t *TODOScanner) findMultilineMatches(c *scanner.Comment, multilineMatch *regexp.Regexp) []*TODO {
 	var matches []*TODO
-	for i, line := range strings.Split(c.Text, "\n") {
-		match := t.multilineMatch.FindAllStringSubmatch(line, 1)
-		if len(match) != 0 && len(match[0]) > 2 && match[0][2] != "" {
+	for i, line := range lineEndMatch.Split(c.Text, -1) {
+		match := multilineMatch.FindAllStringSubmatch(line, 1)
+		if len(match) != 0 && len(match[0]) > 2 && match[0][2] != "" && t.delimited(match[0][3]) {
 			label := match[0][5]
 			if label == "" {
 				label = match[0][6]
@@ -192,25 +462,31 @@ func (t *TODOScanner) findMultilineMatches(c *scanner.Comment) []*TODO {
 				message = match[0][7]
 			}
 
-			matches = append(matches, &TODO{
-				Type:    match[0][2],
-				Text:    strings.TrimSpace(line),
-				Label:   strings.TrimSpace(label),
-				Message: strings.TrimSpace(message),
+			todo := &TODO{
+				Type:       match[0][2],
+				Text:       strings.TrimSpace(line),
+				Label:      strings.TrimSpace(label),
+				Message:    strings.TrimSpace(message),
+				Attributes: ParseAttributes(label),
 				// Add the line relative to the file.
-				Line:        c.Line + i,
-				CommentLine: c.Line,
-			})
+				Line:             c.Line + i,
+				CommentLine:      c.Line,
+				CommentOffset:    c.Offset,
+				CommentEndOffset: c.EndOffset,
+			}
+			t.setReference(todo)
+			matches = append(matches, todo)
 		}
 	}
 	return matches
 }
 
-// findLineMatch returns the TODO for the comment if it was found.
-func (t *TODOScanner) findLineMatch(c *scanner.Comment) *TODO {
-	for _, lnMatch := range t.lineMatch {
+// findLineMatch returns the TODO for the comment if it was matched by one of
+// lineMatch.
+func (t *TODOScanner) findLineMatch(c *scanner.Comment, lineMatch []*regexp.Regexp) *TODO {
+	for _, lnMatch := range lineMatch {
 		match := lnMatch.FindAllStringSubmatch(c.Text, 1)
-		if len(match) != 0 && len(match[0]) > 2 && match[0][2] != "" {
+		if len(match) != 0 && len(match[0]) > 2 && match[0][2] != "" && t.delimited(match[0][3]) {
 			label := match[0][5]
 			if label == "" {
 				label = match[0][6]
@@ -221,15 +497,20 @@ func (t *TODOScanner) findLineMatch(c *scanner.Comment) *TODO {
 				message = match[0][7]
 			}
 
-			return &TODO{
-				Type:    match[0][2],
-				Text:    strings.TrimSpace(c.Text),
-				Label:   strings.TrimSpace(label),
-				Message: strings.TrimSpace(message),
+			todo := &TODO{
+				Type:       match[0][2],
+				Text:       strings.TrimSpace(c.Text),
+				Label:      strings.TrimSpace(label),
+				Message:    strings.TrimSpace(message),
+				Attributes: ParseAttributes(label),
 				// Add the line relative to the file.
-				Line:        c.Line,
-				CommentLine: c.Line,
+				Line:             c.Line,
+				CommentLine:      c.Line,
+				CommentOffset:    c.Offset,
+				CommentEndOffset: c.EndOffset,
 			}
+			t.setReference(todo)
+			return todo
 		}
 	}
 
@@ -244,6 +525,13 @@ func (t *TODOScanner) Next() *TODO {
 	return nil
 }
 
+// NearMisses returns the TODOs found so far whose type matched one of the
+// configured types except for case. It returns nil if near misses are not
+// enabled in the Config.
+func (t *TODOScanner) NearMisses() []*TODO {
+	return t.nearMisses
+}
+
 // Err returns the first error encountered.
 func (t *TODOScanner) Err() error {
 	//nolint:wrapcheck
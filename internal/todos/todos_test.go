@@ -15,6 +15,7 @@
 package todos
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -189,15 +190,200 @@ func TestTODOScanner(t *testing.T) {
 			},
 			expected: []*TODO{
 				{
-					Type:        "TODO",
-					Text:        "// TODO(github.com/foo/bar/issues/1)",
-					Label:       "github.com/foo/bar/issues/1",
+					Type:           "TODO",
+					Text:           "// TODO(github.com/foo/bar/issues/1)",
+					Label:          "github.com/foo/bar/issues/1",
+					CanonicalLabel: "foo/bar#1",
+					ReferenceKind:  ReferenceIssue,
+					Line:           5,
+					CommentLine:    5,
+				},
+			},
+		},
+
+		"line_comments_attributes.go": {
+			s: &testScanner{
+				comments: []*scanner.Comment{
+					{
+						Text: "// TODO(assignee=alice, due=2025-01-01, priority=p2): msg",
+						Line: 5,
+					},
+				},
+			},
+			config: &Config{
+				Types: []string{"TODO"},
+			},
+			expected: []*TODO{
+				{
+					Type:    "TODO",
+					Text:    "// TODO(assignee=alice, due=2025-01-01, priority=p2): msg",
+					Label:   "assignee=alice, due=2025-01-01, priority=p2",
+					Message: "msg",
+					Attributes: map[string]string{
+						"assignee": "alice",
+						"due":      "2025-01-01",
+						"priority": "p2",
+					},
 					Line:        5,
 					CommentLine: 5,
 				},
 			},
 		},
 
+		"line_comments_regions.go": {
+			s: &testScanner{
+				comments: []*scanner.Comment{
+					{
+						Text: "// TODO-BEGIN(#123): disabled until fixed",
+						Line: 1,
+					},
+					{
+						Text: "// TODO: inside",
+						Line: 2,
+					},
+					{
+						Text: "// TODO-END",
+						Line: 3,
+					},
+					{
+						Text: "// TODO: after",
+						Line: 4,
+					},
+				},
+			},
+			config: &Config{
+				Types: []string{"TODO"},
+			},
+			expected: []*TODO{
+				{
+					Type:           "TODO",
+					Text:           "// TODO-BEGIN(#123): disabled until fixed",
+					Label:          "#123",
+					CanonicalLabel: "#123",
+					ReferenceKind:  ReferenceIssue,
+					Message:        "disabled until fixed",
+					Line:           1,
+					CommentLine:    1,
+					EndLine:        3,
+				},
+				{
+					Type:        "TODO",
+					Text:        "// TODO: inside",
+					Message:     "inside",
+					Line:        2,
+					CommentLine: 2,
+				},
+				{
+					Type:        "TODO",
+					Text:        "// TODO: after",
+					Message:     "after",
+					Line:        4,
+					CommentLine: 4,
+				},
+			},
+		},
+
+		"line_comments_nested_regions.go": {
+			s: &testScanner{
+				comments: []*scanner.Comment{
+					{
+						Text: "// TODO-BEGIN(outer)",
+						Line: 1,
+					},
+					{
+						Text: "// TODO-begin(inner)",
+						Line: 2,
+					},
+					{
+						Text: "// TODO-end",
+						Line: 3,
+					},
+					{
+						Text: "// TODO-END",
+						Line: 4,
+					},
+				},
+			},
+			config: &Config{
+				Types: []string{"TODO"},
+			},
+			expected: []*TODO{
+				{
+					Type:        "TODO",
+					Text:        "// TODO-BEGIN(outer)",
+					Label:       "outer",
+					Line:        1,
+					CommentLine: 1,
+					EndLine:     4,
+				},
+				{
+					Type:        "TODO",
+					Text:        "// TODO-begin(inner)",
+					Label:       "inner",
+					Line:        2,
+					CommentLine: 2,
+					EndLine:     3,
+				},
+			},
+		},
+
+		"line_comments_unbalanced_regions.go": {
+			s: &testScanner{
+				comments: []*scanner.Comment{
+					{
+						Text: "// TODO-END",
+						Line: 1,
+					},
+					{
+						Text: "// TODO: fix TODO-END parsing",
+						Line: 2,
+					},
+					{
+						Text: "// TODO-BEGIN: never closed",
+						Line: 3,
+					},
+					{
+						Text: "// TODO: inside",
+						Line: 4,
+					},
+				},
+			},
+			config: &Config{
+				Types: []string{"TODO"},
+			},
+			expected: []*TODO{
+				{
+					Type:        "TODO",
+					Text:        "// TODO-END",
+					Line:        1,
+					CommentLine: 1,
+					Unbalanced:  true,
+				},
+				{
+					Type:        "TODO",
+					Text:        "// TODO: fix TODO-END parsing",
+					Message:     "fix TODO-END parsing",
+					Line:        2,
+					CommentLine: 2,
+				},
+				{
+					Type:        "TODO",
+					Text:        "// TODO-BEGIN: never closed",
+					Message:     "never closed",
+					Line:        3,
+					CommentLine: 3,
+					Unbalanced:  true,
+				},
+				{
+					Type:        "TODO",
+					Text:        "// TODO: inside",
+					Message:     "inside",
+					Line:        4,
+					CommentLine: 4,
+				},
+			},
+		},
+
 		"line_comments_bug_message.go": {
 			s: &testScanner{
 				comments: []*scanner.Comment{
@@ -220,12 +406,14 @@ func TestTODOScanner(t *testing.T) {
 			},
 			expected: []*TODO{
 				{
-					Type:        "TODO",
-					Text:        "// TODO(github.com/foo/bar/issues/1): foo",
-					Message:     "foo",
-					Label:       "github.com/foo/bar/issues/1",
-					Line:        5,
-					CommentLine: 5,
+					Type:           "TODO",
+					Text:           "// TODO(github.com/foo/bar/issues/1): foo",
+					Message:        "foo",
+					Label:          "github.com/foo/bar/issues/1",
+					CanonicalLabel: "foo/bar#1",
+					ReferenceKind:  ReferenceIssue,
+					Line:           5,
+					CommentLine:    5,
 				},
 			},
 		},
@@ -284,12 +472,14 @@ func TestTODOScanner(t *testing.T) {
 			},
 			expected: []*TODO{
 				{
-					Type:        "TODO",
-					Text:        "// TODO(github.com/foo/bar/issues/1) - foo",
-					Message:     "foo",
-					Label:       "github.com/foo/bar/issues/1",
-					Line:        5,
-					CommentLine: 5,
+					Type:           "TODO",
+					Text:           "// TODO(github.com/foo/bar/issues/1) - foo",
+					Message:        "foo",
+					Label:          "github.com/foo/bar/issues/1",
+					CanonicalLabel: "foo/bar#1",
+					ReferenceKind:  ReferenceIssue,
+					Line:           5,
+					CommentLine:    5,
 				},
 			},
 		},
@@ -316,12 +506,14 @@ func TestTODOScanner(t *testing.T) {
 			},
 			expected: []*TODO{
 				{
-					Type:        "TODO",
-					Text:        "// TODO(github.com/foo/bar/issues/1) / foo",
-					Message:     "foo",
-					Label:       "github.com/foo/bar/issues/1",
-					Line:        5,
-					CommentLine: 5,
+					Type:           "TODO",
+					Text:           "// TODO(github.com/foo/bar/issues/1) / foo",
+					Message:        "foo",
+					Label:          "github.com/foo/bar/issues/1",
+					CanonicalLabel: "foo/bar#1",
+					ReferenceKind:  ReferenceIssue,
+					Line:           5,
+					CommentLine:    5,
 				},
 			},
 		},
@@ -348,12 +540,14 @@ func TestTODOScanner(t *testing.T) {
 			},
 			expected: []*TODO{
 				{
-					Type:        "TODO",
-					Text:        "// TODO(github.com/foo/bar/issues/1) // foo",
-					Message:     "foo",
-					Label:       "github.com/foo/bar/issues/1",
-					Line:        5,
-					CommentLine: 5,
+					Type:           "TODO",
+					Text:           "// TODO(github.com/foo/bar/issues/1) // foo",
+					Message:        "foo",
+					Label:          "github.com/foo/bar/issues/1",
+					CanonicalLabel: "foo/bar#1",
+					ReferenceKind:  ReferenceIssue,
+					Line:           5,
+					CommentLine:    5,
 				},
 			},
 		},
@@ -380,12 +574,14 @@ func TestTODOScanner(t *testing.T) {
 			},
 			expected: []*TODO{
 				{
-					Type:        "TODO",
-					Text:        "// TODO(github.com/foo/bar/issues/1) foo",
-					Message:     "foo",
-					Label:       "github.com/foo/bar/issues/1",
-					Line:        5,
-					CommentLine: 5,
+					Type:           "TODO",
+					Text:           "// TODO(github.com/foo/bar/issues/1) foo",
+					Message:        "foo",
+					Label:          "github.com/foo/bar/issues/1",
+					CanonicalLabel: "foo/bar#1",
+					ReferenceKind:  ReferenceIssue,
+					Line:           5,
+					CommentLine:    5,
 				},
 			},
 		},
@@ -451,6 +647,29 @@ func TestTODOScanner(t *testing.T) {
 				},
 			},
 		},
+		"multiline_comments_classic_mac.go": {
+			s: &testScanner{
+				comments: []*scanner.Comment{
+					{
+						Text:      "/*\rfoo\r\nTODO: foo\r*/",
+						Line:      5,
+						Multiline: true,
+					},
+				},
+			},
+			config: &Config{
+				Types: []string{"TODO"},
+			},
+			expected: []*TODO{
+				{
+					Type:        "TODO",
+					Text:        "TODO: foo",
+					Message:     "foo",
+					Line:        7,
+					CommentLine: 5,
+				},
+			},
+		},
 		"multiline_comments_bug.go": {
 			s: &testScanner{
 				comments: []*scanner.Comment{
@@ -708,12 +927,14 @@ func TestTODOScanner(t *testing.T) {
 			},
 			expected: []*TODO{
 				{
-					Type:        "TODO",
-					Text:        "//TODO(github.com/foo/bar/issues/1) Add some useful code here.",
-					Label:       "github.com/foo/bar/issues/1",
-					Message:     "Add some useful code here.",
-					Line:        1,
-					CommentLine: 1,
+					Type:           "TODO",
+					Text:           "//TODO(github.com/foo/bar/issues/1) Add some useful code here.",
+					Label:          "github.com/foo/bar/issues/1",
+					CanonicalLabel: "foo/bar#1",
+					ReferenceKind:  ReferenceIssue,
+					Message:        "Add some useful code here.",
+					Line:           1,
+					CommentLine:    1,
 				},
 			},
 		},
@@ -731,12 +952,14 @@ func TestTODOScanner(t *testing.T) {
 			},
 			expected: []*TODO{
 				{
-					Type:        "TODO",
-					Text:        "//TODO(github.com/foo/bar/issues/1)",
-					Label:       "github.com/foo/bar/issues/1",
-					Message:     "",
-					Line:        1,
-					CommentLine: 1,
+					Type:           "TODO",
+					Text:           "//TODO(github.com/foo/bar/issues/1)",
+					Label:          "github.com/foo/bar/issues/1",
+					CanonicalLabel: "foo/bar#1",
+					ReferenceKind:  ReferenceIssue,
+					Message:        "",
+					Line:           1,
+					CommentLine:    1,
 				},
 			},
 		},
@@ -922,3 +1145,323 @@ func TestTODOScanner(t *testing.T) {
 		})
 	}
 }
+
+func TestTODOScanner_NearMisses(t *testing.T) {
+	t.Parallel()
+
+	s := &testScanner{
+		comments: []*scanner.Comment{
+			{
+				Text: "// TODO: exact",
+				Line: 1,
+			},
+			{
+				Text: "// Todo: title case",
+				Line: 2,
+			},
+			{
+				Text: "// todo(alice): lower case",
+				Line: 3,
+			},
+			{
+				Text:      "/*\nTODO: exact\nfixme: lower case\n*/",
+				Line:      4,
+				Multiline: true,
+			},
+			{
+				Text: "// not a todo",
+				Line: 8,
+			},
+		},
+	}
+
+	ts := NewTODOScanner(s, &Config{
+		Types:      []string{"TODO", "FIXME"},
+		NearMisses: true,
+	})
+	var found []*TODO
+	for ts.Scan() {
+		found = append(found, ts.Next())
+	}
+	if err := ts.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantFound := []*TODO{
+		{
+			Type:        "TODO",
+			Text:        "// TODO: exact",
+			Message:     "exact",
+			Line:        1,
+			CommentLine: 1,
+		},
+		{
+			Type:        "TODO",
+			Text:        "TODO: exact",
+			Message:     "exact",
+			Line:        5,
+			CommentLine: 4,
+		},
+	}
+	if diff := cmp.Diff(wantFound, found); diff != "" {
+		t.Errorf("unexpected todos (-want +got):\n%s", diff)
+	}
+
+	wantNearMisses := []*TODO{
+		{
+			Type:        "Todo",
+			Text:        "// Todo: title case",
+			Message:     "title case",
+			Line:        2,
+			CommentLine: 2,
+		},
+		{
+			Type:        "todo",
+			Text:        "// todo(alice): lower case",
+			Label:       "alice",
+			Message:     "lower case",
+			Line:        3,
+			CommentLine: 3,
+		},
+		{
+			Type:        "fixme",
+			Text:        "fixme: lower case",
+			Message:     "lower case",
+			Line:        6,
+			CommentLine: 4,
+		},
+	}
+	if diff := cmp.Diff(wantNearMisses, ts.NearMisses()); diff != "" {
+		t.Errorf("unexpected near misses (-want +got):\n%s", diff)
+	}
+}
+
+func TestTODOScanner_RequireDelimiter(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		text     string
+		expected bool
+	}{
+		"colon": {
+			text:     "// TODO: foo",
+			expected: true,
+		},
+		"colon no space": {
+			text:     "//TODO:foo",
+			expected: true,
+		},
+		"label colon": {
+			text:     "// TODO(alice): foo",
+			expected: true,
+		},
+		"naked": {
+			text: "// TODO",
+		},
+		"prose": {
+			text: "// TODO list of things",
+		},
+		"label": {
+			text: "// TODO(alice) foo",
+		},
+		"dash": {
+			text: "// TODO - foo",
+		},
+		"region": {
+			text:     "// TODO-BEGIN(alice): foo",
+			expected: true,
+		},
+		"multiline": {
+			text:     "/*\n * TODO: foo\n */",
+			expected: true,
+		},
+		"multiline prose": {
+			text: "/*\n * TODO\n */",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewTODOScanner(&testScanner{
+				comments: []*scanner.Comment{
+					{
+						Text:      tc.text,
+						Line:      1,
+						Multiline: strings.HasPrefix(tc.text, "/*"),
+					},
+				},
+			}, &Config{
+				Types:            []string{"TODO"},
+				RequireDelimiter: true,
+			})
+
+			var found []*TODO
+			for s.Scan() {
+				found = append(found, s.Next())
+			}
+			if err := s.Err(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got, want := len(found) > 0, tc.expected; got != want {
+				t.Errorf("unexpected match, got: %v, want: %v", got, want)
+			}
+		})
+	}
+}
+
+func TestTODOScanner_MatchAnywhere(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		text      string
+		multiline bool
+		anywhere  bool
+		expected  []*TODO
+	}{
+		"leading text": {
+			text: "// package comment, TODO",
+		},
+		"leading text anywhere": {
+			text:     "// package comment, TODO",
+			anywhere: true,
+			expected: []*TODO{
+				{
+					Type:        "TODO",
+					Text:        "// package comment, TODO",
+					Line:        1,
+					CommentLine: 1,
+				},
+			},
+		},
+		"message anywhere": {
+			text:     "// frobs the foo. TODO(alice): frob bars",
+			anywhere: true,
+			expected: []*TODO{
+				{
+					Type:        "TODO",
+					Text:        "// frobs the foo. TODO(alice): frob bars",
+					Label:       "alice",
+					Message:     "frob bars",
+					Line:        1,
+					CommentLine: 1,
+				},
+			},
+		},
+		"first TODO anywhere": {
+			text:     "// see TODO: foo, TODO: bar",
+			anywhere: true,
+			expected: []*TODO{
+				{
+					Type:        "TODO",
+					Text:        "// see TODO: foo, TODO: bar",
+					Message:     "foo, TODO: bar",
+					Line:        1,
+					CommentLine: 1,
+				},
+			},
+		},
+		"prose anywhere": {
+			text:     "// update the TODO list",
+			anywhere: true,
+		},
+		"word anywhere": {
+			text:     "// see xTODO: foo",
+			anywhere: true,
+		},
+		"multiline anywhere": {
+			text:      "/*\n * frobs the foo. TODO: frob bars\n */",
+			multiline: true,
+			anywhere:  true,
+			expected: []*TODO{
+				{
+					Type:        "TODO",
+					Text:        "* frobs the foo. TODO: frob bars",
+					Message:     "frob bars",
+					Line:        2,
+					CommentLine: 1,
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewTODOScanner(&testScanner{
+				comments: []*scanner.Comment{
+					{
+						Text:      tc.text,
+						Line:      1,
+						Multiline: tc.multiline,
+					},
+				},
+			}, &Config{
+				Types:         []string{"TODO"},
+				MatchAnywhere: tc.anywhere,
+			})
+
+			var found []*TODO
+			for s.Scan() {
+				found = append(found, s.Next())
+			}
+			if err := s.Err(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, found); diff != "" {
+				t.Errorf("unexpected todos (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseAttributes(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		label    string
+		expected map[string]string
+	}{
+		"empty": {
+			label: "",
+		},
+		"no attributes": {
+			label: "github.com/foo/bar/issues/1",
+		},
+		"attributes": {
+			label: "assignee=alice, due=2025-01-01",
+			expected: map[string]string{
+				"assignee": "alice",
+				"due":      "2025-01-01",
+			},
+		},
+		"mixed": {
+			label: "alice, priority = p1",
+			expected: map[string]string{
+				"priority": "p1",
+			},
+		},
+		"empty value": {
+			label: "priority=",
+			expected: map[string]string{
+				"priority": "",
+			},
+		},
+		"invalid key": {
+			label: "https://example.com/?a=b",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := ParseAttributes(tc.label)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected attributes (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
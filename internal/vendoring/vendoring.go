@@ -32,11 +32,29 @@ var vendorRaw []byte
 // path is a vendored directory.
 var VendorMatcher *regexp.Regexp
 
+// vendorPatterns are the individual vendoring patterns in the order they
+// appear in vendor.yml.
+var vendorPatterns []*regexp.Regexp
+
 // IsVendor returns if the path is in a vendored directory.
 func IsVendor(path string) bool {
 	return VendorMatcher.MatchString(path)
 }
 
+// Match returns the vendoring pattern that matches the path or an empty
+// string if the path is not in a vendored directory.
+func Match(path string) string {
+	if !IsVendor(path) {
+		return ""
+	}
+	for _, re := range vendorPatterns {
+		if re.MatchString(path) {
+			return re.String()
+		}
+	}
+	return ""
+}
+
 //nolint:gochecknoinits // init needed to load embedded config.
 func init() {
 	// TODO(#1545): Generate Go code rather than loading YAML at runtime.
@@ -48,6 +66,8 @@ func init() {
 	var noPrefix []string
 
 	for _, s := range rawRegex {
+		vendorPatterns = append(vendorPatterns, utils.Must(regexp.Compile(s)))
+
 		switch {
 		case strings.HasPrefix(s, "^"):
 			startPrefix = append(startPrefix, `(?:`+strings.TrimPrefix(s, "^")+`)`)
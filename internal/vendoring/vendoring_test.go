@@ -63,3 +63,39 @@ func TestIsVendor(t *testing.T) {
 		})
 	}
 }
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{
+			name:     "normal_file",
+			path:     "internal/file.go",
+			expected: "",
+		},
+		{
+			name:     "vendor_dir_exact",
+			path:     "vendor/",
+			expected: "(^|/)vendors?/",
+		},
+		{
+			name:     "node_modules",
+			path:     "somepackage/node_modules/someotherpackage/somefile.js",
+			expected: "(^|/)node_modules/",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := Match(tc.path), tc.expected; got != want {
+				t.Errorf("Match(%q); got: %q, want: %q", tc.path, got, want)
+			}
+		})
+	}
+}
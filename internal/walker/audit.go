@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var errAudit = errors.New("skipped files contain TODOs")
+
+// auditRules are the rules that exclude files that could otherwise be
+// scanned. Files skipped by other rules are not audited.
+var auditRules = []SkipRule{
+	SkipExclude,
+	SkipExcludeDir,
+	SkipTest,
+	SkipHidden,
+	SkipVendored,
+	SkipDocs,
+	SkipGenerated,
+}
+
+// audit warns if the skipped file or directory at fullPath contains files
+// that look like they contain TODOs.
+func (w *TODOWalker) audit(r *SkipReason, fullPath string, cfg *dirConfig) error {
+	if !w.options.Audit || !slices.Contains(auditRules, r.Rule) {
+		return nil
+	}
+
+	match := w.auditMatch(cfg)
+	n := 0
+	// NOTE: Auditing is best effort so errors are ignored.
+	_ = filepath.WalkDir(fullPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if isVCS(path) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err == nil && match.Match(b) {
+			n++
+		}
+		return nil
+	})
+	return w.reportAudit(r, n)
+}
+
+// auditContents warns if the contents of the skipped file look like they
+// contain TODOs.
+func (w *TODOWalker) auditContents(r *SkipReason, rawContents []byte, cfg *dirConfig) error {
+	if !w.options.Audit || !slices.Contains(auditRules, r.Rule) {
+		return nil
+	}
+	n := 0
+	if w.auditMatch(cfg).Match(rawContents) {
+		n++
+	}
+	return w.reportAudit(r, n)
+}
+
+// reportAudit passes a warning for the n files skipped for the reason to
+// WarningFunc.
+func (w *TODOWalker) reportAudit(r *SkipReason, n int) error {
+	if n == 0 {
+		return nil
+	}
+	return w.handleWarning(r.Path, fmt.Errorf("%w: %d files skipped by %s", errAudit, n, r))
+}
+
+// auditMatch returns a regular expression that quickly matches text that
+// may contain the TODO types in the config.
+func (w *TODOWalker) auditMatch(cfg *dirConfig) *regexp.Regexp {
+	key := strings.Join(cfg.todoConfig.Types, "\x00")
+	if m, ok := w.auditMatches[key]; ok {
+		return m
+	}
+
+	var quotedTypes []string
+	for _, tp := range cfg.todoConfig.Types {
+		quotedTypes = append(quotedTypes, regexp.QuoteMeta(tp))
+	}
+	m := regexp.MustCompile(`\b(?:` + strings.Join(quotedTypes, "|") + `)\b`)
+	w.auditMatches[key] = m
+	return m
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ianlewis/todos/internal/analysis"
+	"github.com/ianlewis/todos/internal/cache"
+	"github.com/ianlewis/todos/internal/scanner"
+	"github.com/ianlewis/todos/internal/todos"
+)
+
+// cacheVersion is the version of the cached scan result format. It should be
+// incremented whenever scanning produces different results for the same input.
+const cacheVersion = "12"
+
+var errCache = errors.New("cache")
+
+// cacheEntry is the cached scan result for a file.
+type cacheEntry struct {
+	// Language is the detected language of the file.
+	Language string `json:"language"`
+
+	// LanguageDetection describes how the language of the file was
+	// detected.
+	LanguageDetection *scanner.LanguageDetection `json:"language_detection,omitempty"`
+
+	// TODOs are all TODOs found in the file before filtering by label.
+	TODOs []*todos.TODO `json:"todos"`
+
+	// NearMisses are the TODOs whose type only differs from one of the TODO
+	// types by case. They are only found if near miss warnings are enabled.
+	NearMisses []*todos.TODO `json:"near_misses,omitempty"`
+
+	// Metrics are the file's comment metrics. They are only computed if
+	// metrics are enabled.
+	Metrics *analysis.Metrics `json:"metrics,omitempty"`
+
+	// Stats are the comment scanner's counters from when the file was
+	// scanned.
+	Stats scanner.Stats `json:"stats"`
+
+	// Replaced is the number of invalid bytes that were replaced when
+	// decoding the file.
+	Replaced int `json:"replaced,omitempty"`
+}
+
+// cacheKey returns the cache key for the file. The key includes the file's
+// base name since it is used for language detection, and the options that
+// affect the scan result.
+func (w *TODOWalker) cacheKey(fileName string, rawContents []byte, cfg *dirConfig) string {
+	// NOTE: The whole TODO config is included so that new options are part
+	// of the key without having to list them here. Marshalling the config
+	// can't fail since it only holds strings, bools, and slices of them.
+	todoConfig, _ := json.Marshal(cfg.todoConfig)
+	return cache.Key(
+		[]byte(cacheVersion),
+		[]byte(w.options.Charset),
+		todoConfig,
+		[]byte(strconv.FormatBool(w.options.SkipShebang)),
+		[]byte(strconv.Itoa(w.options.MaxLineLength)),
+		[]byte(strconv.FormatBool(w.options.SkipStrings)),
+		[]byte(strconv.FormatBool(w.options.Metrics)),
+		[]byte(cfg.languageOverride(fileName, rawContents)),
+		[]byte(strings.Join(cfg.textLanguages(), ",")),
+		[]byte(w.options.FallbackLanguage),
+		[]byte(strconv.FormatBool(w.extractor(fileName) != nil)),
+		[]byte(filepath.Base(fileName)),
+		rawContents,
+	)
+}
+
+// cacheGet returns the cached entry for the key or nil if it is not cached.
+func (w *TODOWalker) cacheGet(key string) (*cacheEntry, error) {
+	b, err := w.options.Cache.Get(key)
+	if err != nil {
+		if errors.Is(err, cache.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: %w", errCache, err)
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return nil, fmt.Errorf("%w: decoding entry %q: %w", errCache, key, err)
+	}
+	return &entry, nil
+}
+
+// cachePut stores the entry for the key.
+func (w *TODOWalker) cachePut(key string, entry *cacheEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("%w: encoding entry %q: %w", errCache, key, err)
+	}
+	if err := w.options.Cache.Put(key, b); err != nil {
+		return fmt.Errorf("%w: %w", errCache, err)
+	}
+	return nil
+}
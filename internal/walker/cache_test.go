@@ -0,0 +1,252 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gobwas/glob"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/cache"
+	"github.com/ianlewis/todos/internal/scanner"
+	"github.com/ianlewis/todos/internal/testutils"
+	"github.com/ianlewis/todos/internal/todos"
+)
+
+// memCache is an in-memory cache.Cache.
+type memCache struct {
+	values map[string][]byte
+	gets   int
+	puts   int
+}
+
+func (c *memCache) Get(key string) ([]byte, error) {
+	c.gets++
+	v, ok := c.values[key]
+	if !ok {
+		return nil, cache.ErrNotFound
+	}
+	return v, nil
+}
+
+func (c *memCache) Put(key string, value []byte) error {
+	c.puts++
+	c.values[key] = value
+	return nil
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Cache(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path: "code.go",
+			Contents: []byte(`package foo
+// TODO(foo): code
+// TODO(bar): bar
+`),
+			Mode: 0o600,
+		},
+	}
+
+	expected := []*TODORef{
+		{
+			FileName: "code.go",
+			TODO: &todos.TODO{
+				Type:        "TODO",
+				Text:        "// TODO(foo): code",
+				Label:       "foo",
+				Message:     "code",
+				Line:        2,
+				CommentLine: 2,
+			},
+		},
+	}
+
+	c := &memCache{
+		values: map[string][]byte{},
+	}
+
+	// NOTE: The second walk should use the cached result.
+	for i, wantPuts := range []int{1, 1} {
+		opts := &Options{
+			Config: &todos.Config{
+				Types: []string{"TODO"},
+			},
+			Charset:    "UTF-8",
+			LabelGlobs: []glob.Glob{glob.MustCompile("foo")},
+			Cache:      c,
+		}
+
+		f, w := newFixture(files, opts)
+
+		if got, want := w.Walk(), false; got != want {
+			t.Errorf("walk %d: unexpected error code, got: %v, want: %v\nw.err: %v", i, got, want, w.err)
+		}
+
+		if diff := cmp.Diff(expected, f.out, ignoreFingerprint, ignoreOffsets); diff != "" {
+			t.Errorf("walk %d: unexpected output (-want +got):\n%s", i, diff)
+		}
+
+		if got, want := c.puts, wantPuts; got != want {
+			t.Errorf("walk %d: unexpected # of puts, got: %v, want: %v", i, got, want)
+		}
+
+		f.cleanup()
+	}
+
+	if got, want := c.gets, 2; got != want {
+		t.Errorf("unexpected # of gets, got: %v, want: %v", got, want)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Cache_stats(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "code.go",
+			Contents: []byte("package foo\n// TODO: code\n/* TODO: block */\n"),
+			Mode:     0o600,
+		},
+	}
+
+	c, err := cache.NewDisk(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// NOTE: The second walk reads the result from the cache and should
+	// report the same stats as the first walk that scanned the file.
+	var stats []scanner.Stats
+	for i := range 2 {
+		opts := &Options{
+			Config: &todos.Config{
+				Types: []string{"TODO"},
+			},
+			Charset: "UTF-8",
+			Cache:   c,
+			FileResultFunc: func(r *FileResult) error {
+				stats = append(stats, r.Stats)
+				return nil
+			},
+		}
+
+		f, w := newFixture(files, opts)
+
+		if got, want := w.Walk(), false; got != want {
+			t.Errorf("walk %d: unexpected error code, got: %v, want: %v\nw.err: %v", i, got, want, w.err)
+		}
+
+		f.cleanup()
+	}
+
+	if len(stats) != 2 {
+		t.Fatalf("unexpected # of results, got: %v, want: %v", len(stats), 2)
+	}
+	if got, want := stats[0].Comments, 2; got != want {
+		t.Errorf("unexpected # of comments, got: %v, want: %v", got, want)
+	}
+	if diff := cmp.Diff(stats[0], stats[1]); diff != "" {
+		t.Errorf("unexpected cached stats (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Cache_note(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "code.go",
+			Contents: []byte("// TODO: f\xffoo\n"),
+			Mode:     0o600,
+		},
+	}
+
+	c, err := cache.NewDisk(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// NOTE: The second walk reads the result from the cache and should
+	// report the same notes as the first walk that scanned the file.
+	var notes []string
+	for i := range 2 {
+		opts := &Options{
+			Config: &todos.Config{
+				Types: []string{"TODO"},
+			},
+			Charset: "UTF-8",
+			Cache:   c,
+			NoteFunc: func(fileName, note string) error {
+				notes = append(notes, fileName+": "+note)
+				return nil
+			},
+		}
+
+		f, w := newFixture(files, opts)
+
+		if got, want := w.Walk(), false; got != want {
+			t.Errorf("walk %d: unexpected error code, got: %v, want: %v\nw.err: %v", i, got, want, w.err)
+		}
+
+		f.cleanup()
+	}
+
+	want := []string{
+		"code.go: replaced 1 invalid bytes",
+		"code.go: replaced 1 invalid bytes",
+	}
+	if diff := cmp.Diff(want, notes); diff != "" {
+		t.Errorf("unexpected notes (-want +got):\n%s", diff)
+	}
+}
+
+// TestTODOWalker_cacheKey_config checks that every field of todos.Config
+// changes the cache key so that options added later can't return stale
+// results.
+func TestTODOWalker_cacheKey_config(t *testing.T) {
+	t.Parallel()
+
+	w := New(&Options{})
+	key := func(c *todos.Config) string {
+		return w.cacheKey("main.go", []byte("// TODO: foo\n"), &dirConfig{todoConfig: c})
+	}
+	base := key(&todos.Config{})
+
+	typ := reflect.TypeOf(todos.Config{})
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		t.Run(field.Name, func(t *testing.T) {
+			t.Parallel()
+
+			var c todos.Config
+			v := reflect.ValueOf(&c).Elem().Field(i)
+			switch v.Kind() {
+			case reflect.Bool:
+				v.SetBool(true)
+			case reflect.String:
+				v.SetString("foo")
+			case reflect.Slice:
+				v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+			default:
+				t.Fatalf("unsupported field type %v", v.Type())
+			}
+
+			if key(&c) == base {
+				t.Errorf("setting %s does not change the cache key", field.Name)
+			}
+		})
+	}
+}
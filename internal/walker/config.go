@@ -0,0 +1,396 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/gobwas/glob"
+
+	"github.com/ianlewis/todos/internal/config"
+	"github.com/ianlewis/todos/internal/scanner"
+	"github.com/ianlewis/todos/internal/todos"
+)
+
+// detectBytes is the number of bytes at the start of a file that detect rules
+// are matched against.
+const detectBytes = 4096
+
+var (
+	errUnsupportedLanguage = errors.New("unsupported language")
+	errDetectRule          = errors.New("invalid detect rule")
+	errLanguagePolicy      = errors.New("invalid language policy")
+)
+
+// detectRule is a compiled config.DetectRule.
+type detectRule struct {
+	// extensions are the lower case extensions the rule applies to.
+	extensions []string
+
+	// pattern matches the start of the file contents.
+	pattern *regexp.Regexp
+
+	// language is the language of matching files.
+	language string
+}
+
+// match returns whether the rule matches the file.
+func (r *detectRule) match(fileName string, rawContents []byte) bool {
+	if len(r.extensions) > 0 && !slices.Contains(r.extensions, strings.ToLower(filepath.Ext(fileName))) {
+		return false
+	}
+	return r.pattern.Match(rawContents[:min(len(rawContents), detectBytes)])
+}
+
+// dirConfig is the effective configuration for a directory after merging
+// the Options with the configuration files in the directory and its parents.
+type dirConfig struct {
+	// todoConfig is the TODO scanner configuration.
+	todoConfig *todos.Config
+
+	// excludeGlobs matches excluded files.
+	excludeGlobs []glob.Glob
+
+	// excludeDirGlobs matches excluded dirs.
+	excludeDirGlobs []glob.Glob
+
+	// testGlobs matches test files.
+	testGlobs []glob.Glob
+
+	// testDirGlobs matches test dirs.
+	testDirGlobs []glob.Glob
+
+	// languagePolicies maps lower case language names to how files of the
+	// language are scanned.
+	languagePolicies map[string]config.LanguagePolicy
+
+	// extensionLanguages maps lower case file extensions to the language
+	// that files with the extension are scanned as.
+	extensionLanguages map[string]string
+
+	// detectRules detect the language of files from their contents.
+	detectRules []*detectRule
+}
+
+// languageOverride returns the language that the file should be scanned as
+// or an empty string if its language should be detected. Extension mappings
+// take precedence over detect rules.
+func (c *dirConfig) languageOverride(fileName string, rawContents []byte) string {
+	if lang, ok := c.extensionLanguages[strings.ToLower(filepath.Ext(fileName))]; ok {
+		return lang
+	}
+	for _, r := range c.detectRules {
+		if r.match(fileName, rawContents) {
+			return r.language
+		}
+	}
+	return ""
+}
+
+// languageDisabled returns whether scanning files of the language is
+// disabled.
+func (c *dirConfig) languageDisabled(lang string) bool {
+	return c.languagePolicies[strings.ToLower(lang)] == config.LanguageSkip
+}
+
+// languageText returns whether files of the language are scanned as plain
+// text.
+func (c *dirConfig) languageText(lang string) bool {
+	return c.languagePolicies[strings.ToLower(lang)] == config.LanguageText
+}
+
+// textLanguages returns the sorted lower case names of the languages that
+// are scanned as plain text.
+func (c *dirConfig) textLanguages() []string {
+	var langs []string
+	for lang, p := range c.languagePolicies {
+		if p == config.LanguageText {
+			langs = append(langs, lang)
+		}
+	}
+	slices.Sort(langs)
+	return langs
+}
+
+// scannerConfig returns a function that returns the scanner configuration
+// for files of a language, or nil if the language's own configuration is
+// used.
+func (w *TODOWalker) scannerConfig(cfg *dirConfig) func(lang string) *scanner.Config {
+	return func(lang string) *scanner.Config {
+		if cfg.languageText(lang) {
+			return scanner.PlainText
+		}
+		if _, ok := scanner.LanguagesConfig[lang]; ok {
+			return nil
+		}
+		if w.options.FallbackLanguage == "Text" {
+			return scanner.Text
+		}
+		return scanner.LanguagesConfig[w.options.FallbackLanguage]
+	}
+}
+
+// baseConfig returns the configuration given by the Options.
+func (w *TODOWalker) baseConfig() *dirConfig {
+	return &dirConfig{
+		todoConfig:      w.options.Config,
+		excludeGlobs:    w.options.ExcludeGlobs,
+		excludeDirGlobs: w.options.ExcludeDirGlobs,
+		testGlobs:       defaultTestGlobs,
+		testDirGlobs:    defaultTestDirGlobs,
+
+		extensionLanguages: w.options.ExtensionLanguages,
+	}
+}
+
+// dirConfig returns the configuration for the directory rel relative to the
+// walked root path. Configuration files are merged from the root down, much
+// like .gitignore files. If a configuration file cannot be loaded the
+// configuration of the parent directory is returned along with the error.
+func (w *TODOWalker) dirConfig(root, rel string) (*dirConfig, error) {
+	dir := filepath.Join(root, rel)
+	if c, ok := w.configs[dir]; ok {
+		return c, nil
+	}
+
+	parent := w.baseConfig()
+	if rel != "." {
+		var err error
+		parent, err = w.dirConfig(root, filepath.Dir(rel))
+		if err != nil {
+			return parent, err
+		}
+	}
+
+	c, err := w.loadConfig(dir)
+	if err != nil {
+		// NOTE: Cache the parent config so the error is only reported once.
+		w.configs[dir] = parent
+		return parent, err
+	}
+
+	configDir := filepath.ToSlash(rel)
+	if configDir == "." {
+		configDir = ""
+	}
+	merged, err := mergeConfig(parent, c, configDir)
+	if err != nil {
+		w.configs[dir] = parent
+		return parent, err
+	}
+	w.configs[dir] = merged
+
+	if c != nil && w.options.ConfigFunc != nil {
+		if err := w.options.ConfigFunc(filepath.Join(dir, config.FileName)); err != nil {
+			return merged, err
+		}
+	}
+	return merged, nil
+}
+
+// loadConfig loads the configuration file in the directory dir.
+func (w *TODOWalker) loadConfig(dir string) (*config.Config, error) {
+	if w.ref != nil {
+		return w.ref.loadConfig(dir)
+	}
+	ok, err := w.confineConfig(dir)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return config.Load(dir)
+}
+
+// confineConfig returns whether the configuration file in the walked
+// directory dir may be loaded. If Confine is enabled, configuration files
+// that resolve outside of the walked Paths are reported to WarningFunc and
+// ignored.
+func (w *TODOWalker) confineConfig(dir string) (bool, error) {
+	if !w.options.Confine {
+		return true, nil
+	}
+	p := filepath.Join(dir, config.FileName)
+	fullPath, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		// NOTE: Missing configuration files are handled by config.Load.
+		return true, nil
+	}
+	// NOTE: Directories above the walked Paths, such as the directory of
+	// an explicitly specified file, are not confined.
+	dirPath, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return true, nil
+	}
+	ok, err := w.confined(dirPath)
+	if err != nil || !ok {
+		return true, err
+	}
+	ok, err = w.confined(fullPath)
+	if err != nil || ok {
+		return ok, err
+	}
+	return false, w.handleWarning(p, fmt.Errorf("%w: %s", errOutsideRoot, fullPath))
+}
+
+// mergeConfig returns the configuration c merged onto the parent
+// configuration. dir is the slash separated directory of the configuration
+// file relative to the walked path.
+func mergeConfig(parent *dirConfig, c *config.Config, dir string) (*dirConfig, error) {
+	if c == nil {
+		return parent, nil
+	}
+
+	var types []string
+	if parent.todoConfig != nil {
+		types = parent.todoConfig.Types
+	}
+	if len(c.Types) > 0 {
+		types = c.Types
+	}
+	types = append(append([]string{}, types...), c.AddTypes...)
+
+	// NOTE: Other TODO scanner options are inherited from the parent.
+	var todoConfig todos.Config
+	if parent.todoConfig != nil {
+		todoConfig = *parent.todoConfig
+	}
+	todoConfig.Types = types
+	if c.NearMissWarnings != nil {
+		todoConfig.NearMisses = *c.NearMissWarnings
+	}
+
+	merged := &dirConfig{
+		todoConfig:      &todoConfig,
+		excludeGlobs:    append([]glob.Glob{}, parent.excludeGlobs...),
+		excludeDirGlobs: append([]glob.Glob{}, parent.excludeDirGlobs...),
+		testGlobs:       append([]glob.Glob{}, parent.testGlobs...),
+		testDirGlobs:    append([]glob.Glob{}, parent.testDirGlobs...),
+	}
+
+	if len(c.Languages) > 0 {
+		merged.languagePolicies = map[string]config.LanguagePolicy{}
+		for lang, p := range parent.languagePolicies {
+			merged.languagePolicies[lang] = p
+		}
+		for lang, p := range c.Languages {
+			switch p {
+			case config.LanguageScan, config.LanguageSkip, config.LanguageText:
+			default:
+				return nil, fmt.Errorf("%w: language %q: %q", errLanguagePolicy, lang, p)
+			}
+			merged.languagePolicies[strings.ToLower(lang)] = p
+		}
+	} else {
+		merged.languagePolicies = parent.languagePolicies
+	}
+
+	merged.extensionLanguages = parent.extensionLanguages
+	if len(c.Extensions) > 0 {
+		merged.extensionLanguages = map[string]string{}
+		for ext, lang := range parent.extensionLanguages {
+			merged.extensionLanguages[ext] = lang
+		}
+		for ext, lang := range c.Extensions {
+			if _, ok := scanner.LanguagesConfig[lang]; !ok {
+				return nil, fmt.Errorf("%w: extension %q: %q", errUnsupportedLanguage, ext, lang)
+			}
+			merged.extensionLanguages[normalizeExt(ext)] = lang
+		}
+	}
+
+	// NOTE: Rules in subdirectories are evaluated first.
+	merged.detectRules = parent.detectRules
+	if len(c.Detect) > 0 {
+		var rules []*detectRule
+		for _, d := range c.Detect {
+			r, err := compileDetectRule(d)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, r)
+		}
+		merged.detectRules = append(rules, parent.detectRules...)
+	}
+
+	for _, p := range c.Exclude {
+		g, err := compileGlob(p, dir)
+		if err != nil {
+			return nil, err
+		}
+		merged.excludeGlobs = append(merged.excludeGlobs, g)
+	}
+
+	for _, p := range c.ExcludeDir {
+		g, err := compileGlob(trimDirPattern(p), dir)
+		if err != nil {
+			return nil, err
+		}
+		merged.excludeDirGlobs = append(merged.excludeDirGlobs, g)
+	}
+
+	for _, p := range c.Tests {
+		g, err := compileGlob(p, dir)
+		if err != nil {
+			return nil, err
+		}
+		merged.testGlobs = append(merged.testGlobs, g)
+	}
+
+	for _, p := range c.TestDirs {
+		g, err := compileGlob(trimDirPattern(p), dir)
+		if err != nil {
+			return nil, err
+		}
+		merged.testDirGlobs = append(merged.testDirGlobs, g)
+	}
+
+	return merged, nil
+}
+
+// compileDetectRule compiles the detect rule from a configuration file.
+func compileDetectRule(d config.DetectRule) (*detectRule, error) {
+	if _, ok := scanner.LanguagesConfig[d.Language]; !ok {
+		return nil, fmt.Errorf("%w: detect rule %q: %q", errUnsupportedLanguage, d.Pattern, d.Language)
+	}
+	if d.Pattern == "" {
+		return nil, fmt.Errorf("%w: pattern is required", errDetectRule)
+	}
+	re, err := regexp.Compile(d.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errDetectRule, err)
+	}
+
+	r := &detectRule{
+		pattern:  re,
+		language: d.Language,
+	}
+	for _, ext := range d.Extensions {
+		r.extensions = append(r.extensions, normalizeExt(ext))
+	}
+	return r, nil
+}
+
+// normalizeExt returns the lower case extension with a leading dot.
+func normalizeExt(ext string) string {
+	ext = strings.ToLower(ext)
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
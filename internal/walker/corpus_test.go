@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ianlewis/todos/internal/todos"
+)
+
+// corpusEnv is the environment variable that gives the directory containing
+// the repositories used by BenchmarkTODOWalker_Corpus. See the
+// corpus-benchmark make target.
+const corpusEnv = "TODOS_BENCH_CORPUS"
+
+// BenchmarkTODOWalker_Corpus measures end-to-end scan throughput and memory
+// for each repository in the corpus directory.
+func BenchmarkTODOWalker_Corpus(b *testing.B) {
+	dir := os.Getenv(corpusEnv)
+	if dir == "" {
+		b.Skipf("%s is not set", corpusEnv)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		b.Fatalf("reading corpus: %v", err)
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+
+		b.Run(e.Name(), func(b *testing.B) {
+			// NOTE: Sizes are counted in a first walk so that throughput is
+			// reported for the scanned files only.
+			var size, files int64
+			corpusWalk(path, func(r *FileResult) error {
+				size += r.Size
+				files++
+				return nil
+			})
+
+			b.SetBytes(size)
+			b.ReportAllocs()
+			b.ResetTimer()
+			var found int
+			for range b.N {
+				found = corpusWalk(path, nil)
+			}
+			b.ReportMetric(float64(files), "files/op")
+			b.ReportMetric(float64(found), "todos/op")
+		})
+	}
+}
+
+// corpusWalk walks the path with the default options and returns the number
+// of TODOs found.
+func corpusWalk(path string, resultFunc FileResultHandler) int {
+	var found int
+	w := New(&Options{
+		Config: &todos.Config{
+			Types: todos.DefaultTypes,
+		},
+		Charset: "UTF-8",
+		Paths:   []string{path},
+		TODOFunc: func(*TODORef) error {
+			found++
+			return nil
+		},
+		FileResultFunc: resultFunc,
+	})
+	w.Walk()
+	return found
+}
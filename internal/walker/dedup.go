@@ -0,0 +1,48 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"io/fs"
+)
+
+// fileID identifies a physical file.
+type fileID struct {
+	dev uint64
+	ino uint64
+}
+
+// duplicateSkipReason returns the reason that the path should be skipped
+// because it is the same physical file as a path that was already walked or
+// nil if it wasn't walked yet. The path is recorded as walked.
+func (w *TODOWalker) duplicateSkipReason(path, fullPath string, info fs.FileInfo) *SkipReason {
+	if !w.options.Dedup {
+		return nil
+	}
+
+	id, ok := physicalID(fullPath, info)
+	if !ok {
+		return nil
+	}
+
+	if _, ok := w.seen[id]; ok {
+		return &SkipReason{
+			Rule: SkipDuplicate,
+			Path: path,
+		}
+	}
+	w.seen[id] = struct{}{}
+	return nil
+}
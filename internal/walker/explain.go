@@ -0,0 +1,595 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-enry/go-enry/v2"
+
+	"github.com/ianlewis/todos/internal/scanner"
+	"github.com/ianlewis/todos/internal/vendoring"
+)
+
+// SkipRule is a rule that causes the walker to skip a path.
+type SkipRule string
+
+const (
+	// SkipExclude is used for files that match one of the ExcludeGlobs.
+	SkipExclude SkipRule = "exclude"
+
+	// SkipExcludeDir is used for directories that match one of the
+	// ExcludeDirGlobs.
+	SkipExcludeDir SkipRule = "exclude-dir"
+
+	// SkipTest is used for test files and directories when SkipTests is
+	// enabled.
+	SkipTest SkipRule = "test"
+
+	// SkipSize is used for files that are smaller than MinSize or larger than
+	// MaxSize.
+	SkipSize SkipRule = "size"
+
+	// SkipModified is used for files that were last modified before
+	// ModifiedSince.
+	SkipModified SkipRule = "modified"
+
+	// SkipHidden is used for hidden files and directories.
+	SkipHidden SkipRule = "hidden"
+
+	// SkipVCS is used for version control directories.
+	SkipVCS SkipRule = "vcs"
+
+	// SkipVendored is used for vendored directories.
+	SkipVendored SkipRule = "vendored"
+
+	// SkipDocs is used for documentation files and directories.
+	SkipDocs SkipRule = "docs"
+
+	// SkipGenerated is used for generated files.
+	SkipGenerated SkipRule = "generated"
+
+	// SkipShard is used for files that are assigned to a different shard.
+	SkipShard SkipRule = "shard"
+
+	// SkipIrregular is used for files that are not regular files such as
+	// named pipes, sockets, and devices.
+	SkipIrregular SkipRule = "irregular"
+
+	// SkipDuplicate is used for files and directories that are the same
+	// physical file as a path that was already walked, such as hard links and
+	// bind mounts.
+	SkipDuplicate SkipRule = "duplicate"
+
+	// SkipUnstable is used for files that were modified while they were being
+	// read.
+	SkipUnstable SkipRule = "unstable"
+
+	// SkipLanguage is used for files whose detected language is disabled in
+	// configuration files.
+	SkipLanguage SkipRule = "language"
+
+	// SkipNotInPaths is used for paths that are not under any of the walked
+	// Paths.
+	SkipNotInPaths SkipRule = "not-in-paths"
+
+	// SkipSymlink is used for symbolic links that are not followed because of
+	// FollowSymlinks. The pattern is the target of the link.
+	SkipSymlink SkipRule = "symlink"
+
+	// SkipOutsideRoot is used for paths that resolve to a location outside of
+	// all of the walked Paths when Confine is enabled. The pattern is the
+	// resolved path.
+	SkipOutsideRoot SkipRule = "outside-root"
+)
+
+// SkipReason describes why the walker skips a path.
+type SkipReason struct {
+	// Rule is the rule that caused the path to be skipped.
+	Rule SkipRule
+
+	// Path is the path that matched the rule. This may be a parent directory
+	// of the path being explained.
+	Path string
+
+	// Pattern is the glob or regular expression that matched the path, if
+	// any.
+	Pattern string
+}
+
+// String returns a human readable description of the reason.
+func (r *SkipReason) String() string {
+	s := string(r.Rule)
+	if r.Pattern != "" {
+		s += fmt.Sprintf(" pattern %q", r.Pattern)
+	}
+	return s + fmt.Sprintf(" matched %q", r.Path)
+}
+
+// Explain returns the reason that the walker would skip the given path or nil
+// if the path would be scanned.
+func (w *TODOWalker) Explain(path string) (*SkipReason, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("getting absolute path: %w", err)
+	}
+
+	for _, root := range w.options.Paths {
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			return nil, fmt.Errorf("getting absolute path: %w", err)
+		}
+
+		rel, err := filepath.Rel(absRoot, absPath)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+
+		return w.explain(root, rel)
+	}
+
+	return &SkipReason{
+		Rule: SkipNotInPaths,
+		Path: path,
+	}, nil
+}
+
+// explain returns the skip reason for the path rel relative to the walked
+// root path.
+func (w *TODOWalker) explain(root, rel string) (*SkipReason, error) {
+	// NOTE: Paths that were explicitly specified are always scanned unless
+	// they belong to another shard.
+	if rel == "." {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, fmt.Errorf("stat %q: %w", root, err)
+		}
+		if r := irregularSkipReason(root, info.Mode()); r != nil {
+			return r, nil
+		}
+		return w.shardSkipReason(root), nil
+	}
+
+	// Check the parent directories from the root down.
+	parts := strings.Split(rel, string(filepath.Separator))
+	for i := 1; i < len(parts); i++ {
+		dir := filepath.Join(parts[:i]...)
+		fullPath, err := filepath.EvalSymlinks(filepath.Join(root, dir))
+		if err != nil {
+			return nil, fmt.Errorf("evaluating symlinks: %w", err)
+		}
+		cfg, err := w.dirConfig(root, filepath.Dir(dir))
+		if err != nil {
+			return nil, err
+		}
+		if r, err := w.dirSkipReason(dir, fullPath, cfg); r != nil || err != nil {
+			return r, err
+		}
+	}
+
+	fullPath, err := filepath.EvalSymlinks(filepath.Join(root, rel))
+	if err != nil {
+		return nil, fmt.Errorf("evaluating symlinks: %w", err)
+	}
+
+	linkInfo, err := os.Lstat(filepath.Join(root, rel))
+	if err != nil {
+		return nil, fmt.Errorf("stat %q: %w", rel, err)
+	}
+	if linkInfo.Mode()&fs.ModeSymlink != 0 {
+		if r, err := w.symlinkSkipReason(root, rel, fullPath); r != nil || err != nil {
+			return r, err
+		}
+	}
+
+	if r, err := w.confineSkipReason(rel, fullPath); r != nil || err != nil {
+		return r, err
+	}
+
+	cfg, err := w.dirConfig(root, filepath.Dir(rel))
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("stat %q: %w", fullPath, err)
+	}
+	if info.IsDir() {
+		return w.dirSkipReason(rel, fullPath, cfg)
+	}
+
+	if r := irregularSkipReason(rel, info.Mode()); r != nil {
+		return r, nil
+	}
+
+	if r := w.sizeSkipReason(rel, info.Size()); r != nil {
+		return r, nil
+	}
+
+	if r := w.modifiedSkipReason(rel, info.ModTime()); r != nil {
+		return r, nil
+	}
+
+	if r, err := w.fileSkipReason(rel, fullPath, cfg); r != nil || err != nil {
+		return r, err
+	}
+
+	if r := w.shardSkipReason(fullPath); r != nil {
+		return r, nil
+	}
+
+	rawContents, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", fullPath, err)
+	}
+	if r := w.contentSkipReason(fullPath, rawContents); r != nil {
+		return r, nil
+	}
+
+	s, err := scanner.FromBytesWith(fullPath, rawContents, w.options.Charset, cfg.languageOverride(fullPath, rawContents), w.scannerConfig(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("scanning %s: %w", fullPath, err)
+	}
+	if s == nil {
+		return nil, nil
+	}
+	return languageSkipReason(fullPath, s.Language(), cfg), nil
+}
+
+// dirSkipReason returns the reason that the directory should be skipped or
+// nil if it should be walked.
+func (w *TODOWalker) dirSkipReason(path, fullPath string, cfg *dirConfig) (*SkipReason, error) {
+	// Exclude directories that match one of the given glob patterns.
+	for _, g := range cfg.excludeDirGlobs {
+		if w.matchGlob(g, path, fullPath) {
+			return &SkipReason{
+				Rule:    SkipExcludeDir,
+				Path:    path,
+				Pattern: globPattern(g),
+			}, nil
+		}
+	}
+
+	if w.options.SkipTests {
+		for _, g := range cfg.testDirGlobs {
+			if w.matchGlob(g, path, fullPath) {
+				return &SkipReason{
+					Rule:    SkipTest,
+					Path:    path,
+					Pattern: globPattern(g),
+				}, nil
+			}
+		}
+	}
+
+	hdn, err := w.isHidden(fullPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if hdn && !w.options.IncludeHidden {
+		return &SkipReason{
+			Rule: SkipHidden,
+			Path: path,
+		}, nil
+	}
+
+	if !w.options.IncludeVCS && isVCS(fullPath) {
+		return &SkipReason{
+			Rule: SkipVCS,
+			Path: path,
+		}, nil
+	}
+
+	// NOTE: linguist regexs only matches paths with a *nix path separators.
+	basePath := strings.ReplaceAll(filepath.Base(fullPath), string(os.PathSeparator), "/")
+
+	// NOTE: linguist regexs only matches paths with a path separator at the end.
+	if !strings.HasSuffix(basePath, "/") {
+		basePath += "/"
+	}
+
+	if !w.options.IncludeVendored && vendoring.IsVendor(basePath) {
+		return &SkipReason{
+			Rule:    SkipVendored,
+			Path:    path,
+			Pattern: vendoring.Match(basePath),
+		}, nil
+	}
+
+	// NOTE: linguist documentation regexs match paths relative to the
+	// repository root with a path separator at the end.
+	if !w.options.IncludeDocs && enry.IsDocumentation(filepath.ToSlash(path)+"/") {
+		return &SkipReason{
+			Rule: SkipDocs,
+			Path: path,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// fileSkipReason returns the reason that the file should be skipped based on
+// its path or nil if it should be scanned.
+func (w *TODOWalker) fileSkipReason(path, fullPath string, cfg *dirConfig) (*SkipReason, error) {
+	// Exclude files that match one of the given glob patterns.
+	for _, g := range cfg.excludeGlobs {
+		if w.matchGlob(g, path, fullPath) {
+			return &SkipReason{
+				Rule:    SkipExclude,
+				Path:    path,
+				Pattern: globPattern(g),
+			}, nil
+		}
+	}
+
+	if w.options.SkipTests {
+		for _, g := range cfg.testGlobs {
+			if w.matchGlob(g, path, fullPath) {
+				return &SkipReason{
+					Rule:    SkipTest,
+					Path:    path,
+					Pattern: globPattern(g),
+				}, nil
+			}
+		}
+	}
+
+	hdn, err := w.isHidden(fullPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if hdn && !w.options.IncludeHidden {
+		return &SkipReason{
+			Rule: SkipHidden,
+			Path: path,
+		}, nil
+	}
+
+	if !w.options.IncludeDocs && enry.IsDocumentation(filepath.ToSlash(path)) {
+		return &SkipReason{
+			Rule: SkipDocs,
+			Path: path,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// shardSkipReason returns the reason that the file should be skipped because
+// it belongs to another shard or nil if it belongs to the current shard.
+func (w *TODOWalker) shardSkipReason(path string) *SkipReason {
+	if w.options.ShardCount <= 1 {
+		return nil
+	}
+
+	// NOTE: Use slash separated paths so that files are assigned to the same
+	// shard on all platforms.
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(filepath.ToSlash(path)))
+	shard := int(h.Sum32() % uint32(w.options.ShardCount))
+	if shard == w.options.ShardIndex {
+		return nil
+	}
+
+	return &SkipReason{
+		Rule:    SkipShard,
+		Path:    path,
+		Pattern: fmt.Sprintf("%d/%d", shard+1, w.options.ShardCount),
+	}
+}
+
+// contentSkipReason returns the reason that the file should be skipped based
+// on its contents or nil if it should be scanned.
+func (w *TODOWalker) contentSkipReason(path string, rawContents []byte) *SkipReason {
+	if !w.options.IncludeGenerated && enry.IsGenerated(path, rawContents) {
+		return &SkipReason{
+			Rule: SkipGenerated,
+			Path: path,
+		}
+	}
+	return nil
+}
+
+// languageSkipReason returns the reason that the file should be skipped
+// because scanning its language is disabled or nil if it should be scanned.
+func languageSkipReason(path, lang string, cfg *dirConfig) *SkipReason {
+	if !cfg.languageDisabled(lang) {
+		return nil
+	}
+	return &SkipReason{
+		Rule:    SkipLanguage,
+		Path:    path,
+		Pattern: lang,
+	}
+}
+
+// unstableSkipReason returns the reason that the file should be skipped
+// because it was modified while it was being read or nil if it was stable.
+// before and after are the file's info before and after reading n bytes.
+func unstableSkipReason(path string, before, after fs.FileInfo, n int) *SkipReason {
+	// NOTE: Only regular files have a meaningful size and modification time.
+	if !before.Mode().IsRegular() {
+		return nil
+	}
+	if before.Size() == int64(n) && after.Size() == before.Size() && after.ModTime().Equal(before.ModTime()) {
+		return nil
+	}
+	return &SkipReason{
+		Rule: SkipUnstable,
+		Path: path,
+	}
+}
+
+// irregularSkipReason returns the reason that the path should be skipped
+// because it is not a regular file or directory or nil if it is. Irregular
+// files such as named pipes could block forever when read.
+func irregularSkipReason(path string, mode fs.FileMode) *SkipReason {
+	if mode.IsDir() || mode.IsRegular() {
+		return nil
+	}
+	return &SkipReason{
+		Rule: SkipIrregular,
+		Path: path,
+	}
+}
+
+// symlinkSkipReason returns the reason that the symbolic link at path, which
+// resolves to fullPath, should not be followed or nil if it should. Links
+// are only followed to targets inside root if FollowSymlinks is
+// SymlinksInTree.
+func (w *TODOWalker) symlinkSkipReason(root, path, fullPath string) (*SkipReason, error) {
+	skip := &SkipReason{
+		Rule:    SkipSymlink,
+		Path:    path,
+		Pattern: fullPath,
+	}
+	switch w.options.FollowSymlinks {
+	case SymlinksNone:
+		return skip, nil
+	case SymlinksInTree:
+		// NOTE: Paths are made absolute since links can have absolute
+		// targets inside the tree.
+		rootPath, err := resolvePath(root)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := withinPath(rootPath, fullPath)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return skip, nil
+		}
+	case SymlinksAll:
+	}
+	return nil, nil
+}
+
+// confineSkipReason returns the reason that path, which resolves to
+// fullPath, should be skipped because it lies outside of all of the walked
+// Paths or nil if it doesn't. It always returns nil if Confine is not enabled.
+func (w *TODOWalker) confineSkipReason(path, fullPath string) (*SkipReason, error) {
+	if !w.options.Confine {
+		return nil, nil
+	}
+	ok, err := w.confined(fullPath)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return nil, nil
+	}
+	targetPath, err := filepath.Abs(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("evaluating symlinks: %w", err)
+	}
+	return &SkipReason{
+		Rule:    SkipOutsideRoot,
+		Path:    path,
+		Pattern: targetPath,
+	}, nil
+}
+
+// confined returns whether fullPath lies inside one of the walked Paths after
+// their symbolic links are evaluated.
+func (w *TODOWalker) confined(fullPath string) (bool, error) {
+	if w.roots == nil {
+		w.roots = []string{}
+		for _, p := range w.options.Paths {
+			rootPath, err := resolvePath(p)
+			if err != nil {
+				return false, err
+			}
+			w.roots = append(w.roots, rootPath)
+		}
+	}
+	for _, rootPath := range w.roots {
+		ok, err := withinPath(rootPath, fullPath)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// resolvePath returns the absolute path of p with symbolic links evaluated.
+func resolvePath(p string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		return "", fmt.Errorf("evaluating symlinks: %w", err)
+	}
+	resolved, err = filepath.Abs(resolved)
+	if err != nil {
+		return "", fmt.Errorf("evaluating symlinks: %w", err)
+	}
+	return resolved, nil
+}
+
+// withinPath returns whether the resolved path fullPath is rootPath or is
+// inside it. rootPath must be absolute.
+func withinPath(rootPath, fullPath string) (bool, error) {
+	targetPath, err := filepath.Abs(fullPath)
+	if err != nil {
+		return false, fmt.Errorf("evaluating symlinks: %w", err)
+	}
+	rel, err := filepath.Rel(rootPath, targetPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false, nil
+	}
+	return true, nil
+}
+
+// sizeSkipReason returns the reason that the file should be skipped because
+// of its size or nil if its size is within the MinSize and MaxSize limits.
+func (w *TODOWalker) sizeSkipReason(path string, size int64) *SkipReason {
+	if size < w.options.MinSize {
+		return &SkipReason{
+			Rule:    SkipSize,
+			Path:    path,
+			Pattern: fmt.Sprintf("< %d", w.options.MinSize),
+		}
+	}
+	if w.options.MaxSize > 0 && size > w.options.MaxSize {
+		return &SkipReason{
+			Rule:    SkipSize,
+			Path:    path,
+			Pattern: fmt.Sprintf("> %d", w.options.MaxSize),
+		}
+	}
+	return nil
+}
+
+// modifiedSkipReason returns the reason that the file should be skipped
+// because it was last modified before ModifiedSince or nil if it was modified
+// since then.
+func (w *TODOWalker) modifiedSkipReason(path string, modTime time.Time) *SkipReason {
+	if w.options.ModifiedSince.IsZero() || !modTime.Before(w.options.ModifiedSince) {
+		return nil
+	}
+	return &SkipReason{
+		Rule:    SkipModified,
+		Path:    path,
+		Pattern: "< " + w.options.ModifiedSince.Format(time.RFC3339),
+	}
+}
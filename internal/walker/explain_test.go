@@ -0,0 +1,387 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gobwas/glob"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/testutils"
+	"github.com/ianlewis/todos/internal/todos"
+)
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Explain(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "code.go",
+			Contents: []byte("// TODO: code"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "code_test.go",
+			Contents: []byte("// TODO: test"),
+			Mode:     0o600,
+		},
+		{
+			Path:     ".hidden.go",
+			Contents: []byte("// TODO: hidden"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "vendor/lib.go",
+			Contents: []byte("// TODO: vendored"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "docs/doc.go",
+			Contents: []byte("// TODO: docs"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "testdata/data.go",
+			Contents: []byte("// TODO: testdata"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "package-lock.json",
+			Contents: []byte("{}"),
+			Mode:     0o600,
+		},
+		{
+			Path:     ".todos.yml",
+			Contents: []byte("languages: {Python: false}\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "script.py",
+			Contents: []byte("# TODO: python"),
+			Mode:     0o600,
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		path     string
+		paths    []string
+		expected *SkipReason
+	}{
+		{
+			name:     "not skipped",
+			path:     "code.go",
+			expected: nil,
+		},
+		{
+			name: "exclude",
+			path: "code_test.go",
+			expected: &SkipReason{
+				Rule:    SkipExclude,
+				Path:    "code_test.go",
+				Pattern: "*_test.go",
+			},
+		},
+		{
+			name: "exclude-dir",
+			path: "testdata/data.go",
+			expected: &SkipReason{
+				Rule:    SkipExcludeDir,
+				Path:    "testdata",
+				Pattern: "testdata",
+			},
+		},
+		{
+			name: "hidden",
+			path: ".hidden.go",
+			expected: &SkipReason{
+				Rule: SkipHidden,
+				Path: ".hidden.go",
+			},
+		},
+		{
+			name: "vendored",
+			path: "vendor/lib.go",
+			expected: &SkipReason{
+				Rule:    SkipVendored,
+				Path:    "vendor",
+				Pattern: "(^|/)vendors?/",
+			},
+		},
+		{
+			name: "docs",
+			path: "docs/doc.go",
+			expected: &SkipReason{
+				Rule: SkipDocs,
+				Path: "docs",
+			},
+		},
+		{
+			name: "generated",
+			path: "package-lock.json",
+			expected: &SkipReason{
+				Rule: SkipGenerated,
+				Path: "package-lock.json",
+			},
+		},
+		{
+			name: "language",
+			path: "script.py",
+			expected: &SkipReason{
+				Rule:    SkipLanguage,
+				Path:    "script.py",
+				Pattern: "Python",
+			},
+		},
+		{
+			name:     "specified",
+			path:     "vendor/lib.go",
+			paths:    []string{"vendor/lib.go"},
+			expected: nil,
+		},
+		{
+			name:  "not in paths",
+			path:  "code.go",
+			paths: []string{"docs"},
+			expected: &SkipReason{
+				Rule: SkipNotInPaths,
+				Path: "code.go",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := &Options{
+				Config: &todos.Config{
+					Types: []string{"TODO"},
+				},
+				Charset:         "UTF-8",
+				ExcludeGlobs:    []glob.Glob{testutils.Must(CompileGlob("*_test.go"))},
+				ExcludeDirGlobs: []glob.Glob{testutils.Must(CompileGlob("testdata"))},
+				Paths:           tc.paths,
+			}
+
+			f, w := newFixture(files, opts)
+			defer f.cleanup()
+
+			got, err := w.Explain(tc.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected reason (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type fakeFileInfo struct {
+	fs.FileInfo
+
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (fi *fakeFileInfo) Size() int64        { return fi.size }
+func (fi *fakeFileInfo) Mode() fs.FileMode  { return fi.mode }
+func (fi *fakeFileInfo) ModTime() time.Time { return fi.modTime }
+
+func TestUnstableSkipReason(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	later := now.Add(time.Second)
+
+	testCases := map[string]struct {
+		before   *fakeFileInfo
+		after    *fakeFileInfo
+		n        int
+		expected *SkipReason
+	}{
+		"stable": {
+			before: &fakeFileInfo{size: 10, modTime: now},
+			after:  &fakeFileInfo{size: 10, modTime: now},
+			n:      10,
+		},
+		"truncated": {
+			before: &fakeFileInfo{size: 10, modTime: now},
+			after:  &fakeFileInfo{size: 5, modTime: later},
+			n:      5,
+			expected: &SkipReason{
+				Rule: SkipUnstable,
+				Path: "foo.go",
+			},
+		},
+		"appended": {
+			before: &fakeFileInfo{size: 10, modTime: now},
+			after:  &fakeFileInfo{size: 20, modTime: later},
+			n:      20,
+			expected: &SkipReason{
+				Rule: SkipUnstable,
+				Path: "foo.go",
+			},
+		},
+		"modified": {
+			before: &fakeFileInfo{size: 10, modTime: now},
+			after:  &fakeFileInfo{size: 10, modTime: later},
+			n:      10,
+			expected: &SkipReason{
+				Rule: SkipUnstable,
+				Path: "foo.go",
+			},
+		},
+		"not regular": {
+			before: &fakeFileInfo{size: 0, mode: fs.ModeNamedPipe, modTime: now},
+			after:  &fakeFileInfo{size: 0, mode: fs.ModeNamedPipe, modTime: later},
+			n:      10,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := unstableSkipReason("foo.go", tc.before, tc.after, tc.n)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected reason (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Explain_globs(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "internal/fixtures/data.go",
+			Contents: []byte("// TODO: internal testdata"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "fixtures/data.go",
+			Contents: []byte("// TODO: testdata"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "Gen/Code.GO",
+			Contents: []byte("// TODO: generated"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/.todos.yml",
+			Contents: []byte("exclude_dir: [/gen]\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/gen/code.go",
+			Contents: []byte("// TODO: sub generated"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/other/gen/code.go",
+			Contents: []byte("// TODO: not generated"),
+			Mode:     0o600,
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		path            string
+		excludeDir      string
+		caseInsensitive bool
+		expected        *SkipReason
+	}{
+		{
+			name:       "path pattern",
+			path:       filepath.Join("internal", "fixtures", "data.go"),
+			excludeDir: "internal/fixtures/",
+			expected: &SkipReason{
+				Rule:    SkipExcludeDir,
+				Path:    filepath.Join("internal", "fixtures"),
+				Pattern: "internal/fixtures",
+			},
+		},
+		{
+			name:       "path pattern other dir",
+			path:       filepath.Join("fixtures", "data.go"),
+			excludeDir: "internal/fixtures/",
+			expected:   nil,
+		},
+		{
+			name:       "case sensitive",
+			path:       filepath.Join("Gen", "Code.GO"),
+			excludeDir: "gen",
+			expected:   nil,
+		},
+		{
+			name:            "case insensitive",
+			path:            filepath.Join("Gen", "Code.GO"),
+			excludeDir:      "gen",
+			caseInsensitive: true,
+			expected: &SkipReason{
+				Rule:    SkipExcludeDir,
+				Path:    "Gen",
+				Pattern: "gen",
+			},
+		},
+		{
+			name:       "config path pattern",
+			path:       filepath.Join("sub", "gen", "code.go"),
+			excludeDir: "none",
+			expected: &SkipReason{
+				Rule:    SkipExcludeDir,
+				Path:    filepath.Join("sub", "gen"),
+				Pattern: "/gen",
+			},
+		},
+		{
+			name:       "config path pattern other dir",
+			path:       filepath.Join("sub", "other", "gen", "code.go"),
+			excludeDir: "none",
+			expected:   nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := &Options{
+				Config: &todos.Config{
+					Types: []string{"TODO"},
+				},
+				Charset:              "UTF-8",
+				ExcludeDirGlobs:      []glob.Glob{testutils.Must(CompileDirGlob(tc.excludeDir))},
+				CaseInsensitiveGlobs: tc.caseInsensitive,
+			}
+
+			f, w := newFixture(files, opts)
+			defer f.cleanup()
+
+			got, err := w.Explain(tc.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected reason (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Fingerprint returns a stable identifier for a TODO. It is a hash of the
+// slash separated path, the TODO text with whitespace normalized, and the
+// ordinal of the TODO among the TODOs in the same file with the same
+// normalized text. It does not include the line number so that it is
+// unchanged when unrelated lines are added or removed.
+func Fingerprint(path, text string, ordinal int) string {
+	h := sha256.New()
+	_, _ = h.Write([]byte(filepath.ToSlash(filepath.Clean(path))))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(normalizeText(text)))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(strconv.Itoa(ordinal)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalizeText collapses whitespace in the text.
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"testing"
+
+	"github.com/ianlewis/todos/internal/testutils"
+	"github.com/ianlewis/todos/internal/todos"
+)
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	fp := Fingerprint("dir/foo.go", "// TODO: foo", 0)
+
+	testCases := map[string]struct {
+		path     string
+		text     string
+		ordinal  int
+		expected bool
+	}{
+		"same": {
+			path:     "dir/foo.go",
+			text:     "// TODO: foo",
+			expected: true,
+		},
+		"whitespace": {
+			path:     "dir/foo.go",
+			text:     "//  TODO:\tfoo ",
+			expected: true,
+		},
+		"unclean path": {
+			path:     "./dir/../dir/foo.go",
+			text:     "// TODO: foo",
+			expected: true,
+		},
+		"different path": {
+			path:     "dir/bar.go",
+			text:     "// TODO: foo",
+			expected: false,
+		},
+		"different text": {
+			path:     "dir/foo.go",
+			text:     "// TODO: bar",
+			expected: false,
+		},
+		"different ordinal": {
+			path:     "dir/foo.go",
+			text:     "// TODO: foo",
+			ordinal:  1,
+			expected: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := Fingerprint(tc.path, tc.text, tc.ordinal) == fp, tc.expected; got != want {
+				t.Errorf("Fingerprint(%q, %q, %d) == %q; got: %v, want: %v", tc.path, tc.text, tc.ordinal, fp, got, want)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Fingerprint(t *testing.T) {
+	newFiles := func(prefix string) []*testutils.File {
+		return []*testutils.File{
+			{
+				Path:     "foo.go",
+				Contents: []byte(prefix + "// TODO: foo\n// TODO: foo\n// TODO(bar): bar\n"),
+				Mode:     0o600,
+			},
+		}
+	}
+
+	walk := func(files []*testutils.File) []string {
+		opts := &Options{
+			Config: &todos.Config{
+				Types: []string{"TODO"},
+			},
+			Charset: "UTF-8",
+		}
+		f, w := newFixture(files, opts)
+		defer f.cleanup()
+
+		if w.Walk() {
+			t.Fatalf("unexpected error: %v", w.err)
+		}
+		var fps []string
+		for _, r := range f.out {
+			fps = append(fps, r.Fingerprint)
+		}
+		return fps
+	}
+
+	before := walk(newFiles(""))
+	// NOTE: Adding lines before the TODOs should not change the fingerprints.
+	after := walk(newFiles("package foo\n\n"))
+
+	if got, want := len(before), 3; got != want {
+		t.Fatalf("unexpected # of TODOs, got: %v, want: %v", got, want)
+	}
+	for i := range before {
+		if before[i] != after[i] {
+			t.Errorf("fingerprint %d changed, before: %q, after: %q", i, before[i], after[i])
+		}
+	}
+	if before[0] == before[1] {
+		t.Errorf("duplicate TODOs have the same fingerprint: %q", before[0])
+	}
+}
@@ -0,0 +1,323 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/ianlewis/todos/internal/config"
+)
+
+// refTree is the tree of the git ref that is being walked.
+type refTree struct {
+	// root is the root directory of the git repository.
+	root string
+
+	// commit is the commit that the ref resolves to.
+	commit *object.Commit
+
+	// tree is the root tree of the commit.
+	tree *object.Tree
+}
+
+// openRef resolves the git ref in the repository containing path.
+func openRef(path, ref string) (*refTree, error) {
+	root, err := repoRoot(path)
+	if err != nil {
+		return nil, err
+	}
+	if root == "" {
+		return nil, fmt.Errorf("%w: %q is not in a git repository", errGit, path)
+	}
+
+	r, err := git.PlainOpen(root)
+	if err != nil {
+		return nil, fmt.Errorf("%w: opening repository %q: %w", errGit, root, err)
+	}
+
+	hash, err := r.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, fmt.Errorf("%w: resolving ref %q: %w", errGit, ref, err)
+	}
+
+	c, err := r.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: getting commit object for hash %s: %w", errGit, hash, err)
+	}
+
+	t, err := c.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("%w: getting tree for commit %s: %w", errGit, hash, err)
+	}
+
+	return &refTree{
+		root:   root,
+		commit: c,
+		tree:   t,
+	}, nil
+}
+
+// relPath returns the slash separated path of p relative to the repository
+// root.
+func (rt *refTree) relPath(p string) (string, error) {
+	absPath, err := filepath.Abs(p)
+	if err != nil {
+		return "", fmt.Errorf("getting absolute path: %w", err)
+	}
+	rel, err := filepath.Rel(rt.root, absPath)
+	if err != nil {
+		return "", fmt.Errorf("getting relative path: %w", err)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// loadConfig loads the configuration file in the directory dir from the tree.
+func (rt *refTree) loadConfig(dir string) (*config.Config, error) {
+	rel, err := rt.relPath(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := rt.tree.File(path.Join(rel, config.FileName))
+	if err != nil {
+		if errors.Is(err, object.ErrFileNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: %w", errGit, err)
+	}
+
+	contents, err := f.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading %s: %w", errGit, f.Name, err)
+	}
+
+	c, err := config.Parse([]byte(contents))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dir, err)
+	}
+	return c, nil
+}
+
+// isHidden returns whether the path is hidden.
+func (w *TODOWalker) isHidden(fullPath string) (bool, error) {
+	// NOTE: Files read from a git ref may not exist in the working tree so
+	// only their name can be checked.
+	if w.ref != nil {
+		return strings.HasPrefix(filepath.Base(fullPath), "."), nil
+	}
+	return isHidden(fullPath)
+}
+
+// walkRef walks the path in the tree of the git ref given by Options.Ref
+// instead of the working tree.
+func (w *TODOWalker) walkRef(p string) error {
+	rt, err := openRef(p, w.options.Ref)
+	if err != nil {
+		return err
+	}
+	w.ref = rt
+	defer func() {
+		w.ref = nil
+	}()
+
+	rel, err := rt.relPath(p)
+	if err != nil {
+		return err
+	}
+
+	if rel == "." {
+		return w.walkTree(rt.tree, ".")
+	}
+
+	e, err := rt.tree.FindEntry(rel)
+	if err != nil {
+		return fmt.Errorf("%w: finding %q in ref %q: %w", errGit, rel, w.options.Ref, err)
+	}
+
+	if e.Mode == filemode.Dir {
+		t, err := rt.tree.Tree(rel)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errGit, err)
+		}
+		return w.walkTree(t, ".")
+	}
+
+	// Single file. Always scan this file since it was explicitly specified.
+	if w.resumeLast == "." {
+		return nil
+	}
+	cfg, err := w.dirConfig(filepath.Dir(p), ".")
+	if err != nil {
+		if herr := w.handleConfigErr(p, err); herr != nil {
+			return herr
+		}
+	}
+	if err := w.scanEntry(rt.tree, e, rel, p, cfg, true); err != nil {
+		return err
+	}
+	return w.checkpoint(".")
+}
+
+// walkTree walks the git tree. dir is the slash separated path of the tree
+// relative to the walked path.
+func (w *TODOWalker) walkTree(t *object.Tree, dir string) error {
+	// NOTE: Walk entries in the same order as fs.WalkDir so that walks can be
+	// resumed. Git sorts directory entries as if they had a trailing slash.
+	entries := append([]object.TreeEntry{}, t.Entries...)
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	for i := range entries {
+		e := &entries[i]
+		p := path.Join(dir, e.Name)
+		isDir := e.Mode == filemode.Dir
+		if w.resumeSkip(p, isDir) {
+			continue
+		}
+
+		fullPath := filepath.Join(w.path, filepath.FromSlash(p))
+		cfg, err := w.dirConfig(w.path, filepath.FromSlash(dir))
+		if err != nil {
+			if herr := w.handleConfigErr(p, err); herr != nil {
+				return herr
+			}
+		}
+
+		switch e.Mode {
+		case filemode.Dir:
+			r, err := w.dirSkipReason(p, fullPath, cfg)
+			if err != nil {
+				if herr := w.handleErr(p, err); herr != nil {
+					return herr
+				}
+				continue
+			}
+			if r != nil {
+				if err := w.skip(r); err != nil {
+					return err
+				}
+				continue
+			}
+
+			sub, err := t.Tree(e.Name)
+			if err != nil {
+				if herr := w.handleErr(p, fmt.Errorf("%w: %w", errGit, err)); herr != nil {
+					return herr
+				}
+				continue
+			}
+			if err := w.walkTree(sub, p); err != nil {
+				return err
+			}
+
+		case filemode.Regular, filemode.Executable, filemode.Deprecated:
+			r, err := w.fileSkipReason(p, fullPath, cfg)
+			if err != nil {
+				if herr := w.handleErr(p, err); herr != nil {
+					return herr
+				}
+				continue
+			}
+			if r != nil {
+				if err := w.skip(r); err != nil {
+					return err
+				}
+				continue
+			}
+
+			// NOTE: Blobs have no modification time so only their size is
+			// checked.
+			size, err := t.Size(e.Name)
+			if err != nil {
+				if herr := w.handleErr(p, fmt.Errorf("%w: %w", errGit, err)); herr != nil {
+					return herr
+				}
+				continue
+			}
+			if r := w.sizeSkipReason(p, size); r != nil {
+				if err := w.skip(r); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := w.scanEntry(t, e, p, fullPath, cfg, false); err != nil {
+				return err
+			}
+			if err := w.checkpoint(p); err != nil {
+				if herr := w.handleErr(p, err); herr != nil {
+					return herr
+				}
+			}
+
+		case filemode.Empty, filemode.Symlink, filemode.Submodule:
+			// NOTE: Symbolic links and submodules are not followed.
+			if err := w.skip(&SkipReason{
+				Rule: SkipIrregular,
+				Path: p,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// scanEntry scans the blob for the tree entry e. fileName is the name of the
+// file in the working tree.
+func (w *TODOWalker) scanEntry(
+	t *object.Tree,
+	e *object.TreeEntry,
+	p, fileName string,
+	cfg *dirConfig,
+	force bool,
+) error {
+	// NOTE: Sharding applies to all files, including those that were
+	// explicitly specified, so that each file is scanned by exactly one shard.
+	if r := w.shardSkipReason(fileName); r != nil {
+		return w.skip(r)
+	}
+
+	f, err := t.TreeEntryFile(e)
+	if err != nil {
+		return w.handleErr(p, fmt.Errorf("%w: %w", errGit, err))
+	}
+
+	rc, err := f.Reader()
+	if err != nil {
+		return w.handleErr(p, fmt.Errorf("%w: reading blob %s: %w", errGit, f.Hash, err))
+	}
+	defer rc.Close()
+
+	rawContents, err := io.ReadAll(rc)
+	if err != nil {
+		return w.handleErr(p, fmt.Errorf("%w: reading blob %s: %w", errGit, f.Hash, err))
+	}
+
+	return w.scanInput(fileName, rawContents, cfg, force)
+}
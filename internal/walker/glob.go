@@ -0,0 +1,137 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+// patternGlob is a glob.Glob that remembers the pattern it was compiled from.
+type patternGlob struct {
+	glob.Glob
+
+	// Pattern is the original glob pattern.
+	Pattern string
+
+	// Fold is the glob compiled from the lower case pattern. It is used to
+	// match lower case paths when CaseInsensitiveGlobs is enabled.
+	Fold glob.Glob
+
+	// Dir is the slash separated directory relative to the walked path that
+	// path patterns are relative to.
+	Dir string
+
+	// IsPath is whether the pattern contains a "/" and is matched against the
+	// path rather than the base name.
+	IsPath bool
+}
+
+// String implements fmt.Stringer.
+func (g *patternGlob) String() string {
+	return g.Pattern
+}
+
+// CompileGlob compiles the glob pattern. Unlike glob.Compile, the pattern is
+// remembered so that it can be reported by Explain.
+//
+// Patterns are matched the same way on all platforms. "/" is the path
+// separator and "\" escapes the character that follows it. Patterns without
+// a "/" match the base name of a path. Patterns with a "/" match the path
+// relative to the walked path, or to the directory of the configuration file
+// that they are given in, and "*" does not match a "/" in them.
+func CompileGlob(pattern string) (glob.Glob, error) {
+	return compileGlob(pattern, "")
+}
+
+// CompileDirGlob compiles the glob pattern for directories. Trailing "/" and
+// "\" characters are removed so that patterns such as "testdata/" match
+// directories the same way on all platforms.
+func CompileDirGlob(pattern string) (glob.Glob, error) {
+	return compileGlob(trimDirPattern(pattern), "")
+}
+
+// trimDirPattern removes trailing path separators from the directory pattern.
+func trimDirPattern(pattern string) string {
+	return strings.TrimRight(pattern, `/\`)
+}
+
+// compileGlob compiles the glob pattern. Path patterns are relative to the
+// slash separated directory dir.
+func compileGlob(pattern, dir string) (glob.Glob, error) {
+	p := pattern
+	var separators []rune
+	isPath := strings.Contains(p, "/")
+	if isPath {
+		// NOTE: A leading "/" anchors the pattern to dir, which path patterns
+		// are already.
+		p = strings.TrimPrefix(p, "/")
+		separators = []rune{'/'}
+	}
+
+	g, err := glob.Compile(p, separators...)
+	if err != nil {
+		return nil, fmt.Errorf("compiling glob %q: %w", pattern, err)
+	}
+	fold, err := glob.Compile(strings.ToLower(p), separators...)
+	if err != nil {
+		return nil, fmt.Errorf("compiling glob %q: %w", pattern, err)
+	}
+	return &patternGlob{
+		Glob:    g,
+		Pattern: pattern,
+		Fold:    fold,
+		Dir:     dir,
+		IsPath:  isPath,
+	}, nil
+}
+
+// globPattern returns the pattern for globs created by CompileGlob.
+func globPattern(g glob.Glob) string {
+	if pg, ok := g.(*patternGlob); ok {
+		return pg.Pattern
+	}
+	return ""
+}
+
+// matchGlob returns whether the glob matches the path. path is relative to
+// the walked path and fullPath is the resolved path. Globs that weren't
+// created by CompileGlob match the base name as given by glob.Compile.
+func (w *TODOWalker) matchGlob(g glob.Glob, path, fullPath string) bool {
+	name := filepath.Base(fullPath)
+	pg, ok := g.(*patternGlob)
+	if !ok {
+		return g.Match(name)
+	}
+
+	if pg.IsPath {
+		name = filepath.ToSlash(path)
+		if pg.Dir != "" {
+			rel, ok := strings.CutPrefix(name, pg.Dir+"/")
+			if !ok {
+				return false
+			}
+			name = rel
+		}
+	}
+
+	if w.options.CaseInsensitiveGlobs {
+		return pg.Fold.Match(strings.ToLower(name))
+	}
+	return pg.Match(name)
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gobwas/glob"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/testutils"
+	"github.com/ianlewis/todos/internal/todos"
+)
+
+func TestCompileDirGlob(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		pattern  string
+		expected string
+	}{
+		"no separator": {
+			pattern:  "testdata",
+			expected: "testdata",
+		},
+		"slash": {
+			pattern:  "testdata/",
+			expected: "testdata",
+		},
+		"backslash": {
+			pattern:  `testdata\`,
+			expected: "testdata",
+		},
+		"multiple": {
+			pattern:  `testdata/\/`,
+			expected: "testdata",
+		},
+		"path": {
+			pattern:  "internal/testdata/",
+			expected: "internal/testdata",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			g, err := CompileDirGlob(tc.pattern)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, globPattern(g)); diff != "" {
+				t.Errorf("unexpected pattern (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTODOWalker_matchGlob(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		glob            glob.Glob
+		path            string
+		caseInsensitive bool
+		expected        bool
+	}{
+		"base name": {
+			glob:     testutils.Must(CompileGlob("*.go")),
+			path:     "internal/foo.go",
+			expected: true,
+		},
+		"base name only": {
+			glob:     testutils.Must(CompileGlob("*foo*")),
+			path:     "foo/bar.go",
+			expected: false,
+		},
+		"path": {
+			glob:     testutils.Must(CompileGlob("internal/*.go")),
+			path:     "internal/foo.go",
+			expected: true,
+		},
+		"path star does not match separator": {
+			glob:     testutils.Must(CompileGlob("internal/*.go")),
+			path:     "internal/sub/foo.go",
+			expected: false,
+		},
+		"path double star": {
+			glob:     testutils.Must(CompileGlob("internal/**.go")),
+			path:     "internal/sub/foo.go",
+			expected: true,
+		},
+		"path leading slash": {
+			glob:     testutils.Must(CompileGlob("/internal/*.go")),
+			path:     "internal/foo.go",
+			expected: true,
+		},
+		"path not relative": {
+			glob:     testutils.Must(CompileGlob("internal/*.go")),
+			path:     "sub/internal/foo.go",
+			expected: false,
+		},
+		"dir path": {
+			glob:     testutils.Must(CompileDirGlob("internal/testdata/")),
+			path:     "internal/testdata",
+			expected: true,
+		},
+		"config dir": {
+			glob:     testutils.Must(compileGlob("gen/*.go", "sub")),
+			path:     "sub/gen/foo.go",
+			expected: true,
+		},
+		"outside config dir": {
+			glob:     testutils.Must(compileGlob("gen/*.go", "sub")),
+			path:     "gen/foo.go",
+			expected: false,
+		},
+		"case sensitive": {
+			glob:     testutils.Must(CompileGlob("*.go")),
+			path:     "internal/FOO.GO",
+			expected: false,
+		},
+		"case insensitive": {
+			glob:            testutils.Must(CompileGlob("*.go")),
+			path:            "internal/FOO.GO",
+			caseInsensitive: true,
+			expected:        true,
+		},
+		"case insensitive pattern": {
+			glob:            testutils.Must(CompileGlob("Internal/*.GO")),
+			path:            "internal/Foo.go",
+			caseInsensitive: true,
+			expected:        true,
+		},
+		"case insensitive class": {
+			glob:            testutils.Must(CompileGlob("[A-C]*.go")),
+			path:            "bar.go",
+			caseInsensitive: true,
+			expected:        true,
+		},
+		"glob.Compile": {
+			glob:     glob.MustCompile("*.go"),
+			path:     "internal/foo.go",
+			expected: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			w := New(&Options{
+				Config:               &todos.Config{},
+				CaseInsensitiveGlobs: tc.caseInsensitive,
+			})
+
+			// NOTE: Paths are given with the OS path separator as they are
+			// by Explain and with "/" as they are when walking.
+			for _, path := range []string{filepath.FromSlash(tc.path), tc.path} {
+				got := w.matchGlob(tc.glob, path, filepath.Join("root", filepath.FromSlash(tc.path)))
+				if diff := cmp.Diff(tc.expected, got); diff != "" {
+					t.Errorf("%q: unexpected match (-want +got):\n%s", path, diff)
+				}
+			}
+		})
+	}
+}
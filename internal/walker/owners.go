@@ -0,0 +1,61 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ianlewis/todos/internal/codeowners"
+)
+
+// owners returns the owners of the file from the CODEOWNERS file of the git
+// repository containing it.
+func (w *TODOWalker) owners(path string) ([]string, error) {
+	if !w.options.Owners {
+		return nil, nil
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("getting absolute path: %w", err)
+	}
+
+	root, err := repoRoot(absPath)
+	if err != nil {
+		return nil, err
+	}
+	if root == "" {
+		return nil, nil
+	}
+
+	f, ok := w.codeowners[root]
+	if !ok {
+		f, err = codeowners.Find(root)
+		if err != nil {
+			return nil, fmt.Errorf("finding CODEOWNERS: %w", err)
+		}
+		w.codeowners[root] = f
+	}
+	if f == nil {
+		return nil, nil
+	}
+
+	rel, err := filepath.Rel(root, absPath)
+	if err != nil {
+		return nil, fmt.Errorf("getting relative path: %w", err)
+	}
+	return f.Owners(rel), nil
+}
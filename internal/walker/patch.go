@@ -0,0 +1,167 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// hunkHeader matches the header of a hunk in a unified diff and captures the
+// start and length of the hunk in the new file.
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// patchFile is a file changed by a patch.
+type patchFile struct {
+	// name is the path of the file after the patch is applied.
+	name string
+
+	// lines are the lines of the file after the patch is applied. Lines that
+	// are not included in the patch are empty.
+	lines [][]byte
+
+	// added are the numbers of the lines added by the patch.
+	added map[int]bool
+}
+
+// set sets the text of the line with the given number.
+func (f *patchFile) set(line int, text []byte, added bool) {
+	for len(f.lines) < line {
+		f.lines = append(f.lines, nil)
+	}
+	f.lines[line-1] = text
+	if added {
+		f.added[line] = true
+	}
+}
+
+// contents returns the contents of the file after the patch is applied. Lines
+// that are not included in the patch are empty so that line numbers match the
+// patched file.
+func (f *patchFile) contents() []byte {
+	return append(bytes.Join(f.lines, []byte("\n")), '\n')
+}
+
+// parsePatch parses the files changed by the unified diff in contents. Deleted
+// files are not included.
+func parsePatch(contents []byte) []*patchFile {
+	var files []*patchFile
+	var f *patchFile
+
+	// line is the number of the next line of the hunk in the new file and
+	// remaining is the number of lines of the hunk in the new file that have
+	// not been read.
+	var line, remaining int
+
+	for _, l := range bytes.Split(contents, []byte("\n")) {
+		if remaining > 0 {
+			// NOTE: Some tools strip the trailing whitespace of empty
+			// context lines.
+			if len(l) == 0 {
+				l = []byte(" ")
+			}
+			switch l[0] {
+			case '+', ' ':
+				f.set(line, l[1:], l[0] == '+')
+				line++
+				remaining--
+			case '-', '\\':
+				// Removed line or "\ No newline at end of file".
+			default:
+				// The hunk is truncated.
+				remaining = 0
+			}
+			continue
+		}
+
+		if bytes.HasPrefix(l, []byte("+++ ")) {
+			f = nil
+			if name := patchPath(string(l[4:])); name != "" {
+				f = &patchFile{
+					name:  name,
+					added: map[int]bool{},
+				}
+				files = append(files, f)
+			}
+			continue
+		}
+
+		if f == nil {
+			continue
+		}
+		if m := hunkHeader.FindSubmatch(l); m != nil {
+			line, _ = strconv.Atoi(string(m[1]))
+			remaining = 1
+			if m[2] != nil {
+				remaining, _ = strconv.Atoi(string(m[2]))
+			}
+		}
+	}
+
+	return files
+}
+
+// patchPath returns the path of the new file given in a "+++" header line of a
+// unified diff. It returns an empty string for deleted files.
+func patchPath(header string) string {
+	p := header
+	// NOTE: Diffs not created by git may include a timestamp after a tab.
+	if i := strings.IndexByte(p, '\t'); i >= 0 {
+		p = p[:i]
+	}
+	p = strings.TrimSpace(p)
+
+	// NOTE: git quotes paths with unusual characters.
+	if strings.HasPrefix(p, `"`) {
+		if u, err := strconv.Unquote(p); err == nil {
+			p = u
+		}
+	}
+
+	if p == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(p, "b/")
+}
+
+// scanInput scans the contents of a walked file. If Options.Patches is set the
+// contents are scanned as a patch.
+func (w *TODOWalker) scanInput(fileName string, rawContents []byte, cfg *dirConfig, force bool) error {
+	if w.options.Patches {
+		return w.scanPatch(rawContents, cfg, force)
+	}
+	return w.scanContents(fileName, rawContents, cfg, force)
+}
+
+// scanPatch scans the files changed by the patch. Only TODOs on lines added by
+// the patch are reported, and they are attributed to the changed files.
+func (w *TODOWalker) scanPatch(rawContents []byte, cfg *dirConfig, force bool) error {
+	defer func() {
+		w.patchLines = nil
+	}()
+
+	for _, f := range parsePatch(rawContents) {
+		if len(f.added) == 0 {
+			continue
+		}
+		w.patchLines = f.added
+		if err := w.scanContents(f.name, f.contents(), cfg, force); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/testutils"
+	"github.com/ianlewis/todos/internal/todos"
+)
+
+const testPatch = `From 1234567 Mon Sep 17 00:00:00 2001
+From: Alice <alice@example.com>
+Subject: [PATCH] Add foo
+
+---
+ foo.go | 4 +++-
+ old.go | 1 -
+ 2 files changed, 3 insertions(+), 2 deletions(-)
+
+diff --git a/foo.go b/foo.go
+index 1111111..2222222 100644
+--- a/foo.go
++++ b/foo.go
+@@ -1,4 +1,6 @@
+ package foo
+
+-// TODO: removed
++// TODO: added
++
+ // TODO: context
++// FIXME: also added
+@@ -10,0 +12,1 @@
++// TODO: second hunk
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1 +0,0 @@
+-// TODO: deleted
+--- 
+2.39.2
+`
+
+func TestParsePatch(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		patch    string
+		expected []*patchFile
+	}{
+		"format-patch": {
+			patch: testPatch,
+			expected: []*patchFile{
+				{
+					name: "foo.go",
+					lines: [][]byte{
+						[]byte("package foo"),
+						[]byte(""),
+						[]byte("// TODO: added"),
+						[]byte(""),
+						[]byte("// TODO: context"),
+						[]byte("// FIXME: also added"),
+						nil,
+						nil,
+						nil,
+						nil,
+						nil,
+						[]byte("// TODO: second hunk"),
+					},
+					added: map[int]bool{3: true, 4: true, 6: true, 12: true},
+				},
+			},
+		},
+		"diff with timestamps": {
+			patch: "--- foo.py\t2025-01-01 00:00:00\n+++ foo.py\t2025-01-02 00:00:00\n@@ -1 +1 @@\n-# foo\n+# TODO: foo\n",
+			expected: []*patchFile{
+				{
+					name:  "foo.py",
+					lines: [][]byte{[]byte("# TODO: foo")},
+					added: map[int]bool{1: true},
+				},
+			},
+		},
+		"quoted path": {
+			patch: "--- /dev/null\n+++ \"b/foo bar\\t.py\"\n@@ -0,0 +1 @@\n+# TODO: foo\n",
+			expected: []*patchFile{
+				{
+					name:  "foo bar\t.py",
+					lines: [][]byte{[]byte("# TODO: foo")},
+					added: map[int]bool{1: true},
+				},
+			},
+		},
+		"not a patch": {
+			patch:    "// TODO: foo\n",
+			expected: nil,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := parsePatch([]byte(tc.patch))
+			if diff := cmp.Diff(tc.expected, got, cmp.AllowUnexported(patchFile{})); diff != "" {
+				t.Errorf("unexpected files (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Patches(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "0001-add-foo.patch",
+			Contents: []byte(testPatch),
+			Mode:     0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO", "FIXME"},
+		},
+		Charset: "UTF-8",
+		Patches: true,
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	want := []*TODORef{
+		{
+			FileName: "foo.go",
+			TODO: &todos.TODO{
+				Type:        "TODO",
+				Text:        "// TODO: added",
+				Message:     "added",
+				Line:        3,
+				CommentLine: 3,
+			},
+		},
+		{
+			FileName: "foo.go",
+			TODO: &todos.TODO{
+				Type:        "FIXME",
+				Text:        "// FIXME: also added",
+				Message:     "also added",
+				Line:        6,
+				CommentLine: 6,
+			},
+		},
+		{
+			FileName: "foo.go",
+			TODO: &todos.TODO{
+				Type:        "TODO",
+				Text:        "// TODO: second hunk",
+				Message:     "second hunk",
+				Line:        12,
+				CommentLine: 12,
+			},
+		},
+	}
+	if diff := cmp.Diff(want, f.out, ignoreFingerprint, ignoreOffsets); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/ianlewis/todos/internal/todos"
+)
+
+var errSecret = errors.New("TODO may contain a secret")
+
+// FindingCategory is the category of a Finding.
+type FindingCategory string
+
+// FindingSecret is the category of findings for TODOs that contain text that
+// looks like a credential or an internal URL.
+const FindingSecret FindingCategory = "secret"
+
+// Finding is a problem found in a TODO by a check.
+type Finding struct {
+	// Category is the category of the finding.
+	Category FindingCategory
+
+	// Rule is the name of the rule that found the problem.
+	Rule string
+}
+
+// SecretRule matches TODO text that looks like a secret.
+type SecretRule struct {
+	// Name is the name of the rule, such as "aws-access-key".
+	Name string
+
+	// Pattern matches the secret.
+	Pattern *regexp.Regexp
+}
+
+// DefaultSecretRules are the rules for common credential formats and
+// internal URLs.
+var DefaultSecretRules = []*SecretRule{
+	{
+		Name:    "aws-access-key",
+		Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	},
+	{
+		Name:    "github-token",
+		Pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`),
+	},
+	{
+		Name:    "slack-token",
+		Pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`),
+	},
+	{
+		Name:    "private-key",
+		Pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`),
+	},
+	{
+		Name:    "url-credentials",
+		Pattern: regexp.MustCompile(`\b[A-Za-z][A-Za-z0-9+.\-]*://[^/\s:@]+:[^/\s@]+@`),
+	},
+	{
+		Name: "password-assignment",
+		Pattern: regexp.MustCompile(
+			`(?i)\b(?:password|passwd|secret|api[_-]?key|access[_-]?token|auth[_-]?token)\s*(?:=\s*["']?[^\s"']{4,}|:\s*["'][^"']{4,}["'])`),
+	},
+	{
+		Name:    "internal-url",
+		Pattern: regexp.MustCompile(`(?i)\bhttps?://(?:[^/\s@]+@)?[a-z0-9.\-]+\.(?:internal|corp|intranet|local|lan)\b`),
+	},
+	{
+		Name: "private-ip-url",
+		Pattern: regexp.MustCompile(
+			`\bhttps?://(?:[^/\s@]+@)?(?:10\.\d{1,3}|192\.168|172\.(?:1[6-9]|2\d|3[01]))\.\d{1,3}\.\d{1,3}\b`),
+	},
+}
+
+// findings returns the findings for the TODO from the SecretRules. Each rule
+// is reported at most once.
+func (w *TODOWalker) findings(todo *todos.TODO) []*Finding {
+	var found []*Finding
+	for _, r := range w.options.SecretRules {
+		if r.Pattern.MatchString(todo.Text) {
+			found = append(found, &Finding{
+				Category: FindingSecret,
+				Rule:     r.Name,
+			})
+		}
+	}
+	return found
+}
+
+// reportFindings passes a warning for each of the TODO's findings to
+// WarningFunc. The matched text is not included so that secrets are not
+// copied to logs.
+func (w *TODOWalker) reportFindings(ref *TODORef) error {
+	for _, f := range ref.Findings {
+		ferr := fmt.Errorf("%w: line %d: %s", errSecret, ref.TODO.Line, f.Rule)
+		if err := w.handleWarning(ref.FileName, ferr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gobwas/glob"
+
+	"github.com/ianlewis/todos/internal/cache"
+	"github.com/ianlewis/todos/internal/todos"
+)
+
+// defaultCheckpointInterval is the default number of files scanned between
+// checkpoints.
+const defaultCheckpointInterval = 1000
+
+var errState = errors.New("state")
+
+// State is a checkpoint of a walk. It records the walk's position and the
+// results reported so far so that an interrupted walk can be resumed.
+//
+// The state passed to StateFunc only holds the results reported since the
+// previous checkpoint so that checkpoints don't get slower as the walk
+// progresses. The states of each checkpoint can be combined with Append.
+type State struct {
+	// Paths are the walked paths.
+	Paths []string `json:"paths"`
+
+	// Options is a hash of the options that affect the results of the walk.
+	Options string `json:"options"`
+
+	// PathIndex is the index in Paths of the path being walked.
+	PathIndex int `json:"path_index"`
+
+	// Last is the slash separated path, relative to the walked path, of the
+	// last file that was completely scanned. It is "." if the walked path
+	// is a file that was scanned and empty if no files have been scanned.
+	Last string `json:"last"`
+
+	// Files are the files reported before the checkpoint.
+	Files []*FileRef `json:"files,omitempty"`
+
+	// TODOs are the TODOs reported before the checkpoint.
+	TODOs []*TODORef `json:"todos,omitempty"`
+}
+
+// Append adds the results of the later checkpoint next to the state and
+// moves the state's position to the checkpoint.
+func (s *State) Append(next *State) {
+	s.Paths = next.Paths
+	s.Options = next.Options
+	s.PathIndex = next.PathIndex
+	s.Last = next.Last
+	s.Files = append(s.Files, next.Files...)
+	s.TODOs = append(s.TODOs, next.TODOs...)
+}
+
+// StateHandler handles checkpoints of the walk state.
+type StateHandler func(*State) error
+
+// stateOptions are the options that affect the results of a walk. Handlers
+// and options that only affect how the walk is run are not included.
+type stateOptions struct {
+	ListFiles             bool
+	Blame                 bool
+	Owners                bool
+	Dedup                 bool
+	Audit                 bool
+	Config                *todos.Config
+	Charset               string
+	ExcludeGlobs          []string
+	ExcludeDirGlobs       []string
+	CaseInsensitiveGlobs  bool
+	ExtensionLanguages    map[string]string
+	FallbackLanguage      string
+	SkipTests             bool
+	SkipShebang           bool
+	Metrics               bool
+	Patches               bool
+	SkipStrings           bool
+	MaxLineLength         int
+	MinSize               int64
+	MaxSize               int64
+	ModifiedSince         time.Time
+	IncludeDocs           bool
+	IncludeGenerated      bool
+	IncludeHidden         bool
+	IncludeVendored       bool
+	IncludeVCS            bool
+	FollowSymlinks        SymlinkMode
+	Extract               bool
+	Confine               bool
+	LabelGlobs            []string
+	ExcludeTypes          []string
+	ExcludeLabelGlobs     []string
+	Assignees             []string
+	Lines                 *LineRange
+	AttrFilters           []string
+	GrepRegexps           []string
+	DetectMessageLanguage bool
+	MessageLanguages      []string
+	SecretRules           []string
+	ShardIndex            int
+	ShardCount            int
+	Ref                   string
+}
+
+// OptionsHash returns a hash of the options that affect the results of the
+// walk so that a walk isn't resumed with different options.
+func OptionsHash(o *Options) string {
+	globs := func(gs []glob.Glob) []string {
+		var s []string
+		for _, g := range gs {
+			// NOTE: Compiled globs describe their pattern.
+			s = append(s, fmt.Sprint(g))
+		}
+		return s
+	}
+	regexps := func(rs []*regexp.Regexp) []string {
+		var s []string
+		for _, r := range rs {
+			s = append(s, r.String())
+		}
+		return s
+	}
+
+	so := &stateOptions{
+		ListFiles:             o.ListFiles,
+		Blame:                 o.Blame,
+		Owners:                o.Owners,
+		Dedup:                 o.Dedup,
+		Audit:                 o.Audit,
+		Config:                o.Config,
+		Charset:               o.Charset,
+		ExcludeGlobs:          globs(o.ExcludeGlobs),
+		ExcludeDirGlobs:       globs(o.ExcludeDirGlobs),
+		CaseInsensitiveGlobs:  o.CaseInsensitiveGlobs,
+		ExtensionLanguages:    o.ExtensionLanguages,
+		FallbackLanguage:      o.FallbackLanguage,
+		SkipTests:             o.SkipTests,
+		SkipShebang:           o.SkipShebang,
+		Metrics:               o.Metrics,
+		Patches:               o.Patches,
+		SkipStrings:           o.SkipStrings,
+		MaxLineLength:         o.MaxLineLength,
+		MinSize:               o.MinSize,
+		MaxSize:               o.MaxSize,
+		ModifiedSince:         o.ModifiedSince,
+		IncludeDocs:           o.IncludeDocs,
+		IncludeGenerated:      o.IncludeGenerated,
+		IncludeHidden:         o.IncludeHidden,
+		IncludeVendored:       o.IncludeVendored,
+		IncludeVCS:            o.IncludeVCS,
+		FollowSymlinks:        o.FollowSymlinks,
+		Extract:               o.Extract,
+		Confine:               o.Confine,
+		LabelGlobs:            globs(o.LabelGlobs),
+		ExcludeTypes:          o.ExcludeTypes,
+		ExcludeLabelGlobs:     globs(o.ExcludeLabelGlobs),
+		Assignees:             o.Assignees,
+		Lines:                 o.Lines,
+		GrepRegexps:           regexps(o.GrepRegexps),
+		DetectMessageLanguage: o.DetectMessageLanguage,
+		MessageLanguages:      o.MessageLanguages,
+		ShardIndex:            o.ShardIndex,
+		ShardCount:            o.ShardCount,
+		Ref:                   o.Ref,
+	}
+	for _, f := range o.AttrFilters {
+		so.AttrFilters = append(so.AttrFilters, f.Key+"="+fmt.Sprint(f.Value))
+	}
+	for _, r := range o.SecretRules {
+		so.SecretRules = append(so.SecretRules, r.Name+"="+r.Pattern.String())
+	}
+
+	// NOTE: Marshalling the options can't fail since they only hold strings,
+	// numbers, bools, and slices and maps of them.
+	b, _ := json.Marshal(so)
+	return cache.Key(b)
+}
+
+// resume validates the state that the walk is resumed from and reports the
+// results recorded in it to the handlers.
+func (w *TODOWalker) resume(s *State) error {
+	if !slices.Equal(s.Paths, w.options.Paths) {
+		return fmt.Errorf("%w: paths %q do not match %q", errState, s.Paths, w.options.Paths)
+	}
+	if s.Options != w.state.Options {
+		return fmt.Errorf("%w: options do not match the options of the resumed walk", errState)
+	}
+
+	w.state.PathIndex = s.PathIndex
+	w.state.Last = s.Last
+
+	// NOTE: The results in the state were already handled by StateFunc so
+	// they aren't added to the next checkpoint.
+	for _, f := range s.Files {
+		if w.options.FileFunc != nil {
+			if err := w.options.FileFunc(f); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, r := range s.TODOs {
+		if w.options.TODOFunc != nil {
+			if err := w.options.TODOFunc(r); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkpoint records that the file at path has been completely scanned and
+// passes the state to StateFunc every CheckpointInterval files.
+func (w *TODOWalker) checkpoint(path string) error {
+	if w.state == nil {
+		return nil
+	}
+
+	w.state.Last = path
+	w.scanned++
+
+	interval := w.options.CheckpointInterval
+	if interval <= 0 {
+		interval = defaultCheckpointInterval
+	}
+	if w.scanned%interval != 0 || w.options.StateFunc == nil {
+		return nil
+	}
+
+	if err := w.options.StateFunc(w.state); err != nil {
+		return fmt.Errorf("%w: %w", errState, err)
+	}
+
+	// NOTE: The next checkpoint only holds the results reported after this
+	// one.
+	w.state.Files = nil
+	w.state.TODOs = nil
+	return nil
+}
+
+// visitedBefore returns whether fs.WalkDir visits the slash separated path a
+// before the path b.
+func visitedBefore(a, b string) bool {
+	as := strings.Split(a, "/")
+	bs := strings.Split(b, "/")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] != bs[i] {
+			return as[i] < bs[i]
+		}
+	}
+	// NOTE: Directories are visited before their contents.
+	return len(as) < len(bs)
+}
+
+// resumeSkip returns whether the path was already walked before the
+// checkpoint that the walk is resumed from.
+func (w *TODOWalker) resumeSkip(path string, isDir bool) bool {
+	last := w.resumeLast
+	if last == "" || path == "." {
+		return false
+	}
+
+	// Walk directories that contain the last scanned file.
+	if isDir && strings.HasPrefix(last, path+"/") {
+		return false
+	}
+
+	if path == last || visitedBefore(path, last) {
+		return true
+	}
+
+	// The walk has passed the checkpoint.
+	w.resumeLast = ""
+	return false
+}
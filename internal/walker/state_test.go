@@ -0,0 +1,280 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobwas/glob"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/todos/internal/testutils"
+	"github.com/ianlewis/todos/internal/todos"
+)
+
+func TestVisitedBefore(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"a", "b", true},
+		{"b", "a", false},
+		{"a", "a/b", true},
+		{"a/b", "a", false},
+		{"a/z", "b", true},
+		{"a/b", "a/c/d", true},
+		{"a.go", "a/b", false},
+		{"a/b", "a.go", true},
+		{"ab/c", "a/b", false},
+		{"a", "a", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s %s", tc.a, tc.b), func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := visitedBefore(tc.a, tc.b), tc.expected; got != want {
+				t.Errorf("visitedBefore(%q, %q): got: %v, want: %v", tc.a, tc.b, got, want)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Resume(t *testing.T) {
+	var files []*testutils.File
+	for _, p := range []string{"a.go", "b/a.go", "b/c/a.go", "b/d.go", "c.go"} {
+		files = append(files, &testutils.File{
+			Path:     p,
+			Contents: []byte("// TODO: " + p),
+			Mode:     0o600,
+		})
+	}
+
+	newOpts := func() *Options {
+		return &Options{
+			Config: &todos.Config{
+				Types: []string{"TODO"},
+			},
+			Charset:            "UTF-8",
+			CheckpointInterval: 2,
+		}
+	}
+
+	// Walk without interruption to get the expected results.
+	f, w := newFixture(files, newOpts())
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+	want := f.out
+	f.cleanup()
+
+	// Walk and keep the states up to the second checkpoint as if the walk
+	// was interrupted after it.
+	var states [][]byte
+	opts := newOpts()
+	opts.StateFunc = func(s *State) error {
+		if len(states) == 2 {
+			return nil
+		}
+		b, err := json.Marshal(s)
+		states = append(states, b)
+		return err
+	}
+	f, w = newFixture(files, opts)
+	_ = w.Walk()
+	f.cleanup()
+
+	var s State
+	for i, b := range states {
+		var c State
+		if err := json.Unmarshal(b, &c); err != nil {
+			t.Fatalf("unmarshal state: %v", err)
+		}
+		// NOTE: Each checkpoint only holds the TODOs found since the
+		// previous checkpoint.
+		if got, want := len(c.TODOs), 2; got != want {
+			t.Errorf("checkpoint %d: unexpected # of TODOs, got: %v, want: %v", i, got, want)
+		}
+		s.Append(&c)
+	}
+	if got, want := s.Last, "b/d.go"; got != want {
+		t.Errorf("unexpected last file, got: %q, want: %q", got, want)
+	}
+
+	// Resume the walk from the checkpoint.
+	opts = newOpts()
+	opts.Resume = &s
+	f, w = newFixture(files, opts)
+	defer f.cleanup()
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	if diff := cmp.Diff(want, f.out); diff != "" {
+		t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_ResumePaths(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "a.go",
+			Contents: []byte("// TODO: a"),
+			Mode:     0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+		Resume: &State{
+			Paths: []string{"other"},
+		},
+	}
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), true; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v", got, want)
+	}
+	if got, want := len(f.out), 0; got != want {
+		t.Errorf("unexpected # of TODOs, got: %v, want: %v", got, want)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_ResumeOptions(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "a.go",
+			Contents: []byte("// TODO: a"),
+			Mode:     0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+		Paths:   []string{"."},
+	}
+	var errs []error
+	opts.ErrorFunc = func(err error) error {
+		errs = append(errs, err)
+		return nil
+	}
+	opts.Resume = &State{
+		Paths:   []string{"."},
+		Options: OptionsHash(&Options{Config: &todos.Config{Types: []string{"FIXME"}}}),
+	}
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), true; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v", got, want)
+	}
+	if got, want := len(f.out), 0; got != want {
+		t.Errorf("unexpected # of TODOs, got: %v, want: %v", got, want)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "options do not match") {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+// TestOptionsHash checks that every option that affects the results of a walk
+// changes the hash so that options added later can't mix results.
+func TestOptionsHash(t *testing.T) {
+	t.Parallel()
+
+	// NOTE: These options don't affect the results of a walk.
+	ignored := map[string]bool{
+		"TODOFunc":           true,
+		"ErrorFunc":          true,
+		"WarningFunc":        true,
+		"FileFunc":           true,
+		"FileResultFunc":     true,
+		"SkipFunc":           true,
+		"NoteFunc":           true,
+		"ConfigFunc":         true,
+		"StrictConfig":       true,
+		"Deadline":           true,
+		"Paths":              true,
+		"Cache":              true,
+		"StateFunc":          true,
+		"CheckpointInterval": true,
+		"Resume":             true,
+	}
+
+	base := OptionsHash(&Options{})
+	values := map[string]any{
+		"Config":             &todos.Config{},
+		"ExtensionLanguages": map[string]string{"foo": "Go"},
+		"ModifiedSince":      time.Unix(1, 0),
+		"Lines":              &LineRange{},
+		"FollowSymlinks":     SymlinksNone,
+		"ExcludeGlobs":       []glob.Glob{glob.MustCompile("*.go")},
+		"ExcludeDirGlobs":    []glob.Glob{glob.MustCompile("foo")},
+		"LabelGlobs":         []glob.Glob{glob.MustCompile("foo")},
+		"ExcludeLabelGlobs":  []glob.Glob{glob.MustCompile("foo")},
+		"AttrFilters":        []*AttrFilter{{Key: "foo", Value: glob.MustCompile("bar")}},
+		"GrepRegexps":        []*regexp.Regexp{regexp.MustCompile("foo")},
+		"SecretRules":        []*SecretRule{{Name: "foo", Pattern: regexp.MustCompile("foo")}},
+	}
+
+	typ := reflect.TypeOf(Options{})
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if ignored[field.Name] {
+			continue
+		}
+		t.Run(field.Name, func(t *testing.T) {
+			t.Parallel()
+
+			var o Options
+			v := reflect.ValueOf(&o).Elem().Field(i)
+			switch {
+			case values[field.Name] != nil:
+				v.Set(reflect.ValueOf(values[field.Name]))
+			case v.Kind() == reflect.Bool:
+				v.SetBool(true)
+			case v.Kind() == reflect.String:
+				v.SetString("foo")
+			case v.CanInt():
+				v.SetInt(1)
+			case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+				v.Set(reflect.ValueOf([]string{"foo"}))
+			default:
+				t.Fatalf("unsupported option type %v", v.Type())
+			}
+
+			if OptionsHash(&o) == base {
+				t.Errorf("setting %s does not change the options hash", field.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walker
+
+import (
+	"github.com/gobwas/glob"
+
+	"github.com/ianlewis/todos/internal/utils"
+)
+
+// defaultTestPatterns are globs for well-known test file names.
+var defaultTestPatterns = []string{
+	// C#
+	"*Test.cs",
+	"*Tests.cs",
+	// Dart
+	"*_test.dart",
+	// Elixir
+	"*_test.exs",
+	// Go
+	"*_test.go",
+	// Java, Kotlin, Scala
+	"*Test.java",
+	"*Tests.java",
+	"*Test.kt",
+	"*Spec.scala",
+	// JavaScript, TypeScript
+	"*.test.[jt]s",
+	"*.spec.[jt]s",
+	"*.test.[jt]sx",
+	"*.spec.[jt]sx",
+	// PHP
+	"*Test.php",
+	// Python
+	"test_*.py",
+	"*_test.py",
+	// Ruby
+	"*_spec.rb",
+	"*_test.rb",
+}
+
+// defaultTestDirPatterns are globs for well-known test directory names.
+var defaultTestDirPatterns = []string{
+	"__tests__",
+	"spec",
+	"test",
+	"tests",
+}
+
+// defaultTestGlobs and defaultTestDirGlobs are the compiled default patterns.
+var (
+	defaultTestGlobs    = mustCompileGlobs(defaultTestPatterns)
+	defaultTestDirGlobs = mustCompileGlobs(defaultTestDirPatterns)
+)
+
+// mustCompileGlobs compiles the patterns and panics if any are invalid.
+func mustCompileGlobs(patterns []string) []glob.Glob {
+	globs := make([]glob.Glob, 0, len(patterns))
+	for _, p := range patterns {
+		globs = append(globs, utils.Must(CompileGlob(p)))
+	}
+	return globs
+}
@@ -18,8 +18,10 @@ package walker
 
 import (
 	"fmt"
+	"io/fs"
 	"path/filepath"
 	"strings"
+	"syscall"
 )
 
 // isHidden checks if a file is hidden on most operating systems.
@@ -35,3 +37,17 @@ func isHidden(path string) (bool, error) {
 	}
 	return strings.HasPrefix(base, "."), nil
 }
+
+// physicalID returns the device and inode of the file. It returns false if
+// they cannot be determined.
+func physicalID(_ string, info fs.FileInfo) (fileID, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileID{}, false
+	}
+	//nolint:unconvert // Dev is not a uint64 on all platforms.
+	return fileID{
+		dev: uint64(st.Dev),
+		ino: st.Ino,
+	}, true
+}
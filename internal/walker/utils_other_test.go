@@ -17,10 +17,15 @@
 package walker
 
 import (
+	"os"
 	"path/filepath"
+	"syscall"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/ianlewis/todos/internal/testutils"
+	"github.com/ianlewis/todos/internal/todos"
 )
 
 func Test_isHidden(t *testing.T) {
@@ -61,3 +66,388 @@ func Test_isHidden(t *testing.T) {
 		})
 	}
 }
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_FIFO(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "code.go",
+			Contents: []byte("// TODO: code"),
+			Mode:     0o600,
+		},
+	}
+
+	testCases := map[string]struct {
+		paths []string
+	}{
+		"walked": {
+			paths: []string{"."},
+		},
+		"specified": {
+			paths: []string{"pipe"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var skipped []*SkipReason
+			opts := &Options{
+				Config: &todos.Config{
+					Types: []string{"TODO"},
+				},
+				Charset: "UTF-8",
+				Paths:   tc.paths,
+				SkipFunc: func(r *SkipReason) error {
+					skipped = append(skipped, r)
+					return nil
+				},
+			}
+
+			f, w := newFixture(files, opts)
+			defer f.cleanup()
+
+			testutils.Check(syscall.Mkfifo("pipe", 0o600))
+
+			if got, want := w.Walk(), false; got != want {
+				t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+			}
+
+			want := []*SkipReason{
+				{
+					Rule: SkipIrregular,
+					Path: "pipe",
+				},
+			}
+			if diff := cmp.Diff(want, skipped); diff != "" {
+				t.Errorf("unexpected skipped paths (-want +got):\n%s", diff)
+			}
+
+			r, err := w.Explain("pipe")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(want[0], r); diff != "" {
+				t.Errorf("unexpected reason (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Dedup(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "a.go",
+			Contents: []byte("// TODO: code"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "dir/c.go",
+			Contents: []byte("// TODO: other"),
+			Mode:     0o600,
+		},
+	}
+
+	testCases := map[string]struct {
+		dedup    bool
+		paths    []string
+		expected int
+		skipped  []*SkipReason
+	}{
+		"no dedup": {
+			paths:    []string{"."},
+			expected: 3,
+		},
+		"dedup": {
+			dedup:    true,
+			paths:    []string{"."},
+			expected: 2,
+			skipped: []*SkipReason{
+				{
+					Rule: SkipDuplicate,
+					Path: "b.go",
+				},
+			},
+		},
+		"dedup overlapping paths": {
+			dedup:    true,
+			paths:    []string{".", "dir", "b.go"},
+			expected: 2,
+			skipped: []*SkipReason{
+				{
+					Rule: SkipDuplicate,
+					Path: "b.go",
+				},
+				{
+					Rule: SkipDuplicate,
+					Path: ".",
+				},
+				{
+					Rule: SkipDuplicate,
+					Path: "b.go",
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var skipped []*SkipReason
+			opts := &Options{
+				Config: &todos.Config{
+					Types: []string{"TODO"},
+				},
+				Charset: "UTF-8",
+				Dedup:   tc.dedup,
+				Paths:   tc.paths,
+				SkipFunc: func(r *SkipReason) error {
+					skipped = append(skipped, r)
+					return nil
+				},
+			}
+
+			f, w := newFixture(files, opts)
+			defer f.cleanup()
+
+			testutils.Check(os.Link("a.go", "b.go"))
+
+			if got, want := w.Walk(), false; got != want {
+				t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+			}
+
+			if got, want := len(f.out), tc.expected; got != want {
+				t.Errorf("unexpected # of TODOs, got: %v, want: %v", got, want)
+			}
+			if diff := cmp.Diff(tc.skipped, skipped); diff != "" {
+				t.Errorf("unexpected skipped paths (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_FollowSymlinks(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "code.go",
+			Contents: []byte("// TODO: code"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/target.go",
+			Contents: []byte("// TODO: target"),
+			Mode:     0o600,
+		},
+	}
+
+	outside := t.TempDir()
+	outsidePath := filepath.Join(outside, "outside.go")
+	testutils.Check(os.WriteFile(outsidePath, []byte("// TODO: outside"), 0o600))
+	outsidePath = testutils.Must(filepath.EvalSymlinks(outsidePath))
+
+	testCases := map[string]struct {
+		mode     SymlinkMode
+		expected []string
+		skipped  []*SkipReason
+	}{
+		"all": {
+			mode: SymlinksAll,
+			// NOTE: TODOs are reported with the path of the link target.
+			expected: []string{
+				"code.go:code",
+				filepath.Join("sub", "target.go") + ":target",
+				outsidePath + ":outside",
+				filepath.Join("sub", "target.go") + ":target",
+			},
+		},
+		"in-tree": {
+			mode: SymlinksInTree,
+			expected: []string{
+				"code.go:code",
+				filepath.Join("sub", "target.go") + ":target",
+				filepath.Join("sub", "target.go") + ":target",
+			},
+			skipped: []*SkipReason{
+				{
+					Rule:    SkipSymlink,
+					Path:    "out.go",
+					Pattern: outsidePath,
+				},
+			},
+		},
+		"none": {
+			mode: SymlinksNone,
+			expected: []string{
+				"code.go:code",
+				filepath.Join("sub", "target.go") + ":target",
+			},
+			skipped: []*SkipReason{
+				{
+					Rule:    SkipSymlink,
+					Path:    "in.go",
+					Pattern: filepath.Join("sub", "target.go"),
+				},
+				{
+					Rule:    SkipSymlink,
+					Path:    "out.go",
+					Pattern: outsidePath,
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var skipped []*SkipReason
+			opts := &Options{
+				Config: &todos.Config{
+					Types: []string{"TODO"},
+				},
+				Charset:        "UTF-8",
+				FollowSymlinks: tc.mode,
+				SkipFunc: func(r *SkipReason) error {
+					skipped = append(skipped, r)
+					return nil
+				},
+			}
+
+			f, w := newFixture(files, opts)
+			defer f.cleanup()
+
+			testutils.Check(os.Symlink(filepath.Join("sub", "target.go"), "in.go"))
+			testutils.Check(os.Symlink(outsidePath, "out.go"))
+
+			if got, want := w.Walk(), false; got != want {
+				t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+			}
+
+			var got []string
+			for _, r := range f.out {
+				got = append(got, r.FileName+":"+r.TODO.Message)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.skipped, skipped); diff != "" {
+				t.Errorf("unexpected skipped paths (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Confine(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "code.go",
+			Contents: []byte("// TODO: code"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/target.go",
+			Contents: []byte("// TODO: target"),
+			Mode:     0o600,
+		},
+	}
+
+	outside := t.TempDir()
+	outsidePath := filepath.Join(outside, "outside.go")
+	testutils.Check(os.WriteFile(outsidePath, []byte("// TODO: outside"), 0o600))
+	outsidePath = testutils.Must(filepath.EvalSymlinks(outsidePath))
+	configPath := filepath.Join(outside, ".todos.yml")
+	testutils.Check(os.WriteFile(configPath, []byte("types: [FIXME]\n"), 0o600))
+	configPath = testutils.Must(filepath.EvalSymlinks(configPath))
+
+	testCases := map[string]struct {
+		confine  bool
+		expected []string
+		skipped  []*SkipReason
+		warnings []string
+	}{
+		"not confined": {
+			expected: []string{
+				"code.go:code",
+				outsidePath + ":outside",
+			},
+			skipped: []*SkipReason{
+				{
+					Rule: SkipHidden,
+					Path: filepath.Join("sub", ".todos.yml"),
+				},
+			},
+		},
+		"confined": {
+			confine: true,
+			expected: []string{
+				"code.go:code",
+				filepath.Join("sub", "target.go") + ":target",
+			},
+			skipped: []*SkipReason{
+				{
+					Rule:    SkipOutsideRoot,
+					Path:    "out.go",
+					Pattern: outsidePath,
+				},
+				{
+					Rule:    SkipOutsideRoot,
+					Path:    filepath.Join("sub", ".todos.yml"),
+					Pattern: configPath,
+				},
+			},
+			warnings: []string{
+				"out.go: " + errOutsideRoot.Error() + ": " + outsidePath,
+				filepath.Join("sub", ".todos.yml") + ": " + errOutsideRoot.Error() + ": " + configPath,
+				filepath.Join("sub", ".todos.yml") + ": " + errOutsideRoot.Error() + ": " + configPath,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var skipped []*SkipReason
+			var warnings []string
+			opts := &Options{
+				Config: &todos.Config{
+					Types: []string{"TODO"},
+				},
+				Charset: "UTF-8",
+				Confine: tc.confine,
+				SkipFunc: func(r *SkipReason) error {
+					skipped = append(skipped, r)
+					return nil
+				},
+				WarningFunc: func(err error) error {
+					warnings = append(warnings, err.Error())
+					return nil
+				},
+			}
+
+			f, w := newFixture(files, opts)
+			defer f.cleanup()
+
+			testutils.Check(os.Symlink(outsidePath, "out.go"))
+			testutils.Check(os.Symlink(configPath, filepath.Join("sub", ".todos.yml")))
+
+			if got, want := w.Walk(), false; got != want {
+				t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+			}
+
+			var got []string
+			for _, r := range f.out {
+				got = append(got, r.FileName+":"+r.TODO.Message)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.skipped, skipped); diff != "" {
+				t.Errorf("unexpected skipped paths (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.warnings, warnings); diff != "" {
+				t.Errorf("unexpected warnings (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
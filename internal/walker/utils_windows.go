@@ -17,6 +17,7 @@
 package walker
 
 import (
+	"io/fs"
 	"path/filepath"
 	"strings"
 	"syscall"
@@ -50,3 +51,29 @@ func isHidden(path string) (bool, error) {
 
 	return attributes&syscall.FILE_ATTRIBUTE_HIDDEN != 0, nil
 }
+
+// physicalID returns the volume serial number and file index of the file. It
+// returns false if they cannot be determined.
+func physicalID(path string, _ fs.FileInfo) (fileID, bool) {
+	pointer, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return fileID{}, false
+	}
+
+	// NOTE: FILE_FLAG_BACKUP_SEMANTICS is required to open directories.
+	h, err := syscall.CreateFile(pointer, 0, syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE,
+		nil, syscall.OPEN_EXISTING, syscall.FILE_FLAG_BACKUP_SEMANTICS, 0)
+	if err != nil {
+		return fileID{}, false
+	}
+	defer syscall.CloseHandle(h)
+
+	var d syscall.ByHandleFileInformation
+	if err := syscall.GetFileInformationByHandle(h, &d); err != nil {
+		return fileID{}, false
+	}
+	return fileID{
+		dev: uint64(d.VolumeSerialNumber),
+		ino: uint64(d.FileIndexHigh)<<32 | uint64(d.FileIndexLow),
+	}, true
+}
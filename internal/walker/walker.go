@@ -15,26 +15,45 @@
 package walker
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
-	"github.com/go-enry/go-enry/v2"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/gobwas/glob"
 
+	"github.com/ianlewis/todos/internal/analysis"
+	"github.com/ianlewis/todos/internal/cache"
+	"github.com/ianlewis/todos/internal/codeowners"
+	"github.com/ianlewis/todos/internal/extract"
+	"github.com/ianlewis/todos/internal/natlang"
 	"github.com/ianlewis/todos/internal/scanner"
 	"github.com/ianlewis/todos/internal/todos"
-	"github.com/ianlewis/todos/internal/vendoring"
 )
 
 var errGit = errors.New("git")
 
+var errUnstable = errors.New("file was modified while it was being read")
+
+var errUnbalancedRegion = errors.New("unbalanced region marker")
+
+var errNearMiss = errors.New("TODO type only differs by case")
+
+var errOutsideRoot = errors.New("path resolves outside of the walked paths")
+
+// ErrDeadline is passed to ErrorFunc when a file isn't scanned by the
+// Options.Deadline.
+var ErrDeadline = errors.New("deadline exceeded")
+
 // GitUser is a git user (e.g. committer).
 type GitUser struct {
 	// Name is the git user.name.
@@ -49,14 +68,149 @@ type TODORef struct {
 	FileName string
 	TODO     *todos.TODO
 	GitUser  *GitUser
+
+	// Fingerprint is a stable identifier for the TODO. See Fingerprint.
+	Fingerprint string
+
+	// Owners are the owners of the file from the repository's CODEOWNERS
+	// file.
+	Owners []string
+
+	// Findings are the problems found in the TODO by checks such as
+	// Options.SecretRules.
+	Findings []*Finding
+
+	// MessageLanguage is the ISO 639-1 code of the natural language of the
+	// TODO message if Options.DetectMessageLanguage is set. See
+	// natlang.Detect.
+	MessageLanguage string
+}
+
+// SymlinkMode controls which symbolic links the walker follows.
+type SymlinkMode string
+
+const (
+	// SymlinksAll follows all symbolic links.
+	SymlinksAll SymlinkMode = ""
+
+	// SymlinksInTree only follows symbolic links whose targets are inside
+	// the path being walked. This prevents scanning files elsewhere on the
+	// system, such as in /usr or home directories.
+	SymlinksInTree SymlinkMode = "in-tree"
+
+	// SymlinksNone doesn't follow symbolic links.
+	SymlinksNone SymlinkMode = "none"
+)
+
+// FileRef represents a file that is scanned by the walker.
+type FileRef struct {
+	FileName string
+
+	// Language is the detected language of the file.
+	Language string
+
+	// LanguageDetection describes how the language of the file was
+	// detected.
+	LanguageDetection *scanner.LanguageDetection
+}
+
+// FileResult is the result of scanning a single file.
+type FileResult struct {
+	FileName string
+
+	// Language is the detected language of the file.
+	Language string
+
+	// LanguageDetection describes how the language of the file was
+	// detected.
+	LanguageDetection *scanner.LanguageDetection
+
+	// Size is the size of the file in bytes.
+	Size int64
+
+	// Lines is the number of lines in the file.
+	Lines int
+
+	// TODOs are the TODOs in the file that were reported.
+	TODOs []*TODORef
+
+	// Errors are the errors that occurred while scanning the file.
+	Errors []error
+
+	// Duration is the time taken to scan the file.
+	Duration time.Duration
+
+	// Stats are the comment scanner's counters for the file. If the file's
+	// results were read from the cache they are the counters from when the
+	// file was scanned.
+	Stats scanner.Stats
+
+	// Metrics are the file's comment metrics. They are only computed if
+	// Options.Metrics is set.
+	Metrics *analysis.Metrics
 }
 
 // TODOHandler handles found TODO references. It can return SkipAll or SkipDir.
 type TODOHandler func(*TODORef) error
 
+// FileHandler handles files that are scanned. It can return SkipAll or SkipDir.
+type FileHandler func(*FileRef) error
+
+// FileResultHandler handles the results of scanned files. It can return
+// SkipAll or SkipDir.
+type FileResultHandler func(*FileResult) error
+
+// SkipHandler handles paths that are skipped. It can return SkipAll or
+// SkipDir.
+type SkipHandler func(*SkipReason) error
+
+// NoteHandler handles informational notes about files. It can return SkipAll
+// or SkipDir.
+type NoteHandler func(fileName, note string) error
+
 // ErrorHandler handles found TODO references. It can return SkipAll or SkipDir.
 type ErrorHandler func(error) error
 
+// ConfigHandler handles the paths of configuration files that are loaded. It
+// can return SkipAll.
+type ConfigHandler func(path string) error
+
+// AttrFilter matches TODOs by one of their attributes.
+type AttrFilter struct {
+	// Key is the attribute key.
+	Key string
+
+	// Value matches the attribute value. TODOs with the attribute match
+	// if Value is nil.
+	Value glob.Glob
+}
+
+// Match returns whether the TODO matches the filter.
+func (f *AttrFilter) Match(todo *todos.TODO) bool {
+	v, ok := todo.Attributes[f.Key]
+	if !ok {
+		return false
+	}
+	return f.Value == nil || f.Value.Match(v)
+}
+
+// LineRange is an inclusive range of lines in a file.
+type LineRange struct {
+	// Start is the first line of the range.
+	Start int
+
+	// End is the last line of the range. The range extends to the end of the
+	// file if End is zero.
+	End int
+}
+
+// Overlaps returns whether the TODO is in the range. Regions are in the range
+// if any part of them overlaps it.
+func (r *LineRange) Overlaps(todo *todos.TODO) bool {
+	end := max(todo.Line, todo.EndLine)
+	return end >= r.Start && (r.End == 0 || todo.Line <= r.End)
+}
+
 // Options are options for the walker.
 type Options struct {
 	// TODOFunc handles when TODOs are found.
@@ -65,10 +219,65 @@ type Options struct {
 	// ErrorFunc handles when errors are found.
 	ErrorFunc ErrorHandler
 
+	// WarningFunc handles warnings such as files that are skipped because
+	// they were modified while being read. Warnings do not cause Walk to
+	// return true.
+	WarningFunc ErrorHandler
+
+	// FileFunc handles when files are scanned.
+	FileFunc FileHandler
+
+	// FileResultFunc handles the result of each scanned file after all of
+	// its TODOs have been passed to TODOFunc. Files restored when resuming
+	// from a checkpoint are not passed to it.
+	FileResultFunc FileResultHandler
+
+	// SkipFunc handles when paths are skipped.
+	SkipFunc SkipHandler
+
+	// NoteFunc handles informational notes about files, such as the number
+	// of invalid bytes that were replaced when decoding them.
+	NoteFunc NoteHandler
+
+	// ConfigFunc handles each configuration file that is loaded.
+	ConfigFunc ConfigHandler
+
+	// StrictConfig stops the walk if a configuration file can't be read or
+	// parsed. Otherwise the error is passed to ErrorFunc and the directory
+	// is scanned with its parent's configuration, which may scan files that
+	// the configuration file excludes.
+	StrictConfig bool
+
+	// Deadline is the time by which the walk must finish. It is checked after
+	// each comment that is scanned. If a file isn't scanned by the deadline
+	// ErrDeadline is passed to ErrorFunc and the walk is stopped. The zero
+	// time means no deadline.
+	Deadline time.Time
+
+	// ListFiles indicates that files should only be passed to FileFunc and
+	// not scanned for TODOs.
+	ListFiles bool
+
 	// Blame indicates that the walker should attempt to find the git committer
 	// that committed each TODO.
 	Blame bool
 
+	// Owners indicates that the walker should find the owners of the files
+	// containing TODOs from the repository's CODEOWNERS file.
+	Owners bool
+
+	// Dedup indicates that files and directories that are the same physical
+	// file (same device and inode) as a path that was already walked should
+	// be skipped. This prevents files from being reported more than once due
+	// to hard links or bind mounts.
+	Dedup bool
+
+	// Audit indicates that files skipped by exclude rules, such as excluded,
+	// hidden, or vendored files, should be quickly searched for the TODO
+	// types. Skipped files that appear to contain TODOs are reported to
+	// WarningFunc so that misconfigured rules are visible.
+	Audit bool
+
 	// Config is the config for scanning todos.
 	Config *todos.Config
 
@@ -82,6 +291,66 @@ type Options struct {
 	// ExcludeDirGlobs is a list of Glob that matches excluded dirs.
 	ExcludeDirGlobs []glob.Glob
 
+	// CaseInsensitiveGlobs matches globs created by CompileGlob, such as
+	// ExcludeGlobs, ExcludeDirGlobs, and the globs in configuration files,
+	// regardless of case. This is useful on case-insensitive file systems
+	// such as the defaults on Windows and macOS.
+	CaseInsensitiveGlobs bool
+
+	// ExtensionLanguages maps lower case file extensions, such as ".m", to
+	// the language that files with the extension are scanned as instead of
+	// the detected language. Configuration files can override the mappings.
+	ExtensionLanguages map[string]string
+
+	// FallbackLanguage is the language that files whose language is not
+	// supported or wasn't detected are scanned as. Files are scanned for
+	// TODOs on whole lines if it is "Text". Such files are not scanned if it
+	// is empty.
+	FallbackLanguage string
+
+	// SkipTests indicates that well-known test files and directories, such as
+	// *_test.go and __tests__, should be skipped. Additional test patterns can
+	// be given in configuration files.
+	SkipTests bool
+
+	// SkipShebang indicates that shebang lines (e.g. "#!/bin/bash") at the
+	// start of files should not be scanned for TODOs.
+	SkipShebang bool
+
+	// Metrics indicates that comment metrics should be computed for each
+	// file and included in its FileResult.
+	Metrics bool
+
+	// Patches indicates that files are unified diffs (e.g. created by git
+	// format-patch). The files changed by each diff are scanned instead and
+	// only TODOs on lines added by the diff are reported.
+	Patches bool
+
+	// SkipStrings indicates that string literals should not be tracked when
+	// scanning. Scanning is faster but TODOs in strings may be reported.
+	SkipStrings bool
+
+	// MaxLineLength is the maximum number of bytes of each comment line that
+	// are captured. Longer lines are truncated. There is no limit if
+	// MaxLineLength is zero.
+	MaxLineLength int
+
+	// MinSize is the minimum size in bytes of files to scan.
+	MinSize int64
+
+	// MaxSize is the maximum size in bytes of files to scan. Files of any size
+	// are scanned if MaxSize is zero.
+	MaxSize int64
+
+	// ModifiedSince causes files that were last modified before it to be
+	// skipped. Files are not filtered by modification time if it is zero.
+	ModifiedSince time.Time
+
+	// IncludeDocs indicates whether documentation files and directories should
+	// be processed. Documentation paths are always processed if they are
+	// specified explicitly in `paths`.
+	IncludeDocs bool
+
 	// IncludeGenerated indicates whether generated files should be processed. Generated
 	// paths are always processed if there are specified explicitly in `paths`.
 	IncludeGenerated bool
@@ -97,11 +366,98 @@ type Options struct {
 	// IncludeVCS indicates that VCS paths (.git, .hg, .svn, etc.) should be included.
 	IncludeVCS bool
 
+	// FollowSymlinks controls which symbolic links to files are followed.
+	// All links are followed if it is empty. Links to directories are never
+	// walked.
+	FollowSymlinks SymlinkMode
+
+	// Extract indicates that documents in binary formats, such as .docx
+	// files, should be converted to text by the extractor registered for
+	// their extension and scanned for TODOs on whole lines.
+	Extract bool
+
+	// Confine indicates that files and configuration files whose paths
+	// resolve outside of all of the Paths after symbolic links are evaluated
+	// should be reported to WarningFunc and skipped rather than opened.
+	Confine bool
+
 	// LabelGlobs is a list of Glob to filter TODOs by label.
 	LabelGlobs []glob.Glob
 
+	// ExcludeTypes is a list of TODO types that should not be reported.
+	ExcludeTypes []string
+
+	// ExcludeLabelGlobs is a list of Glob that matches labels of TODOs that
+	// should not be reported.
+	ExcludeLabelGlobs []glob.Glob
+
+	// Assignees is a list of users to filter TODOs by. TODOs are reported if
+	// their label assigns them to any of the users. See todos.Assignee.
+	Assignees []string
+
+	// Lines limits the reported TODOs to a range of lines. Files are still
+	// scanned from the start so that comments spanning the range are found.
+	// It is intended for rescanning part of a single file.
+	Lines *LineRange
+
+	// AttrFilters is a list of filters for TODO attributes. TODOs are
+	// reported if they match all of them.
+	AttrFilters []*AttrFilter
+
+	// GrepRegexps is a list of regular expressions to filter TODOs by their
+	// text. TODOs are reported if their text matches any of them.
+	GrepRegexps []*regexp.Regexp
+
+	// DetectMessageLanguage enables detecting the natural language of TODO
+	// messages.
+	DetectMessageLanguage bool
+
+	// MessageLanguages is a list of natural languages to filter TODOs by.
+	// TODOs are reported if their message is written in any of them. It
+	// implies DetectMessageLanguage. natlang.Undetermined matches messages
+	// whose language can't be detected.
+	MessageLanguages []string
+
+	// SecretRules match TODO text that looks like credentials or internal
+	// URLs. A Finding is added to the TODORef and a warning is passed to
+	// WarningFunc for each matching rule.
+	SecretRules []*SecretRule
+
 	// Paths are the paths to walk to look for TODOs.
 	Paths []string
+
+	// ShardIndex is the 0-based index of the shard to scan when ShardCount is
+	// greater than 1.
+	ShardIndex int
+
+	// ShardCount is the number of shards that files are partitioned into.
+	// Files are assigned to shards by a hash of their path. All files are
+	// scanned if ShardCount is less than or equal to 1.
+	ShardCount int
+
+	// Cache is used to store and retrieve scan results for files with the
+	// same contents. Results are not cached if Cache is nil.
+	Cache cache.Cache
+
+	// StateFunc handles checkpoints of the walk state. The state only holds
+	// the results reported since the previous checkpoint. The states of each
+	// checkpoint combined with State.Append can be passed as Resume to a later
+	// walk to resume the walk from the last checkpoint.
+	StateFunc StateHandler
+
+	// CheckpointInterval is the number of files scanned between checkpoints.
+	// A default of 1000 is used if CheckpointInterval is zero.
+	CheckpointInterval int
+
+	// Ref is a git ref, such as a tag or branch name, to scan instead of the
+	// working tree. Files are read from the git object database of the
+	// repository containing each path.
+	Ref string
+
+	// Resume is the state to resume the walk from. The results recorded in
+	// the state are reported before the walk continues. The walk must have
+	// the same paths and options as the resumed walk.
+	Resume *State
 }
 
 // New returns a new walker for the options.
@@ -114,7 +470,12 @@ func New(opts *Options) *TODOWalker {
 		}
 	}
 	return &TODOWalker{
-		options: opts,
+		options:    opts,
+		codeowners: map[string]*codeowners.File{},
+		configs:    map[string]*dirConfig{},
+		seen:       map[fileID]struct{}{},
+
+		auditMatches: map[string]*regexp.Regexp{},
 	}
 }
 
@@ -126,6 +487,54 @@ type TODOWalker struct {
 	// path is the currently walked path.
 	path string
 
+	// codeowners caches the CODEOWNERS file for each repository root.
+	codeowners map[string]*codeowners.File
+
+	// configs caches the merged configuration for each directory.
+	configs map[string]*dirConfig
+
+	// ref is the tree of the git ref that is being walked.
+	ref *refTree
+
+	// seen are the physical files that have been walked when Dedup is
+	// enabled.
+	seen map[fileID]struct{}
+
+	// auditMatches caches the audit regular expressions for each set of
+	// TODO types.
+	auditMatches map[string]*regexp.Regexp
+
+	// state is the current walk state. It is nil if checkpoints are not
+	// enabled.
+	state *State
+
+	// scanned is the number of files scanned since the walk started.
+	scanned int
+
+	// resumeLast is the last scanned file in the walked path that the walk is
+	// being resumed from.
+	resumeLast string
+
+	// result is the result of the file currently being scanned when
+	// FileResultFunc is set.
+	result *FileResult
+
+	// resultReported indicates that the file currently being scanned was
+	// reported by reportFile rather than skipped.
+	resultReported bool
+
+	// patchLines are the lines added by the patch currently being scanned
+	// when Options.Patches is set.
+	patchLines map[int]bool
+
+	// roots are the absolute walked Paths with symbolic links evaluated
+	// when Confine is enabled.
+	roots []string
+
+	// aborted indicates that the walk was stopped because of an error that
+	// is fatal to the whole walk.
+	aborted bool
+
 	// The last error encountered.
 	err error
 }
@@ -134,10 +543,50 @@ type TODOWalker struct {
 // when it encounters errors. It instead prints an error message and returns true
 // if errors were encountered.
 func (w *TODOWalker) Walk() bool {
-	for _, path := range w.options.Paths {
+	resumeIndex := 0
+	if w.options.StateFunc != nil || w.options.Resume != nil {
+		w.state = &State{
+			Paths:   w.options.Paths,
+			Options: OptionsHash(w.options),
+		}
+	}
+	if w.options.Resume != nil {
+		if err := w.resume(w.options.Resume); err != nil {
+			_ = w.handleErr("", err)
+			return true
+		}
+		resumeIndex = w.state.PathIndex
+	}
+
+	for i, path := range w.options.Paths {
+		if w.aborted {
+			break
+		}
+		if i < resumeIndex {
+			continue
+		}
 		w.path = path
 
-		f, err := os.Open(path)
+		if w.state != nil {
+			if i == resumeIndex {
+				w.resumeLast = w.state.Last
+			} else {
+				w.resumeLast = ""
+			}
+			w.state.PathIndex = i
+			w.state.Last = ""
+		}
+
+		if w.options.Ref != "" {
+			if err := w.walkRef(path); err != nil {
+				if herr := w.handleErr(path, err); herr != nil {
+					break
+				}
+			}
+			continue
+		}
+
+		fInfo, err := os.Stat(path)
 		if err != nil {
 			if herr := w.handleErr(path, err); herr != nil {
 				break
@@ -145,7 +594,26 @@ func (w *TODOWalker) Walk() bool {
 			continue
 		}
 
-		fInfo, err := f.Stat()
+		// NOTE: Irregular files are skipped even if they are explicitly
+		// specified since opening them could block forever.
+		if r := irregularSkipReason(path, fInfo.Mode()); r != nil {
+			if err := w.skip(r); err != nil {
+				break
+			}
+			continue
+		}
+
+		// NOTE: Directories are checked for duplicates when they are walked.
+		if !fInfo.IsDir() {
+			if r := w.duplicateSkipReason(path, path, fInfo); r != nil {
+				if err := w.skip(r); err != nil {
+					break
+				}
+				continue
+			}
+		}
+
+		f, err := os.Open(path)
 		if err != nil {
 			if herr := w.handleErr(path, err); herr != nil {
 				break
@@ -153,24 +621,55 @@ func (w *TODOWalker) Walk() bool {
 			continue
 		}
 
+		stop := false
 		if fInfo.IsDir() {
 			// Walk the directory
 			w.walkDir(path)
-		} else {
+		} else if w.resumeLast != "." {
 			// Single file. Always scan this file since it was explicitly specified.
-			if err := w.scanFile(f, true); err != nil {
-				if herr := w.handleErr(path, err); herr != nil {
-					break
-				}
-			}
+			stop = w.walkFile(path, f)
 		}
 
 		f.Close()
+		if stop {
+			break
+		}
 	}
 
 	return w.err != nil
 }
 
+// noteReplaced passes a note to NoteFunc if invalid bytes were replaced when
+// decoding the file.
+func (w *TODOWalker) noteReplaced(fileName string, n int) error {
+	if n == 0 || w.options.NoteFunc == nil {
+		return nil
+	}
+	return w.options.NoteFunc(fileName, fmt.Sprintf("replaced %d invalid bytes", n))
+}
+
+// walkFile scans a single file that was explicitly specified. It returns
+// whether the walk should be stopped.
+func (w *TODOWalker) walkFile(path string, f *os.File) bool {
+	cfg, err := w.dirConfig(filepath.Dir(path), ".")
+	if err != nil {
+		if herr := w.handleConfigErr(path, err); herr != nil {
+			return true
+		}
+	}
+	if err := w.scanFile(f, cfg, true); err != nil {
+		if herr := w.handleErr(path, err); herr != nil {
+			return true
+		}
+	}
+	if err := w.checkpoint("."); err != nil {
+		if herr := w.handleErr(path, err); herr != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func (w *TODOWalker) walkDir(path string) {
 	if err := fs.WalkDir(os.DirFS(path), ".", w.walkFunc); err != nil {
 		// This shouldn't happen. Errors are all handled in the WalkDir.
@@ -185,6 +684,14 @@ func (w *TODOWalker) walkFunc(path string, d fs.DirEntry, err error) error {
 		return w.handleErr(path, err)
 	}
 
+	// Skip paths that were walked before the checkpoint being resumed from.
+	if w.resumeSkip(path, d.IsDir()) {
+		if d.IsDir() {
+			return fs.SkipDir
+		}
+		return nil
+	}
+
 	fullPath, err := filepath.EvalSymlinks(filepath.Join(w.path, path))
 	if err != nil {
 		// NOTE: If the symbolic link couldn't be evaluated just skip it.
@@ -194,7 +701,30 @@ func (w *TODOWalker) walkFunc(path string, d fs.DirEntry, err error) error {
 		return nil
 	}
 
-	f, err := os.Open(fullPath)
+	if d.Type()&fs.ModeSymlink != 0 {
+		r, err := w.symlinkSkipReason(w.path, path, fullPath)
+		if err != nil {
+			return w.handleErr(path, err)
+		}
+		if r != nil {
+			return w.skip(r)
+		}
+	}
+
+	r, err := w.confineSkipReason(path, fullPath)
+	if err != nil {
+		return w.handleErr(path, err)
+	}
+	if r != nil {
+		if err := w.handleWarning(path, fmt.Errorf("%w: %s", errOutsideRoot, r.Pattern)); err != nil {
+			return err
+		}
+		return w.skip(r)
+	}
+
+	// NOTE: fullPath has had symbolic links evaluated so Lstat returns the
+	// mode of the file itself.
+	info, err := os.Lstat(fullPath)
 	if err != nil {
 		if herr := w.handleErr(path, err); herr != nil {
 			return herr
@@ -204,9 +734,24 @@ func (w *TODOWalker) walkFunc(path string, d fs.DirEntry, err error) error {
 		}
 		return nil
 	}
-	defer f.Close()
 
-	info, err := f.Stat()
+	// Skip irregular files such as named pipes before opening them since
+	// opening them could block forever.
+	if r := irregularSkipReason(path, info.Mode()); r != nil {
+		return w.skip(r)
+	}
+
+	if r := w.duplicateSkipReason(path, fullPath, info); r != nil {
+		if err := w.skip(r); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return fs.SkipDir
+		}
+		return nil
+	}
+
+	f, err := os.Open(fullPath)
 	if err != nil {
 		if herr := w.handleErr(path, err); herr != nil {
 			return herr
@@ -216,12 +761,13 @@ func (w *TODOWalker) walkFunc(path string, d fs.DirEntry, err error) error {
 		}
 		return nil
 	}
+	defer f.Close()
 
 	// NOTE(github.com/ianlewis/todos/issues/40): d.IsDir sometimes returns false for some directories.
 	if info.IsDir() {
 		return w.processDir(path, fullPath)
 	}
-	return w.processFile(path, fullPath, f)
+	return w.processFile(path, fullPath, info, f)
 }
 
 func (w *TODOWalker) processDir(path, fullPath string) error {
@@ -230,94 +776,435 @@ func (w *TODOWalker) processDir(path, fullPath string) error {
 		return nil
 	}
 
-	// Exclude directories that match one of the given glob patterns.
-	for _, g := range w.options.ExcludeDirGlobs {
-		if g.Match(filepath.Base(fullPath)) {
-			return fs.SkipDir
+	cfg, err := w.dirConfig(w.path, filepath.Dir(path))
+	if err != nil {
+		if herr := w.handleConfigErr(path, err); herr != nil {
+			return herr
 		}
 	}
 
-	hdn, err := isHidden(fullPath)
+	r, err := w.dirSkipReason(path, fullPath, cfg)
 	if err != nil {
 		if herr := w.handleErr(path, err); herr != nil {
 			return herr
 		}
 		return fs.SkipDir
 	}
-
-	if hdn && !w.options.IncludeHidden {
-		// Skip hidden files.
+	if r != nil {
+		if err := w.skip(r); err != nil {
+			return err
+		}
+		if err := w.audit(r, fullPath, cfg); err != nil {
+			return err
+		}
 		return fs.SkipDir
 	}
+	return nil
+}
 
-	if !w.options.IncludeVCS && isVCS(fullPath) {
-		return fs.SkipDir
+func (w *TODOWalker) processFile(path, fullPath string, info fs.FileInfo, f *os.File) error {
+	cfg, err := w.dirConfig(w.path, filepath.Dir(path))
+	if err != nil {
+		if herr := w.handleConfigErr(path, err); herr != nil {
+			return herr
+		}
 	}
 
-	// NOTE: linguist regexs only matches paths with a *nix path separators.
-	basePath := strings.ReplaceAll(filepath.Base(fullPath), string(os.PathSeparator), "/")
+	r, err := w.fileSkipReason(path, fullPath, cfg)
+	if err != nil {
+		return w.handleErr(path, err)
+	}
+	if r != nil {
+		// Skip file.
+		if err := w.skip(r); err != nil {
+			return err
+		}
+		return w.audit(r, fullPath, cfg)
+	}
 
-	// NOTE: linguist regexs only matches paths with a path separator at the end.
-	if !strings.HasSuffix(basePath, "/") {
-		basePath += "/"
+	if r := w.sizeSkipReason(path, info.Size()); r != nil {
+		return w.skip(r)
 	}
 
-	if !w.options.IncludeVendored && vendoring.IsVendor(basePath) {
-		return fs.SkipDir
+	if r := w.modifiedSkipReason(path, info.ModTime()); r != nil {
+		return w.skip(r)
+	}
+
+	if err := w.scanFile(f, cfg, false); err != nil {
+		return err
+	}
+
+	if err := w.checkpoint(path); err != nil {
+		return w.handleErr(path, err)
 	}
 	return nil
 }
 
-func (w *TODOWalker) processFile(path, fullPath string, f *os.File) error {
-	// Exclude files that match one of the given glob patterns.
-	for _, g := range w.options.ExcludeGlobs {
-		if g.Match(filepath.Base(fullPath)) {
-			// Skip file.
-			return nil
-		}
+func (w *TODOWalker) scanFile(f *os.File, cfg *dirConfig, force bool) error {
+	// NOTE: Sharding applies to all files, including those that were
+	// explicitly specified, so that each file is scanned by exactly one shard.
+	if r := w.shardSkipReason(f.Name()); r != nil {
+		return w.skip(r)
 	}
 
-	hdn, err := isHidden(fullPath)
+	before, err := f.Stat()
 	if err != nil {
-		return w.handleErr(path, err)
+		return fmt.Errorf("reading %s: %w", f.Name(), err)
 	}
 
-	if hdn && !w.options.IncludeHidden {
-		// Skip hidden files.
-		return nil
+	rawContents, err := io.ReadAll(f)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", f.Name(), err)
+	}
+
+	// NOTE: Files that change while being read could produce inconsistent
+	// results so skip them with a warning.
+	after, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", f.Name(), err)
+	}
+	if r := unstableSkipReason(f.Name(), before, after, len(rawContents)); r != nil {
+		if err := w.skip(r); err != nil {
+			return err
+		}
+		return w.handleWarning(f.Name(), fmt.Errorf("%w: skipped", errUnstable))
 	}
 
-	return w.scanFile(f, false)
+	return w.scanInput(f.Name(), rawContents, cfg, force)
 }
 
-func (w *TODOWalker) scanFile(f *os.File, force bool) error {
-	rawContents, err := io.ReadAll(f)
+// scanContents scans the contents of the file with the given name for TODOs
+// and passes the result to FileResultFunc.
+func (w *TODOWalker) scanContents(fileName string, rawContents []byte, cfg *dirConfig, force bool) error {
+	if w.options.FileResultFunc == nil {
+		return w.scanTODOs(fileName, rawContents, cfg, force)
+	}
+
+	start := time.Now()
+	w.result = &FileResult{
+		FileName: fileName,
+		Size:     int64(len(rawContents)),
+		Lines:    countLines(rawContents),
+	}
+	w.resultReported = false
+	err := w.scanTODOs(fileName, rawContents, cfg, force)
+	result, reported := w.result, w.resultReported
+	w.result = nil
+	if err != nil || !reported {
+		return err
+	}
+
+	result.Duration = time.Since(start)
+	return w.options.FileResultFunc(result)
+}
+
+// scanTODOs scans the contents of the file with the given name for TODOs.
+func (w *TODOWalker) scanTODOs(fileName string, rawContents []byte, cfg *dirConfig, force bool) error {
+	if !force {
+		if r := w.contentSkipReason(fileName, rawContents); r != nil {
+			if err := w.skip(r); err != nil {
+				return err
+			}
+			return w.auditContents(r, rawContents, cfg)
+		}
+	}
+
+	var key string
+	if w.options.Cache != nil {
+		key = w.cacheKey(fileName, rawContents, cfg)
+		entry, err := w.cacheGet(key)
+		if err != nil {
+			if herr := w.handleErr(fileName, err); herr != nil {
+				return herr
+			}
+		}
+		if entry != nil {
+			if r := languageSkipReason(fileName, entry.Language, cfg); r != nil && !force {
+				return w.skip(r)
+			}
+			if err := w.noteReplaced(fileName, entry.Replaced); err != nil {
+				return err
+			}
+			if err := w.reportNearMisses(fileName, entry.NearMisses); err != nil {
+				return err
+			}
+			if w.result != nil {
+				w.result.Stats = entry.Stats
+				w.result.Metrics = entry.Metrics
+			}
+			return w.reportFile(fileName, entry.Language, entry.LanguageDetection, entry.TODOs)
+		}
+	}
+
+	charset, lang, config := w.options.Charset, cfg.languageOverride(fileName, rawContents), w.scannerConfig(cfg)
+	if e := w.extractor(fileName); e != nil {
+		text, err := e.Extract(rawContents)
+		if err != nil {
+			return w.handleErr(fileName, fmt.Errorf("extracting text: %w", err))
+		}
+		// NOTE: Extracted text is always UTF-8 and is scanned for TODOs on
+		// whole lines since documents don't have comments.
+		rawContents, charset, lang = text, "UTF-8", "Text"
+		config = func(string) *scanner.Config {
+			return scanner.Text
+		}
+	}
+
+	s, err := scanner.FromBytesWith(fileName, rawContents, charset, lang, config)
 	if err != nil {
-		return fmt.Errorf("reading %s: %w", f.Name(), err)
+		if herr := w.handleErr(fileName, err); herr != nil {
+			return herr
+		}
 	}
 
-	if !force && !w.options.IncludeGenerated && enry.IsGenerated(f.Name(), rawContents) {
+	// Skip files that can't be scanned.
+	if s == nil {
 		return nil
 	}
 
-	s, err := scanner.FromBytes(f.Name(), rawContents, w.options.Charset)
-	if err != nil {
-		if herr := w.handleErr(f.Name(), err); herr != nil {
+	// NOTE: Languages are detected from the file contents so they can only be
+	// checked after the scanner is created.
+	if r := languageSkipReason(fileName, s.Language(), cfg); r != nil && !force {
+		return w.skip(r)
+	}
+
+	if err := w.noteReplaced(fileName, s.Replaced()); err != nil {
+		return err
+	}
+
+	if w.options.ListFiles {
+		return w.reportFile(fileName, s.Language(), s.LanguageDetection(), nil)
+	}
+
+	s.SetSkipShebang(w.options.SkipShebang)
+	s.SetMaxLineLength(w.options.MaxLineLength)
+	s.SetSkipStrings(w.options.SkipStrings)
+	// NOTE: The TODOScanner copies the comment fields it needs so comments
+	// can be reused.
+	s.SetReuseComments(true)
+
+	var cs todos.CommentScanner = s
+	var a *analysis.Analyzer
+	if w.options.Metrics {
+		a = &analysis.Analyzer{}
+		cs = &analyzingScanner{
+			CommentScanner: s,
+			analyzer:       a,
+		}
+	}
+
+	if !w.options.Deadline.IsZero() {
+		cs = WithDeadline(cs, w.options.Deadline)
+	}
+
+	var found []*todos.TODO
+	t := todos.NewTODOScanner(cs, cfg.todoConfig)
+	for t.Scan() {
+		found = append(found, t.Next())
+	}
+	scanErr := t.Err()
+
+	var metrics *analysis.Metrics
+	if a != nil {
+		metrics = a.Metrics(rawContents)
+	}
+	if w.result != nil {
+		w.result.Stats = s.Stats()
+		w.result.Metrics = metrics
+	}
+
+	// NOTE: Only cache complete results.
+	if w.options.Cache != nil && scanErr == nil {
+		if err := w.cachePut(key, &cacheEntry{
+			Language:          s.Language(),
+			LanguageDetection: s.LanguageDetection(),
+			TODOs:             found,
+			NearMisses:        t.NearMisses(),
+			Metrics:           metrics,
+			Stats:             s.Stats(),
+			Replaced:          s.Replaced(),
+		}); err != nil {
+			if herr := w.handleErr(fileName, err); herr != nil {
+				return herr
+			}
+		}
+	}
+
+	if err := w.reportNearMisses(fileName, t.NearMisses()); err != nil {
+		return err
+	}
+
+	if err := w.reportFile(fileName, s.Language(), s.LanguageDetection(), found); err != nil {
+		return err
+	}
+
+	if scanErr != nil {
+		if herr := w.handleErr(fileName, scanErr); herr != nil {
 			return herr
 		}
+		if errors.Is(scanErr, ErrDeadline) {
+			return fs.SkipAll
+		}
+	}
+
+	return nil
+}
+
+// extractor returns the extractor used to convert the file to text before
+// scanning it or nil if the file is scanned as is.
+func (w *TODOWalker) extractor(fileName string) extract.Extractor {
+	if !w.options.Extract {
+		return nil
+	}
+	return extract.ForFile(fileName)
+}
+
+// analyzingScanner adds the comments returned by a CommentScanner to an
+// Analyzer.
+type analyzingScanner struct {
+	todos.CommentScanner
+	analyzer *analysis.Analyzer
+}
+
+// Next implements todos.CommentScanner.Next.
+func (s *analyzingScanner) Next() *scanner.Comment {
+	c := s.CommentScanner.Next()
+	if c != nil {
+		s.analyzer.Add(c)
+	}
+	return c
+}
+
+// deadlineScanner stops a CommentScanner when a deadline has passed.
+type deadlineScanner struct {
+	todos.CommentScanner
+	deadline time.Time
+	err      error
+}
+
+// WithDeadline returns a CommentScanner that stops scanning with ErrDeadline
+// if a comment is scanned after the deadline.
+func WithDeadline(s todos.CommentScanner, deadline time.Time) todos.CommentScanner {
+	return &deadlineScanner{
+		CommentScanner: s,
+		deadline:       deadline,
+	}
+}
+
+// Scan implements todos.CommentScanner.Scan.
+func (s *deadlineScanner) Scan() bool {
+	if s.err != nil || !s.CommentScanner.Scan() {
+		return false
+	}
+	if time.Now().After(s.deadline) {
+		s.err = ErrDeadline
+		return false
+	}
+	return true
+}
+
+// Err implements todos.CommentScanner.Err.
+func (s *deadlineScanner) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	//nolint:wrapcheck
+	return s.CommentScanner.Err()
+}
+
+// noteDetection passes a note to NoteFunc if the language of the file was
+// guessed from several candidates so that users can override it.
+func (w *TODOWalker) noteDetection(fileName, lang string, detection *scanner.LanguageDetection) error {
+	if w.options.NoteFunc == nil || detection == nil || detection.Confident {
+		return nil
+	}
+	note := fmt.Sprintf("detected as %s by %s", lang, detection.Method)
+	if len(detection.Candidates) > 1 {
+		note += " from " + strings.Join(detection.Candidates, ", ")
+	}
+	return w.options.NoteFunc(fileName, note)
+}
+
+// reportNearMisses passes a warning for each TODO whose type only differs
+// from one of the TODO types by case to WarningFunc.
+func (w *TODOWalker) reportNearMisses(fileName string, nearMisses []*todos.TODO) error {
+	for _, todo := range nearMisses {
+		nerr := fmt.Errorf("%w: line %d: %s", errNearMiss, todo.Line, todo.Text)
+		if err := w.handleWarning(fileName, nerr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reportFile passes the file and the TODOs found in it to the handlers.
+func (w *TODOWalker) reportFile(
+	fileName, lang string,
+	detection *scanner.LanguageDetection,
+	found []*todos.TODO,
+) error {
+	fileRef := &FileRef{
+		FileName:          fileName,
+		Language:          lang,
+		LanguageDetection: detection,
+	}
+	if w.result != nil {
+		w.result.Language = lang
+		w.result.LanguageDetection = detection
+		w.resultReported = true
+	}
+	if err := w.noteDetection(fileName, lang, detection); err != nil {
+		return err
+	}
+	if w.options.FileFunc != nil {
+		if w.state != nil {
+			w.state.Files = append(w.state.Files, fileRef)
+		}
+		if err := w.options.FileFunc(fileRef); err != nil {
+			return err
+		}
+	}
+	if w.options.ListFiles {
+		return nil
 	}
 
 	// Cache these values for each file for performance reasons.
 	var repo *git.Repository
 	var br *git.BlameResult
 
-	// Skip files that can't be scanned.
-	if s == nil {
-		return nil
+	owners, err := w.owners(fileName)
+	if err != nil {
+		if herr := w.handleErr(fileName, err); herr != nil {
+			return herr
+		}
 	}
-	t := todos.NewTODOScanner(s, w.options.Config)
-	for t.Scan() {
-		todo := t.Next()
+
+	// NOTE: Ordinals are counted before filtering by label so that
+	// fingerprints do not depend on the filter.
+	ordinals := map[string]int{}
+
+	for _, todo := range found {
+		norm := normalizeText(todo.Text)
+		ordinal := ordinals[norm]
+		ordinals[norm]++
+
+		if todo.Unbalanced {
+			uerr := fmt.Errorf("%w: line %d: %s", errUnbalancedRegion, todo.Line, todo.Text)
+			if err := w.handleWarning(fileName, uerr); err != nil {
+				return err
+			}
+		}
+
+		if slices.Contains(w.options.ExcludeTypes, todo.Type) {
+			continue
+		}
+
+		// Check the excluded label globs to see if any match.
+		if slices.ContainsFunc(w.options.ExcludeLabelGlobs, func(g glob.Glob) bool {
+			return g.Match(todo.Label)
+		}) {
+			continue
+		}
 
 		// Check the label globs to see if any match.
 		if len(w.options.LabelGlobs) > 0 {
@@ -333,27 +1220,81 @@ func (w *TODOWalker) scanFile(f *os.File, force bool) error {
 			}
 		}
 
-		if w.options.TODOFunc != nil {
+		if len(w.options.Assignees) > 0 && !slices.ContainsFunc(w.options.Assignees, func(a string) bool {
+			// NOTE: User names are case-insensitive.
+			return strings.EqualFold(a, todos.Assignee(todo.Label))
+		}) {
+			continue
+		}
+
+		if w.patchLines != nil && !w.patchLines[todo.Line] {
+			continue
+		}
+
+		if w.options.Lines != nil && !w.options.Lines.Overlaps(todo) {
+			continue
+		}
+
+		// Check that all of the attribute filters match.
+		if !allAttrsMatch(w.options.AttrFilters, todo) {
+			continue
+		}
+
+		// Check the regular expressions to see if any match the text.
+		if len(w.options.GrepRegexps) > 0 {
+			textMatch := false
+			for _, re := range w.options.GrepRegexps {
+				if re.MatchString(todo.Text) {
+					textMatch = true
+					break
+				}
+			}
+			if !textMatch {
+				continue
+			}
+		}
+
+		var msgLang string
+		if w.options.DetectMessageLanguage || len(w.options.MessageLanguages) > 0 {
+			msgLang = natlang.Detect(todo.Message)
+		}
+		if len(w.options.MessageLanguages) > 0 && !slices.Contains(w.options.MessageLanguages, msgLang) {
+			continue
+		}
+
+		if w.options.TODOFunc != nil || w.result != nil {
 			var gitUser *GitUser
-			repo, br, gitUser, err = w.gitUser(f.Name(), repo, br, todo.Line)
+			repo, br, gitUser, err = w.gitUser(fileName, repo, br, todo.Line)
 			if err != nil {
-				if herr := w.handleErr(f.Name(), err); herr != nil {
+				if herr := w.handleErr(fileName, err); herr != nil {
 					return herr
 				}
 			}
 
-			if err := w.options.TODOFunc(&TODORef{
-				FileName: f.Name(),
-				TODO:     todo,
-				GitUser:  gitUser,
-			}); err != nil {
+			ref := &TODORef{
+				FileName:        fileName,
+				TODO:            todo,
+				GitUser:         gitUser,
+				Fingerprint:     Fingerprint(fileName, todo.Text, ordinal),
+				Owners:          owners,
+				Findings:        w.findings(todo),
+				MessageLanguage: msgLang,
+			}
+			if err := w.reportFindings(ref); err != nil {
+				return err
+			}
+			if w.state != nil {
+				w.state.TODOs = append(w.state.TODOs, ref)
+			}
+			if w.result != nil {
+				w.result.TODOs = append(w.result.TODOs, ref)
+			}
+			if w.options.TODOFunc == nil {
+				continue
+			}
+			if err := w.options.TODOFunc(ref); err != nil {
 				return err
 			}
-		}
-	}
-	if err := t.Err(); err != nil {
-		if herr := w.handleErr(f.Name(), err); herr != nil {
-			return herr
 		}
 	}
 
@@ -363,17 +1304,39 @@ func (w *TODOWalker) scanFile(f *os.File, force bool) error {
 // gitRepo finds the git repository for the given path and returns the
 // *git.Repository, and root path.
 func (w *TODOWalker) gitRepo(path string) (*git.Repository, string, error) {
+	path, err := repoRoot(path)
+	if err != nil {
+		return nil, "", err
+	}
+	if path == "" {
+		// No repository found.
+		return nil, "", nil
+	}
+
+	r, err := git.PlainOpen(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: opening git repo at path %q: %w", errGit, path, err)
+	}
+
+	return r, path, nil
+}
+
+// repoRoot finds the root directory of the git repository containing the
+// given path. It returns an empty string if the path is not in a git
+// repository.
+func repoRoot(path string) (string, error) {
 	var err error
 	if path, err = filepath.Abs(path); err != nil {
-		return nil, "", fmt.Errorf("%w: getting absolute path %q: %w", errGit, path, err)
+		return "", fmt.Errorf("%w: getting absolute path %q: %w", errGit, path, err)
 	}
 
 	// If the given path is a file, start at its parent directory.
+	// NOTE: Files read from a git ref may not exist in the working tree.
 	fi, err := os.Stat(path)
-	if err != nil {
-		return nil, "", fmt.Errorf("%w: stat %q: %w", errGit, path, err)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("%w: stat %q: %w", errGit, path, err)
 	}
-	if !fi.IsDir() {
+	if fi == nil || !fi.IsDir() {
 		path = filepath.Dir(path)
 	}
 
@@ -381,10 +1344,10 @@ func (w *TODOWalker) gitRepo(path string) (*git.Repository, string, error) {
 		gitPath := filepath.Join(path, ".git")
 		_, err = os.Stat(gitPath)
 		if err == nil {
-			break
+			return path, nil
 		}
 		if !os.IsNotExist(err) {
-			return nil, "", fmt.Errorf("%w: stat %q: %w", errGit, gitPath, err)
+			return "", fmt.Errorf("%w: stat %q: %w", errGit, gitPath, err)
 		}
 
 		// Check if the root directory has been reached.
@@ -394,15 +1357,28 @@ func (w *TODOWalker) gitRepo(path string) (*git.Repository, string, error) {
 		}
 
 		// No repository found.
-		return nil, "", nil
+		return "", nil
 	}
+}
 
-	r, err := git.PlainOpen(path)
+// blameCommit returns the commit to find committers in. This is the commit for
+// Options.Ref when walking a git ref and HEAD otherwise.
+func (w *TODOWalker) blameCommit(r *git.Repository) (*object.Commit, error) {
+	if w.ref != nil {
+		return w.ref.commit, nil
+	}
+
+	ref, err := r.Head()
 	if err != nil {
-		return nil, "", fmt.Errorf("%w: opening git repo at path %q: %w", errGit, path, err)
+		return nil, fmt.Errorf("%w: getting HEAD ref: %w", errGit, err)
 	}
 
-	return r, path, nil
+	hash := ref.Hash()
+	c, err := r.CommitObject(hash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: getting commit object for hash %s, %w", errGit, hash, err)
+	}
+	return c, nil
 }
 
 func (w *TODOWalker) gitBlame(r *git.Repository, repoRoot, path string) (*git.BlameResult, error) {
@@ -418,16 +1394,11 @@ func (w *TODOWalker) gitBlame(r *git.Repository, repoRoot, path string) (*git.Bl
 		return nil, fmt.Errorf("%w: getting relative path: %w", errGit, err)
 	}
 
-	ref, err := r.Head()
+	c, err := w.blameCommit(r)
 	if err != nil {
-		return nil, fmt.Errorf("%w: getting HEAD ref: %w", errGit, err)
-	}
-
-	hash := ref.Hash()
-	c, err := r.CommitObject(hash)
-	if err != nil {
-		return nil, fmt.Errorf("%w: getting commit object for hash %s, %w", errGit, hash, err)
+		return nil, err
 	}
+	hash := c.Hash
 
 	// NOTE: git.Blame only supports paths with slash.
 	br, err := git.Blame(c, filepath.ToSlash(relPath))
@@ -494,6 +1465,9 @@ func (w *TODOWalker) handleErr(prefix string, err error) error {
 	}
 
 	w.err = err
+	if w.result != nil {
+		w.result.Errors = append(w.result.Errors, err)
+	}
 	if w.options.ErrorFunc != nil {
 		if prefix != "" {
 			err = fmt.Errorf("%s: %w", prefix, err)
@@ -505,6 +1479,59 @@ func (w *TODOWalker) handleErr(prefix string, err error) error {
 	return nil
 }
 
+// countLines returns the number of lines in the contents.
+func countLines(contents []byte) int {
+	// NOTE: Classic Mac OS files use a carriage return as the line ending.
+	n := bytes.Count(contents, []byte("\n")) + bytes.Count(contents, []byte("\r")) - bytes.Count(contents, []byte("\r\n"))
+	if len(contents) > 0 && contents[len(contents)-1] != '\n' && contents[len(contents)-1] != '\r' {
+		n++
+	}
+	return n
+}
+
+// allAttrsMatch returns whether the TODO matches all of the filters.
+func allAttrsMatch(filters []*AttrFilter, todo *todos.TODO) bool {
+	for _, f := range filters {
+		if !f.Match(todo) {
+			return false
+		}
+	}
+	return true
+}
+
+// skip passes the reason that a path is skipped to SkipFunc.
+func (w *TODOWalker) skip(r *SkipReason) error {
+	if w.options.SkipFunc == nil {
+		return nil
+	}
+	return w.options.SkipFunc(r)
+}
+
+// handleConfigErr handles an error loading the configuration for path. If
+// StrictConfig is enabled the walk is stopped.
+func (w *TODOWalker) handleConfigErr(path string, err error) error {
+	if herr := w.handleErr(path, err); herr != nil {
+		return herr
+	}
+	if w.options.StrictConfig {
+		w.aborted = true
+		return fs.SkipAll
+	}
+	return nil
+}
+
+// handleWarning passes the warning to WarningFunc. Unlike handleErr it does
+// not record the warning as an error.
+func (w *TODOWalker) handleWarning(prefix string, err error) error {
+	if w.options.WarningFunc == nil {
+		return nil
+	}
+	if prefix != "" {
+		err = fmt.Errorf("%s: %w", prefix, err)
+	}
+	return w.options.WarningFunc(err)
+}
+
 // isVCS returns whether the path is a vcs path. Should only be called on directories.
 func isVCS(path string) bool {
 	basePath := filepath.Base(path)
@@ -15,16 +15,26 @@
 package walker
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gobwas/glob"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 
+	"github.com/ianlewis/todos/internal/analysis"
+	"github.com/ianlewis/todos/internal/config"
+	"github.com/ianlewis/todos/internal/extract"
+	"github.com/ianlewis/todos/internal/natlang"
+	"github.com/ianlewis/todos/internal/scanner"
 	"github.com/ianlewis/todos/internal/testutils"
 	"github.com/ianlewis/todos/internal/todos"
 )
@@ -770,6 +780,184 @@ var testCases = []testCase{
 		},
 	},
 
+	{
+		name: "docs dir skipped",
+		files: []*testutils.File{
+			{
+				Path: filepath.Join("examples", "line_comments.go"),
+				Contents: []byte(`package foo
+				// package comment
+
+				// TODO is a function.
+				// TODO: some task.
+				func TODO() {
+					return // Random comment
+				}`),
+				Mode: 0o600,
+			},
+		},
+		opts: &Options{
+			Config: &todos.Config{
+				Types: []string{"TODO"},
+			},
+			Charset: "UTF-8",
+		},
+		expected: nil,
+	},
+	{
+		name: "docs dir specified",
+		files: []*testutils.File{
+			{
+				Path: filepath.Join("examples", "line_comments.go"),
+				Contents: []byte(`package foo
+				// package comment
+
+				// TODO is a function.
+				// TODO: some task.
+				func TODO() {
+					return // Random comment
+				}`),
+				Mode: 0o600,
+			},
+		},
+		opts: &Options{
+			Config: &todos.Config{
+				Types: []string{"TODO"},
+			},
+			Charset: "UTF-8",
+			Paths:   []string{"examples"},
+		},
+		expected: []*TODORef{
+			{
+				FileName: filepath.Join("examples", "line_comments.go"),
+				TODO: &todos.TODO{
+					Type:        "TODO",
+					Text:        "// TODO: some task.",
+					Message:     "some task.",
+					Line:        5,
+					CommentLine: 5,
+				},
+			},
+		},
+	},
+	{
+		name: "docs dir processed",
+		files: []*testutils.File{
+			{
+				Path: filepath.Join("examples", "line_comments.go"),
+				Contents: []byte(`package foo
+				// package comment
+
+				// TODO is a function.
+				// TODO: some task.
+				func TODO() {
+					return // Random comment
+				}`),
+				Mode: 0o600,
+			},
+		},
+		opts: &Options{
+			Config: &todos.Config{
+				Types: []string{"TODO"},
+			},
+			Charset:     "UTF-8",
+			IncludeDocs: true,
+		},
+		expected: []*TODORef{
+			{
+				FileName: filepath.Join("examples", "line_comments.go"),
+				TODO: &todos.TODO{
+					Type:        "TODO",
+					Text:        "// TODO: some task.",
+					Message:     "some task.",
+					Line:        5,
+					CommentLine: 5,
+				},
+			},
+		},
+	},
+	{
+		name: "docs file skipped",
+		files: []*testutils.File{
+			{
+				Path: "INSTALL.sh",
+				Contents: []byte(`#!/bin/sh
+				# TODO: some task.
+				echo "Installing..."`),
+				Mode: 0o600,
+			},
+		},
+		opts: &Options{
+			Config: &todos.Config{
+				Types: []string{"TODO"},
+			},
+			Charset: "UTF-8",
+		},
+		expected: nil,
+	},
+	{
+		name: "docs file specified",
+		files: []*testutils.File{
+			{
+				Path: "INSTALL.sh",
+				Contents: []byte(`#!/bin/sh
+				# TODO: some task.
+				echo "Installing..."`),
+				Mode: 0o600,
+			},
+		},
+		opts: &Options{
+			Config: &todos.Config{
+				Types: []string{"TODO"},
+			},
+			Charset: "UTF-8",
+			Paths:   []string{"INSTALL.sh"},
+		},
+		expected: []*TODORef{
+			{
+				FileName: "INSTALL.sh",
+				TODO: &todos.TODO{
+					Type:        "TODO",
+					Text:        "# TODO: some task.",
+					Message:     "some task.",
+					Line:        2,
+					CommentLine: 2,
+				},
+			},
+		},
+	},
+	{
+		name: "docs file processed",
+		files: []*testutils.File{
+			{
+				Path: "INSTALL.sh",
+				Contents: []byte(`#!/bin/sh
+				# TODO: some task.
+				echo "Installing..."`),
+				Mode: 0o600,
+			},
+		},
+		opts: &Options{
+			Config: &todos.Config{
+				Types: []string{"TODO"},
+			},
+			Charset:     "UTF-8",
+			IncludeDocs: true,
+		},
+		expected: []*TODORef{
+			{
+				FileName: "INSTALL.sh",
+				TODO: &todos.TODO{
+					Type:        "TODO",
+					Text:        "# TODO: some task.",
+					Message:     "some task.",
+					Line:        2,
+					CommentLine: 2,
+				},
+			},
+		},
+	},
+
 	{
 		name: "single file traverse path multiple todos",
 		files: []*testutils.File{
@@ -1049,6 +1237,13 @@ func init() {
 	}
 }
 
+// ignoreFingerprint ignores TODO fingerprints which are tested separately.
+var ignoreFingerprint = cmpopts.IgnoreFields(TODORef{}, "Fingerprint")
+
+// ignoreOffsets ignores comment offsets which are tested in the scanner
+// package.
+var ignoreOffsets = cmpopts.IgnoreFields(todos.TODO{}, "CommentOffset", "CommentEndOffset")
+
 type fixture struct {
 	dir *testutils.TempDir
 	wd  string
@@ -1195,7 +1390,7 @@ func TestTODOWalker(t *testing.T) {
 			}
 
 			got, want := f.out, tc.expected
-			if diff := cmp.Diff(want, got, cmp.AllowUnexported(TODORef{})); diff != "" {
+			if diff := cmp.Diff(want, got, cmp.AllowUnexported(TODORef{}), ignoreFingerprint, ignoreOffsets); diff != "" {
 				t.Errorf("unexpected output (-want +got):\n%s", diff)
 			}
 		})
@@ -1221,7 +1416,7 @@ func TestTODOWalker_git(t *testing.T) {
 			}
 
 			got, want := f.out, tc.expected
-			if diff := cmp.Diff(want, got, cmp.AllowUnexported(TODORef{})); diff != "" {
+			if diff := cmp.Diff(want, got, cmp.AllowUnexported(TODORef{}), ignoreFingerprint, ignoreOffsets); diff != "" {
 				t.Errorf("unexpected output (-want +got):\n%s", diff)
 			}
 		})
@@ -1284,7 +1479,7 @@ func TestTODOWalker_PathNotExists(t *testing.T) {
 			},
 		},
 	}
-	if diff := cmp.Diff(want, got); diff != "" {
+	if diff := cmp.Diff(want, got, ignoreFingerprint, ignoreOffsets); diff != "" {
 		t.Errorf("unexpected output (-want +got):\n%s", diff)
 	}
 }
@@ -1347,7 +1542,7 @@ func TestTODOWalker_StopEarly(t *testing.T) {
 			},
 		},
 	}
-	if diff := cmp.Diff(want, got); diff != "" {
+	if diff := cmp.Diff(want, got, ignoreFingerprint, ignoreOffsets); diff != "" {
 		t.Errorf("unexpected output (-want +got):\n%s", diff)
 	}
 }
@@ -1429,7 +1624,1995 @@ func TestTODOWalker_gitSubDir(t *testing.T) {
 	}
 
 	got, want := f.out, expected
-	if diff := cmp.Diff(want, got, cmp.AllowUnexported(TODORef{})); diff != "" {
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(TODORef{}), ignoreFingerprint, ignoreOffsets); diff != "" {
 		t.Errorf("unexpected output (-want +got):\n%s", diff)
 	}
 }
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_ListFiles(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "code.go",
+			Contents: []byte("// TODO: code"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "script.py",
+			Contents: []byte("# TODO: script"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "unknown.foo",
+			Contents: []byte("TODO: unknown"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "vendor/lib.go",
+			Contents: []byte("// TODO: vendored"),
+			Mode:     0o600,
+		},
+	}
+
+	var got []*FileRef
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+		FileFunc: func(r *FileRef) error {
+			got = append(got, r)
+			return nil
+		},
+		ListFiles: true,
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	want := []*FileRef{
+		{
+			FileName: "code.go",
+			Language: "Go",
+			LanguageDetection: &scanner.LanguageDetection{
+				Method:     "extension",
+				Candidates: []string{"Go"},
+				Confident:  true,
+			},
+		},
+		{
+			FileName: "script.py",
+			Language: "Python",
+			LanguageDetection: &scanner.LanguageDetection{
+				Method:     "extension",
+				Candidates: []string{"Python"},
+				Confident:  true,
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected files (-want +got):\n%s", diff)
+	}
+
+	if got, want := len(f.out), 0; got != want {
+		t.Errorf("unexpected # of TODOs, got: %v, want: %v", got, want)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Shard(t *testing.T) {
+	var files []*testutils.File
+	for i := range 20 {
+		files = append(files, &testutils.File{
+			Path:     fmt.Sprintf("dir/file%d.go", i),
+			Contents: []byte(fmt.Sprintf("// TODO: file %d", i)),
+			Mode:     0o600,
+		})
+	}
+
+	shardCount := 3
+	seen := map[string]int{}
+	for i := range shardCount {
+		opts := &Options{
+			Config: &todos.Config{
+				Types: []string{"TODO"},
+			},
+			Charset:    "UTF-8",
+			ShardIndex: i,
+			ShardCount: shardCount,
+		}
+
+		f, w := newFixture(files, opts)
+		if got, want := w.Walk(), false; got != want {
+			t.Errorf("shard %d: unexpected error code, got: %v, want: %v\nw.err: %v", i, got, want, w.err)
+		}
+		if len(f.out) == 0 {
+			t.Errorf("shard %d: no files scanned", i)
+		}
+		for _, r := range f.out {
+			seen[r.FileName]++
+		}
+		f.cleanup()
+	}
+
+	if got, want := len(seen), len(files); got != want {
+		t.Errorf("unexpected # of files scanned, got: %v, want: %v", got, want)
+	}
+	for name, n := range seen {
+		if n != 1 {
+			t.Errorf("file %q scanned by %d shards", name, n)
+		}
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Owners(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     ".github/CODEOWNERS",
+			Contents: []byte("* @default\n/frontend/ @frontend-team\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "main.go",
+			Contents: []byte("// TODO: main"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "frontend/app.js",
+			Contents: []byte("// TODO: app"),
+			Mode:     0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+		Owners:  true,
+	}
+
+	f, w := newRepoFixture("John Doe", "john@doe.com", files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	got := map[string][]string{}
+	for _, r := range f.out {
+		got[r.FileName] = r.Owners
+	}
+	want := map[string][]string{
+		"main.go":                           {"@default"},
+		filepath.Join("frontend", "app.js"): {"@frontend-team"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected owners (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Config(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "code.go",
+			Contents: []byte("// TODO: root\n// FIXME: root"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "add/.todos.yml",
+			Contents: []byte("add_types: [FIXME]\nexclude: ['*.py']\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "add/code.go",
+			Contents: []byte("// TODO: add\n// FIXME: add"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "add/script.py",
+			Contents: []byte("# TODO: excluded"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "add/override/.todos.yml",
+			Contents: []byte("types: [XXX]\nexclude_dir: [skip]\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "add/override/code.go",
+			Contents: []byte("// TODO: override\n// XXX: override"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "add/override/script.py",
+			Contents: []byte("# XXX: excluded"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "add/override/skip/code.go",
+			Contents: []byte("// XXX: excluded"),
+			Mode:     0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	var got []string
+	for _, r := range f.out {
+		got = append(got, r.FileName+":"+r.TODO.Text)
+	}
+	want := []string{
+		filepath.Join("add", "code.go") + ":// TODO: add",
+		filepath.Join("add", "code.go") + ":// FIXME: add",
+		filepath.Join("add", "override", "code.go") + ":// XXX: override",
+		"code.go:// TODO: root",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_ConfigLanguages(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     ".todos.yml",
+			Contents: []byte("languages:\n  json: false\n  YAML: false\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "code.go",
+			Contents: []byte("// TODO: code"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "data.yaml",
+			Contents: []byte("# TODO: disabled"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "enabled/.todos.yml",
+			Contents: []byte("languages: {YAML: true}\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "enabled/data.yaml",
+			Contents: []byte("# TODO: enabled"),
+			Mode:     0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	var skipped []*SkipReason
+	w.options.SkipFunc = func(r *SkipReason) error {
+		if r.Rule == SkipLanguage {
+			skipped = append(skipped, r)
+		}
+		return nil
+	}
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	var got []string
+	for _, r := range f.out {
+		got = append(got, r.FileName+":"+r.TODO.Text)
+	}
+	want := []string{
+		"code.go:// TODO: code",
+		filepath.Join("enabled", "data.yaml") + ":# TODO: enabled",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+	}
+
+	wantSkipped := []*SkipReason{
+		{
+			Rule:    SkipLanguage,
+			Path:    "data.yaml",
+			Pattern: "YAML",
+		},
+	}
+	if diff := cmp.Diff(wantSkipped, skipped); diff != "" {
+		t.Errorf("unexpected skipped (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_ConfigLanguagePolicies(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     ".todos.yml",
+			Contents: []byte("languages:\n  Text: text\n  Go: skip\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "code.go",
+			Contents: []byte("// TODO: skipped"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "notes.txt",
+			Contents: []byte("# TODO: text\nTODO: not a comment\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "bad/.todos.yml",
+			Contents: []byte("languages: {Python: maybe}\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "bad/script.py",
+			Contents: []byte("# TODO: bad"),
+			Mode:     0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), true; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v", got, want)
+	}
+
+	// NOTE: Invalid policies are reported as errors and the inherited
+	// configuration is used.
+	if got, want := len(f.err), 1; got != want {
+		t.Fatalf("unexpected # of errors, got: %v, want: %v", got, want)
+	}
+	if !errors.Is(f.err[0], errLanguagePolicy) {
+		t.Errorf("unexpected error, got: %v, want: %v", f.err[0], errLanguagePolicy)
+	}
+
+	var got []string
+	for _, r := range f.out {
+		got = append(got, r.FileName+":"+r.TODO.Text)
+	}
+	want := []string{
+		filepath.Join("bad", "script.py") + ":# TODO: bad",
+		"notes.txt:# TODO: text",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_FallbackLanguage(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "code.go",
+			Contents: []byte("// TODO: code\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "notes.txt",
+			Contents: []byte("TODO: line\n# TODO: comment\n"),
+			Mode:     0o600,
+		},
+	}
+
+	testCases := map[string]struct {
+		fallback string
+		expected []string
+	}{
+		"none": {
+			expected: []string{
+				"code.go:// TODO: code",
+			},
+		},
+		"text": {
+			fallback: "Text",
+			expected: []string{
+				"code.go:// TODO: code",
+				"notes.txt:TODO: line",
+				"notes.txt:# TODO: comment",
+			},
+		},
+		"language": {
+			fallback: "Shell",
+			expected: []string{
+				"code.go:// TODO: code",
+				"notes.txt:# TODO: comment",
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			opts := &Options{
+				Config: &todos.Config{
+					Types: []string{"TODO"},
+				},
+				Charset:          "UTF-8",
+				FallbackLanguage: tc.fallback,
+			}
+
+			f, w := newFixture(files, opts)
+			defer f.cleanup()
+
+			if got, want := w.Walk(), false; got != want {
+				t.Errorf("unexpected error code, got: %v, want: %v", got, want)
+			}
+
+			var got []string
+			for _, r := range f.out {
+				got = append(got, r.FileName+":"+r.TODO.Text)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// testDocHeader is the header of the documents that the test extractor
+// converts to text.
+const testDocHeader = "\x00TESTDOC\n"
+
+func init() {
+	extract.Register(".testdoc", extract.ExtractorFunc(func(contents []byte) ([]byte, error) {
+		text, ok := bytes.CutPrefix(contents, []byte(testDocHeader))
+		if !ok {
+			return nil, errors.New("missing header")
+		}
+		return text, nil
+	}))
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Extract(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "code.go",
+			Contents: []byte("// TODO: code\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "design.testdoc",
+			Contents: []byte(testDocHeader + "Design\nTODO: doc\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "broken.testdoc",
+			Contents: []byte("\x00TODO: broken\n"),
+			Mode:     0o600,
+		},
+	}
+
+	testCases := map[string]struct {
+		extract  bool
+		expected []string
+		errs     int
+	}{
+		"disabled": {
+			expected: []string{
+				"code.go:1:// TODO: code",
+			},
+		},
+		"enabled": {
+			extract: true,
+			expected: []string{
+				"code.go:1:// TODO: code",
+				"design.testdoc:2:TODO: doc",
+			},
+			errs: 1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			opts := &Options{
+				Config: &todos.Config{
+					Types: []string{"TODO"},
+				},
+				Charset: "UTF-8",
+				Extract: tc.extract,
+			}
+
+			f, w := newFixture(files, opts)
+			defer f.cleanup()
+
+			if got, want := w.Walk(), tc.errs > 0; got != want {
+				t.Errorf("unexpected error code, got: %v, want: %v", got, want)
+			}
+			if got, want := len(f.err), tc.errs; got != want {
+				t.Errorf("unexpected # of errors, got: %v, want: %v: %v", got, want, f.err)
+			}
+
+			var got []string
+			for _, r := range f.out {
+				got = append(got, fmt.Sprintf("%s:%d:%s", r.FileName, r.TODO.Line, r.TODO.Text))
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_ConfigNearMissWarnings(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     ".todos.yml",
+			Contents: []byte("near_miss_warnings: true\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "code.go",
+			Contents: []byte("// TODO: exact\n// Todo: near miss\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "disabled/.todos.yml",
+			Contents: []byte("near_miss_warnings: false\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "disabled/code.go",
+			Contents: []byte("// todo: disabled\n"),
+			Mode:     0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	var warnings []error
+	w.options.WarningFunc = func(err error) error {
+		warnings = append(warnings, err)
+		return nil
+	}
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	if got, want := len(f.out), 1; got != want {
+		t.Fatalf("unexpected # of TODOs, got: %v, want: %v", got, want)
+	}
+	if got, want := len(warnings), 1; got != want {
+		t.Fatalf("unexpected # of warnings, got: %v, want: %v", got, want)
+	}
+	if !errors.Is(warnings[0], errNearMiss) {
+		t.Errorf("unexpected warning: %v", warnings[0])
+	}
+	if got, want := warnings[0].Error(), "code.go: TODO type only differs by case: line 2: // Todo: near miss"; got != want {
+		t.Errorf("unexpected warning, got: %q, want: %q", got, want)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_ConfigExtensions(t *testing.T) {
+	// NOTE: Qt translation files are detected as XML.
+	qtContents := []byte("<?xml version=\"1.0\"?>\n<TS>\n// TODO: script\n</TS>\n")
+
+	files := []*testutils.File{
+		{
+			Path:     "detected.ts",
+			Contents: qtContents,
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/.todos.yml",
+			Contents: []byte("extensions: {TS: TypeScript}\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/override.ts",
+			Contents: qtContents,
+			Mode:     0o600,
+		},
+		{
+			Path:     "bad/.todos.yml",
+			Contents: []byte("extensions: {.ts: NotALanguage}\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "bad/code.go",
+			Contents: []byte("// TODO: bad"),
+			Mode:     0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), true; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v", got, want)
+	}
+
+	// NOTE: Unsupported languages are reported as errors and the inherited
+	// configuration is used.
+	if got, want := len(f.err), 1; got != want {
+		t.Fatalf("unexpected # of errors, got: %v, want: %v", got, want)
+	}
+	if !errors.Is(f.err[0], errUnsupportedLanguage) {
+		t.Errorf("unexpected error, got: %v, want: %v", f.err[0], errUnsupportedLanguage)
+	}
+
+	var got []string
+	for _, r := range f.out {
+		got = append(got, r.FileName+":"+r.TODO.Text)
+	}
+	want := []string{
+		filepath.Join("bad", "code.go") + ":// TODO: bad",
+		filepath.Join("sub", "override.ts") + ":// TODO: script",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_RMarkdown(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path: "report.Rmd",
+			Contents: []byte("# TODO: heading\n" +
+				"<!-- TODO: prose -->\n" +
+				"```{r}\n" +
+				"#' TODO: roxygen\n" +
+				"x <- 1 # TODO: r\n" +
+				"```\n"),
+			Mode: 0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v", got, want)
+	}
+
+	var got []string
+	for _, r := range f.out {
+		got = append(got, r.TODO.Text)
+	}
+	want := []string{"<!-- TODO: prose -->", "#' TODO: roxygen", "# TODO: r"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_ConfigDetect(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path: ".todos.yml",
+			Contents: []byte(`detect:
+  - extensions: [.m]
+    pattern: '^#import'
+    language: Objective-C
+  - pattern: '^%%'
+    language: MATLAB
+`),
+			Mode: 0o600,
+		},
+		{
+			Path:     "a.m",
+			Contents: []byte("#import <Foo.h>\n// TODO: objc\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "b.m",
+			Contents: []byte("%% cell\n% TODO: matlab\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "notes.txt",
+			Contents: []byte("%% notes\n% TODO: notes\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/.todos.yml",
+			Contents: []byte("detect: [{pattern: '^#import', language: C}]\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/a.m",
+			Contents: []byte("#import <Foo.h>\n// TODO: c\n"),
+			Mode:     0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	var got []string
+	w.options.FileFunc = func(r *FileRef) error {
+		got = append(got, r.FileName+":"+r.Language)
+		return nil
+	}
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	want := []string{
+		"a.m:Objective-C",
+		"b.m:MATLAB",
+		"notes.txt:MATLAB",
+		filepath.Join("sub", "a.m") + ":C",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected files (-want +got):\n%s", diff)
+	}
+}
+
+func TestCompileDetectRule(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		rule config.DetectRule
+		err  error
+	}{
+		"valid": {
+			rule: config.DetectRule{
+				Extensions: []string{"TS"},
+				Pattern:    `^<\?xml`,
+				Language:   "XML",
+			},
+		},
+		"unsupported language": {
+			rule: config.DetectRule{
+				Pattern:  `^<\?xml`,
+				Language: "NotALanguage",
+			},
+			err: errUnsupportedLanguage,
+		},
+		"missing pattern": {
+			rule: config.DetectRule{
+				Language: "XML",
+			},
+			err: errDetectRule,
+		},
+		"invalid pattern": {
+			rule: config.DetectRule{
+				Pattern:  `(`,
+				Language: "XML",
+			},
+			err: errDetectRule,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := compileDetectRule(tc.rule)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("unexpected error, got: %v, want: %v", err, tc.err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff([]string{".ts"}, r.extensions); diff != "" {
+				t.Errorf("unexpected extensions (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_ExtensionLanguages(t *testing.T) {
+	contents := []byte("// TODO: objc\n% TODO: matlab\n")
+	files := []*testutils.File{
+		{
+			Path:     "foo.m",
+			Contents: contents,
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/.todos.yml",
+			Contents: []byte("extensions: {.m: Objective-C}\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/foo.m",
+			Contents: contents,
+			Mode:     0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+		ExtensionLanguages: map[string]string{
+			".m": "MATLAB",
+		},
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	var got []string
+	for _, r := range f.out {
+		got = append(got, r.FileName+":"+r.TODO.Text)
+	}
+	// NOTE: Configuration files override the options.
+	want := []string{
+		"foo.m:% TODO: matlab",
+		filepath.Join("sub", "foo.m") + ":// TODO: objc",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_ConfigError(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "sub/.todos.yml",
+			Contents: []byte("types: [TODO"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/code.go",
+			Contents: []byte("// TODO: sub"),
+			Mode:     0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), true; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v", got, want)
+	}
+
+	// NOTE: The error is reported once and the inherited configuration is used.
+	if got, want := len(f.err), 1; got != want {
+		t.Errorf("unexpected # of errors, got: %v, want: %v", got, want)
+	}
+	if got, want := len(f.out), 1; got != want {
+		t.Errorf("unexpected # of TODOs, got: %v, want: %v", got, want)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Ref(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "code.go",
+			Contents: []byte("// TODO: committed"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/code.go",
+			Contents: []byte("// TODO: sub committed"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/.todos.yml",
+			Contents: []byte("add_types: [XXX]\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/other.go",
+			Contents: []byte("// XXX: other committed"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "vendor/lib.go",
+			Contents: []byte("// TODO: vendored"),
+			Mode:     0o600,
+		},
+	}
+
+	testCases := map[string]struct {
+		paths    []string
+		expected []string
+	}{
+		"root": {
+			paths: []string{"."},
+			expected: []string{
+				"code.go:// TODO: committed",
+				filepath.Join("sub", "code.go") + ":// TODO: sub committed",
+				filepath.Join("sub", "other.go") + ":// XXX: other committed",
+			},
+		},
+		"sub dir": {
+			paths: []string{"sub"},
+			expected: []string{
+				filepath.Join("sub", "code.go") + ":// TODO: sub committed",
+				filepath.Join("sub", "other.go") + ":// XXX: other committed",
+			},
+		},
+		"file": {
+			paths: []string{filepath.Join("sub", "other.go")},
+			expected: []string{
+				filepath.Join("sub", "other.go") + ":// XXX: other committed",
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			opts := &Options{
+				Config: &todos.Config{
+					Types: []string{"TODO"},
+				},
+				Charset: "UTF-8",
+				Ref:     "v1.2.3",
+				Paths:   tc.paths,
+			}
+
+			f, w := newRepoFixture("Author", "author@example.com", files, opts)
+			defer f.cleanup()
+
+			r := f.repo.Repository()
+			head := testutils.Must(r.Head())
+			_ = testutils.Must(r.CreateTag("v1.2.3", head.Hash(), nil))
+
+			// Change the working tree after the tag.
+			testutils.Check(os.WriteFile("code.go", []byte("// TODO: changed"), 0o600))
+			testutils.Check(os.WriteFile("new.go", []byte("// TODO: new"), 0o600))
+			testutils.Check(os.Remove(filepath.Join("sub", "other.go")))
+			testutils.Check(os.Remove(filepath.Join("sub", ".todos.yml")))
+
+			if got, want := w.Walk(), false; got != want {
+				t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+			}
+
+			var got []string
+			for _, r := range f.out {
+				got = append(got, r.FileName+":"+r.TODO.Text)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_RefNotFound(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "code.go",
+			Contents: []byte("// TODO: committed"),
+			Mode:     0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+		Ref:     "does-not-exist",
+	}
+
+	f, w := newRepoFixture("Author", "author@example.com", files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), true; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v", got, want)
+	}
+	if got, want := len(f.out), 0; got != want {
+		t.Errorf("unexpected # of TODOs, got: %v, want: %v", got, want)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_FileResults(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "code.go",
+			Contents: []byte("package main\n\n// TODO: code\n// FIXME: excluded\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "script.py",
+			Contents: []byte("print('hello')"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "binary.exe",
+			Contents: []byte{0, 1, 2, 3},
+			Mode:     0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO", "FIXME"},
+		},
+		ExcludeTypes: []string{"FIXME"},
+		Charset:      "UTF-8",
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	var got []*FileResult
+	w.options.FileResultFunc = func(r *FileResult) error {
+		got = append(got, r)
+		return nil
+	}
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	want := []*FileResult{
+		{
+			FileName: "code.go",
+			Language: "Go",
+			LanguageDetection: &scanner.LanguageDetection{
+				Method:     "extension",
+				Candidates: []string{"Go"},
+				Confident:  true,
+			},
+			Size:  int64(len(files[0].Contents)),
+			Lines: 4,
+			TODOs: []*TODORef{
+				{
+					FileName: "code.go",
+					TODO: &todos.TODO{
+						Type:        "TODO",
+						Text:        "// TODO: code",
+						Message:     "code",
+						Line:        3,
+						CommentLine: 3,
+					},
+				},
+			},
+			Stats: scanner.Stats{
+				Transitions: 4,
+				Bytes:       len(files[0].Contents),
+				Comments:    2,
+			},
+		},
+		{
+			FileName: "script.py",
+			Language: "Python",
+			LanguageDetection: &scanner.LanguageDetection{
+				Method:     "extension",
+				Candidates: []string{"Python"},
+				Confident:  true,
+			},
+			Size:  int64(len(files[1].Contents)),
+			Lines: 1,
+			Stats: scanner.Stats{
+				Transitions: 2,
+				Bytes:       len(files[1].Contents),
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got, ignoreFingerprint, ignoreOffsets, cmpopts.IgnoreFields(FileResult{}, "Duration")); diff != "" {
+		t.Errorf("unexpected results (-want +got):\n%s", diff)
+	}
+	// NOTE: The TODOs in the results are the same as those given to TODOFunc.
+	if diff := cmp.Diff(f.out, got[0].TODOs); diff != "" {
+		t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Metrics(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "code.go",
+			Contents: []byte("package main\n\n// main is the entry point.\nfunc main() {}\n\n// TODO: code\nfunc f() {}\n"),
+			Mode:     0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+		Metrics: true,
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	var got []*analysis.Metrics
+	w.options.FileResultFunc = func(r *FileResult) error {
+		got = append(got, r.Metrics)
+		return nil
+	}
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	want := []*analysis.Metrics{
+		{
+			Lines:        7,
+			BlankLines:   2,
+			CodeLines:    3,
+			CommentLines: 2,
+			Declarations: 3,
+			Documented:   2,
+			CommentWords: 7,
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected metrics (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_SkipTests(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "code.go",
+			Contents: []byte("// TODO: code"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "code_test.go",
+			Contents: []byte("// TODO: test"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "test_code.py",
+			Contents: []byte("# TODO: test"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "code.spec.ts",
+			Contents: []byte("// TODO: test"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "__tests__/code.js",
+			Contents: []byte("// TODO: test"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/.todos.yml",
+			Contents: []byte("tests: ['*_it.go']\ntest_dirs: [e2e]\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/code_it.go",
+			Contents: []byte("// TODO: test"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/e2e/code.go",
+			Contents: []byte("// TODO: test"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "sub/code.go",
+			Contents: []byte("// TODO: sub"),
+			Mode:     0o600,
+		},
+	}
+
+	testCases := map[string]struct {
+		skipTests bool
+		expected  []string
+	}{
+		"include tests": {
+			skipTests: false,
+			expected: []string{
+				filepath.Join("__tests__", "code.js"),
+				"code.go",
+				"code.spec.ts",
+				"code_test.go",
+				filepath.Join("sub", "code.go"),
+				filepath.Join("sub", "code_it.go"),
+				filepath.Join("sub", "e2e", "code.go"),
+				"test_code.py",
+			},
+		},
+		"skip tests": {
+			skipTests: true,
+			expected: []string{
+				"code.go",
+				filepath.Join("sub", "code.go"),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			opts := &Options{
+				Config: &todos.Config{
+					Types: []string{"TODO"},
+				},
+				Charset:   "UTF-8",
+				SkipTests: tc.skipTests,
+			}
+
+			f, w := newFixture(files, opts)
+			defer f.cleanup()
+
+			if got, want := w.Walk(), false; got != want {
+				t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+			}
+
+			var got []string
+			for _, r := range f.out {
+				got = append(got, r.FileName)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected files (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Metadata(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "small.go",
+			Contents: []byte("// TODO: small"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "large.go",
+			Contents: []byte("// TODO: large\n" + strings.Repeat("// padding\n", 10)),
+			Mode:     0o600,
+		},
+		{
+			Path:     "old.go",
+			Contents: []byte("// TODO: old file"),
+			Mode:     0o600,
+		},
+	}
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		minSize       int64
+		maxSize       int64
+		modifiedSince time.Time
+		expected      []string
+	}{
+		"no filters": {
+			expected: []string{"large.go", "old.go", "small.go"},
+		},
+		"min size": {
+			minSize:  15,
+			expected: []string{"large.go", "old.go"},
+		},
+		"max size": {
+			maxSize:  20,
+			expected: []string{"old.go", "small.go"},
+		},
+		"modified since": {
+			modifiedSince: since,
+			expected:      []string{"large.go", "small.go"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			opts := &Options{
+				Config: &todos.Config{
+					Types: []string{"TODO"},
+				},
+				Charset:       "UTF-8",
+				MinSize:       tc.minSize,
+				MaxSize:       tc.maxSize,
+				ModifiedSince: tc.modifiedSince,
+			}
+
+			f, w := newFixture(files, opts)
+			defer f.cleanup()
+
+			old := since.Add(-24 * time.Hour)
+			testutils.Check(os.Chtimes("old.go", old, old))
+
+			if got, want := w.Walk(), false; got != want {
+				t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+			}
+
+			var got []string
+			for _, r := range f.out {
+				got = append(got, r.FileName)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected files (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Grep(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "code.go",
+			Contents: []byte("// TODO: deprecate this\n// TODO(foo): Deprecated API\n// TODO: something else\n"),
+			Mode:     0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset:     "UTF-8",
+		GrepRegexps: []*regexp.Regexp{regexp.MustCompile("(?i)deprecate")},
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	var got []string
+	for _, r := range f.out {
+		got = append(got, r.TODO.Text)
+	}
+	want := []string{
+		"// TODO: deprecate this",
+		"// TODO(foo): Deprecated API",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_ExcludeTypesLabels(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path: "code.go",
+			Contents: []byte(`// TODO: keep
+// NOTE: excluded type
+// TODO(wontfix-123): excluded label
+// FIXME(bug-1): keep
+`),
+			Mode: 0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO", "NOTE", "FIXME"},
+		},
+		Charset:           "UTF-8",
+		ExcludeTypes:      []string{"NOTE"},
+		ExcludeLabelGlobs: []glob.Glob{glob.MustCompile("wontfix*")},
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	var got []string
+	for _, r := range f.out {
+		got = append(got, r.TODO.Text)
+	}
+	want := []string{
+		"// TODO: keep",
+		"// FIXME(bug-1): keep",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Assignees(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path: "code.go",
+			Contents: []byte(`// TODO(alice): keep
+// TODO(@Alice): keep mention
+// TODO(bob): other user
+// TODO(#123): issue
+// TODO: unassigned
+`),
+			Mode: 0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset:   "UTF-8",
+		Assignees: []string{"alice"},
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	var got []string
+	for _, r := range f.out {
+		got = append(got, r.TODO.Text)
+	}
+	want := []string{
+		"// TODO(alice): keep",
+		"// TODO(@Alice): keep mention",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Attributes(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path: "code.go",
+			Contents: []byte(`// TODO(priority=p1, assignee=alice): keep
+// TODO(priority=p2, assignee=alice): wrong priority
+// TODO(priority=p1): no assignee
+// TODO: no attributes
+`),
+			Mode: 0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+		AttrFilters: []*AttrFilter{
+			{Key: "priority", Value: glob.MustCompile("p1")},
+			{Key: "assignee"},
+		},
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	var got []string
+	for _, r := range f.out {
+		got = append(got, r.TODO.Message)
+	}
+	want := []string{"keep"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Note(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "code.go",
+			Contents: []byte("// TODO: f\xffoo\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "script.pl",
+			Contents: []byte("# TODO: perl\nprint 1;\n"),
+			Mode:     0o600,
+		},
+	}
+
+	var notes []string
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+		NoteFunc: func(fileName, note string) error {
+			notes = append(notes, fileName+": "+note)
+			return nil
+		},
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	if got, want := len(f.out), 2; got != want {
+		t.Errorf("unexpected # of TODOs, got: %v, want: %v", got, want)
+	}
+	want := []string{
+		"code.go: replaced 1 invalid bytes",
+		"script.pl: detected as Perl by classifier from Perl, Prolog, Raku",
+	}
+	if diff := cmp.Diff(want, notes); diff != "" {
+		t.Errorf("unexpected notes (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_UnbalancedRegion(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path: "code.go",
+			Contents: []byte(`// TODO-BEGIN(#123): disabled
+func foo() {}
+// TODO-END
+
+// TODO-BEGIN: never closed
+`),
+			Mode: 0o600,
+		},
+	}
+
+	var warnings []error
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+		WarningFunc: func(err error) error {
+			warnings = append(warnings, err)
+			return nil
+		},
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	if got, want := len(f.out), 2; got != want {
+		t.Fatalf("unexpected # of TODOs, got: %v, want: %v", got, want)
+	}
+	if got, want := f.out[0].TODO.EndLine, 3; got != want {
+		t.Errorf("unexpected end line, got: %v, want: %v", got, want)
+	}
+	if got, want := len(warnings), 1; got != want {
+		t.Fatalf("unexpected # of warnings, got: %v, want: %v", got, want)
+	}
+	if !errors.Is(warnings[0], errUnbalancedRegion) {
+		t.Errorf("unexpected warning: %v", warnings[0])
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Audit(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "code.go",
+			Contents: []byte("// TODO: code"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "excluded.go",
+			Contents: []byte("// TODO: excluded"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "vendor/a.go",
+			Contents: []byte("// TODO: vendored"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "vendor/b.go",
+			Contents: []byte("// TODO: vendored"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "vendor/c.go",
+			Contents: []byte("// no todos"),
+			Mode:     0o600,
+		},
+		{
+			Path:     ".hidden.go",
+			Contents: []byte("// no todos"),
+			Mode:     0o600,
+		},
+	}
+
+	var warnings []string
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset:      "UTF-8",
+		ExcludeGlobs: []glob.Glob{testutils.Must(CompileGlob("excluded.go"))},
+		Audit:        true,
+		WarningFunc: func(err error) error {
+			if !errors.Is(err, errAudit) {
+				t.Errorf("unexpected warning: %v", err)
+			}
+			warnings = append(warnings, err.Error())
+			return nil
+		},
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	if got, want := len(f.out), 1; got != want {
+		t.Errorf("unexpected # of TODOs, got: %v, want: %v", got, want)
+	}
+
+	want := []string{
+		`excluded.go: skipped files contain TODOs: 1 files skipped by exclude pattern "excluded.go" matched "excluded.go"`,
+		`vendor: skipped files contain TODOs: 2 files skipped by vendored pattern "(^|/)vendors?/" matched "vendor"`,
+	}
+	if diff := cmp.Diff(want, warnings); diff != "" {
+		t.Errorf("unexpected warnings (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Lines(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path: "code.go",
+			Contents: []byte(`// TODO: before
+/*
+TODO: spans the range
+*/
+// TODO: in range
+// TODO-BEGIN: region
+// TODO-END
+// TODO: after
+`),
+			Mode: 0o600,
+		},
+	}
+
+	opts := &Options{
+		Config: &todos.Config{
+			Types: []string{"TODO"},
+		},
+		Charset: "UTF-8",
+		Lines:   &LineRange{Start: 3, End: 7},
+	}
+
+	f, w := newFixture(files, opts)
+	defer f.cleanup()
+
+	if got, want := w.Walk(), false; got != want {
+		t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+	}
+
+	var got []string
+	for _, r := range f.out {
+		got = append(got, r.TODO.Message)
+	}
+	want := []string{"spans the range", "in range", "region"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_MessageLanguages(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path: "code.go",
+			Contents: []byte(`// TODO: remove this workaround after the next release
+// TODO: 後で修正する
+// TODO: diese Methode muss noch überarbeitet werden
+// TODO: fix
+`),
+			Mode: 0o600,
+		},
+	}
+
+	testCases := map[string]struct {
+		detect    bool
+		languages []string
+		expected  map[string]string
+	}{
+		"disabled": {
+			expected: map[string]string{
+				"remove this workaround after the next release": "",
+				"後で修正する":                                        "",
+				"diese Methode muss noch überarbeitet werden":   "",
+				"fix": "",
+			},
+		},
+		"detect": {
+			detect: true,
+			expected: map[string]string{
+				"remove this workaround after the next release": "en",
+				"後で修正する":                                        "ja",
+				"diese Methode muss noch überarbeitet werden":   "de",
+				"fix": natlang.Undetermined,
+			},
+		},
+		"filter": {
+			languages: []string{"ja", "de"},
+			expected: map[string]string{
+				"後で修正する":                                      "ja",
+				"diese Methode muss noch überarbeitet werden": "de",
+			},
+		},
+		"undetermined": {
+			languages: []string{natlang.Undetermined},
+			expected: map[string]string{
+				"fix": natlang.Undetermined,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			opts := &Options{
+				Config: &todos.Config{
+					Types: []string{"TODO"},
+				},
+				Charset:               "UTF-8",
+				DetectMessageLanguage: tc.detect,
+				MessageLanguages:      tc.languages,
+			}
+
+			f, w := newFixture(files, opts)
+			defer f.cleanup()
+
+			if got, want := w.Walk(), false; got != want {
+				t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+			}
+
+			got := map[string]string{}
+			for _, r := range f.out {
+				got[r.TODO.Message] = r.MessageLanguage
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected languages (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_StrictConfig(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "a/.todos.yml",
+			Contents: []byte("exclude: [\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "a/excluded.go",
+			Contents: []byte("// TODO: excluded"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "b/.todos.yml",
+			Contents: []byte("exclude: [\"*_gen.go\"]\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "b/code.go",
+			Contents: []byte("// TODO: code"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "b/code_gen.go",
+			Contents: []byte("// TODO: generated"),
+			Mode:     0o600,
+		},
+	}
+
+	testCases := map[string]struct {
+		paths    []string
+		strict   bool
+		err      bool
+		expected []string
+		configs  []string
+	}{
+		"not strict": {
+			err:      true,
+			expected: []string{"excluded", "code"},
+			configs:  []string{filepath.Join("b", ".todos.yml")},
+		},
+		"strict": {
+			strict: true,
+			err:    true,
+		},
+		"strict paths": {
+			paths:  []string{"a", "b"},
+			strict: true,
+			err:    true,
+		},
+		"strict valid": {
+			paths:    []string{"b"},
+			strict:   true,
+			expected: []string{"code"},
+			configs:  []string{filepath.Join("b", ".todos.yml")},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var configs []string
+			opts := &Options{
+				Config: &todos.Config{
+					Types: []string{"TODO"},
+				},
+				Charset:      "UTF-8",
+				Paths:        tc.paths,
+				StrictConfig: tc.strict,
+				ConfigFunc: func(path string) error {
+					configs = append(configs, path)
+					return nil
+				},
+			}
+
+			f, w := newFixture(files, opts)
+			defer f.cleanup()
+
+			if got, want := w.Walk(), tc.err; got != want {
+				t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+			}
+
+			var got []string
+			for _, r := range f.out {
+				got = append(got, r.TODO.Message)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.configs, configs); diff != "" {
+				t.Errorf("unexpected configs (-want +got):\n%s", diff)
+			}
+			if tc.err && len(f.err) != 1 {
+				t.Errorf("unexpected errors: %v", f.err)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // fixture uses Chdir and cannot be run in parallel.
+func TestTODOWalker_Deadline(t *testing.T) {
+	files := []*testutils.File{
+		{
+			Path:     "a.go",
+			Contents: []byte(strings.Repeat("// comment\n", 1000) + "// TODO: a\n"),
+			Mode:     0o600,
+		},
+		{
+			Path:     "b.go",
+			Contents: []byte("// TODO: b\n"),
+			Mode:     0o600,
+		},
+	}
+
+	testCases := map[string]struct {
+		deadline time.Time
+		err      bool
+		expected []string
+	}{
+		"no deadline": {
+			expected: []string{"a", "b"},
+		},
+		"before deadline": {
+			deadline: time.Now().Add(time.Hour),
+			expected: []string{"a", "b"},
+		},
+		"deadline passed": {
+			deadline: time.Now().Add(-time.Second),
+			err:      true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			opts := &Options{
+				Config: &todos.Config{
+					Types: []string{"TODO"},
+				},
+				Charset:  "UTF-8",
+				Deadline: tc.deadline,
+			}
+
+			f, w := newFixture(files, opts)
+			defer f.cleanup()
+
+			if got, want := w.Walk(), tc.err; got != want {
+				t.Errorf("unexpected error code, got: %v, want: %v\nw.err: %v", got, want, w.err)
+			}
+
+			var got []string
+			for _, r := range f.out {
+				got = append(got, r.TODO.Message)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected TODOs (-want +got):\n%s", diff)
+			}
+			if tc.err && (len(f.err) != 1 || !errors.Is(f.err[0], ErrDeadline)) {
+				t.Errorf("unexpected errors: %v", f.err)
+			}
+		})
+	}
+}